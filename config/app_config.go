@@ -2,28 +2,120 @@ package config
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/febriansr/simple-payment-api/model/money"
 	"github.com/febriansr/simple-payment-api/utils"
 )
 
 type ApiConfig struct {
-	ServerPort string
-	ServerHost string
+	ServerPort      string
+	ServerHost      string
+	ShutdownTimeout time.Duration
 }
 
 type JsonFileConfig struct {
-	Customer string
-	Merchant string
-	History  string
+	Customer        string
+	Merchant        string
+	History         string
+	Posting         string
+	ApiKey          string
+	PaymentRequest  string
+	Hold            string
+	Webhook         string
+	WebhookDelivery string
+	Outbox          string
+	Checkpoint      string
+	TopUp           string
+	BankAccount     string
+	Withdrawal      string
+	PayoutBatch     string
+	FeeRule         string
+	Wallet          string
+	FxRate          string
+}
+
+type StorageConfig struct {
+	Driver               string
+	SqliteFile           string
+	AllowLedgerImbalance bool
+	JsonFileConfig
 }
 
 type TokenConfig struct {
-	ApplicationName     string
-	JwtSignatureKey     string
-	JwtSigningMethod    *jwt.SigningMethodHMAC
-	AccessTokenLifetime time.Duration
+	ApplicationName         string
+	JwtSignatureKey         string
+	JwtSigningMethod        string
+	JwtPrivateKeyFile       string
+	JwtVerificationKeyFiles []string
+	AccessTokenLifetime     time.Duration
+	RefreshTokenLifetime    time.Duration
+}
+
+type SecurityConfig struct {
+	BcryptCost                 int
+	PasswordMinLength          int
+	LoginMaxAttempts           int
+	LoginMaxIpAttempts         int
+	LoginBaseDelay             time.Duration
+	LoginLockoutDuration       time.Duration
+	AdminApiKey                string
+	TotpIssuer                 string
+	TwoFactorChallengeLifetime time.Duration
+	PaymentPinThreshold        money.Amount
+	PinMaxAttempts             int
+	PinLockoutDuration         time.Duration
+	StepUpTokenLifetime        time.Duration
+	MerchantSignatureWindow    time.Duration
+	MerchantKeyEncryptionKey   string
+}
+
+type IdempotencyConfig struct {
+	ProcessingLifetime time.Duration
+	KeyLifetime        time.Duration
+	WaitTimeout        time.Duration
+}
+
+type HoldConfig struct {
+	Lifetime       time.Duration
+	ExpiryInterval time.Duration
+}
+
+type WebhookConfig struct {
+	MaxAttempts      int
+	RetryBaseDelay   time.Duration
+	DispatchInterval time.Duration
+	Timeout          time.Duration
+	AllowedNetworks  []string
+}
+
+type EventConfig struct {
+	PollInterval time.Duration
+	AuditLogFile string
+}
+
+type TopUpConfig struct {
+	MaxAmount         money.Amount
+	ReconcileAfter    time.Duration
+	ReconcileInterval time.Duration
+	Lifetime          time.Duration
+}
+
+type GatewayConfig struct {
+	Secret               string
+	CallbackUrl          string
+	CallbackDelay        time.Duration
+	DelayedCallbackDelay time.Duration
+}
+
+type PayoutConfig struct {
+	MinWithdrawal money.Amount
+	BatchSize     int
+}
+
+type FxConfig struct {
+	RatesFile string
 }
 
 type RedisConfig struct {
@@ -34,28 +126,165 @@ type RedisConfig struct {
 
 type AppConfig struct {
 	ApiConfig
-	JsonFileConfig
+	StorageConfig
 	TokenConfig
+	SecurityConfig
+	IdempotencyConfig
+	HoldConfig
+	WebhookConfig
+	EventConfig
+	TopUpConfig
+	GatewayConfig
+	PayoutConfig
+	FxConfig
 	RedisConfig
 }
 
 func (c *AppConfig) readConfigFile() {
 	envFilePath := ".env"
-	c.JsonFileConfig = JsonFileConfig{
-		Customer: utils.DotEnv("JSON_FILE_NAME_CUSTOMER", envFilePath),
-		Merchant: utils.DotEnv("JSON_FILE_NAME_MERCHANT", envFilePath),
-		History:  utils.DotEnv("JSON_FILE_NAME_HISTORY", envFilePath),
+	allowLedgerImbalance, _ := strconv.ParseBool(utils.DotEnv("LEDGER_ALLOW_IMBALANCE", envFilePath))
+	c.StorageConfig = StorageConfig{
+		Driver:               utils.DotEnv("STORAGE_DRIVER", envFilePath),
+		SqliteFile:           utils.DotEnv("SQLITE_FILE_NAME", envFilePath),
+		AllowLedgerImbalance: allowLedgerImbalance,
+		JsonFileConfig: JsonFileConfig{
+			Customer:        utils.DotEnv("JSON_FILE_NAME_CUSTOMER", envFilePath),
+			Merchant:        utils.DotEnv("JSON_FILE_NAME_MERCHANT", envFilePath),
+			History:         utils.DotEnv("JSON_FILE_NAME_HISTORY", envFilePath),
+			Posting:         utils.DotEnv("JSON_FILE_NAME_POSTING", envFilePath),
+			ApiKey:          utils.DotEnv("JSON_FILE_NAME_API_KEY", envFilePath),
+			PaymentRequest:  utils.DotEnv("JSON_FILE_NAME_PAYMENT_REQUEST", envFilePath),
+			Hold:            utils.DotEnv("JSON_FILE_NAME_HOLD", envFilePath),
+			Webhook:         utils.DotEnv("JSON_FILE_NAME_WEBHOOK", envFilePath),
+			WebhookDelivery: utils.DotEnv("JSON_FILE_NAME_WEBHOOK_DELIVERY", envFilePath),
+			Outbox:          utils.DotEnv("JSON_FILE_NAME_OUTBOX", envFilePath),
+			Checkpoint:      utils.DotEnv("JSON_FILE_NAME_CHECKPOINT", envFilePath),
+			TopUp:           utils.DotEnv("JSON_FILE_NAME_TOPUP", envFilePath),
+			BankAccount:     utils.DotEnv("JSON_FILE_NAME_BANK_ACCOUNT", envFilePath),
+			Withdrawal:      utils.DotEnv("JSON_FILE_NAME_WITHDRAWAL", envFilePath),
+			PayoutBatch:     utils.DotEnv("JSON_FILE_NAME_PAYOUT_BATCH", envFilePath),
+			FeeRule:         utils.DotEnv("JSON_FILE_NAME_FEE_RULE", envFilePath),
+			Wallet:          utils.DotEnv("JSON_FILE_NAME_WALLET", envFilePath),
+			FxRate:          utils.DotEnv("JSON_FILE_NAME_FX_RATE", envFilePath),
+		},
 	}
+	shutdownTimeout, _ := strconv.Atoi(utils.DotEnv("SHUTDOWN_TIMEOUT", envFilePath))
 	c.ApiConfig = ApiConfig{
-		ServerPort: utils.DotEnv("SERVER_PORT", envFilePath),
-		ServerHost: utils.DotEnv("SERVER_HOST", envFilePath),
+		ServerPort:      utils.DotEnv("SERVER_PORT", envFilePath),
+		ServerHost:      utils.DotEnv("SERVER_HOST", envFilePath),
+		ShutdownTimeout: time.Duration(shutdownTimeout) * time.Second,
 	}
 	lifeTime, _ := strconv.Atoi(utils.DotEnv("ACCESS_TOKEN_LIFETIME", envFilePath))
+	refreshLifeTime, _ := strconv.Atoi(utils.DotEnv("REFRESH_TOKEN_LIFETIME", envFilePath))
+	var verificationKeyFiles []string
+	for _, file := range strings.Split(utils.DotEnv("JWT_VERIFICATION_KEY_FILES", envFilePath), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			verificationKeyFiles = append(verificationKeyFiles, file)
+		}
+	}
 	c.TokenConfig = TokenConfig{
-		ApplicationName:     utils.DotEnv("APPLICATION_NAME", envFilePath),
-		JwtSignatureKey:     utils.DotEnv("JWT_SIGNATURE_KEY", envFilePath),
-		JwtSigningMethod:    jwt.SigningMethodHS256,
-		AccessTokenLifetime: time.Duration(lifeTime) * time.Minute,
+		ApplicationName:         utils.DotEnv("APPLICATION_NAME", envFilePath),
+		JwtSignatureKey:         utils.DotEnv("JWT_SIGNATURE_KEY", envFilePath),
+		JwtSigningMethod:        utils.DotEnv("JWT_SIGNING_METHOD", envFilePath),
+		JwtPrivateKeyFile:       utils.DotEnv("JWT_PRIVATE_KEY_FILE", envFilePath),
+		JwtVerificationKeyFiles: verificationKeyFiles,
+		AccessTokenLifetime:     time.Duration(lifeTime) * time.Minute,
+		RefreshTokenLifetime:    time.Duration(refreshLifeTime) * time.Hour,
+	}
+	bcryptCost, _ := strconv.Atoi(utils.DotEnv("BCRYPT_COST", envFilePath))
+	passwordMinLength, _ := strconv.Atoi(utils.DotEnv("PASSWORD_MIN_LENGTH", envFilePath))
+	loginMaxAttempts, _ := strconv.Atoi(utils.DotEnv("LOGIN_MAX_ATTEMPTS", envFilePath))
+	loginMaxIpAttempts, _ := strconv.Atoi(utils.DotEnv("LOGIN_MAX_IP_ATTEMPTS", envFilePath))
+	loginBaseDelay, _ := strconv.Atoi(utils.DotEnv("LOGIN_BASE_DELAY", envFilePath))
+	loginLockoutDuration, _ := strconv.Atoi(utils.DotEnv("LOGIN_LOCKOUT_DURATION", envFilePath))
+	challengeLifetime, _ := strconv.Atoi(utils.DotEnv("TWO_FACTOR_CHALLENGE_LIFETIME", envFilePath))
+	pinThreshold, _ := money.Parse(utils.DotEnv("PAYMENT_PIN_THRESHOLD", envFilePath))
+	pinMaxAttempts, _ := strconv.Atoi(utils.DotEnv("PIN_MAX_ATTEMPTS", envFilePath))
+	pinLockoutDuration, _ := strconv.Atoi(utils.DotEnv("PIN_LOCKOUT_DURATION", envFilePath))
+	stepUpTokenLifetime, _ := strconv.Atoi(utils.DotEnv("STEP_UP_TOKEN_LIFETIME", envFilePath))
+	signatureWindow, _ := strconv.Atoi(utils.DotEnv("MERCHANT_SIGNATURE_WINDOW", envFilePath))
+	c.SecurityConfig = SecurityConfig{
+		BcryptCost:                 bcryptCost,
+		PasswordMinLength:          passwordMinLength,
+		LoginMaxAttempts:           loginMaxAttempts,
+		LoginMaxIpAttempts:         loginMaxIpAttempts,
+		LoginBaseDelay:             time.Duration(loginBaseDelay) * time.Second,
+		LoginLockoutDuration:       time.Duration(loginLockoutDuration) * time.Minute,
+		AdminApiKey:                utils.DotEnv("ADMIN_API_KEY", envFilePath),
+		TotpIssuer:                 utils.DotEnv("TOTP_ISSUER", envFilePath),
+		TwoFactorChallengeLifetime: time.Duration(challengeLifetime) * time.Minute,
+		PaymentPinThreshold:        pinThreshold,
+		PinMaxAttempts:             pinMaxAttempts,
+		PinLockoutDuration:         time.Duration(pinLockoutDuration) * time.Minute,
+		StepUpTokenLifetime:        time.Duration(stepUpTokenLifetime) * time.Minute,
+		MerchantSignatureWindow:    time.Duration(signatureWindow) * time.Second,
+		MerchantKeyEncryptionKey:   utils.DotEnv("MERCHANT_KEY_ENCRYPTION_KEY", envFilePath),
+	}
+	if c.SecurityConfig.TotpIssuer == "" {
+		c.SecurityConfig.TotpIssuer = c.TokenConfig.ApplicationName
+	}
+	processingLifetime, _ := strconv.Atoi(utils.DotEnv("IDEMPOTENCY_PROCESSING_LIFETIME", envFilePath))
+	keyLifetime, _ := strconv.Atoi(utils.DotEnv("IDEMPOTENCY_KEY_LIFETIME", envFilePath))
+	waitTimeout, _ := strconv.Atoi(utils.DotEnv("IDEMPOTENCY_WAIT_TIMEOUT", envFilePath))
+	c.IdempotencyConfig = IdempotencyConfig{
+		ProcessingLifetime: time.Duration(processingLifetime) * time.Second,
+		KeyLifetime:        time.Duration(keyLifetime) * time.Hour,
+		WaitTimeout:        time.Duration(waitTimeout) * time.Second,
+	}
+	holdLifetime, _ := strconv.Atoi(utils.DotEnv("HOLD_LIFETIME", envFilePath))
+	holdExpiryInterval, _ := strconv.Atoi(utils.DotEnv("HOLD_EXPIRY_INTERVAL", envFilePath))
+	c.HoldConfig = HoldConfig{
+		Lifetime:       time.Duration(holdLifetime) * time.Hour,
+		ExpiryInterval: time.Duration(holdExpiryInterval) * time.Second,
+	}
+	webhookMaxAttempts, _ := strconv.Atoi(utils.DotEnv("WEBHOOK_MAX_ATTEMPTS", envFilePath))
+	webhookRetryBaseDelay, _ := strconv.Atoi(utils.DotEnv("WEBHOOK_RETRY_BASE_DELAY", envFilePath))
+	webhookDispatchInterval, _ := strconv.Atoi(utils.DotEnv("WEBHOOK_DISPATCH_INTERVAL", envFilePath))
+	webhookTimeout, _ := strconv.Atoi(utils.DotEnv("WEBHOOK_TIMEOUT", envFilePath))
+	var webhookAllowedNetworks []string
+	for _, network := range strings.Split(utils.DotEnv("WEBHOOK_ALLOWED_NETWORKS", envFilePath), ",") {
+		if network = strings.TrimSpace(network); network != "" {
+			webhookAllowedNetworks = append(webhookAllowedNetworks, network)
+		}
+	}
+	c.WebhookConfig = WebhookConfig{
+		MaxAttempts:      webhookMaxAttempts,
+		RetryBaseDelay:   time.Duration(webhookRetryBaseDelay) * time.Second,
+		DispatchInterval: time.Duration(webhookDispatchInterval) * time.Second,
+		Timeout:          time.Duration(webhookTimeout) * time.Second,
+		AllowedNetworks:  webhookAllowedNetworks,
+	}
+	eventPollInterval, _ := strconv.Atoi(utils.DotEnv("EVENT_POLL_INTERVAL", envFilePath))
+	c.EventConfig = EventConfig{
+		PollInterval: time.Duration(eventPollInterval) * time.Second,
+		AuditLogFile: utils.DotEnv("AUDIT_LOG_FILE", envFilePath),
+	}
+	topUpMaxAmount, _ := money.Parse(utils.DotEnv("TOPUP_MAX_AMOUNT", envFilePath))
+	topUpReconcileAfter, _ := strconv.Atoi(utils.DotEnv("TOPUP_RECONCILE_AFTER", envFilePath))
+	topUpReconcileInterval, _ := strconv.Atoi(utils.DotEnv("TOPUP_RECONCILE_INTERVAL", envFilePath))
+	topUpLifetime, _ := strconv.Atoi(utils.DotEnv("TOPUP_LIFETIME", envFilePath))
+	c.TopUpConfig = TopUpConfig{
+		MaxAmount:         topUpMaxAmount,
+		ReconcileAfter:    time.Duration(topUpReconcileAfter) * time.Second,
+		ReconcileInterval: time.Duration(topUpReconcileInterval) * time.Second,
+		Lifetime:          time.Duration(topUpLifetime) * time.Minute,
+	}
+	callbackDelay, _ := strconv.Atoi(utils.DotEnv("GATEWAY_CALLBACK_DELAY", envFilePath))
+	delayedCallbackDelay, _ := strconv.Atoi(utils.DotEnv("GATEWAY_DELAYED_CALLBACK_DELAY", envFilePath))
+	c.GatewayConfig = GatewayConfig{
+		Secret:               utils.DotEnv("GATEWAY_SECRET", envFilePath),
+		CallbackUrl:          utils.DotEnv("GATEWAY_CALLBACK_URL", envFilePath),
+		CallbackDelay:        time.Duration(callbackDelay) * time.Second,
+		DelayedCallbackDelay: time.Duration(delayedCallbackDelay) * time.Second,
+	}
+	minWithdrawal, _ := money.Parse(utils.DotEnv("WITHDRAWAL_MIN_AMOUNT", envFilePath))
+	payoutBatchSize, _ := strconv.Atoi(utils.DotEnv("PAYOUT_BATCH_SIZE", envFilePath))
+	c.PayoutConfig = PayoutConfig{
+		MinWithdrawal: minWithdrawal,
+		BatchSize:     payoutBatchSize,
+	}
+	c.FxConfig = FxConfig{
+		RatesFile: utils.DotEnv("FX_RATES_FILE", envFilePath),
 	}
 	c.RedisConfig = RedisConfig{
 		Address:  utils.DotEnv("REDDIS_ADDRESS", envFilePath),
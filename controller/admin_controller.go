@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	BaseController
+	router       *gin.RouterGroup
+	adminUsecase usecase.AdminUsecase
+}
+
+func (a *AdminController) UnlockHandler(ctx *gin.Context) {
+	var request req.UnlockRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		a.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	err := a.adminUsecase.UnlockLogin(request)
+
+	if err == nil {
+		a.Success(ctx, nil)
+	} else {
+		a.Failed(ctx, err)
+	}
+}
+
+func (a *AdminController) RoleHandler(ctx *gin.Context) {
+	var request req.RoleRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		a.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	err := a.adminUsecase.SetRole(request)
+
+	if err == nil {
+		a.Success(ctx, nil)
+	} else {
+		a.Failed(ctx, err)
+	}
+}
+
+func (a *AdminController) ReconciliationHandler(ctx *gin.Context) {
+	reconciliation, err := a.adminUsecase.Reconcile()
+
+	if err == nil {
+		a.Success(ctx, reconciliation)
+	} else {
+		a.Failed(ctx, err)
+	}
+}
+
+func NewAdminController(r *gin.RouterGroup, u usecase.AdminUsecase, k middleware.AdminKeyMiddleware, m middleware.AuthTokenMiddleware) *AdminController {
+	controller := AdminController{
+		adminUsecase: u,
+	}
+	ra := r.Group("/admin", k.IdentifyAdminKey(), m.RequireRole(authenticator.RoleAdmin))
+	ra.POST("/unlock", controller.UnlockHandler)
+	ra.PUT("/roles", controller.RoleHandler)
+	ra.GET("/ledger/reconciliation", controller.ReconciliationHandler)
+	return &controller
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyUnlockRequest = req.UnlockRequest{
+	Username: "dummyUsername",
+}
+
+type adminUsecaseMock struct {
+	mock.Mock
+}
+
+func (a *adminUsecaseMock) UnlockLogin(request req.UnlockRequest) error {
+	args := a.Called(request)
+	return args.Error(0)
+}
+
+func (a *adminUsecaseMock) SetRole(request req.RoleRequest) error {
+	args := a.Called(request)
+	return args.Error(0)
+}
+
+func (a *adminUsecaseMock) Reconcile() (res.LedgerReconciliation, error) {
+	args := a.Called()
+	return args.Get(0).(res.LedgerReconciliation), args.Error(1)
+}
+
+type AdminControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *adminUsecaseMock
+	authMock        *authMock
+}
+
+func (suite *AdminControllerTestSuite) serve(body []byte, adminKey string) *httptest.ResponseRecorder {
+	return suite.serveRequest(http.MethodPost, "/v1/admin/unlock", body, func(request *http.Request) {
+		request.Header.Set(middleware.AdminKeyHeader, adminKey)
+	})
+}
+
+func (suite *AdminControllerTestSuite) serveRequest(method string, path string, body []byte, prepare func(request *http.Request)) *httptest.ResponseRecorder {
+	NewAdminController(suite.routerGroupMock, suite.usecaseMock, middleware.NewAdminKeyMiddleware("Dummy Admin Key"), middleware.NewAuthTokenMiddleware(suite.authMock))
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	prepare(request)
+	suite.routerMock.ServeHTTP(r, request)
+	return r
+}
+
+func (suite *AdminControllerTestSuite) withToken(accessDetails authenticator.AccessDetails) func(request *http.Request) {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(accessDetails, nil)
+	suite.authMock.On("FetchAccessToken", accessDetails).Return(nil)
+	return func(request *http.Request) {
+		request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	}
+}
+
+func (suite *AdminControllerTestSuite) TestUnlock_Success() {
+	reqBody, _ := json.Marshal(dummyUnlockRequest)
+	suite.usecaseMock.On("UnlockLogin", dummyUnlockRequest).Return(nil)
+
+	r := suite.serve(reqBody, "Dummy Admin Key")
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *AdminControllerTestSuite) TestUnlock_FailedAdminKey() {
+	reqBody, _ := json.Marshal(dummyUnlockRequest)
+
+	r := suite.serve(reqBody, "Wrong Admin Key")
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "UnlockLogin", mock.Anything)
+}
+
+func (suite *AdminControllerTestSuite) TestUnlock_FailedBindJSON() {
+	r := suite.serve([]byte(`{1}`), "Dummy Admin Key")
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *AdminControllerTestSuite) TestUnlock_FailedUsecase() {
+	reqBody, _ := json.Marshal(dummyUnlockRequest)
+	suite.usecaseMock.On("UnlockLogin", dummyUnlockRequest).Return(errors.New("Failed"))
+
+	r := suite.serve(reqBody, "Dummy Admin Key")
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *AdminControllerTestSuite) TestUnlock_SuccessAdminToken() {
+	reqBody, _ := json.Marshal(dummyUnlockRequest)
+	suite.usecaseMock.On("UnlockLogin", dummyUnlockRequest).Return(nil)
+	adminDetails := authenticator.AccessDetails{AccessUuid: "Dummy Access Uuid", Username: "dummyAdmin", Role: authenticator.RoleAdmin}
+
+	r := suite.serveRequest(http.MethodPost, "/v1/admin/unlock", reqBody, suite.withToken(adminDetails))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *AdminControllerTestSuite) TestUnlock_FailedCustomerToken() {
+	reqBody, _ := json.Marshal(dummyUnlockRequest)
+	customerDetails := authenticator.AccessDetails{AccessUuid: "Dummy Access Uuid", Username: "dummyUsername", Role: authenticator.RoleCustomer}
+
+	r := suite.serveRequest(http.MethodPost, "/v1/admin/unlock", reqBody, suite.withToken(customerDetails))
+
+	assert.Equal(suite.T(), http.StatusForbidden, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "UnlockLogin", mock.Anything)
+}
+
+func (suite *AdminControllerTestSuite) TestSetRole_Success() {
+	request := req.RoleRequest{Username: "dummyUsername", Role: authenticator.RoleMerchant}
+	reqBody, _ := json.Marshal(request)
+	suite.usecaseMock.On("SetRole", request).Return(nil)
+
+	r := suite.serveRequest(http.MethodPut, "/v1/admin/roles", reqBody, func(request *http.Request) {
+		request.Header.Set(middleware.AdminKeyHeader, "Dummy Admin Key")
+	})
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *AdminControllerTestSuite) TestSetRole_FailedBindJSON() {
+	r := suite.serveRequest(http.MethodPut, "/v1/admin/roles", []byte(`{1}`), func(request *http.Request) {
+		request.Header.Set(middleware.AdminKeyHeader, "Dummy Admin Key")
+	})
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "SetRole", mock.Anything)
+}
+
+func (suite *AdminControllerTestSuite) TestReconciliation_Success() {
+	suite.usecaseMock.On("Reconcile").Return(res.LedgerReconciliation{
+		Balanced: false,
+		Mismatches: []res.LedgerMismatch{
+			{Account: "merchant:MRC125", Currency: "IDR"},
+		},
+	}, nil)
+
+	r := suite.serveRequest(http.MethodGet, "/v1/admin/ledger/reconciliation", nil, func(request *http.Request) {
+		request.Header.Set(middleware.AdminKeyHeader, "Dummy Admin Key")
+	})
+	var response struct {
+		Data res.LedgerReconciliation `json:"data"`
+	}
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.False(suite.T(), response.Data.Balanced)
+	assert.Equal(suite.T(), "merchant:MRC125", response.Data.Mismatches[0].Account)
+}
+
+func (suite *AdminControllerTestSuite) TestReconciliation_FailedAdminKey() {
+	r := suite.serveRequest(http.MethodGet, "/v1/admin/ledger/reconciliation", nil, func(request *http.Request) {
+		request.Header.Set(middleware.AdminKeyHeader, "Wrong Admin Key")
+	})
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Reconcile")
+}
+
+func (suite *AdminControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(adminUsecaseMock)
+	suite.authMock = new(authMock)
+}
+
+func TestAdminControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminControllerTestSuite))
+}
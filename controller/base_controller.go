@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,3 +16,21 @@ func (b *BaseController) Success(ctx *gin.Context, data any) {
 func (b *BaseController) Failed(ctx *gin.Context, err error) {
 	res.NewErrorJsonResponse(ctx, err).Send()
 }
+
+// AccessDetails verifies the bearer token of the request and writes the
+// error response when it is missing or invalid.
+func (b *BaseController) AccessDetails(ctx *gin.Context, accessToken authenticator.AccessToken) (authenticator.AccessDetails, bool) {
+	token, err := authenticator.BindAuthHeader(ctx)
+	if err != nil {
+		b.Failed(ctx, err)
+		ctx.Abort()
+		return authenticator.AccessDetails{}, false
+	}
+
+	accessDetails, err := accessToken.VerifyAccessToken(token)
+	if err != nil {
+		b.Failed(ctx, err)
+		return authenticator.AccessDetails{}, false
+	}
+	return accessDetails, true
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type FeeController struct {
+	BaseController
+	router     *gin.RouterGroup
+	feeUsecase usecase.FeeUsecase
+}
+
+func (f *FeeController) QuoteHandler(ctx *gin.Context) {
+	var query req.FeeQuoteQuery
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		f.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	quote, err := f.feeUsecase.Quote(query)
+
+	if err == nil {
+		f.Success(ctx, quote)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func (f *FeeController) SaveRuleHandler(ctx *gin.Context) {
+	var request req.FeeRuleRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		f.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	rule, err := f.feeUsecase.SaveRule(request)
+
+	if err == nil {
+		f.Success(ctx, rule)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func (f *FeeController) ListRulesHandler(ctx *gin.Context) {
+	rules, err := f.feeUsecase.ListRules()
+
+	if err == nil {
+		f.Success(ctx, rules)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func (f *FeeController) DisableRuleHandler(ctx *gin.Context) {
+	rule, err := f.feeUsecase.DisableRule(ctx.Param("rule_id"))
+
+	if err == nil {
+		f.Success(ctx, rule)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func (f *FeeController) SetCategoryHandler(ctx *gin.Context) {
+	var request req.MerchantCategoryRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		f.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+	request.MerchantCode = ctx.Param("merchant_code")
+
+	merchant, err := f.feeUsecase.SetCategory(request)
+
+	if err == nil {
+		f.Success(ctx, merchant)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func NewFeeController(r *gin.RouterGroup, u usecase.FeeUsecase, k middleware.AdminKeyMiddleware, m middleware.AuthTokenMiddleware) *FeeController {
+	controller := FeeController{
+		feeUsecase: u,
+	}
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
+	rm.GET("/fees/quote", controller.QuoteHandler)
+	ra := r.Group("/admin", k.IdentifyAdminKey(), m.RequireRole(authenticator.RoleAdmin))
+	ra.POST("/fees/rules", controller.SaveRuleHandler)
+	ra.GET("/fees/rules", controller.ListRulesHandler)
+	ra.DELETE("/fees/rules/:rule_id", controller.DisableRuleHandler)
+	ra.PUT("/merchants/:merchant_code/category", controller.SetCategoryHandler)
+	return &controller
+}
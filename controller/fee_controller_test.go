@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type feeUsecaseMock struct {
+	mock.Mock
+}
+
+func (f *feeUsecaseMock) SaveRule(request req.FeeRuleRequest) (entity.FeeRule, error) {
+	args := f.Called(request)
+	return args.Get(0).(entity.FeeRule), args.Error(1)
+}
+
+func (f *feeUsecaseMock) ListRules() ([]entity.FeeRule, error) {
+	args := f.Called()
+	return args.Get(0).([]entity.FeeRule), args.Error(1)
+}
+
+func (f *feeUsecaseMock) DisableRule(ruleId string) (entity.FeeRule, error) {
+	args := f.Called(ruleId)
+	return args.Get(0).(entity.FeeRule), args.Error(1)
+}
+
+func (f *feeUsecaseMock) SetCategory(request req.MerchantCategoryRequest) (entity.Merchant, error) {
+	args := f.Called(request)
+	return args.Get(0).(entity.Merchant), args.Error(1)
+}
+
+func (f *feeUsecaseMock) Quote(query req.FeeQuoteQuery) (res.FeeQuote, error) {
+	args := f.Called(query)
+	return args.Get(0).(res.FeeQuote), args.Error(1)
+}
+
+type FeeControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *feeUsecaseMock
+	authMock        *authMock
+}
+
+func (suite *FeeControllerTestSuite) serve(method string, path string, body []byte, prepare func(request *http.Request)) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewFeeController(suite.routerGroupMock, suite.usecaseMock, middleware.NewAdminKeyMiddleware("Dummy Admin Key"), middleware.NewAuthTokenMiddleware(suite.authMock))
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	prepare(request)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *FeeControllerTestSuite) withToken() func(request *http.Request) {
+	accessDetails := dummyAccessDetails[0]
+	accessDetails.Scopes = []string{authenticator.ScopePaymentsWrite}
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(accessDetails, nil)
+	suite.authMock.On("FetchAccessToken", accessDetails).Return(nil)
+	return func(request *http.Request) {
+		request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	}
+}
+
+func withAdminKey(adminKey string) func(request *http.Request) {
+	return func(request *http.Request) {
+		request.Header.Set(middleware.AdminKeyHeader, adminKey)
+	}
+}
+
+func (suite *FeeControllerTestSuite) TestQuote_Success() {
+	suite.usecaseMock.On("Quote", req.FeeQuoteQuery{MerchantCode: "MRC125", Amount: "20000"}).
+		Return(res.FeeQuote{MerchantCode: "MRC125", Fee: money.MustParse("500"), TotalDebit: money.MustParse("20500")}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/menu/fees/quote?merchant_code=MRC125&amount=20000", nil, suite.withToken())
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), 500.0, response.Data.(map[string]interface{})["fee"])
+	assert.Equal(suite.T(), 20500.0, response.Data.(map[string]interface{})["total_debit"])
+}
+
+func (suite *FeeControllerTestSuite) TestQuote_WithCurrency() {
+	suite.usecaseMock.On("Quote", req.FeeQuoteQuery{MerchantCode: "MRC125", Amount: "10", Currency: "USD"}).
+		Return(res.FeeQuote{MerchantCode: "MRC125", Currency: "USD", SettlementCurrency: "IDR", FxRate: "15000"}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/menu/fees/quote?merchant_code=MRC125&amount=10&currency=USD", nil, suite.withToken())
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "USD", response.Data.(map[string]interface{})["currency"])
+	assert.Equal(suite.T(), "IDR", response.Data.(map[string]interface{})["settlement_currency"])
+	assert.Equal(suite.T(), "15000", response.Data.(map[string]interface{})["fx_rate"])
+}
+
+func (suite *FeeControllerTestSuite) TestQuote_FailedScope() {
+	accessDetails := dummyAccessDetails[0]
+	accessDetails.Scopes = []string{authenticator.ScopeHistoryRead}
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(accessDetails, nil)
+	suite.authMock.On("FetchAccessToken", accessDetails).Return(nil)
+
+	r, _ := suite.serve(http.MethodGet, "/v1/menu/fees/quote?merchant_code=MRC125&amount=20000", nil, func(request *http.Request) {
+		request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	})
+
+	assert.Equal(suite.T(), http.StatusForbidden, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Quote", mock.Anything)
+}
+
+func (suite *FeeControllerTestSuite) TestSaveRule_Success() {
+	suite.usecaseMock.On("SaveRule", req.FeeRuleRequest{
+		Category:      "food",
+		Type:          entity.FeeTypePercentage,
+		PercentageBps: 150,
+		MaxFee:        money.MustParse("5000"),
+		ChargedTo:     entity.FeeChargedToMerchant,
+	}).Return(entity.FeeRule{RuleId: "Dummy Rule Id"}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/admin/fees/rules",
+		[]byte(`{"category":"food","type":"percentage","percentage_bps":150,"max_fee":"5000","charged_to":"merchant"}`), withAdminKey("Dummy Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "Dummy Rule Id", response.Data.(map[string]interface{})["rule_id"])
+}
+
+func (suite *FeeControllerTestSuite) TestSaveRule_FailedAdminKey() {
+	r, _ := suite.serve(http.MethodPost, "/v1/admin/fees/rules", []byte(`{"category":"food"}`), withAdminKey("Wrong Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "SaveRule", mock.Anything)
+}
+
+func (suite *FeeControllerTestSuite) TestListRules_Success() {
+	suite.usecaseMock.On("ListRules").Return([]entity.FeeRule{{RuleId: "Dummy Rule Id"}}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/admin/fees/rules", nil, withAdminKey("Dummy Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *FeeControllerTestSuite) TestDisableRule_FailedUsecase() {
+	suite.usecaseMock.On("DisableRule", "Dummy Rule Id").Return(entity.FeeRule{}, app_error.DataNotFound("fee rule not found"))
+
+	r, _ := suite.serve(http.MethodDelete, "/v1/admin/fees/rules/Dummy%20Rule%20Id", nil, withAdminKey("Dummy Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusNotFound, r.Code)
+}
+
+func (suite *FeeControllerTestSuite) TestSetCategory_Success() {
+	suite.usecaseMock.On("SetCategory", req.MerchantCategoryRequest{MerchantCode: "MRC125", Category: "food"}).
+		Return(entity.Merchant{MerchantCode: "MRC125", Category: "food"}, nil)
+
+	r, response := suite.serve(http.MethodPut, "/v1/admin/merchants/MRC125/category", []byte(`{"category":"food"}`), withAdminKey("Dummy Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "food", response.Data.(map[string]interface{})["category"])
+}
+
+func (suite *FeeControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(feeUsecaseMock)
+	suite.authMock = new(authMock)
+}
+
+func TestFeeControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(FeeControllerTestSuite))
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type FxController struct {
+	BaseController
+	router    *gin.RouterGroup
+	fxUsecase usecase.FxUsecase
+}
+
+func (f *FxController) SaveRateHandler(ctx *gin.Context) {
+	var request req.FxRateRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		f.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	rate, err := f.fxUsecase.SaveRate(request)
+
+	if err == nil {
+		f.Success(ctx, rate)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func (f *FxController) ListRatesHandler(ctx *gin.Context) {
+	rates, err := f.fxUsecase.ListRates()
+
+	if err == nil {
+		f.Success(ctx, rates)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func (f *FxController) SettlementCurrencyHandler(ctx *gin.Context) {
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		f.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return
+	}
+
+	var request req.SettlementCurrencyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		f.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+	request.MerchantCode = merchant.MerchantCode
+
+	updated, err := f.fxUsecase.SetSettlementCurrency(request)
+
+	if err == nil {
+		f.Success(ctx, updated)
+	} else {
+		f.Failed(ctx, err)
+	}
+}
+
+func NewFxController(r *gin.RouterGroup, u usecase.FxUsecase, k middleware.AdminKeyMiddleware, m middleware.AuthTokenMiddleware, s middleware.MerchantSignatureMiddleware) *FxController {
+	controller := FxController{
+		fxUsecase: u,
+	}
+	ra := r.Group("/admin", k.IdentifyAdminKey(), m.RequireRole(authenticator.RoleAdmin))
+	ra.PUT("/fx/rates", controller.SaveRateHandler)
+	ra.GET("/fx/rates", controller.ListRatesHandler)
+	rs := r.Group("/merchant", s.RequireSignature())
+	rs.PUT("/settlement-currency", controller.SettlementCurrencyHandler)
+	return &controller
+}
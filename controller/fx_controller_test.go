@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type fxUsecaseMock struct {
+	mock.Mock
+}
+
+func (f *fxUsecaseMock) SaveRate(request req.FxRateRequest) (entity.FxRate, error) {
+	args := f.Called(request)
+	return args.Get(0).(entity.FxRate), args.Error(1)
+}
+
+func (f *fxUsecaseMock) ListRates() ([]entity.FxRate, error) {
+	args := f.Called()
+	return args.Get(0).([]entity.FxRate), args.Error(1)
+}
+
+func (f *fxUsecaseMock) LoadFile(fileName string) (int, error) {
+	args := f.Called(fileName)
+	return args.Int(0), args.Error(1)
+}
+
+func (f *fxUsecaseMock) SetSettlementCurrency(request req.SettlementCurrencyRequest) (entity.Merchant, error) {
+	args := f.Called(request)
+	return args.Get(0).(entity.Merchant), args.Error(1)
+}
+
+type FxControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *fxUsecaseMock
+	authMock        *authMock
+	signatureMock   *merchantSignatureMock
+}
+
+func (suite *FxControllerTestSuite) serve(method string, path string, body []byte, prepare func(request *http.Request)) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewFxController(suite.routerGroupMock, suite.usecaseMock, middleware.NewAdminKeyMiddleware("Dummy Admin Key"), middleware.NewAuthTokenMiddleware(suite.authMock), suite.signatureMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	prepare(request)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *FxControllerTestSuite) TestSaveRate_Success() {
+	suite.usecaseMock.On("SaveRate", req.FxRateRequest{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15650.25", SpreadBps: 50}).
+		Return(entity.FxRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15650.25", SpreadBps: 50, Source: entity.FxRateSourceAdmin}, nil)
+
+	r, response := suite.serve(http.MethodPut, "/v1/admin/fx/rates",
+		[]byte(`{"base_currency":"USD","quote_currency":"IDR","rate":"15650.25","spread_bps":50}`), withAdminKey("Dummy Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "15650.25", response.Data.(map[string]interface{})["rate"])
+	assert.Equal(suite.T(), entity.FxRateSourceAdmin, response.Data.(map[string]interface{})["source"])
+}
+
+func (suite *FxControllerTestSuite) TestSaveRate_FailedAdminKey() {
+	r, _ := suite.serve(http.MethodPut, "/v1/admin/fx/rates", []byte(`{"base_currency":"USD"}`), withAdminKey("Wrong Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "SaveRate", mock.Anything)
+}
+
+func (suite *FxControllerTestSuite) TestListRates_Success() {
+	suite.usecaseMock.On("ListRates").Return([]entity.FxRate{{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15650.25"}}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/admin/fx/rates", nil, withAdminKey("Dummy Admin Key"))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *FxControllerTestSuite) TestSettlementCurrency_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("SetSettlementCurrency", req.SettlementCurrencyRequest{MerchantCode: "MRC125", Currency: "USD"}).
+		Return(entity.Merchant{MerchantCode: "MRC125", Currency: "USD"}, nil)
+
+	r, response := suite.serve(http.MethodPut, "/v1/merchant/settlement-currency", []byte(`{"currency":"USD"}`), func(*http.Request) {})
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "USD", response.Data.(map[string]interface{})["currency"])
+}
+
+func (suite *FxControllerTestSuite) TestSettlementCurrency_FailedUsecase() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("SetSettlementCurrency", mock.Anything).
+		Return(entity.Merchant{}, app_error.Conflict("Settlement currency can only be changed with a zero balance"))
+
+	r, _ := suite.serve(http.MethodPut, "/v1/merchant/settlement-currency", []byte(`{"currency":"USD"}`), func(*http.Request) {})
+
+	assert.Equal(suite.T(), http.StatusConflict, r.Code)
+}
+
+func (suite *FxControllerTestSuite) TestSettlementCurrency_FailedMissingMerchant() {
+	r, _ := suite.serve(http.MethodPut, "/v1/merchant/settlement-currency", []byte(`{"currency":"USD"}`), func(*http.Request) {})
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "SetSettlementCurrency", mock.Anything)
+}
+
+func (suite *FxControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(fxUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.signatureMock = new(merchantSignatureMock)
+}
+
+func TestFxControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(FxControllerTestSuite))
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type HistoryController struct {
+	historyUsecase usecase.HistoryUsecase
+	authenticator  authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (h *HistoryController) HistoryHandler(ctx *gin.Context) {
+	var query req.HistoryQuery
+
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		h.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	accountDetails, ok := h.AccessDetails(ctx, h.authenticator)
+	if !ok {
+		return
+	}
+
+	query.CustomerUsername = accountDetails.Username
+
+	page, err := h.historyUsecase.FindHistories(query)
+
+	if err == nil {
+		h.Success(ctx, page)
+	} else {
+		h.Failed(ctx, err)
+	}
+}
+
+func NewHistoryController(r *gin.RouterGroup, u usecase.HistoryUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *HistoryController {
+	controller := HistoryController{
+		historyUsecase: u,
+		authenticator:  a,
+	}
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopeHistoryRead))
+	rm.GET("/history", controller.HistoryHandler)
+	return &controller
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyHistoryPage = res.HistoryPage{
+	Items: []entity.History{
+		{
+			TransactionId:    "Dummy Transaction Id",
+			CustomerUsername: "Dummy Username",
+			MerchantCode:     "Dummy Merchant Code",
+			Amount:           money.MustParse("20000.00"),
+		},
+	},
+}
+
+type historyUsecaseMock struct {
+	mock.Mock
+}
+
+func (h *historyUsecaseMock) FindHistories(query req.HistoryQuery) (res.HistoryPage, error) {
+	args := h.Called(query)
+	return args.Get(0).(res.HistoryPage), args.Error(1)
+}
+
+type HistoryControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *historyUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+}
+
+func (suite *HistoryControllerTestSuite) TestFindHistories_Success() {
+	NewHistoryController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/v1/menu/history?limit=10&sort=asc&merchant_code=MRC125", nil)
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("FindHistories", req.HistoryQuery{
+		CustomerUsername: dummyAccessDetails[0].Username,
+		Limit:            10,
+		Sort:             "asc",
+		MerchantCode:     "MRC125",
+	}).Return(dummyHistoryPage, nil)
+
+	suite.routerMock.ServeHTTP(r, request)
+
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.NotNil(suite.T(), response.Data)
+}
+
+func (suite *HistoryControllerTestSuite) TestFindHistories_FailedBindQuery() {
+	NewHistoryController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/v1/menu/history?limit=abc", nil)
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+
+	suite.routerMock.ServeHTTP(r, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *HistoryControllerTestSuite) TestFindHistories_FailedBindAuthHeader() {
+	NewHistoryController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/v1/menu/history", nil)
+	request.Header.Set("Authorization", "Bearer")
+
+	suite.routerMock.ServeHTTP(r, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *HistoryControllerTestSuite) TestFindHistories_FailedVerifyAccessToken() {
+	NewHistoryController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/v1/menu/history", nil)
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	suite.routerMock.ServeHTTP(r, request)
+
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *HistoryControllerTestSuite) TestFindHistories_FailedUsecase() {
+	NewHistoryController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/v1/menu/history", nil)
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("FindHistories", req.HistoryQuery{
+		CustomerUsername: dummyAccessDetails[0].Username,
+	}).Return(res.HistoryPage{}, errors.New("Failed"))
+
+	suite.routerMock.ServeHTTP(r, request)
+
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *HistoryControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(historyUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+}
+
+func TestHistoryControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(HistoryControllerTestSuite))
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type HoldController struct {
+	holdUsecase   usecase.HoldUsecase
+	authenticator authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (h *HoldController) merchantCode(ctx *gin.Context) (string, bool) {
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		h.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return "", false
+	}
+	return merchant.MerchantCode, true
+}
+
+func (h *HoldController) AuthorizeHandler(ctx *gin.Context) {
+	var request req.HoldRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		h.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	var authorization req.PaymentAuthorization
+	if err := ctx.ShouldBindHeader(&authorization); err != nil {
+		h.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+	accessDetails, ok := h.AccessDetails(ctx, h.authenticator)
+	if !ok {
+		return
+	}
+	request.CustomerUsername = accessDetails.Username
+
+	hold, err := h.holdUsecase.Authorize(request, authorization)
+
+	if err == nil {
+		h.Success(ctx, hold)
+	} else {
+		h.Failed(ctx, err)
+	}
+}
+
+func (h *HoldController) ListHandler(ctx *gin.Context) {
+	accessDetails, ok := h.AccessDetails(ctx, h.authenticator)
+	if !ok {
+		return
+	}
+
+	holds, err := h.holdUsecase.ListForCustomer(accessDetails.Username)
+
+	if err == nil {
+		h.Success(ctx, holds)
+	} else {
+		h.Failed(ctx, err)
+	}
+}
+
+func (h *HoldController) BalanceHandler(ctx *gin.Context) {
+	accessDetails, ok := h.AccessDetails(ctx, h.authenticator)
+	if !ok {
+		return
+	}
+
+	balance, err := h.holdUsecase.Balance(accessDetails.Username)
+
+	if err == nil {
+		h.Success(ctx, balance)
+	} else {
+		h.Failed(ctx, err)
+	}
+}
+
+func (h *HoldController) MerchantListHandler(ctx *gin.Context) {
+	merchantCode, ok := h.merchantCode(ctx)
+	if !ok {
+		return
+	}
+
+	holds, err := h.holdUsecase.ListForMerchant(merchantCode)
+
+	if err == nil {
+		h.Success(ctx, holds)
+	} else {
+		h.Failed(ctx, err)
+	}
+}
+
+func (h *HoldController) CaptureHandler(ctx *gin.Context) {
+	var request req.CaptureRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		h.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	merchantCode, ok := h.merchantCode(ctx)
+	if !ok {
+		return
+	}
+	request.MerchantCode = merchantCode
+	request.HoldId = ctx.Param("hold_id")
+
+	hold, err := h.holdUsecase.Capture(request)
+
+	if err == nil {
+		h.Success(ctx, hold)
+	} else {
+		h.Failed(ctx, err)
+	}
+}
+
+func (h *HoldController) VoidHandler(ctx *gin.Context) {
+	merchantCode, ok := h.merchantCode(ctx)
+	if !ok {
+		return
+	}
+
+	hold, err := h.holdUsecase.Void(merchantCode, ctx.Param("hold_id"))
+
+	if err == nil {
+		h.Success(ctx, hold)
+	} else {
+		h.Failed(ctx, err)
+	}
+}
+
+func NewHoldController(r *gin.RouterGroup, u usecase.HoldUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware, s middleware.MerchantSignatureMiddleware) *HoldController {
+	controller := HoldController{
+		holdUsecase:   u,
+		authenticator: a,
+	}
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
+	rm.POST("/holds", controller.AuthorizeHandler)
+	rm.GET("/holds", controller.ListHandler)
+	rb := r.Group("/menu", m.RequireScope(authenticator.ScopeHistoryRead))
+	rb.GET("/balance", controller.BalanceHandler)
+	rs := r.Group("/merchant", s.RequireSignature())
+	rs.GET("/holds", controller.MerchantListHandler)
+	rs.POST("/holds/:hold_id/capture", controller.CaptureHandler)
+	rs.POST("/holds/:hold_id/void", controller.VoidHandler)
+	return &controller
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyHold = entity.Hold{
+	HoldId:           "hold-1",
+	CustomerUsername: "dummyUsername1",
+	MerchantCode:     "MRC125",
+	Amount:           money.MustParse("10000"),
+	Currency:         money.DefaultCurrency,
+	Status:           entity.HoldStatusAuthorized,
+}
+
+type holdUsecaseMock struct {
+	mock.Mock
+}
+
+func (h *holdUsecaseMock) Authorize(request req.HoldRequest, authorization req.PaymentAuthorization) (entity.Hold, error) {
+	args := h.Called(request, authorization)
+	return args.Get(0).(entity.Hold), args.Error(1)
+}
+
+func (h *holdUsecaseMock) Capture(request req.CaptureRequest) (entity.Hold, error) {
+	args := h.Called(request)
+	return args.Get(0).(entity.Hold), args.Error(1)
+}
+
+func (h *holdUsecaseMock) Void(merchantCode string, holdId string) (entity.Hold, error) {
+	args := h.Called(merchantCode, holdId)
+	return args.Get(0).(entity.Hold), args.Error(1)
+}
+
+func (h *holdUsecaseMock) ListForCustomer(customerUsername string) ([]entity.Hold, error) {
+	args := h.Called(customerUsername)
+	return args.Get(0).([]entity.Hold), args.Error(1)
+}
+
+func (h *holdUsecaseMock) ListForMerchant(merchantCode string) ([]entity.Hold, error) {
+	args := h.Called(merchantCode)
+	return args.Get(0).([]entity.Hold), args.Error(1)
+}
+
+func (h *holdUsecaseMock) Balance(customerUsername string) (res.Balance, error) {
+	args := h.Called(customerUsername)
+	return args.Get(0).(res.Balance), args.Error(1)
+}
+
+func (h *holdUsecaseMock) ExpireStale() (int, error) {
+	args := h.Called()
+	return args.Int(0), args.Error(1)
+}
+
+type HoldControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *holdUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+	signatureMock   *merchantSignatureMock
+}
+
+func (suite *HoldControllerTestSuite) serve(method string, path string, body []byte, headers map[string]string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewHoldController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock, suite.signatureMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *HoldControllerTestSuite) TestAuthorize_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Authorize", req.HoldRequest{
+		CustomerUsername: dummyAccessDetails[0].Username,
+		MerchantCode:     "MRC125",
+		Amount:           money.MustParse("10000"),
+	}, req.PaymentAuthorization{Pin: "123456"}).Return(dummyHold, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/menu/holds", []byte(`{"merchant_code":"MRC125","amount":"10000"}`), map[string]string{"X-Transaction-Pin": "123456"})
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), dummyHold.HoldId, response.Data.(map[string]interface{})["hold_id"])
+}
+
+func (suite *HoldControllerTestSuite) TestAuthorize_FailedBindJSON() {
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/holds", []byte(`{1}`), nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Authorize", mock.Anything, mock.Anything)
+}
+
+func (suite *HoldControllerTestSuite) TestBalance_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Balance", dummyAccessDetails[0].Username).Return(res.Balance{
+		Currency:  money.DefaultCurrency,
+		Available: money.MustParse("40"),
+		Held:      money.MustParse("60"),
+		Total:     money.MustParse("100"),
+	}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/menu/balance", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	data := response.Data.(map[string]interface{})
+	assert.NotNil(suite.T(), data["available"])
+	assert.NotNil(suite.T(), data["held"])
+}
+
+func (suite *HoldControllerTestSuite) TestBalance_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	r, _ := suite.serve(http.MethodGet, "/v1/menu/balance", nil, nil)
+
+	assert.NotEqual(suite.T(), http.StatusOK, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Balance", mock.Anything)
+}
+
+func (suite *HoldControllerTestSuite) TestCapture_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	captured := dummyHold
+	captured.Status = entity.HoldStatusCaptured
+	suite.usecaseMock.On("Capture", req.CaptureRequest{MerchantCode: "MRC125", HoldId: "hold-1", Amount: money.MustParse("7500")}).Return(captured, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/merchant/holds/hold-1/capture", []byte(`{"amount":"7500"}`), nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), entity.HoldStatusCaptured, response.Data.(map[string]interface{})["status"])
+}
+
+func (suite *HoldControllerTestSuite) TestCapture_FailedMissingMerchant() {
+	r, _ := suite.serve(http.MethodPost, "/v1/merchant/holds/hold-1/capture", []byte(`{"amount":"7500"}`), nil)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Capture", mock.Anything)
+}
+
+func (suite *HoldControllerTestSuite) TestVoid_FailedUsecase() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("Void", "MRC125", "hold-1").Return(entity.Hold{}, app_error.DataNotFound("hold not found"))
+
+	r, _ := suite.serve(http.MethodPost, "/v1/merchant/holds/hold-1/void", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, r.Code)
+}
+
+func (suite *HoldControllerTestSuite) TestMerchantList_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("ListForMerchant", "MRC125").Return([]entity.Hold{dummyHold}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/merchant/holds", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *HoldControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(holdUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+	suite.signatureMock = new(merchantSignatureMock)
+}
+
+func TestHoldControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(HoldControllerTestSuite))
+}
@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type JwksController struct {
+	keys   authenticator.KeySet
+	router *gin.RouterGroup
+}
+
+func (j *JwksController) JwksHandler(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, j.keys.Jwks())
+}
+
+func NewJwksController(r *gin.RouterGroup, k authenticator.KeySet) *JwksController {
+	controller := JwksController{
+		keys:   k,
+		router: r,
+	}
+	r.GET("/.well-known/jwks.json", controller.JwksHandler)
+	return &controller
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type keySetMock struct {
+	mock.Mock
+}
+
+func (k *keySetMock) Sign(claims jwt.Claims) (string, error) {
+	args := k.Called(claims)
+	return args.String(0), args.Error(1)
+}
+
+func (k *keySetMock) Verify(token *jwt.Token) (interface{}, error) {
+	args := k.Called(token)
+	return args.Get(0), args.Error(1)
+}
+
+func (k *keySetMock) Jwks() authenticator.JsonWebKeySet {
+	args := k.Called()
+	return args.Get(0).(authenticator.JsonWebKeySet)
+}
+
+type JwksControllerTestSuite struct {
+	suite.Suite
+	routerMock *gin.Engine
+	keySetMock *keySetMock
+}
+
+func (suite *JwksControllerTestSuite) TestJwks_Success() {
+	jwks := authenticator.JsonWebKeySet{Keys: []authenticator.JsonWebKey{
+		{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "Dummy Kid", Crv: "Ed25519", X: "Dummy X"},
+	}}
+	suite.keySetMock.On("Jwks").Return(jwks)
+	NewJwksController(&suite.routerMock.RouterGroup, suite.keySetMock)
+
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	suite.routerMock.ServeHTTP(r, request)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	var response authenticator.JsonWebKeySet
+	suite.Require().NoError(json.Unmarshal(r.Body.Bytes(), &response))
+	assert.Equal(suite.T(), jwks, response)
+}
+
+func (suite *JwksControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.keySetMock = new(keySetMock)
+}
+
+func TestJwksControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(JwksControllerTestSuite))
+}
@@ -2,8 +2,9 @@ package controller
 
 import (
 	"github.com/febriansr/simple-payment-api/model/app_error"
-	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
 	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,28 +15,51 @@ type LoginController struct {
 }
 
 func (l *LoginController) LoginHandler(ctx *gin.Context) {
-	var customer entity.Customer
+	var request req.LoginRequest
 
-	if err := ctx.ShouldBindJSON(&customer); err != nil {
+	if err := ctx.ShouldBindJSON(&request); err != nil {
 		l.Failed(ctx, app_error.InvalidError("invalid request body"))
 		return
 	}
 
-	token, err := l.loginUsecase.Login(customer)
+	loginResponse, err := l.loginUsecase.Login(request, clientInfo(ctx))
 
 	if err == nil {
-		l.Success(ctx, map[string]interface{}{
-			"token": token,
-		})
+		l.Success(ctx, loginResponse)
 	} else {
 		l.Failed(ctx, err)
 	}
 }
 
+func (l *LoginController) VerifyHandler(ctx *gin.Context) {
+	var request req.LoginVerifyRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		l.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	tokenPair, err := l.loginUsecase.VerifyLogin(request, clientInfo(ctx))
+
+	if err == nil {
+		l.Success(ctx, tokenPair)
+	} else {
+		l.Failed(ctx, err)
+	}
+}
+
+func clientInfo(ctx *gin.Context) authenticator.ClientInfo {
+	return authenticator.ClientInfo{
+		UserAgent: ctx.Request.UserAgent(),
+		Ip:        ctx.ClientIP(),
+	}
+}
+
 func NewLoginController(r *gin.RouterGroup, u usecase.LoginUsecase) *LoginController {
 	controller := LoginController{
 		loginUsecase: u,
 	}
 	r.POST("/login", controller.LoginHandler)
+	r.POST("/login/verify", controller.VerifyHandler)
 	return &controller
 }
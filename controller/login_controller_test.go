@@ -8,8 +8,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/febriansr/simple-payment-api/model/dto/req"
 	"github.com/febriansr/simple-payment-api/model/dto/res"
-	entity "github.com/febriansr/simple-payment-api/model/entity"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -17,11 +17,9 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
-var dummyCustomer = []entity.Customer{
-	{
-		Username: "dummyUsername",
-		Password: "dummyPassword",
-	},
+var dummyLoginRequest = req.LoginRequest{
+	Username: "dummyUsername",
+	Password: "dummyPassword",
 }
 
 var dummyTokenDetails = []authenticator.TokenDetails{
@@ -36,12 +34,17 @@ type LoginUsecaseMock struct {
 	mock.Mock
 }
 
-func (l *LoginUsecaseMock) Login(customer entity.Customer) (token string, err error) {
-	args := l.Called(customer)
+func (l *LoginUsecaseMock) Login(request req.LoginRequest, client authenticator.ClientInfo) (res.LoginResponse, error) {
+	args := l.Called(request, client)
 	if args.Get(0) == nil {
-		return "", errors.New("Failed")
+		return res.LoginResponse{}, errors.New("Failed")
 	}
-	return args.Get(0).(string), args.Error(1)
+	return args.Get(0).(res.LoginResponse), args.Error(1)
+}
+
+func (l *LoginUsecaseMock) VerifyLogin(request req.LoginVerifyRequest, client authenticator.ClientInfo) (res.TokenPair, error) {
+	args := l.Called(request, client)
+	return args.Get(0).(res.TokenPair), args.Error(1)
 }
 
 type LoginControllerTestSuite struct {
@@ -52,23 +55,41 @@ type LoginControllerTestSuite struct {
 }
 
 func (suite *LoginControllerTestSuite) TestLogin_Success() {
-	customer := dummyCustomer[0]
+	customer := dummyLoginRequest
 	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
 	r := httptest.NewRecorder()
 	reqBody, _ := json.Marshal(customer)
 	request, _ := http.NewRequest(http.MethodPost, "/v1/login", bytes.NewBuffer(reqBody))
 
-	suite.usecaseMock.On("Login", customer).Return(dummyTokenDetails[0].AccessToken, nil)
+	tokenPair := res.NewTokenPair(dummyTokenDetails[0])
+	suite.usecaseMock.On("Login", customer, mock.Anything).Return(res.LoginResponse{TokenPair: &tokenPair}, nil)
 	suite.routerMock.ServeHTTP(r, request)
 	var response res.ApiResponse
 	json.Unmarshal(r.Body.Bytes(), &response)
 
 	assert.Equal(suite.T(), http.StatusOK, r.Code)
 	assert.Equal(suite.T(), response.Data, map[string]interface{}{
-		"token": dummyTokenDetails[0].AccessToken,
+		"token":              dummyTokenDetails[0].AccessToken,
+		"expires_at":         float64(dummyTokenDetails[0].AtExpires),
+		"refresh_token":      dummyTokenDetails[0].RefreshToken,
+		"refresh_expires_at": float64(dummyTokenDetails[0].RtExpires),
 	})
 }
 
+func (suite *LoginControllerTestSuite) TestLogin_IgnoresRoleInBody() {
+	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
+	r := httptest.NewRecorder()
+	reqBody := []byte(`{"username":"dummyUsername","password":"dummyPassword","role":"admin"}`)
+	request, _ := http.NewRequest(http.MethodPost, "/v1/login", bytes.NewBuffer(reqBody))
+
+	tokenPair := res.NewTokenPair(dummyTokenDetails[0])
+	suite.usecaseMock.On("Login", dummyLoginRequest, mock.Anything).Return(res.LoginResponse{TokenPair: &tokenPair}, nil)
+	suite.routerMock.ServeHTTP(r, request)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	suite.usecaseMock.AssertCalled(suite.T(), "Login", dummyLoginRequest, mock.Anything)
+}
+
 func (suite *LoginControllerTestSuite) TestLogin_FailedBindJSON() {
 	invalidReqBody := []byte(`{1}`)
 	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
@@ -83,13 +104,78 @@ func (suite *LoginControllerTestSuite) TestLogin_FailedBindJSON() {
 }
 
 func (suite *LoginControllerTestSuite) TestLogin_FailedErrorUsecase() {
-	customer := dummyCustomer[0]
+	customer := dummyLoginRequest
+	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
+	r := httptest.NewRecorder()
+	reqBody, _ := json.Marshal(customer)
+	request, _ := http.NewRequest(http.MethodPost, "/v1/login", bytes.NewBuffer(reqBody))
+
+	suite.usecaseMock.On("Login", customer, mock.Anything).Return(res.LoginResponse{}, errors.New("Failed"))
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *LoginControllerTestSuite) TestLogin_TwoFactorRequired() {
+	customer := dummyLoginRequest
 	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
 	r := httptest.NewRecorder()
 	reqBody, _ := json.Marshal(customer)
 	request, _ := http.NewRequest(http.MethodPost, "/v1/login", bytes.NewBuffer(reqBody))
 
-	suite.usecaseMock.On("Login", customer).Return("", errors.New("Failed"))
+	suite.usecaseMock.On("Login", customer, mock.Anything).Return(res.LoginResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     "Dummy Challenge Token",
+		ChallengeExpiresAt: 300,
+	}, nil)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), response.Data, map[string]interface{}{
+		"two_factor_required":  true,
+		"challenge_token":      "Dummy Challenge Token",
+		"challenge_expires_at": float64(300),
+	})
+}
+
+func (suite *LoginControllerTestSuite) TestVerify_Success() {
+	verifyRequest := req.LoginVerifyRequest{ChallengeToken: "Dummy Challenge Token", Code: "123456"}
+	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
+	r := httptest.NewRecorder()
+	reqBody, _ := json.Marshal(verifyRequest)
+	request, _ := http.NewRequest(http.MethodPost, "/v1/login/verify", bytes.NewBuffer(reqBody))
+
+	suite.usecaseMock.On("VerifyLogin", verifyRequest, mock.Anything).Return(res.NewTokenPair(dummyTokenDetails[0]), nil)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), dummyTokenDetails[0].AccessToken, response.Data.(map[string]interface{})["token"])
+}
+
+func (suite *LoginControllerTestSuite) TestVerify_FailedBindJSON() {
+	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/v1/login/verify", bytes.NewBuffer([]byte(`{1}`)))
+
+	suite.routerMock.ServeHTTP(r, request)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *LoginControllerTestSuite) TestVerify_FailedUsecase() {
+	verifyRequest := req.LoginVerifyRequest{ChallengeToken: "Dummy Challenge Token", Code: "123456"}
+	NewLoginController(suite.routerGroupMock, suite.usecaseMock)
+	r := httptest.NewRecorder()
+	reqBody, _ := json.Marshal(verifyRequest)
+	request, _ := http.NewRequest(http.MethodPost, "/v1/login/verify", bytes.NewBuffer(reqBody))
+
+	suite.usecaseMock.On("VerifyLogin", verifyRequest, mock.Anything).Return(res.TokenPair{}, errors.New("Failed"))
 	suite.routerMock.ServeHTTP(r, request)
 	var response res.ApiResponse
 	json.Unmarshal(r.Body.Bytes(), &response)
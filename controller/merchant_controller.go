@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/gin-gonic/gin"
+)
+
+type MerchantController struct {
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (m *MerchantController) ProfileHandler(ctx *gin.Context) {
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		m.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return
+	}
+	m.Success(ctx, merchant)
+}
+
+func NewMerchantController(r *gin.RouterGroup, s middleware.MerchantSignatureMiddleware) *MerchantController {
+	controller := MerchantController{
+		router: r,
+	}
+	rm := r.Group("/merchant", s.RequireSignature())
+	rm.GET("/profile", controller.ProfileHandler)
+	return &controller
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type merchantSignatureMock struct {
+	merchant *entity.Merchant
+}
+
+func (m *merchantSignatureMock) RequireSignature() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if m.merchant != nil {
+			ctx.Set(middleware.MerchantKey, *m.merchant)
+		}
+	}
+}
+
+type MerchantControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	signatureMock   *merchantSignatureMock
+}
+
+func (suite *MerchantControllerTestSuite) serve() (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewMerchantController(suite.routerGroupMock, suite.signatureMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/v1/merchant/profile", nil)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *MerchantControllerTestSuite) TestProfile_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125", Name: "Dummy Merchant"}
+
+	r, response := suite.serve()
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "MRC125", response.Data.(map[string]interface{})["merchant_code"])
+}
+
+func (suite *MerchantControllerTestSuite) TestProfile_FailedMissingMerchant() {
+	r, _ := suite.serve()
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+}
+
+func (suite *MerchantControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.signatureMock = new(merchantSignatureMock)
+}
+
+func TestMerchantControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantControllerTestSuite))
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type MerchantKeyController struct {
+	BaseController
+	router             *gin.RouterGroup
+	merchantKeyUsecase usecase.MerchantKeyUsecase
+}
+
+func (m *MerchantKeyController) IssueHandler(ctx *gin.Context) {
+	var request req.ApiKeyRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		m.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	request.MerchantCode = ctx.Param("merchant_code")
+
+	apiKey, err := m.merchantKeyUsecase.Issue(request)
+
+	if err == nil {
+		m.Success(ctx, apiKey)
+	} else {
+		m.Failed(ctx, err)
+	}
+}
+
+func (m *MerchantKeyController) ListHandler(ctx *gin.Context) {
+	apiKeys, err := m.merchantKeyUsecase.List(ctx.Param("merchant_code"))
+
+	if err == nil {
+		m.Success(ctx, apiKeys)
+	} else {
+		m.Failed(ctx, err)
+	}
+}
+
+func (m *MerchantKeyController) RevokeHandler(ctx *gin.Context) {
+	err := m.merchantKeyUsecase.Revoke(ctx.Param("merchant_code"), ctx.Param("key_id"))
+
+	if err == nil {
+		m.Success(ctx, nil)
+	} else {
+		m.Failed(ctx, err)
+	}
+}
+
+func NewMerchantKeyController(r *gin.RouterGroup, u usecase.MerchantKeyUsecase, k middleware.AdminKeyMiddleware, m middleware.AuthTokenMiddleware) *MerchantKeyController {
+	controller := MerchantKeyController{
+		merchantKeyUsecase: u,
+	}
+	ra := r.Group("/admin/merchants/:merchant_code/api-keys", k.IdentifyAdminKey(), m.RequireRole(authenticator.RoleAdmin))
+	ra.POST("", controller.IssueHandler)
+	ra.GET("", controller.ListHandler)
+	ra.DELETE("/:key_id", controller.RevokeHandler)
+	return &controller
+}
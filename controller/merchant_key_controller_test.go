@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type merchantKeyUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *merchantKeyUsecaseMock) Issue(request req.ApiKeyRequest) (res.ApiKeySecret, error) {
+	args := m.Called(request)
+	return args.Get(0).(res.ApiKeySecret), args.Error(1)
+}
+
+func (m *merchantKeyUsecaseMock) List(merchantCode string) ([]res.ApiKey, error) {
+	args := m.Called(merchantCode)
+	return args.Get(0).([]res.ApiKey), args.Error(1)
+}
+
+func (m *merchantKeyUsecaseMock) Revoke(merchantCode string, keyId string) error {
+	args := m.Called(merchantCode, keyId)
+	return args.Error(0)
+}
+
+func (m *merchantKeyUsecaseMock) Authenticate(request req.SignedRequest) (entity.Merchant, error) {
+	args := m.Called(request)
+	return args.Get(0).(entity.Merchant), args.Error(1)
+}
+
+type MerchantKeyControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *merchantKeyUsecaseMock
+	authMock        *authMock
+}
+
+func (suite *MerchantKeyControllerTestSuite) serve(method string, path string, body []byte, adminKey string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewMerchantKeyController(suite.routerGroupMock, suite.usecaseMock, middleware.NewAdminKeyMiddleware("Dummy Admin Key"), middleware.NewAuthTokenMiddleware(suite.authMock))
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set(middleware.AdminKeyHeader, adminKey)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *MerchantKeyControllerTestSuite) TestIssue_Success() {
+	suite.usecaseMock.On("Issue", req.ApiKeyRequest{MerchantCode: "MRC125", Label: "primary"}).
+		Return(res.ApiKeySecret{ApiKey: res.ApiKey{KeyId: "mk_dummy", Label: "primary", Active: true}, Secret: "msk_dummy"}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/admin/merchants/MRC125/api-keys", []byte(`{"label":"primary"}`), "Dummy Admin Key")
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	data := response.Data.(map[string]interface{})
+	assert.Equal(suite.T(), "mk_dummy", data["key_id"])
+	assert.Equal(suite.T(), "msk_dummy", data["secret"])
+}
+
+func (suite *MerchantKeyControllerTestSuite) TestIssue_FailedAdminKey() {
+	r, _ := suite.serve(http.MethodPost, "/v1/admin/merchants/MRC125/api-keys", []byte(`{"label":"primary"}`), "Wrong Admin Key")
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Issue", mock.Anything)
+}
+
+func (suite *MerchantKeyControllerTestSuite) TestIssue_FailedBindJSON() {
+	r, _ := suite.serve(http.MethodPost, "/v1/admin/merchants/MRC125/api-keys", []byte(`{1}`), "Dummy Admin Key")
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *MerchantKeyControllerTestSuite) TestList_Success() {
+	suite.usecaseMock.On("List", "MRC125").Return([]res.ApiKey{{KeyId: "mk_dummy", Active: true}}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/admin/merchants/MRC125/api-keys", nil, "Dummy Admin Key")
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *MerchantKeyControllerTestSuite) TestRevoke_FailedUsecase() {
+	suite.usecaseMock.On("Revoke", "MRC125", "mk_dummy").Return(errors.New("Failed"))
+
+	_, response := suite.serve(http.MethodDelete, "/v1/admin/merchants/MRC125/api-keys/mk_dummy", nil, "Dummy Admin Key")
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *MerchantKeyControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(merchantKeyUsecaseMock)
+	suite.authMock = new(authMock)
+}
+
+func TestMerchantKeyControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantKeyControllerTestSuite))
+}
@@ -1,17 +1,23 @@
 package controller
 
 import (
+	"log"
+
 	"github.com/febriansr/simple-payment-api/middleware"
 	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
 	"github.com/febriansr/simple-payment-api/usecase"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/idempotency"
 	"github.com/gin-gonic/gin"
 )
 
 type PaymentController struct {
 	paymentUsecase usecase.PaymentUsecase
 	authenticator  authenticator.AccessToken
+	idempotency    idempotency.Idempotency
 	BaseController
 	router *gin.RouterGroup
 }
@@ -24,36 +30,67 @@ func (l *PaymentController) PaymentHandler(ctx *gin.Context) {
 		return
 	}
 
-	token, err := authenticator.BindAuthHeader(ctx)
-	if err != nil {
-		l.Failed(ctx, err)
-		ctx.Abort()
+	accountDetails, ok := l.AccessDetails(ctx, l.authenticator)
+	if !ok {
 		return
 	}
 
-	accountDetails, err := l.authenticator.VerifyAccessToken(token)
+	transaction.CustomerUsername = accountDetails.Username
+
+	var authorization req.PaymentAuthorization
+	if err := ctx.ShouldBindHeader(&authorization); err != nil {
+		l.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	key := ctx.GetHeader(idempotency.HeaderKey)
+	if key == "" {
+		l.pay(ctx, transaction, authorization).Send()
+		return
+	}
+
+	fingerprint := idempotency.Fingerprint(transaction)
+	record, err := l.idempotency.Begin(transaction.CustomerUsername, key, fingerprint)
 	if err != nil {
 		l.Failed(ctx, err)
 		return
 	}
+	if record != nil {
+		ctx.Header(idempotency.HeaderReplayed, "true")
+		res.NewJsonResponse(ctx, record.StatusCode, record.Response).Send()
+		return
+	}
 
-	transaction.CustomerUsername = accountDetails.Username
+	response := l.pay(ctx, transaction, authorization)
+	statusCode, apiResponse := response.Get()
+	if idempotency.Cacheable(statusCode) {
+		err = l.idempotency.Complete(transaction.CustomerUsername, key, fingerprint, statusCode, apiResponse)
+	} else {
+		err = l.idempotency.Release(transaction.CustomerUsername, key)
+	}
+	if err != nil {
+		log.Println("Failed to record idempotent payment response:", err)
+	}
+	response.Send()
+}
 
-	err = l.paymentUsecase.PayTransaction(transaction)
+func (l *PaymentController) pay(ctx *gin.Context, transaction entity.History, authorization req.PaymentAuthorization) res.AppHttpResponse {
+	err := l.paymentUsecase.PayTransaction(transaction, authorization)
 
 	if err == nil {
-		l.Success(ctx, nil)
+		return res.NewSuccessJsonResponse(ctx, nil)
 	} else {
-		l.Failed(ctx, err)
+		return res.NewErrorJsonResponse(ctx, err)
 	}
 }
 
-func NewPaymentController(r *gin.RouterGroup, u usecase.PaymentUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *PaymentController {
+func NewPaymentController(r *gin.RouterGroup, u usecase.PaymentUsecase, a authenticator.AccessToken, i idempotency.Idempotency, m middleware.AuthTokenMiddleware) *PaymentController {
 	controller := PaymentController{
 		paymentUsecase: u,
 		authenticator:  a,
+		idempotency:    i,
 	}
-	rm := r.Group("/menu", m.RequireToken())
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
 	rm.POST("/payment", controller.PaymentHandler)
 	return &controller
 }
@@ -7,11 +7,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
 	"github.com/febriansr/simple-payment-api/model/dto/res"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/idempotency"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -20,7 +28,7 @@ import (
 var dummyTransaction = []entity.History{
 	{
 		MerchantCode: "Dummy Merchant Code",
-		Amount:       20000.00,
+		Amount:       money.MustParse("20000.00"),
 	},
 }
 
@@ -35,8 +43,8 @@ type paymentUsecaseMock struct {
 	mock.Mock
 }
 
-func (p *paymentUsecaseMock) PayTransaction(transaction entity.History) error {
-	args := p.Called(transaction)
+func (p *paymentUsecaseMock) PayTransaction(transaction entity.History, authorization req.PaymentAuthorization) error {
+	args := p.Called(transaction, authorization)
 	if args.Get(0) != nil {
 		return args.Error(0)
 	}
@@ -99,6 +107,53 @@ func (a *authMock) DeleteAccessToken(accessUuid string) error {
 	return nil
 }
 
+func (a *authMock) RefreshAccessToken(refreshToken string, currentRole func(username string) (string, error)) (authenticator.TokenDetails, error) {
+	args := a.Called(refreshToken)
+	return args.Get(0).(authenticator.TokenDetails), args.Error(1)
+}
+
+func (a *authMock) RevokeTokenFamily(familyId string) error {
+	args := a.Called(familyId)
+	return args.Error(0)
+}
+
+func (a *authMock) RevokeSessions(username string) error {
+	args := a.Called(username)
+	return args.Error(0)
+}
+
+func (a *authMock) ListSessions(username string) ([]authenticator.Session, error) {
+	args := a.Called(username)
+	return args.Get(0).([]authenticator.Session), args.Error(1)
+}
+
+func (a *authMock) RevokeSession(username string, sessionId string) error {
+	args := a.Called(username, sessionId)
+	return args.Error(0)
+}
+
+type idempotencyMock struct {
+	mock.Mock
+}
+
+func (i *idempotencyMock) Begin(scope string, key string, fingerprint string) (*idempotency.Record, error) {
+	args := i.Called(scope, key, fingerprint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*idempotency.Record), args.Error(1)
+}
+
+func (i *idempotencyMock) Complete(scope string, key string, fingerprint string, statusCode int, response res.ApiResponse) error {
+	args := i.Called(scope, key, fingerprint, statusCode, response)
+	return args.Error(0)
+}
+
+func (i *idempotencyMock) Release(scope string, key string) error {
+	args := i.Called(scope, key)
+	return args.Error(0)
+}
+
 type middlewareMock struct {
 	mock.Mock
 }
@@ -109,6 +164,18 @@ func (m *middlewareMock) RequireToken() gin.HandlerFunc {
 	}
 }
 
+func (m *middlewareMock) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+	}
+}
+
+func (m *middlewareMock) RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+	}
+}
+
 type PaymentControllerTestSuite struct {
 	suite.Suite
 	routerMock         *gin.Engine
@@ -116,12 +183,13 @@ type PaymentControllerTestSuite struct {
 	usecaseMock        *paymentUsecaseMock
 	bindAuthHeaderMock *bindAuthHeaderMock
 	authMock           *authMock
+	idempotencyMock    *idempotencyMock
 	middlewareMock     *middlewareMock
 }
 
 func (suite *PaymentControllerTestSuite) TestPayTransaction_Success() {
 	transaction := dummyTransaction[0]
-	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
 	r := httptest.NewRecorder()
 	reqBody, _ := json.Marshal(transaction)
 	request, _ := http.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(reqBody))
@@ -131,7 +199,7 @@ func (suite *PaymentControllerTestSuite) TestPayTransaction_Success() {
 	suite.bindAuthHeaderMock.On("BindAuthHeader", ctx).Return(dummyTokenDetails[0].AccessToken, nil)
 	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
 	transaction.CustomerUsername = dummyAccessDetails[0].Username
-	suite.usecaseMock.On("PayTransaction", transaction).Return(nil)
+	suite.usecaseMock.On("PayTransaction", transaction, req.PaymentAuthorization{}).Return(nil)
 
 	paymentController.PaymentHandler(ctx)
 
@@ -141,8 +209,28 @@ func (suite *PaymentControllerTestSuite) TestPayTransaction_Success() {
 	assert.Equal(suite.T(), http.StatusOK, r.Code)
 }
 
+func (suite *PaymentControllerTestSuite) TestPayTransaction_ForwardsPin() {
+	transaction := dummyTransaction[0]
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	reqBody, _ := json.Marshal(transaction)
+	request, _ := http.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(reqBody))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	request.Header.Set("X-Transaction-Pin", "123456")
+	request.Header.Set("X-Step-Up-Token", "Dummy Step Up Token")
+	ctx, _ := gin.CreateTestContext(r)
+	ctx.Request = request
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	transaction.CustomerUsername = dummyAccessDetails[0].Username
+	suite.usecaseMock.On("PayTransaction", transaction, req.PaymentAuthorization{Pin: "123456", StepUpToken: "Dummy Step Up Token"}).Return(nil)
+
+	paymentController.PaymentHandler(ctx)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
 func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedBindJSON() {
-	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
 	r := httptest.NewRecorder()
 	reqBody := []byte(`{1}`)
 	request, _ := http.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(reqBody))
@@ -160,7 +248,7 @@ func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedBindJSON() {
 
 func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedBindAuthHeader() {
 	transaction := dummyTransaction[0]
-	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
 	r := httptest.NewRecorder()
 	reqBody, _ := json.Marshal(transaction)
 	request, _ := http.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(reqBody))
@@ -179,7 +267,7 @@ func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedBindAuthHeader
 
 func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedVerifyAccessToken() {
 	transaction := dummyTransaction[0]
-	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
 	r := httptest.NewRecorder()
 	reqBody, _ := json.Marshal(transaction)
 	request, _ := http.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(reqBody))
@@ -199,7 +287,7 @@ func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedVerifyAccessTo
 
 func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedUsecase() {
 	transaction := dummyTransaction[0]
-	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
 	r := httptest.NewRecorder()
 	reqBody, _ := json.Marshal(transaction)
 	request, _ := http.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(reqBody))
@@ -209,7 +297,7 @@ func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedUsecase() {
 	suite.bindAuthHeaderMock.On("BindAuthHeader", ctx).Return(dummyTokenDetails[0].AccessToken, nil)
 	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
 	transaction.CustomerUsername = dummyAccessDetails[0].Username
-	suite.usecaseMock.On("PayTransaction", transaction).Return(errors.New("Failed"))
+	suite.usecaseMock.On("PayTransaction", transaction, req.PaymentAuthorization{}).Return(errors.New("Failed"))
 	paymentController.PaymentHandler(ctx)
 
 	var response res.ApiResponse
@@ -218,12 +306,132 @@ func (suite *PaymentControllerTestSuite) TestPayTransaction_FailedUsecase() {
 	assert.Equal(suite.T(), "XX", response.Status.Code)
 }
 
+func (suite *PaymentControllerTestSuite) newIdempotentRequest(transaction entity.History) (*gin.Context, *httptest.ResponseRecorder) {
+	r := httptest.NewRecorder()
+	reqBody, _ := json.Marshal(transaction)
+	request, _ := http.NewRequest(http.MethodPost, "/v1/payment", bytes.NewBuffer(reqBody))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	request.Header.Set(idempotency.HeaderKey, "Dummy Idempotency Key")
+	ctx, _ := gin.CreateTestContext(r)
+	ctx.Request = request
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	return ctx, r
+}
+
+func (suite *PaymentControllerTestSuite) TestPayTransaction_IdempotentFirstRequest() {
+	transaction := dummyTransaction[0]
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
+	ctx, r := suite.newIdempotentRequest(transaction)
+	transaction.CustomerUsername = dummyAccessDetails[0].Username
+	fingerprint := idempotency.Fingerprint(transaction)
+	suite.idempotencyMock.On("Begin", dummyAccessDetails[0].Username, "Dummy Idempotency Key", fingerprint).Return(nil, nil)
+	suite.usecaseMock.On("PayTransaction", transaction, req.PaymentAuthorization{}).Return(nil)
+	_, successResponse := res.NewSuccessMessage(nil)
+	suite.idempotencyMock.On("Complete", dummyAccessDetails[0].Username, "Dummy Idempotency Key", fingerprint, http.StatusOK, successResponse).Return(nil)
+
+	paymentController.PaymentHandler(ctx)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	suite.idempotencyMock.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentControllerTestSuite) TestPayTransaction_IdempotentReplay() {
+	transaction := dummyTransaction[0]
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
+	ctx, r := suite.newIdempotentRequest(transaction)
+	transaction.CustomerUsername = dummyAccessDetails[0].Username
+	_, failedResponse := res.NewFailedMessage(errors.New("Failed"))
+	suite.idempotencyMock.On("Begin", dummyAccessDetails[0].Username, "Dummy Idempotency Key", idempotency.Fingerprint(transaction)).
+		Return(&idempotency.Record{StatusCode: http.StatusBadRequest, Response: failedResponse}, nil)
+
+	paymentController.PaymentHandler(ctx)
+
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	assert.Equal(suite.T(), "true", r.Header().Get(idempotency.HeaderReplayed))
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "PayTransaction", mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentControllerTestSuite) TestPayTransaction_IdempotentFailedBegin() {
+	transaction := dummyTransaction[0]
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
+	ctx, r := suite.newIdempotentRequest(transaction)
+	transaction.CustomerUsername = dummyAccessDetails[0].Username
+	suite.idempotencyMock.On("Begin", dummyAccessDetails[0].Username, "Dummy Idempotency Key", idempotency.Fingerprint(transaction)).
+		Return(nil, app_error.Conflict(""))
+
+	paymentController.PaymentHandler(ctx)
+
+	assert.Equal(suite.T(), http.StatusConflict, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "PayTransaction", mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentControllerTestSuite) TestPayTransaction_IdempotentReleaseOnServerError() {
+	transaction := dummyTransaction[0]
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
+	ctx, r := suite.newIdempotentRequest(transaction)
+	transaction.CustomerUsername = dummyAccessDetails[0].Username
+	suite.idempotencyMock.On("Begin", dummyAccessDetails[0].Username, "Dummy Idempotency Key", idempotency.Fingerprint(transaction)).Return(nil, nil)
+	suite.usecaseMock.On("PayTransaction", transaction, req.PaymentAuthorization{}).Return(errors.New("Failed"))
+	suite.idempotencyMock.On("Release", dummyAccessDetails[0].Username, "Dummy Idempotency Key").Return(nil)
+
+	paymentController.PaymentHandler(ctx)
+
+	assert.Equal(suite.T(), http.StatusInternalServerError, r.Code)
+	suite.idempotencyMock.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentControllerTestSuite) TestPayTransaction_IdempotentRetryAfterPinRequired() {
+	transaction := dummyTransaction[0]
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(suite.T()).Addr()})
+	store := idempotency.NewIdempotency(config.IdempotencyConfig{
+		KeyLifetime: time.Hour,
+		WaitTimeout: 100 * time.Millisecond,
+	}, client)
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, store, suite.middlewareMock)
+	expected := transaction
+	expected.CustomerUsername = dummyAccessDetails[0].Username
+	suite.usecaseMock.On("PayTransaction", expected, req.PaymentAuthorization{}).Return(app_error.Forbidden("Transaction PIN required"))
+	suite.usecaseMock.On("PayTransaction", expected, req.PaymentAuthorization{Pin: "123456"}).Return(nil)
+
+	ctx, r := suite.newIdempotentRequest(transaction)
+	paymentController.PaymentHandler(ctx)
+	assert.Equal(suite.T(), http.StatusForbidden, r.Code)
+
+	ctx, r = suite.newIdempotentRequest(transaction)
+	ctx.Request.Header.Set("X-Transaction-Pin", "123456")
+	paymentController.PaymentHandler(ctx)
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Empty(suite.T(), r.Header().Get(idempotency.HeaderReplayed))
+	suite.usecaseMock.AssertNumberOfCalls(suite.T(), "PayTransaction", 2)
+}
+
+func (suite *PaymentControllerTestSuite) TestPayTransaction_IdempotentReleaseOnLockout() {
+	transaction := dummyTransaction[0]
+	paymentController := NewPaymentController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.idempotencyMock, suite.middlewareMock)
+	ctx, r := suite.newIdempotentRequest(transaction)
+	transaction.CustomerUsername = dummyAccessDetails[0].Username
+	suite.idempotencyMock.On("Begin", dummyAccessDetails[0].Username, "Dummy Idempotency Key", idempotency.Fingerprint(transaction)).Return(nil, nil)
+	suite.usecaseMock.On("PayTransaction", transaction, req.PaymentAuthorization{}).Return(app_error.TooManyRequests("Too many incorrect PIN attempts"))
+	suite.idempotencyMock.On("Release", dummyAccessDetails[0].Username, "Dummy Idempotency Key").Return(nil)
+
+	paymentController.PaymentHandler(ctx)
+
+	assert.Equal(suite.T(), http.StatusTooManyRequests, r.Code)
+	suite.idempotencyMock.AssertExpectations(suite.T())
+	suite.idempotencyMock.AssertNotCalled(suite.T(), "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (suite *PaymentControllerTestSuite) SetupTest() {
 	suite.routerMock = gin.Default()
 	suite.routerGroupMock = suite.routerMock.Group("/v1")
 	suite.usecaseMock = new(paymentUsecaseMock)
 	suite.bindAuthHeaderMock = new(bindAuthHeaderMock)
 	suite.authMock = new(authMock)
+	suite.idempotencyMock = new(idempotencyMock)
 	suite.middlewareMock = new(middlewareMock)
 }
 
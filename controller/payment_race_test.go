@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/config"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaymentRaceTestSuite struct {
+	suite.Suite
+	config config.StorageConfig
+}
+
+func (suite *PaymentRaceTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	suite.config = config.StorageConfig{
+		SqliteFile: filepath.Join(dir, "race.db"),
+		JsonFileConfig: config.JsonFileConfig{
+			Customer: filepath.Join(dir, "customer.json"),
+			Merchant: filepath.Join(dir, "merchant.json"),
+			History:  filepath.Join(dir, "history.json"),
+		},
+	}
+	suite.Require().NoError(utils.WriteJSON(suite.config.Customer, []entity.Customer{
+		{Uuid: "Dummy Uuid", Username: dummyAccessDetails[0].Username, Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(suite.config.Merchant, []entity.Merchant{
+		{Uuid: "Dummy Merchant Uuid", MerchantCode: dummyTransaction[0].MerchantCode},
+	}))
+	suite.Require().NoError(utils.WriteJSON(suite.config.History, []entity.History{}))
+}
+
+func (suite *PaymentRaceTestSuite) hammer(driver string) {
+	suite.config.Driver = driver
+	store, err := storage.NewStorage(suite.config)
+	suite.Require().NoError(err)
+	defer store.Close()
+
+	authMock := new(authMock)
+	authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	router := gin.New()
+	NewPaymentController(router.Group("/v1"), usecase.NewPaymentUsecase(repository.NewPaymentRepository(store), usecase.NewPinUsecase(repository.NewPinRepository(store), nil, config.SecurityConfig{})), authMock, new(idempotencyMock), new(middlewareMock))
+
+	requests := 50
+	reqBody, _ := json.Marshal(entity.History{MerchantCode: dummyTransaction[0].MerchantCode, Amount: money.MustParse("3")})
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRecorder()
+			request, _ := http.NewRequest(http.MethodPost, "/v1/menu/payment", bytes.NewBuffer(reqBody))
+			request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+			router.ServeHTTP(r, request)
+			if r.Code == http.StatusOK {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	customer, err := store.Customers().FindByUsername(dummyAccessDetails[0].Username)
+	suite.Require().NoError(err)
+	histories, err := store.Histories().Find(storage.HistoryFilter{CustomerUsername: dummyAccessDetails[0].Username})
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), 33, succeeded, driver)
+	assert.Equal(suite.T(), money.MustParse("1"), customer.Balance, driver)
+	assert.Len(suite.T(), histories, succeeded, driver)
+}
+
+func (suite *PaymentRaceTestSuite) TestPayTransaction_ConcurrentJson() {
+	suite.hammer(storage.DriverJson)
+}
+
+func (suite *PaymentRaceTestSuite) TestPayTransaction_ConcurrentSqlite() {
+	suite.hammer(storage.DriverSqlite)
+}
+
+func TestPaymentRaceTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentRaceTestSuite))
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentRequestController struct {
+	paymentRequestUsecase usecase.PaymentRequestUsecase
+	authenticator         authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (p *PaymentRequestController) CreateHandler(ctx *gin.Context) {
+	var request req.CreatePaymentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		p.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		p.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return
+	}
+	request.MerchantCode = merchant.MerchantCode
+
+	paymentRequest, err := p.paymentRequestUsecase.Create(request)
+
+	if err == nil {
+		p.Success(ctx, paymentRequest)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func (p *PaymentRequestController) MerchantListHandler(ctx *gin.Context) {
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		p.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return
+	}
+
+	paymentRequests, err := p.paymentRequestUsecase.ListForMerchant(merchant.MerchantCode)
+
+	if err == nil {
+		p.Success(ctx, paymentRequests)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func (p *PaymentRequestController) ListHandler(ctx *gin.Context) {
+	accessDetails, ok := p.AccessDetails(ctx, p.authenticator)
+	if !ok {
+		return
+	}
+
+	paymentRequests, err := p.paymentRequestUsecase.ListPending(accessDetails.Username)
+
+	if err == nil {
+		p.Success(ctx, paymentRequests)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func (p *PaymentRequestController) ApproveHandler(ctx *gin.Context) {
+	var authorization req.PaymentAuthorization
+	if err := ctx.ShouldBindHeader(&authorization); err != nil {
+		p.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+	accessDetails, ok := p.AccessDetails(ctx, p.authenticator)
+	if !ok {
+		return
+	}
+
+	paymentRequest, err := p.paymentRequestUsecase.Approve(accessDetails.Username, ctx.Param("request_id"), authorization)
+
+	if err == nil {
+		p.Success(ctx, paymentRequest)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func (p *PaymentRequestController) DeclineHandler(ctx *gin.Context) {
+	accessDetails, ok := p.AccessDetails(ctx, p.authenticator)
+	if !ok {
+		return
+	}
+
+	paymentRequest, err := p.paymentRequestUsecase.Decline(accessDetails.Username, ctx.Param("request_id"))
+
+	if err == nil {
+		p.Success(ctx, paymentRequest)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func NewPaymentRequestController(r *gin.RouterGroup, u usecase.PaymentRequestUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware, s middleware.MerchantSignatureMiddleware) *PaymentRequestController {
+	controller := PaymentRequestController{
+		paymentRequestUsecase: u,
+		authenticator:         a,
+	}
+	rs := r.Group("/merchant", s.RequireSignature())
+	rs.POST("/payment-requests", controller.CreateHandler)
+	rs.GET("/payment-requests", controller.MerchantListHandler)
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
+	rm.GET("/payment-requests", controller.ListHandler)
+	rm.POST("/payment-requests/:request_id/approve", controller.ApproveHandler)
+	rm.POST("/payment-requests/:request_id/decline", controller.DeclineHandler)
+	return &controller
+}
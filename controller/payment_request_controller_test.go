@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyPaymentRequest = entity.PaymentRequest{
+	RequestId:        "Dummy Request Id",
+	MerchantCode:     "MRC125",
+	CustomerUsername: "dummyUsername1",
+	Amount:           money.MustParse("10000"),
+	Currency:         money.DefaultCurrency,
+	Reference:        "INV-1",
+	Status:           entity.PaymentRequestStatusPending,
+}
+
+type paymentRequestUsecaseMock struct {
+	mock.Mock
+}
+
+func (p *paymentRequestUsecaseMock) Create(request req.CreatePaymentRequest) (entity.PaymentRequest, error) {
+	args := p.Called(request)
+	return args.Get(0).(entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestUsecaseMock) ListForMerchant(merchantCode string) ([]entity.PaymentRequest, error) {
+	args := p.Called(merchantCode)
+	return args.Get(0).([]entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestUsecaseMock) ListPending(customerUsername string) ([]entity.PaymentRequest, error) {
+	args := p.Called(customerUsername)
+	return args.Get(0).([]entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestUsecaseMock) Approve(customerUsername string, requestId string, authorization req.PaymentAuthorization) (entity.PaymentRequest, error) {
+	args := p.Called(customerUsername, requestId, authorization)
+	return args.Get(0).(entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestUsecaseMock) Decline(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	args := p.Called(customerUsername, requestId)
+	return args.Get(0).(entity.PaymentRequest), args.Error(1)
+}
+
+type PaymentRequestControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *paymentRequestUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+	signatureMock   *merchantSignatureMock
+}
+
+func (suite *PaymentRequestControllerTestSuite) serve(method string, path string, body []byte, headers map[string]string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewPaymentRequestController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock, suite.signatureMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestCreate_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("Create", req.CreatePaymentRequest{
+		MerchantCode:     "MRC125",
+		CustomerUsername: "dummyUsername1",
+		Amount:           money.MustParse("10000"),
+		Reference:        "INV-1",
+	}).Return(dummyPaymentRequest, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/merchant/payment-requests", []byte(`{"customer_username":"dummyUsername1","amount":"10000","reference":"INV-1"}`), nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), dummyPaymentRequest.RequestId, response.Data.(map[string]interface{})["request_id"])
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestCreate_FailedBindJSON() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+
+	r, _ := suite.serve(http.MethodPost, "/v1/merchant/payment-requests", []byte(`{1}`), nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestCreate_FailedMissingMerchant() {
+	r, _ := suite.serve(http.MethodPost, "/v1/merchant/payment-requests", []byte(`{"reference":"INV-1"}`), nil)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestMerchantList_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("ListForMerchant", "MRC125").Return([]entity.PaymentRequest{dummyPaymentRequest}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/merchant/payment-requests", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestList_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("ListPending", dummyAccessDetails[0].Username).Return([]entity.PaymentRequest{dummyPaymentRequest}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/menu/payment-requests", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestApprove_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	approved := dummyPaymentRequest
+	approved.Status = entity.PaymentRequestStatusApproved
+	suite.usecaseMock.On("Approve", dummyAccessDetails[0].Username, dummyPaymentRequest.RequestId, req.PaymentAuthorization{Pin: "123456"}).Return(approved, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/menu/payment-requests/Dummy Request Id/approve", nil, map[string]string{"X-Transaction-Pin": "123456"})
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), entity.PaymentRequestStatusApproved, response.Data.(map[string]interface{})["status"])
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestApprove_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/payment-requests/Dummy Request Id/approve", nil, nil)
+
+	assert.NotEqual(suite.T(), http.StatusOK, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Approve", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentRequestControllerTestSuite) TestDecline_FailedUsecase() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Decline", dummyAccessDetails[0].Username, dummyPaymentRequest.RequestId).Return(entity.PaymentRequest{}, app_error.DataNotFound("payment request not found"))
+
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/payment-requests/Dummy Request Id/decline", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, r.Code)
+}
+
+func (suite *PaymentRequestControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(paymentRequestUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+	suite.signatureMock = new(merchantSignatureMock)
+}
+
+func TestPaymentRequestControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentRequestControllerTestSuite))
+}
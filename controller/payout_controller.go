@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type PayoutController struct {
+	BaseController
+	router        *gin.RouterGroup
+	payoutUsecase usecase.PayoutUsecase
+}
+
+func (p *PayoutController) CreateBatchesHandler(ctx *gin.Context) {
+	batches, err := p.payoutUsecase.CreateBatches()
+
+	if err == nil {
+		p.Success(ctx, batches)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func (p *PayoutController) ListBatchesHandler(ctx *gin.Context) {
+	batches, err := p.payoutUsecase.ListBatches()
+
+	if err == nil {
+		p.Success(ctx, batches)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func (p *PayoutController) ExportHandler(ctx *gin.Context) {
+	fileName, file, err := p.payoutUsecase.ExportBatch(ctx.Param("batch_id"))
+	if err != nil {
+		p.Failed(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	ctx.Data(http.StatusOK, "text/csv", file)
+}
+
+func (p *PayoutController) SettlementHandler(ctx *gin.Context) {
+	file, err := ctx.GetRawData()
+	if err != nil {
+		p.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	batch, err := p.payoutUsecase.ImportSettlement(ctx.Param("batch_id"), file)
+
+	if err == nil {
+		p.Success(ctx, batch)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func NewPayoutController(r *gin.RouterGroup, u usecase.PayoutUsecase, k middleware.AdminKeyMiddleware, m middleware.AuthTokenMiddleware) *PayoutController {
+	controller := PayoutController{
+		payoutUsecase: u,
+	}
+	ra := r.Group("/admin/payouts/batches", k.IdentifyAdminKey(), m.RequireRole(authenticator.RoleAdmin))
+	ra.POST("", controller.CreateBatchesHandler)
+	ra.GET("", controller.ListBatchesHandler)
+	ra.GET("/:batch_id/file", controller.ExportHandler)
+	ra.POST("/:batch_id/settlement", controller.SettlementHandler)
+	return &controller
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type payoutUsecaseMock struct {
+	mock.Mock
+}
+
+func (p *payoutUsecaseMock) CreateBatches() ([]entity.PayoutBatch, error) {
+	args := p.Called()
+	return args.Get(0).([]entity.PayoutBatch), args.Error(1)
+}
+
+func (p *payoutUsecaseMock) ListBatches() ([]entity.PayoutBatch, error) {
+	args := p.Called()
+	return args.Get(0).([]entity.PayoutBatch), args.Error(1)
+}
+
+func (p *payoutUsecaseMock) ExportBatch(batchId string) (string, []byte, error) {
+	args := p.Called(batchId)
+	return args.String(0), args.Get(1).([]byte), args.Error(2)
+}
+
+func (p *payoutUsecaseMock) ImportSettlement(batchId string, file []byte) (entity.PayoutBatch, error) {
+	args := p.Called(batchId, file)
+	return args.Get(0).(entity.PayoutBatch), args.Error(1)
+}
+
+type PayoutControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *payoutUsecaseMock
+	authMock        *authMock
+}
+
+func (suite *PayoutControllerTestSuite) serve(method string, path string, body []byte, adminKey string) *httptest.ResponseRecorder {
+	NewPayoutController(suite.routerGroupMock, suite.usecaseMock, middleware.NewAdminKeyMiddleware("Dummy Admin Key"), middleware.NewAuthTokenMiddleware(suite.authMock))
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set(middleware.AdminKeyHeader, adminKey)
+	suite.routerMock.ServeHTTP(r, request)
+	return r
+}
+
+func (suite *PayoutControllerTestSuite) TestCreateBatches_Success() {
+	suite.usecaseMock.On("CreateBatches").Return([]entity.PayoutBatch{{BatchId: "Dummy Batch Id", Count: 2}}, nil)
+
+	r := suite.serve(http.MethodPost, "/v1/admin/payouts/batches", nil, "Dummy Admin Key")
+
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *PayoutControllerTestSuite) TestCreateBatches_FailedAdminKey() {
+	r := suite.serve(http.MethodPost, "/v1/admin/payouts/batches", nil, "Wrong Admin Key")
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "CreateBatches")
+}
+
+func (suite *PayoutControllerTestSuite) TestExport_Success() {
+	suite.usecaseMock.On("ExportBatch", "Dummy Batch Id").Return("payout_dummy.csv", []byte("H,Dummy Batch Id\nT,0,0.00\n"), nil)
+
+	r := suite.serve(http.MethodGet, "/v1/admin/payouts/batches/Dummy%20Batch%20Id/file", nil, "Dummy Admin Key")
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "text/csv", r.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), `attachment; filename="payout_dummy.csv"`, r.Header().Get("Content-Disposition"))
+	assert.Equal(suite.T(), "H,Dummy Batch Id\nT,0,0.00\n", r.Body.String())
+}
+
+func (suite *PayoutControllerTestSuite) TestExport_FailedUsecase() {
+	suite.usecaseMock.On("ExportBatch", "unknown").Return("", []byte(nil), app_error.DataNotFound("payout batch not found"))
+
+	r := suite.serve(http.MethodGet, "/v1/admin/payouts/batches/unknown/file", nil, "Dummy Admin Key")
+
+	assert.Equal(suite.T(), http.StatusNotFound, r.Code)
+}
+
+func (suite *PayoutControllerTestSuite) TestSettlement_Success() {
+	file := []byte("H,Dummy Batch Id,20230628\nD,wd-1,50000.00,PAID\nT,1\n")
+	suite.usecaseMock.On("ImportSettlement", "Dummy Batch Id", file).
+		Return(entity.PayoutBatch{BatchId: "Dummy Batch Id", Status: entity.PayoutBatchStatusSettled}, nil)
+
+	r := suite.serve(http.MethodPost, "/v1/admin/payouts/batches/Dummy%20Batch%20Id/settlement", file, "Dummy Admin Key")
+
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), entity.PayoutBatchStatusSettled, response.Data.(map[string]interface{})["status"])
+}
+
+func (suite *PayoutControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(payoutUsecaseMock)
+	suite.authMock = new(authMock)
+}
+
+func TestPayoutControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(PayoutControllerTestSuite))
+}
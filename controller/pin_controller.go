@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type PinController struct {
+	pinUsecase    usecase.PinUsecase
+	authenticator authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (p *PinController) SetPinHandler(ctx *gin.Context) {
+	var request req.SetPinRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		p.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	accessDetails, ok := p.AccessDetails(ctx, p.authenticator)
+	if !ok {
+		return
+	}
+	request.CustomerUsername = accessDetails.Username
+
+	err := p.pinUsecase.SetPin(request)
+
+	if err == nil {
+		p.Success(ctx, nil)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func (p *PinController) StepUpHandler(ctx *gin.Context) {
+	var request req.StepUpRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		p.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	accessDetails, ok := p.AccessDetails(ctx, p.authenticator)
+	if !ok {
+		return
+	}
+	request.CustomerUsername = accessDetails.Username
+
+	stepUpToken, err := p.pinUsecase.StepUp(request)
+
+	if err == nil {
+		p.Success(ctx, stepUpToken)
+	} else {
+		p.Failed(ctx, err)
+	}
+}
+
+func NewPinController(r *gin.RouterGroup, u usecase.PinUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *PinController {
+	controller := PinController{
+		pinUsecase:    u,
+		authenticator: a,
+	}
+	rm := r.Group("/menu", m.RequireToken())
+	rm.PUT("/pin", controller.SetPinHandler)
+	rm.POST("/pin/step-up", controller.StepUpHandler)
+	return &controller
+}
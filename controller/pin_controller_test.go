@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type pinUsecaseMock struct {
+	mock.Mock
+}
+
+func (p *pinUsecaseMock) SetPin(request req.SetPinRequest) error {
+	args := p.Called(request)
+	return args.Error(0)
+}
+
+func (p *pinUsecaseMock) StepUp(request req.StepUpRequest) (res.StepUpToken, error) {
+	args := p.Called(request)
+	return args.Get(0).(res.StepUpToken), args.Error(1)
+}
+
+func (p *pinUsecaseMock) Authorize(username string, authorization req.PaymentAuthorization) error {
+	args := p.Called(username, authorization)
+	return args.Error(0)
+}
+
+func (p *pinUsecaseMock) AuthorizeAmount(username string, amount money.Amount, currency string, authorization req.PaymentAuthorization) error {
+	args := p.Called(username, amount, currency, authorization)
+	return args.Error(0)
+}
+
+type PinControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *pinUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+}
+
+func (suite *PinControllerTestSuite) serve(method string, path string, body []byte) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewPinController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *PinControllerTestSuite) TestSetPin_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("SetPin", req.SetPinRequest{
+		CustomerUsername: dummyAccessDetails[0].Username,
+		Pin:              "123456",
+		Password:         "dummyPassword1",
+	}).Return(nil)
+
+	r, _ := suite.serve(http.MethodPut, "/v1/menu/pin", []byte(`{"pin":"123456","password":"dummyPassword1"}`))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *PinControllerTestSuite) TestSetPin_FailedBindJSON() {
+	r, _ := suite.serve(http.MethodPut, "/v1/menu/pin", []byte(`{1}`))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "SetPin", mock.Anything)
+}
+
+func (suite *PinControllerTestSuite) TestSetPin_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	_, response := suite.serve(http.MethodPut, "/v1/menu/pin", []byte(`{"pin":"123456","password":"dummyPassword1"}`))
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "SetPin", mock.Anything)
+}
+
+func (suite *PinControllerTestSuite) TestStepUp_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("StepUp", req.StepUpRequest{CustomerUsername: dummyAccessDetails[0].Username, Pin: "123456"}).
+		Return(res.StepUpToken{StepUpToken: "Dummy Step Up Token", ExpiresAt: 300}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/menu/pin/step-up", []byte(`{"pin":"123456"}`))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), map[string]interface{}{
+		"step_up_token": "Dummy Step Up Token",
+		"expires_at":    float64(300),
+	}, response.Data)
+}
+
+func (suite *PinControllerTestSuite) TestStepUp_FailedUsecase() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("StepUp", mock.Anything).Return(res.StepUpToken{}, errors.New("Failed"))
+
+	_, response := suite.serve(http.MethodPost, "/v1/menu/pin/step-up", []byte(`{"pin":"123456"}`))
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *PinControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(pinUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+}
+
+func TestPinControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(PinControllerTestSuite))
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type RefreshController struct {
+	BaseController
+	router         *gin.RouterGroup
+	refreshUsecase usecase.RefreshUsecase
+}
+
+func (r *RefreshController) RefreshHandler(ctx *gin.Context) {
+	var request req.RefreshRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		r.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	tokenPair, err := r.refreshUsecase.Refresh(request)
+
+	if err == nil {
+		r.Success(ctx, tokenPair)
+	} else {
+		r.Failed(ctx, err)
+	}
+}
+
+func NewRefreshController(r *gin.RouterGroup, u usecase.RefreshUsecase) *RefreshController {
+	controller := RefreshController{
+		refreshUsecase: u,
+	}
+	r.POST("/refresh", controller.RefreshHandler)
+	return &controller
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyRefreshRequest = req.RefreshRequest{
+	RefreshToken: "Dummy Refresh Token",
+}
+
+type refreshUsecaseMock struct {
+	mock.Mock
+}
+
+func (r *refreshUsecaseMock) Refresh(request req.RefreshRequest) (res.TokenPair, error) {
+	args := r.Called(request)
+	return args.Get(0).(res.TokenPair), args.Error(1)
+}
+
+type RefreshControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *refreshUsecaseMock
+}
+
+func (suite *RefreshControllerTestSuite) serve(body []byte) *httptest.ResponseRecorder {
+	NewRefreshController(suite.routerGroupMock, suite.usecaseMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/v1/refresh", bytes.NewBuffer(body))
+	suite.routerMock.ServeHTTP(r, request)
+	return r
+}
+
+func (suite *RefreshControllerTestSuite) TestRefresh_Success() {
+	reqBody, _ := json.Marshal(dummyRefreshRequest)
+	suite.usecaseMock.On("Refresh", dummyRefreshRequest).Return(res.TokenPair{Token: "New Access Token", RefreshToken: "New Refresh Token"}, nil)
+
+	r := suite.serve(reqBody)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "New Access Token", response.Data.(map[string]interface{})["token"])
+	assert.Equal(suite.T(), "New Refresh Token", response.Data.(map[string]interface{})["refresh_token"])
+}
+
+func (suite *RefreshControllerTestSuite) TestRefresh_FailedBindJSON() {
+	r := suite.serve([]byte(`{1}`))
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *RefreshControllerTestSuite) TestRefresh_FailedUsecase() {
+	reqBody, _ := json.Marshal(dummyRefreshRequest)
+	suite.usecaseMock.On("Refresh", dummyRefreshRequest).Return(res.TokenPair{}, app_error.Unauthorized("Refresh token revoked"))
+
+	r := suite.serve(reqBody)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+}
+
+func (suite *RefreshControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(refreshUsecaseMock)
+}
+
+func TestRefreshControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshControllerTestSuite))
+}
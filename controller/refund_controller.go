@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type RefundController struct {
+	refundUsecase usecase.RefundUsecase
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (r *RefundController) MerchantRefundHandler(ctx *gin.Context) {
+	var request req.RefundRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		r.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		r.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return
+	}
+	request.MerchantCode = merchant.MerchantCode
+
+	r.refund(ctx, request)
+}
+
+func (r *RefundController) AdminRefundHandler(ctx *gin.Context) {
+	var request req.RefundRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		r.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	r.refund(ctx, request)
+}
+
+func (r *RefundController) refund(ctx *gin.Context, request req.RefundRequest) {
+	refund, err := r.refundUsecase.Refund(request)
+
+	if err == nil {
+		r.Success(ctx, refund)
+	} else {
+		r.Failed(ctx, err)
+	}
+}
+
+func NewRefundController(r *gin.RouterGroup, u usecase.RefundUsecase, k middleware.AdminKeyMiddleware, m middleware.AuthTokenMiddleware, s middleware.MerchantSignatureMiddleware) *RefundController {
+	controller := RefundController{
+		refundUsecase: u,
+	}
+	rs := r.Group("/merchant", s.RequireSignature())
+	rs.POST("/refunds", controller.MerchantRefundHandler)
+	ra := r.Group("/admin", k.IdentifyAdminKey(), m.RequireRole(authenticator.RoleAdmin))
+	ra.POST("/refunds", controller.AdminRefundHandler)
+	return &controller
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyRefundRequest = req.RefundRequest{
+	TransactionId: "Dummy Transaction Id",
+	Amount:        money.MustParse("5000"),
+	Reason:        "Dummy Reason",
+}
+
+type refundUsecaseMock struct {
+	mock.Mock
+}
+
+func (r *refundUsecaseMock) Refund(request req.RefundRequest) (entity.History, error) {
+	args := r.Called(request)
+	return args.Get(0).(entity.History), args.Error(1)
+}
+
+type RefundControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *refundUsecaseMock
+	authMock        *authMock
+	signatureMock   *merchantSignatureMock
+}
+
+func (suite *RefundControllerTestSuite) serve(path string, body []byte, headers map[string]string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewRefundController(suite.routerGroupMock, suite.usecaseMock, middleware.NewAdminKeyMiddleware("Dummy Admin Key"), middleware.NewAuthTokenMiddleware(suite.authMock), suite.signatureMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *RefundControllerTestSuite) TestMerchantRefund_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	reqBody, _ := json.Marshal(dummyRefundRequest)
+	request := dummyRefundRequest
+	request.MerchantCode = "MRC125"
+	suite.usecaseMock.On("Refund", request).Return(entity.History{TransactionId: "Dummy Refund Id"}, nil)
+
+	r, response := suite.serve("/v1/merchant/refunds", reqBody, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "Dummy Refund Id", response.Data.(map[string]interface{})["transaction_id"])
+}
+
+func (suite *RefundControllerTestSuite) TestMerchantRefund_FailedBindJSON() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+
+	r, _ := suite.serve("/v1/merchant/refunds", []byte(`{1}`), nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *RefundControllerTestSuite) TestMerchantRefund_FailedMissingMerchant() {
+	reqBody, _ := json.Marshal(dummyRefundRequest)
+
+	r, _ := suite.serve("/v1/merchant/refunds", reqBody, nil)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Refund", mock.Anything)
+}
+
+func (suite *RefundControllerTestSuite) TestMerchantRefund_FailedUsecase() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	reqBody, _ := json.Marshal(dummyRefundRequest)
+	request := dummyRefundRequest
+	request.MerchantCode = "MRC125"
+	suite.usecaseMock.On("Refund", request).Return(entity.History{}, errors.New("Failed"))
+
+	_, response := suite.serve("/v1/merchant/refunds", reqBody, nil)
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *RefundControllerTestSuite) TestAdminRefund_Success() {
+	reqBody, _ := json.Marshal(dummyRefundRequest)
+	suite.usecaseMock.On("Refund", dummyRefundRequest).Return(entity.History{TransactionId: "Dummy Refund Id"}, nil)
+
+	r, _ := suite.serve("/v1/admin/refunds", reqBody, map[string]string{middleware.AdminKeyHeader: "Dummy Admin Key"})
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *RefundControllerTestSuite) TestAdminRefund_FailedCustomerToken() {
+	reqBody, _ := json.Marshal(dummyRefundRequest)
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.authMock.On("FetchAccessToken", mock.Anything).Return(nil)
+
+	r, _ := suite.serve("/v1/admin/refunds", reqBody, map[string]string{"Authorization": "Bearer " + dummyTokenDetails[0].AccessToken})
+
+	assert.Equal(suite.T(), http.StatusForbidden, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Refund", mock.Anything)
+}
+
+func (suite *RefundControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(refundUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.signatureMock = new(merchantSignatureMock)
+}
+
+func TestRefundControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundControllerTestSuite))
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type RegisterController struct {
+	BaseController
+	router          *gin.RouterGroup
+	registerUsecase usecase.RegisterUsecase
+}
+
+func (r *RegisterController) RegisterHandler(ctx *gin.Context) {
+	var request req.RegisterRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		r.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	profile, err := r.registerUsecase.Register(request)
+
+	if err == nil {
+		r.Success(ctx, profile)
+	} else {
+		r.Failed(ctx, err)
+	}
+}
+
+func NewRegisterController(r *gin.RouterGroup, u usecase.RegisterUsecase) *RegisterController {
+	controller := RegisterController{
+		registerUsecase: u,
+	}
+	r.POST("/register", controller.RegisterHandler)
+	return &controller
+}
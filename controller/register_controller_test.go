@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyRegisterRequest = req.RegisterRequest{
+	Username: "dummyUsername",
+	Password: "dummyPassword1",
+}
+
+type registerUsecaseMock struct {
+	mock.Mock
+}
+
+func (r *registerUsecaseMock) Register(request req.RegisterRequest) (res.CustomerProfile, error) {
+	args := r.Called(request)
+	return args.Get(0).(res.CustomerProfile), args.Error(1)
+}
+
+type RegisterControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *registerUsecaseMock
+}
+
+func (suite *RegisterControllerTestSuite) serve(body []byte) *httptest.ResponseRecorder {
+	NewRegisterController(suite.routerGroupMock, suite.usecaseMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/v1/register", bytes.NewBuffer(body))
+	suite.routerMock.ServeHTTP(r, request)
+	return r
+}
+
+func (suite *RegisterControllerTestSuite) TestRegister_Success() {
+	reqBody, _ := json.Marshal(dummyRegisterRequest)
+	suite.usecaseMock.On("Register", dummyRegisterRequest).Return(res.CustomerProfile{
+		Uuid:     "Dummy Uuid",
+		Username: dummyRegisterRequest.Username,
+		Currency: money.DefaultCurrency,
+	}, nil)
+
+	r := suite.serve(reqBody)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), map[string]interface{}{
+		"uuid":     "Dummy Uuid",
+		"username": dummyRegisterRequest.Username,
+		"balance":  float64(0),
+		"currency": money.DefaultCurrency,
+	}, response.Data)
+}
+
+func (suite *RegisterControllerTestSuite) TestRegister_FailedBindJSON() {
+	r := suite.serve([]byte(`{1}`))
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *RegisterControllerTestSuite) TestRegister_FailedConflict() {
+	reqBody, _ := json.Marshal(dummyRegisterRequest)
+	suite.usecaseMock.On("Register", dummyRegisterRequest).Return(res.CustomerProfile{}, app_error.Conflict("Username already taken"))
+
+	r := suite.serve(reqBody)
+
+	assert.Equal(suite.T(), http.StatusConflict, r.Code)
+}
+
+func (suite *RegisterControllerTestSuite) TestRegister_FailedUsecase() {
+	reqBody, _ := json.Marshal(dummyRegisterRequest)
+	suite.usecaseMock.On("Register", dummyRegisterRequest).Return(res.CustomerProfile{}, errors.New("Failed"))
+
+	r := suite.serve(reqBody)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *RegisterControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(registerUsecaseMock)
+}
+
+func TestRegisterControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(RegisterControllerTestSuite))
+}
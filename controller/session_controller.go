@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	sessionUsecase usecase.SessionUsecase
+	authenticator  authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (s *SessionController) ListHandler(ctx *gin.Context) {
+	accessDetails, ok := s.AccessDetails(ctx, s.authenticator)
+	if !ok {
+		return
+	}
+
+	sessions, err := s.sessionUsecase.ListSessions(accessDetails)
+
+	if err == nil {
+		s.Success(ctx, sessions)
+	} else {
+		s.Failed(ctx, err)
+	}
+}
+
+func (s *SessionController) RevokeHandler(ctx *gin.Context) {
+	accessDetails, ok := s.AccessDetails(ctx, s.authenticator)
+	if !ok {
+		return
+	}
+
+	err := s.sessionUsecase.RevokeSession(accessDetails, ctx.Param("id"))
+
+	if err == nil {
+		s.Success(ctx, nil)
+	} else {
+		s.Failed(ctx, err)
+	}
+}
+
+func (s *SessionController) RevokeOthersHandler(ctx *gin.Context) {
+	accessDetails, ok := s.AccessDetails(ctx, s.authenticator)
+	if !ok {
+		return
+	}
+
+	revoked, err := s.sessionUsecase.RevokeOtherSessions(accessDetails)
+
+	if err == nil {
+		s.Success(ctx, map[string]interface{}{
+			"revoked": revoked,
+		})
+	} else {
+		s.Failed(ctx, err)
+	}
+}
+
+func NewSessionController(r *gin.RouterGroup, u usecase.SessionUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *SessionController {
+	controller := SessionController{
+		sessionUsecase: u,
+		authenticator:  a,
+	}
+	rm := r.Group("/menu", m.RequireToken())
+	rm.GET("/sessions", controller.ListHandler)
+	rm.DELETE("/sessions/:id", controller.RevokeHandler)
+	rm.DELETE("/sessions", controller.RevokeOthersHandler)
+	return &controller
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type sessionUsecaseMock struct {
+	mock.Mock
+}
+
+func (s *sessionUsecaseMock) ListSessions(accessDetails authenticator.AccessDetails) ([]authenticator.Session, error) {
+	args := s.Called(accessDetails)
+	return args.Get(0).([]authenticator.Session), args.Error(1)
+}
+
+func (s *sessionUsecaseMock) RevokeSession(accessDetails authenticator.AccessDetails, sessionId string) error {
+	args := s.Called(accessDetails, sessionId)
+	return args.Error(0)
+}
+
+func (s *sessionUsecaseMock) RevokeOtherSessions(accessDetails authenticator.AccessDetails) (int, error) {
+	args := s.Called(accessDetails)
+	return args.Int(0), args.Error(1)
+}
+
+type SessionControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *sessionUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+}
+
+func (suite *SessionControllerTestSuite) serve(method string, path string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewSessionController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, nil)
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *SessionControllerTestSuite) TestListSessions_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("ListSessions", dummyAccessDetails[0]).Return([]authenticator.Session{{Id: "session-1", Current: true}}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/menu/sessions")
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	sessions := response.Data.([]interface{})
+	assert.Len(suite.T(), sessions, 1)
+	assert.Equal(suite.T(), "session-1", sessions[0].(map[string]interface{})["id"])
+	assert.Equal(suite.T(), true, sessions[0].(map[string]interface{})["current"])
+}
+
+func (suite *SessionControllerTestSuite) TestListSessions_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	_, response := suite.serve(http.MethodGet, "/v1/menu/sessions")
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "ListSessions", mock.Anything)
+}
+
+func (suite *SessionControllerTestSuite) TestRevokeSession_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("RevokeSession", dummyAccessDetails[0], "session-2").Return(nil)
+
+	r, _ := suite.serve(http.MethodDelete, "/v1/menu/sessions/session-2")
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *SessionControllerTestSuite) TestRevokeSession_FailedUsecase() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("RevokeSession", dummyAccessDetails[0], "session-2").Return(errors.New("Failed"))
+
+	_, response := suite.serve(http.MethodDelete, "/v1/menu/sessions/session-2")
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *SessionControllerTestSuite) TestRevokeOtherSessions_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("RevokeOtherSessions", dummyAccessDetails[0]).Return(2, nil)
+
+	r, response := suite.serve(http.MethodDelete, "/v1/menu/sessions")
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), map[string]interface{}{"revoked": float64(2)}, response.Data)
+}
+
+func (suite *SessionControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(sessionUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+}
+
+func TestSessionControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionControllerTestSuite))
+}
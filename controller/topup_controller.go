@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type TopUpController struct {
+	topUpUsecase  usecase.TopUpUsecase
+	authenticator authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (t *TopUpController) CreateHandler(ctx *gin.Context) {
+	var request req.TopUpRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		t.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	accessDetails, ok := t.AccessDetails(ctx, t.authenticator)
+	if !ok {
+		return
+	}
+	request.CustomerUsername = accessDetails.Username
+
+	topUp, err := t.topUpUsecase.Create(request)
+
+	if err == nil {
+		t.Success(ctx, topUp)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func (t *TopUpController) ListHandler(ctx *gin.Context) {
+	accessDetails, ok := t.AccessDetails(ctx, t.authenticator)
+	if !ok {
+		return
+	}
+
+	topUps, err := t.topUpUsecase.List(accessDetails.Username)
+
+	if err == nil {
+		t.Success(ctx, topUps)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func (t *TopUpController) GetHandler(ctx *gin.Context) {
+	accessDetails, ok := t.AccessDetails(ctx, t.authenticator)
+	if !ok {
+		return
+	}
+
+	topUp, err := t.topUpUsecase.Get(accessDetails.Username, ctx.Param("topup_id"))
+
+	if err == nil {
+		t.Success(ctx, topUp)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func (t *TopUpController) CallbackHandler(ctx *gin.Context) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		t.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	topUp, err := t.topUpUsecase.HandleCallback(ctx.Request.Header, body)
+
+	if err == nil {
+		t.Success(ctx, topUp)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func NewTopUpController(r *gin.RouterGroup, u usecase.TopUpUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *TopUpController {
+	controller := TopUpController{
+		topUpUsecase:  u,
+		authenticator: a,
+	}
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
+	rm.POST("/topups", controller.CreateHandler)
+	rh := r.Group("/menu", m.RequireScope(authenticator.ScopeHistoryRead))
+	rh.GET("/topups", controller.ListHandler)
+	rh.GET("/topups/:topup_id", controller.GetHandler)
+	r.POST("/topups/callback", controller.CallbackHandler)
+	return &controller
+}
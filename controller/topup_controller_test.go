@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyTopUp = entity.TopUp{
+	TopUpId:          "topup-1",
+	CustomerUsername: "dummyUsername1",
+	Amount:           money.MustParse("50000"),
+	Currency:         money.DefaultCurrency,
+	Gateway:          "simulator",
+	Status:           entity.TopUpStatusPending,
+}
+
+type topUpUsecaseMock struct {
+	mock.Mock
+}
+
+func (t *topUpUsecaseMock) Create(request req.TopUpRequest) (entity.TopUp, error) {
+	args := t.Called(request)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpUsecaseMock) Get(customerUsername string, topUpId string) (entity.TopUp, error) {
+	args := t.Called(customerUsername, topUpId)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpUsecaseMock) List(customerUsername string) ([]entity.TopUp, error) {
+	args := t.Called(customerUsername)
+	return args.Get(0).([]entity.TopUp), args.Error(1)
+}
+
+func (t *topUpUsecaseMock) HandleCallback(header http.Header, body []byte) (entity.TopUp, error) {
+	args := t.Called(header, body)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpUsecaseMock) Reconcile() (int, error) {
+	args := t.Called()
+	return args.Int(0), args.Error(1)
+}
+
+type TopUpControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *topUpUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+}
+
+func (suite *TopUpControllerTestSuite) serve(method string, path string, body []byte) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewTopUpController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *TopUpControllerTestSuite) TestCreate_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Create", req.TopUpRequest{
+		CustomerUsername: dummyAccessDetails[0].Username,
+		Amount:           money.MustParse("50000"),
+		Scenario:         "delayed",
+	}).Return(dummyTopUp, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/menu/topups", []byte(`{"amount":"50000","scenario":"delayed"}`))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), dummyTopUp.TopUpId, response.Data.(map[string]interface{})["topup_id"])
+}
+
+func (suite *TopUpControllerTestSuite) TestCreate_FailedBindJSON() {
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/topups", []byte(`{1}`))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *TopUpControllerTestSuite) TestList_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	r, _ := suite.serve(http.MethodGet, "/v1/menu/topups", nil)
+
+	assert.NotEqual(suite.T(), http.StatusOK, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "List", mock.Anything)
+}
+
+func (suite *TopUpControllerTestSuite) TestGet_FailedUsecase() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Get", dummyAccessDetails[0].Username, "topup-1").Return(entity.TopUp{}, app_error.DataNotFound("top-up not found"))
+
+	r, _ := suite.serve(http.MethodGet, "/v1/menu/topups/topup-1", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, r.Code)
+}
+
+func (suite *TopUpControllerTestSuite) TestCallback_Success() {
+	succeeded := dummyTopUp
+	succeeded.Status = entity.TopUpStatusSucceeded
+	suite.usecaseMock.On("HandleCallback", mock.Anything, []byte(`{"reference":"sim_1"}`)).Return(succeeded, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/topups/callback", []byte(`{"reference":"sim_1"}`))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), entity.TopUpStatusSucceeded, response.Data.(map[string]interface{})["status"])
+	suite.authMock.AssertNotCalled(suite.T(), "VerifyAccessToken", mock.Anything)
+}
+
+func (suite *TopUpControllerTestSuite) TestCallback_FailedSignature() {
+	suite.usecaseMock.On("HandleCallback", mock.Anything, mock.Anything).Return(entity.TopUp{}, app_error.Unauthorized("Invalid gateway signature"))
+
+	r, _ := suite.serve(http.MethodPost, "/v1/topups/callback", []byte(`{}`))
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+}
+
+func (suite *TopUpControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(topUpUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+}
+
+func TestTopUpControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TopUpControllerTestSuite))
+}
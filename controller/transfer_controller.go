@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferController struct {
+	transferUsecase usecase.TransferUsecase
+	authenticator   authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (t *TransferController) TransferHandler(ctx *gin.Context) {
+	var request req.TransferRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		t.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	accountDetails, ok := t.AccessDetails(ctx, t.authenticator)
+	if !ok {
+		return
+	}
+
+	request.CustomerUsername = accountDetails.Username
+
+	var authorization req.PaymentAuthorization
+	if err := ctx.ShouldBindHeader(&authorization); err != nil {
+		t.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	transfer, err := t.transferUsecase.Transfer(request, authorization)
+
+	if err == nil {
+		t.Success(ctx, transfer)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func NewTransferController(r *gin.RouterGroup, u usecase.TransferUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *TransferController {
+	controller := TransferController{
+		transferUsecase: u,
+		authenticator:   a,
+	}
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
+	rm.POST("/transfer", controller.TransferHandler)
+	return &controller
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type transferUsecaseMock struct {
+	mock.Mock
+}
+
+func (t *transferUsecaseMock) Transfer(request req.TransferRequest, authorization req.PaymentAuthorization) (entity.History, error) {
+	args := t.Called(request, authorization)
+	return args.Get(0).(entity.History), args.Error(1)
+}
+
+type TransferControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *transferUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+}
+
+func (suite *TransferControllerTestSuite) serve(body []byte, headers map[string]string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewTransferController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/v1/menu/transfer", bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *TransferControllerTestSuite) TestTransfer_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Transfer", req.TransferRequest{
+		CustomerUsername:  dummyAccessDetails[0].Username,
+		RecipientUsername: "dummyRecipient",
+		Amount:            money.MustParse("10000"),
+		Note:              "dinner",
+	}, req.PaymentAuthorization{Pin: "123456"}).Return(entity.History{TransactionId: "Dummy Transaction Id", Type: entity.HistoryTypeTransfer}, nil)
+
+	r, response := suite.serve([]byte(`{"recipient_username":"dummyRecipient","amount":"10000","note":"dinner"}`), map[string]string{"X-Transaction-Pin": "123456"})
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "Dummy Transaction Id", response.Data.(map[string]interface{})["transaction_id"])
+}
+
+func (suite *TransferControllerTestSuite) TestTransfer_FailedBindJSON() {
+	r, _ := suite.serve([]byte(`{1}`), nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Transfer", mock.Anything, mock.Anything)
+}
+
+func (suite *TransferControllerTestSuite) TestTransfer_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	r, _ := suite.serve([]byte(`{"recipient_username":"dummyRecipient","amount":"10000"}`), nil)
+
+	assert.NotEqual(suite.T(), http.StatusOK, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Transfer", mock.Anything, mock.Anything)
+}
+
+func (suite *TransferControllerTestSuite) TestTransfer_FailedUsecase() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Transfer", mock.Anything, mock.Anything).Return(entity.History{}, app_error.InvalidError("Balance insufficient"))
+
+	r, _ := suite.serve([]byte(`{"recipient_username":"dummyRecipient","amount":"10000"}`), nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *TransferControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(transferUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+}
+
+func TestTransferControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferControllerTestSuite))
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	twoFactorUsecase usecase.TwoFactorUsecase
+	authenticator    authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (t *TwoFactorController) EnrollHandler(ctx *gin.Context) {
+	accessDetails, ok := t.AccessDetails(ctx, t.authenticator)
+	if !ok {
+		return
+	}
+
+	enrollment, err := t.twoFactorUsecase.Enroll(accessDetails.Username)
+
+	if err == nil {
+		t.Success(ctx, enrollment)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func (t *TwoFactorController) ConfirmHandler(ctx *gin.Context) {
+	accessDetails, ok := t.AccessDetails(ctx, t.authenticator)
+	if !ok {
+		return
+	}
+	var request req.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		t.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	recoveryCodes, err := t.twoFactorUsecase.Confirm(accessDetails.Username, request.Code)
+
+	if err == nil {
+		t.Success(ctx, recoveryCodes)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func (t *TwoFactorController) DisableHandler(ctx *gin.Context) {
+	accessDetails, ok := t.AccessDetails(ctx, t.authenticator)
+	if !ok {
+		return
+	}
+	var request req.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		t.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+
+	err := t.twoFactorUsecase.Disable(accessDetails.Username, request.Code, clientInfo(ctx))
+
+	if err == nil {
+		t.Success(ctx, nil)
+	} else {
+		t.Failed(ctx, err)
+	}
+}
+
+func NewTwoFactorController(r *gin.RouterGroup, u usecase.TwoFactorUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *TwoFactorController {
+	controller := TwoFactorController{
+		twoFactorUsecase: u,
+		authenticator:    a,
+	}
+	rm := r.Group("/menu", m.RequireToken())
+	rm.POST("/2fa/enroll", controller.EnrollHandler)
+	rm.POST("/2fa/confirm", controller.ConfirmHandler)
+	rm.POST("/2fa/disable", controller.DisableHandler)
+	return &controller
+}
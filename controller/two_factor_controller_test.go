@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type twoFactorUsecaseMock struct {
+	mock.Mock
+}
+
+func (t *twoFactorUsecaseMock) Enroll(username string) (res.TwoFactorEnrollment, error) {
+	args := t.Called(username)
+	return args.Get(0).(res.TwoFactorEnrollment), args.Error(1)
+}
+
+func (t *twoFactorUsecaseMock) Confirm(username string, code string) (res.RecoveryCodes, error) {
+	args := t.Called(username, code)
+	return args.Get(0).(res.RecoveryCodes), args.Error(1)
+}
+
+func (t *twoFactorUsecaseMock) Disable(username string, code string, client authenticator.ClientInfo) error {
+	args := t.Called(username, code, client)
+	return args.Error(0)
+}
+
+type TwoFactorControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *twoFactorUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+}
+
+func (suite *TwoFactorControllerTestSuite) serve(path string, body []byte) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewTwoFactorController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *TwoFactorControllerTestSuite) TestEnroll_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Enroll", dummyAccessDetails[0].Username).Return(res.TwoFactorEnrollment{
+		Secret:     "Dummy Secret",
+		OtpauthUri: "otpauth://totp/dummy",
+	}, nil)
+
+	r, response := suite.serve("/v1/menu/2fa/enroll", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), map[string]interface{}{
+		"secret":      "Dummy Secret",
+		"otpauth_uri": "otpauth://totp/dummy",
+	}, response.Data)
+}
+
+func (suite *TwoFactorControllerTestSuite) TestEnroll_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	_, response := suite.serve("/v1/menu/2fa/enroll", nil)
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Enroll", mock.Anything)
+}
+
+func (suite *TwoFactorControllerTestSuite) TestConfirm_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Confirm", dummyAccessDetails[0].Username, "123456").Return(res.RecoveryCodes{RecoveryCodes: []string{"abcd-efgh"}}, nil)
+
+	r, response := suite.serve("/v1/menu/2fa/confirm", []byte(`{"code":"123456"}`))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), map[string]interface{}{"recovery_codes": []interface{}{"abcd-efgh"}}, response.Data)
+}
+
+func (suite *TwoFactorControllerTestSuite) TestConfirm_FailedBindJSON() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+
+	r, _ := suite.serve("/v1/menu/2fa/confirm", []byte(`{1}`))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+}
+
+func (suite *TwoFactorControllerTestSuite) TestDisable_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Disable", dummyAccessDetails[0].Username, "123456", mock.Anything).Return(nil)
+
+	r, _ := suite.serve("/v1/menu/2fa/disable", []byte(`{"code":"123456"}`))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *TwoFactorControllerTestSuite) TestDisable_FailedUsecase() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Disable", dummyAccessDetails[0].Username, "123456", mock.Anything).Return(errors.New("Failed"))
+
+	_, response := suite.serve("/v1/menu/2fa/disable", []byte(`{"code":"123456"}`))
+
+	assert.Equal(suite.T(), "XX", response.Status.Code)
+}
+
+func (suite *TwoFactorControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(twoFactorUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+}
+
+func TestTwoFactorControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorControllerTestSuite))
+}
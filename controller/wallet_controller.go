@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type WalletController struct {
+	BaseController
+	router        *gin.RouterGroup
+	walletUsecase usecase.WalletUsecase
+	authenticator authenticator.AccessToken
+}
+
+func (w *WalletController) OpenHandler(ctx *gin.Context) {
+	var request req.WalletRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		w.Failed(ctx, app_error.InvalidError(err.Error()))
+		return
+	}
+
+	accessDetails, ok := w.AccessDetails(ctx, w.authenticator)
+	if !ok {
+		return
+	}
+	request.CustomerUsername = accessDetails.Username
+
+	wallet, err := w.walletUsecase.Open(request)
+
+	if err == nil {
+		w.Success(ctx, wallet)
+	} else {
+		w.Failed(ctx, err)
+	}
+}
+
+func (w *WalletController) ListHandler(ctx *gin.Context) {
+	accessDetails, ok := w.AccessDetails(ctx, w.authenticator)
+	if !ok {
+		return
+	}
+
+	wallets, err := w.walletUsecase.List(accessDetails.Username)
+
+	if err == nil {
+		w.Success(ctx, wallets)
+	} else {
+		w.Failed(ctx, err)
+	}
+}
+
+func NewWalletController(r *gin.RouterGroup, u usecase.WalletUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware) *WalletController {
+	controller := WalletController{
+		walletUsecase: u,
+		authenticator: a,
+	}
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
+	rm.POST("/wallets", controller.OpenHandler)
+	rb := r.Group("/menu", m.RequireScope(authenticator.ScopeHistoryRead))
+	rb.GET("/wallets", controller.ListHandler)
+	return &controller
+}
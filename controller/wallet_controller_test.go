@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type walletUsecaseMock struct {
+	mock.Mock
+}
+
+func (w *walletUsecaseMock) Open(request req.WalletRequest) (entity.Wallet, error) {
+	args := w.Called(request)
+	return args.Get(0).(entity.Wallet), args.Error(1)
+}
+
+func (w *walletUsecaseMock) List(customerUsername string) ([]res.Wallet, error) {
+	args := w.Called(customerUsername)
+	return args.Get(0).([]res.Wallet), args.Error(1)
+}
+
+type WalletControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *walletUsecaseMock
+	authMock        *authMock
+}
+
+func (suite *WalletControllerTestSuite) serve(method string, path string, body []byte, scopes ...string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	accessDetails := dummyAccessDetails[0]
+	accessDetails.Scopes = scopes
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(accessDetails, nil)
+	suite.authMock.On("FetchAccessToken", accessDetails).Return(nil)
+
+	NewWalletController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, middleware.NewAuthTokenMiddleware(suite.authMock))
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *WalletControllerTestSuite) TestOpen_Success() {
+	suite.usecaseMock.On("Open", req.WalletRequest{CustomerUsername: dummyAccessDetails[0].Username, Currency: "USD"}).
+		Return(entity.Wallet{Username: dummyAccessDetails[0].Username, Currency: "USD"}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/menu/wallets", []byte(`{"currency":"USD"}`), authenticator.ScopePaymentsWrite)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "USD", response.Data.(map[string]interface{})["currency"])
+}
+
+func (suite *WalletControllerTestSuite) TestOpen_FailedBindJSON() {
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/wallets", []byte(`{1}`), authenticator.ScopePaymentsWrite)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Open", mock.Anything)
+}
+
+func (suite *WalletControllerTestSuite) TestOpen_FailedUsecase() {
+	suite.usecaseMock.On("Open", mock.Anything).Return(entity.Wallet{}, app_error.Conflict("Wallet already exists"))
+
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/wallets", []byte(`{"currency":"USD"}`), authenticator.ScopePaymentsWrite)
+
+	assert.Equal(suite.T(), http.StatusConflict, r.Code)
+}
+
+func (suite *WalletControllerTestSuite) TestList_Success() {
+	suite.usecaseMock.On("List", dummyAccessDetails[0].Username).Return([]res.Wallet{
+		{Currency: money.DefaultCurrency, Balance: money.MustParse("100"), Primary: true},
+		{Currency: "USD", Balance: money.MustParse("5")},
+	}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/menu/wallets", nil, authenticator.ScopeHistoryRead)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	suite.Require().Len(response.Data, 2)
+	assert.Equal(suite.T(), true, response.Data.([]interface{})[0].(map[string]interface{})["primary"])
+	assert.Equal(suite.T(), "USD", response.Data.([]interface{})[1].(map[string]interface{})["currency"])
+}
+
+func (suite *WalletControllerTestSuite) TestList_FailedScope() {
+	r, _ := suite.serve(http.MethodGet, "/v1/menu/wallets", nil, authenticator.ScopePaymentsWrite)
+
+	assert.Equal(suite.T(), http.StatusForbidden, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "List", mock.Anything)
+}
+
+func (suite *WalletControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(walletUsecaseMock)
+	suite.authMock = new(authMock)
+}
+
+func TestWalletControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletControllerTestSuite))
+}
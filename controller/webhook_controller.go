@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	BaseController
+	router         *gin.RouterGroup
+	webhookUsecase usecase.WebhookUsecase
+}
+
+func (w *WebhookController) merchantCode(ctx *gin.Context) (string, bool) {
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		w.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return "", false
+	}
+	return merchant.MerchantCode, true
+}
+
+func (w *WebhookController) RegisterHandler(ctx *gin.Context) {
+	var request req.WebhookRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		w.Failed(ctx, app_error.InvalidError("invalid request body"))
+		return
+	}
+	merchantCode, ok := w.merchantCode(ctx)
+	if !ok {
+		return
+	}
+	request.MerchantCode = merchantCode
+
+	webhook, err := w.webhookUsecase.Register(request)
+
+	if err == nil {
+		w.Success(ctx, webhook)
+	} else {
+		w.Failed(ctx, err)
+	}
+}
+
+func (w *WebhookController) ListHandler(ctx *gin.Context) {
+	merchantCode, ok := w.merchantCode(ctx)
+	if !ok {
+		return
+	}
+
+	webhooks, err := w.webhookUsecase.List(merchantCode)
+
+	if err == nil {
+		w.Success(ctx, webhooks)
+	} else {
+		w.Failed(ctx, err)
+	}
+}
+
+func (w *WebhookController) DisableHandler(ctx *gin.Context) {
+	merchantCode, ok := w.merchantCode(ctx)
+	if !ok {
+		return
+	}
+
+	err := w.webhookUsecase.Disable(merchantCode, ctx.Param("webhook_id"))
+
+	if err == nil {
+		w.Success(ctx, nil)
+	} else {
+		w.Failed(ctx, err)
+	}
+}
+
+func (w *WebhookController) DeliveriesHandler(ctx *gin.Context) {
+	merchantCode, ok := w.merchantCode(ctx)
+	if !ok {
+		return
+	}
+
+	deliveries, err := w.webhookUsecase.Deliveries(merchantCode, ctx.Query("status"))
+
+	if err == nil {
+		w.Success(ctx, deliveries)
+	} else {
+		w.Failed(ctx, err)
+	}
+}
+
+func (w *WebhookController) RedeliverHandler(ctx *gin.Context) {
+	merchantCode, ok := w.merchantCode(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := w.webhookUsecase.Redeliver(merchantCode, ctx.Param("delivery_id"))
+
+	if err == nil {
+		w.Success(ctx, delivery)
+	} else {
+		w.Failed(ctx, err)
+	}
+}
+
+func NewWebhookController(r *gin.RouterGroup, u usecase.WebhookUsecase, s middleware.MerchantSignatureMiddleware) *WebhookController {
+	controller := WebhookController{
+		webhookUsecase: u,
+	}
+	rs := r.Group("/merchant/webhooks", s.RequireSignature())
+	rs.POST("", controller.RegisterHandler)
+	rs.GET("", controller.ListHandler)
+	rs.DELETE("/:webhook_id", controller.DisableHandler)
+	rs.GET("/deliveries", controller.DeliveriesHandler)
+	rs.POST("/deliveries/:delivery_id/redeliver", controller.RedeliverHandler)
+	return &controller
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type webhookUsecaseMock struct {
+	mock.Mock
+}
+
+func (w *webhookUsecaseMock) Handle(event entity.OutboxEvent) error {
+	args := w.Called(event)
+	return args.Error(0)
+}
+
+func (w *webhookUsecaseMock) Register(request req.WebhookRequest) (res.WebhookSecret, error) {
+	args := w.Called(request)
+	return args.Get(0).(res.WebhookSecret), args.Error(1)
+}
+
+func (w *webhookUsecaseMock) List(merchantCode string) ([]res.Webhook, error) {
+	args := w.Called(merchantCode)
+	return args.Get(0).([]res.Webhook), args.Error(1)
+}
+
+func (w *webhookUsecaseMock) Disable(merchantCode string, webhookId string) error {
+	args := w.Called(merchantCode, webhookId)
+	return args.Error(0)
+}
+
+func (w *webhookUsecaseMock) Deliveries(merchantCode string, status string) ([]entity.WebhookDelivery, error) {
+	args := w.Called(merchantCode, status)
+	return args.Get(0).([]entity.WebhookDelivery), args.Error(1)
+}
+
+func (w *webhookUsecaseMock) Redeliver(merchantCode string, deliveryId string) (entity.WebhookDelivery, error) {
+	args := w.Called(merchantCode, deliveryId)
+	return args.Get(0).(entity.WebhookDelivery), args.Error(1)
+}
+
+func (w *webhookUsecaseMock) Dispatch() (int, error) {
+	args := w.Called()
+	return args.Int(0), args.Error(1)
+}
+
+type WebhookControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *webhookUsecaseMock
+	signatureMock   *merchantSignatureMock
+}
+
+func (suite *WebhookControllerTestSuite) serve(method string, path string, body []byte) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewWebhookController(suite.routerGroupMock, suite.usecaseMock, suite.signatureMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *WebhookControllerTestSuite) TestRegister_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("Register", req.WebhookRequest{
+		MerchantCode: "MRC125",
+		Url:          "https://merchant.example/hooks",
+		EventTypes:   []string{entity.EventPaymentSucceeded},
+	}).Return(res.WebhookSecret{Webhook: res.Webhook{WebhookId: "wh_dummy", Active: true}, Secret: "whsec_dummy"}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/merchant/webhooks", []byte(`{"url":"https://merchant.example/hooks","event_types":["payment.succeeded"]}`))
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "whsec_dummy", response.Data.(map[string]interface{})["secret"])
+}
+
+func (suite *WebhookControllerTestSuite) TestRegister_FailedBindJSON() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+
+	r, _ := suite.serve(http.MethodPost, "/v1/merchant/webhooks", []byte(`{1}`))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Register", mock.Anything)
+}
+
+func (suite *WebhookControllerTestSuite) TestRegister_FailedMissingMerchant() {
+	r, _ := suite.serve(http.MethodPost, "/v1/merchant/webhooks", []byte(`{"url":"https://merchant.example/hooks"}`))
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Register", mock.Anything)
+}
+
+func (suite *WebhookControllerTestSuite) TestList_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("List", "MRC125").Return([]res.Webhook{{WebhookId: "wh_dummy"}}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/merchant/webhooks", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *WebhookControllerTestSuite) TestDisable_FailedUsecase() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("Disable", "MRC125", "wh_dummy").Return(app_error.DataNotFound("webhook not found"))
+
+	r, _ := suite.serve(http.MethodDelete, "/v1/merchant/webhooks/wh_dummy", nil)
+
+	assert.Equal(suite.T(), http.StatusNotFound, r.Code)
+}
+
+func (suite *WebhookControllerTestSuite) TestDeliveries_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("Deliveries", "MRC125", entity.DeliveryStatusDead).
+		Return([]entity.WebhookDelivery{{DeliveryId: "Dummy Delivery Id", Status: entity.DeliveryStatusDead}}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/merchant/webhooks/deliveries?status=dead", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *WebhookControllerTestSuite) TestRedeliver_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("Redeliver", "MRC125", "Dummy Delivery Id").
+		Return(entity.WebhookDelivery{DeliveryId: "Dummy Delivery Id", Status: entity.DeliveryStatusPending}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/merchant/webhooks/deliveries/Dummy%20Delivery%20Id/redeliver", nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), entity.DeliveryStatusPending, response.Data.(map[string]interface{})["status"])
+}
+
+func (suite *WebhookControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(webhookUsecaseMock)
+	suite.signatureMock = new(merchantSignatureMock)
+}
+
+func TestWebhookControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookControllerTestSuite))
+}
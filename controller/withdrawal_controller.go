@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"github.com/febriansr/simple-payment-api/middleware"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+type ownerResolver func(ctx *gin.Context) (string, string, bool)
+
+type WithdrawalController struct {
+	withdrawalUsecase usecase.WithdrawalUsecase
+	authenticator     authenticator.AccessToken
+	BaseController
+	router *gin.RouterGroup
+}
+
+func (w *WithdrawalController) customer(ctx *gin.Context) (string, string, bool) {
+	accessDetails, ok := w.AccessDetails(ctx, w.authenticator)
+	if !ok {
+		return "", "", false
+	}
+	return entity.OwnerTypeCustomer, accessDetails.Username, true
+}
+
+func (w *WithdrawalController) merchant(ctx *gin.Context) (string, string, bool) {
+	merchant, ok := middleware.Merchant(ctx)
+	if !ok {
+		w.Failed(ctx, app_error.Unauthorized("Missing request signature"))
+		return "", "", false
+	}
+	return entity.OwnerTypeMerchant, merchant.MerchantCode, true
+}
+
+func (w *WithdrawalController) AddBankAccountHandler(owner ownerResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request req.BankAccountRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			w.Failed(ctx, app_error.InvalidError("invalid request body"))
+			return
+		}
+		var ok bool
+		if request.OwnerType, request.Owner, ok = owner(ctx); !ok {
+			return
+		}
+
+		bankAccount, err := w.withdrawalUsecase.AddBankAccount(request)
+
+		if err == nil {
+			w.Success(ctx, bankAccount)
+		} else {
+			w.Failed(ctx, err)
+		}
+	}
+}
+
+func (w *WithdrawalController) ListBankAccountsHandler(owner ownerResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ownerType, ownerId, ok := owner(ctx)
+		if !ok {
+			return
+		}
+
+		bankAccounts, err := w.withdrawalUsecase.ListBankAccounts(ownerType, ownerId)
+
+		if err == nil {
+			w.Success(ctx, bankAccounts)
+		} else {
+			w.Failed(ctx, err)
+		}
+	}
+}
+
+func (w *WithdrawalController) RequestHandler(owner ownerResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request req.WithdrawalRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			w.Failed(ctx, app_error.InvalidError("invalid request body"))
+			return
+		}
+		var authorization req.PaymentAuthorization
+		if err := ctx.ShouldBindHeader(&authorization); err != nil {
+			w.Failed(ctx, app_error.InvalidError(err.Error()))
+			return
+		}
+		var ok bool
+		if request.OwnerType, request.Owner, ok = owner(ctx); !ok {
+			return
+		}
+
+		withdrawal, err := w.withdrawalUsecase.Request(request, authorization)
+
+		if err == nil {
+			w.Success(ctx, withdrawal)
+		} else {
+			w.Failed(ctx, err)
+		}
+	}
+}
+
+func (w *WithdrawalController) ListHandler(owner ownerResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ownerType, ownerId, ok := owner(ctx)
+		if !ok {
+			return
+		}
+
+		withdrawals, err := w.withdrawalUsecase.List(ownerType, ownerId)
+
+		if err == nil {
+			w.Success(ctx, withdrawals)
+		} else {
+			w.Failed(ctx, err)
+		}
+	}
+}
+
+func NewWithdrawalController(r *gin.RouterGroup, u usecase.WithdrawalUsecase, a authenticator.AccessToken, m middleware.AuthTokenMiddleware, s middleware.MerchantSignatureMiddleware) *WithdrawalController {
+	controller := WithdrawalController{
+		withdrawalUsecase: u,
+		authenticator:     a,
+	}
+	rm := r.Group("/menu", m.RequireScope(authenticator.ScopePaymentsWrite))
+	rm.POST("/bank-accounts", controller.AddBankAccountHandler(controller.customer))
+	rm.POST("/withdrawals", controller.RequestHandler(controller.customer))
+	rh := r.Group("/menu", m.RequireScope(authenticator.ScopeHistoryRead))
+	rh.GET("/bank-accounts", controller.ListBankAccountsHandler(controller.customer))
+	rh.GET("/withdrawals", controller.ListHandler(controller.customer))
+	rs := r.Group("/merchant", s.RequireSignature())
+	rs.POST("/bank-accounts", controller.AddBankAccountHandler(controller.merchant))
+	rs.GET("/bank-accounts", controller.ListBankAccountsHandler(controller.merchant))
+	rs.POST("/withdrawals", controller.RequestHandler(controller.merchant))
+	rs.GET("/withdrawals", controller.ListHandler(controller.merchant))
+	return &controller
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type withdrawalUsecaseMock struct {
+	mock.Mock
+}
+
+func (w *withdrawalUsecaseMock) AddBankAccount(request req.BankAccountRequest) (entity.BankAccount, error) {
+	args := w.Called(request)
+	return args.Get(0).(entity.BankAccount), args.Error(1)
+}
+
+func (w *withdrawalUsecaseMock) ListBankAccounts(ownerType string, owner string) ([]entity.BankAccount, error) {
+	args := w.Called(ownerType, owner)
+	return args.Get(0).([]entity.BankAccount), args.Error(1)
+}
+
+func (w *withdrawalUsecaseMock) Request(request req.WithdrawalRequest, authorization req.PaymentAuthorization) (entity.Withdrawal, error) {
+	args := w.Called(request, authorization)
+	return args.Get(0).(entity.Withdrawal), args.Error(1)
+}
+
+func (w *withdrawalUsecaseMock) List(ownerType string, owner string) ([]entity.Withdrawal, error) {
+	args := w.Called(ownerType, owner)
+	return args.Get(0).([]entity.Withdrawal), args.Error(1)
+}
+
+type WithdrawalControllerTestSuite struct {
+	suite.Suite
+	routerMock      *gin.Engine
+	routerGroupMock *gin.RouterGroup
+	usecaseMock     *withdrawalUsecaseMock
+	authMock        *authMock
+	middlewareMock  *middlewareMock
+	signatureMock   *merchantSignatureMock
+}
+
+func (suite *WithdrawalControllerTestSuite) serve(method string, path string, body []byte, headers map[string]string) (*httptest.ResponseRecorder, res.ApiResponse) {
+	NewWithdrawalController(suite.routerGroupMock, suite.usecaseMock, suite.authMock, suite.middlewareMock, suite.signatureMock)
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	request.Header.Set("Authorization", dummyTokenDetails[0].AccessToken)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	suite.routerMock.ServeHTTP(r, request)
+	var response res.ApiResponse
+	json.Unmarshal(r.Body.Bytes(), &response)
+	return r, response
+}
+
+func (suite *WithdrawalControllerTestSuite) TestAddBankAccount_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("AddBankAccount", req.BankAccountRequest{
+		OwnerType:     entity.OwnerTypeCustomer,
+		Owner:         dummyAccessDetails[0].Username,
+		BankCode:      "014",
+		AccountNumber: "1234567890",
+		AccountName:   "Dummy Customer",
+	}).Return(entity.BankAccount{BankAccountId: "Dummy Bank Account Id"}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/menu/bank-accounts", []byte(`{"bank_code":"014","account_number":"1234567890","account_name":"Dummy Customer"}`), nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "Dummy Bank Account Id", response.Data.(map[string]interface{})["bank_account_id"])
+}
+
+func (suite *WithdrawalControllerTestSuite) TestAddBankAccount_FailedBindJSON() {
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/bank-accounts", []byte(`{1}`), nil)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "AddBankAccount", mock.Anything)
+}
+
+func (suite *WithdrawalControllerTestSuite) TestRequest_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("Request", req.WithdrawalRequest{
+		OwnerType:     entity.OwnerTypeCustomer,
+		Owner:         dummyAccessDetails[0].Username,
+		BankAccountId: "Dummy Bank Account Id",
+		Amount:        money.MustParse("50000"),
+	}, req.PaymentAuthorization{Pin: "123456"}).Return(entity.Withdrawal{WithdrawalId: "Dummy Withdrawal Id", Status: entity.WithdrawalStatusPending}, nil)
+
+	r, response := suite.serve(http.MethodPost, "/v1/menu/withdrawals", []byte(`{"bank_account_id":"Dummy Bank Account Id","amount":"50000"}`), map[string]string{"X-Transaction-Pin": "123456"})
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), entity.WithdrawalStatusPending, response.Data.(map[string]interface{})["status"])
+}
+
+func (suite *WithdrawalControllerTestSuite) TestRequest_FailedVerifyAccessToken() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
+
+	r, _ := suite.serve(http.MethodPost, "/v1/menu/withdrawals", []byte(`{"bank_account_id":"Dummy Bank Account Id","amount":"50000"}`), nil)
+
+	assert.NotEqual(suite.T(), http.StatusOK, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "Request", mock.Anything, mock.Anything)
+}
+
+func (suite *WithdrawalControllerTestSuite) TestList_Success() {
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(dummyAccessDetails[0], nil)
+	suite.usecaseMock.On("List", entity.OwnerTypeCustomer, dummyAccessDetails[0].Username).Return([]entity.Withdrawal{{WithdrawalId: "Dummy Withdrawal Id"}}, nil)
+
+	r, response := suite.serve(http.MethodGet, "/v1/menu/withdrawals", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Len(suite.T(), response.Data, 1)
+}
+
+func (suite *WithdrawalControllerTestSuite) TestMerchantRequest_Success() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("Request", req.WithdrawalRequest{
+		OwnerType:     entity.OwnerTypeMerchant,
+		Owner:         "MRC125",
+		BankAccountId: "Dummy Bank Account Id",
+		Amount:        money.MustParse("50000"),
+	}, req.PaymentAuthorization{}).Return(entity.Withdrawal{WithdrawalId: "Dummy Withdrawal Id"}, nil)
+
+	r, _ := suite.serve(http.MethodPost, "/v1/merchant/withdrawals", []byte(`{"bank_account_id":"Dummy Bank Account Id","amount":"50000"}`), nil)
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+}
+
+func (suite *WithdrawalControllerTestSuite) TestMerchantListBankAccounts_FailedMissingMerchant() {
+	r, _ := suite.serve(http.MethodGet, "/v1/merchant/bank-accounts", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+	suite.usecaseMock.AssertNotCalled(suite.T(), "ListBankAccounts", mock.Anything, mock.Anything)
+}
+
+func (suite *WithdrawalControllerTestSuite) TestMerchantListBankAccounts_FailedUsecase() {
+	suite.signatureMock.merchant = &entity.Merchant{MerchantCode: "MRC125"}
+	suite.usecaseMock.On("ListBankAccounts", entity.OwnerTypeMerchant, "MRC125").Return([]entity.BankAccount{}, app_error.InternalServerError("Failed"))
+
+	r, _ := suite.serve(http.MethodGet, "/v1/merchant/bank-accounts", nil, nil)
+
+	assert.Equal(suite.T(), http.StatusInternalServerError, r.Code)
+}
+
+func (suite *WithdrawalControllerTestSuite) SetupTest() {
+	suite.routerMock = gin.Default()
+	suite.routerGroupMock = suite.routerMock.Group("/v1")
+	suite.usecaseMock = new(withdrawalUsecaseMock)
+	suite.authMock = new(authMock)
+	suite.middlewareMock = new(middlewareMock)
+	suite.signatureMock = new(merchantSignatureMock)
+}
+
+func TestWithdrawalControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WithdrawalControllerTestSuite))
+}
@@ -1,56 +1,268 @@
 package delivery
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/febriansr/simple-payment-api/config"
 	"github.com/febriansr/simple-payment-api/controller"
+	"github.com/febriansr/simple-payment-api/eventbus"
+	"github.com/febriansr/simple-payment-api/gateway"
 	"github.com/febriansr/simple-payment-api/manager"
 	"github.com/febriansr/simple-payment-api/middleware"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/idempotency"
+	"github.com/febriansr/simple-payment-api/utils/loginchallenge"
+	"github.com/febriansr/simple-payment-api/utils/loginguard"
+	"github.com/febriansr/simple-payment-api/utils/pinguard"
+	"github.com/febriansr/simple-payment-api/utils/replayguard"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 )
 
 type AppServer struct {
-	usecaseManager manager.UsecaseManager
-	authenticator  authenticator.AccessToken
-	engine         *gin.Engine
-	host           string
+	repositoryManager  manager.RepositoryManager
+	usecaseManager     manager.UsecaseManager
+	eventBus           eventbus.EventBus
+	auditLog           *os.File
+	authenticator      authenticator.AccessToken
+	keys               authenticator.KeySet
+	idempotency        idempotency.Idempotency
+	adminApiKey        string
+	holdExpiryInterval time.Duration
+	webhookInterval    time.Duration
+	reconcileInterval  time.Duration
+	shutdownTimeout    time.Duration
+	engine             *gin.Engine
+	host               string
 }
 
 func (p *AppServer) menu() {
+	p.jwksController(&p.engine.RouterGroup)
 	routes := p.engine.Group("/v1")
 	routes.Use(middleware.LoggingMiddleware(".log"))
 	middleware := middleware.NewAuthTokenMiddleware(p.authenticator)
+	p.registerController(routes)
 	p.loginController(routes)
+	p.refreshController(routes)
 	p.logoutController(routes)
 	p.paymentController(routes, p.authenticator, middleware)
+	p.historyController(routes, p.authenticator, middleware)
+	p.refundController(routes, middleware)
+	p.sessionController(routes, p.authenticator, middleware)
+	p.twoFactorController(routes, p.authenticator, middleware)
+	p.pinController(routes, p.authenticator, middleware)
+	p.adminController(routes, middleware)
+	p.merchantKeyController(routes, middleware)
+	p.merchantController(routes)
+	p.paymentRequestController(routes, p.authenticator, middleware)
+	p.holdController(routes, p.authenticator, middleware)
+	p.webhookController(routes)
+	p.topUpController(routes, p.authenticator, middleware)
+	p.transferController(routes, p.authenticator, middleware)
+	p.withdrawalController(routes, p.authenticator, middleware)
+	p.payoutController(routes, middleware)
+	p.feeController(routes, middleware)
+	p.walletController(routes, p.authenticator, middleware)
+	p.fxController(routes, middleware)
+}
+
+func (p *AppServer) jwksController(rg *gin.RouterGroup) {
+	controller.NewJwksController(rg, p.keys)
+}
+
+func (p *AppServer) registerController(rg *gin.RouterGroup) {
+	controller.NewRegisterController(rg, p.usecaseManager.RegisterUsecase())
 }
 
 func (p *AppServer) loginController(rg *gin.RouterGroup) {
 	controller.NewLoginController(rg, p.usecaseManager.LoginUsecase())
 }
 
+func (p *AppServer) refreshController(rg *gin.RouterGroup) {
+	controller.NewRefreshController(rg, p.usecaseManager.RefreshUsecase())
+}
+
 func (p *AppServer) logoutController(rg *gin.RouterGroup) {
 	controller.NewLogoutController(rg, p.usecaseManager.LogoutUsecase())
 }
 
 func (p *AppServer) paymentController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
-	controller.NewPaymentController(rg, p.usecaseManager.PaymentUsecase(), authenticator, middleware)
+	controller.NewPaymentController(rg, p.usecaseManager.PaymentUsecase(), authenticator, p.idempotency, middleware)
+}
+
+func (p *AppServer) historyController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
+	controller.NewHistoryController(rg, p.usecaseManager.HistoryUsecase(), authenticator, middleware)
+}
+
+func (p *AppServer) refundController(rg *gin.RouterGroup, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewRefundController(rg, p.usecaseManager.RefundUsecase(), middleware.NewAdminKeyMiddleware(p.adminApiKey), authMiddleware, middleware.NewMerchantSignatureMiddleware(p.usecaseManager.MerchantKeyUsecase()))
+}
+
+func (p *AppServer) sessionController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
+	controller.NewSessionController(rg, p.usecaseManager.SessionUsecase(), authenticator, middleware)
+}
+
+func (p *AppServer) twoFactorController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
+	controller.NewTwoFactorController(rg, p.usecaseManager.TwoFactorUsecase(), authenticator, middleware)
+}
+
+func (p *AppServer) pinController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
+	controller.NewPinController(rg, p.usecaseManager.PinUsecase(), authenticator, middleware)
+}
+
+func (p *AppServer) adminController(rg *gin.RouterGroup, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewAdminController(rg, p.usecaseManager.AdminUsecase(), middleware.NewAdminKeyMiddleware(p.adminApiKey), authMiddleware)
+}
+
+func (p *AppServer) merchantKeyController(rg *gin.RouterGroup, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewMerchantKeyController(rg, p.usecaseManager.MerchantKeyUsecase(), middleware.NewAdminKeyMiddleware(p.adminApiKey), authMiddleware)
+}
+
+func (p *AppServer) merchantController(rg *gin.RouterGroup) {
+	controller.NewMerchantController(rg, middleware.NewMerchantSignatureMiddleware(p.usecaseManager.MerchantKeyUsecase()))
+}
+
+func (p *AppServer) paymentRequestController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewPaymentRequestController(rg, p.usecaseManager.PaymentRequestUsecase(), authenticator, authMiddleware, middleware.NewMerchantSignatureMiddleware(p.usecaseManager.MerchantKeyUsecase()))
+}
+
+func (p *AppServer) holdController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewHoldController(rg, p.usecaseManager.HoldUsecase(), authenticator, authMiddleware, middleware.NewMerchantSignatureMiddleware(p.usecaseManager.MerchantKeyUsecase()))
+}
+
+func (p *AppServer) webhookController(rg *gin.RouterGroup) {
+	controller.NewWebhookController(rg, p.usecaseManager.WebhookUsecase(), middleware.NewMerchantSignatureMiddleware(p.usecaseManager.MerchantKeyUsecase()))
+}
+
+func (p *AppServer) topUpController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
+	controller.NewTopUpController(rg, p.usecaseManager.TopUpUsecase(), authenticator, middleware)
+}
+
+func (p *AppServer) transferController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
+	controller.NewTransferController(rg, p.usecaseManager.TransferUsecase(), authenticator, middleware)
+}
+
+func (p *AppServer) withdrawalController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewWithdrawalController(rg, p.usecaseManager.WithdrawalUsecase(), authenticator, authMiddleware, middleware.NewMerchantSignatureMiddleware(p.usecaseManager.MerchantKeyUsecase()))
+}
+
+func (p *AppServer) payoutController(rg *gin.RouterGroup, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewPayoutController(rg, p.usecaseManager.PayoutUsecase(), middleware.NewAdminKeyMiddleware(p.adminApiKey), authMiddleware)
+}
+
+func (p *AppServer) feeController(rg *gin.RouterGroup, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewFeeController(rg, p.usecaseManager.FeeUsecase(), middleware.NewAdminKeyMiddleware(p.adminApiKey), authMiddleware)
+}
+
+func (p *AppServer) walletController(rg *gin.RouterGroup, authenticator authenticator.AccessToken, middleware middleware.AuthTokenMiddleware) {
+	controller.NewWalletController(rg, p.usecaseManager.WalletUsecase(), authenticator, middleware)
+}
+
+func (p *AppServer) fxController(rg *gin.RouterGroup, authMiddleware middleware.AuthTokenMiddleware) {
+	controller.NewFxController(rg, p.usecaseManager.FxUsecase(), middleware.NewAdminKeyMiddleware(p.adminApiKey), authMiddleware, middleware.NewMerchantSignatureMiddleware(p.usecaseManager.MerchantKeyUsecase()))
+}
+
+func (p *AppServer) every(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
+
+func (p *AppServer) expireHolds() {
+	expired, err := p.usecaseManager.HoldUsecase().ExpireStale()
+	if err != nil {
+		log.Println("Failed to expire holds:", err)
+	} else if expired > 0 {
+		log.Println("Expired holds:", expired)
+	}
+}
+
+func (p *AppServer) dispatchWebhooks() {
+	if _, err := p.usecaseManager.WebhookUsecase().Dispatch(); err != nil {
+		log.Println("Failed to dispatch webhooks:", err)
+	}
+}
+
+func (p *AppServer) reconcileTopUps() {
+	resolved, err := p.usecaseManager.TopUpUsecase().Reconcile()
+	if err != nil {
+		log.Println("Failed to reconcile top-ups:", err)
+	} else if resolved > 0 {
+		log.Println("Reconciled top-ups:", resolved)
+	}
 }
 
 func (p *AppServer) Run() {
 	p.menu()
-	err := p.engine.Run(p.host)
-	defer func() {
-		if err := recover(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	workers, cancelWorkers := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		p.every(workers, p.holdExpiryInterval, p.expireHolds)
+	}()
+	go func() {
+		defer wg.Done()
+		p.every(workers, p.webhookInterval, p.dispatchWebhooks)
+	}()
+	go func() {
+		defer wg.Done()
+		p.every(workers, p.reconcileInterval, p.reconcileTopUps)
+	}()
+	go func() {
+		defer wg.Done()
+		p.eventBus.Run(workers)
+	}()
+
+	srv := &http.Server{Addr: p.host, Handler: p.engine}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
 			log.Println("Application failed to run", err)
 		}
-	}()
-	if err != nil {
-		log.Fatal(err)
+	case <-ctx.Done():
+		log.Println("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), p.shutdownTimeout)
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Println("Failed to shut down server:", err)
+		}
+		cancel()
+	}
+
+	cancelWorkers()
+	wg.Wait()
+	if _, err := p.eventBus.Poll(); err != nil {
+		log.Println("Failed to deliver events:", err)
+	}
+	if err := p.repositoryManager.Close(); err != nil {
+		log.Println("Failed to close storage:", err)
+	}
+	if err := p.auditLog.Close(); err != nil {
+		log.Println("Failed to close audit log:", err)
 	}
 }
 
@@ -62,14 +274,72 @@ func Server() *AppServer {
 		Password: config.RedisConfig.Password,
 		DB:       config.RedisConfig.Db,
 	})
-	authenticator := authenticator.NewAccessToken(config.TokenConfig, client)
-	repositoryManager := manager.NewRepositoryManager(config.JsonFileConfig, authenticator)
-	usecaseManager := manager.NewUsecaseManager(repositoryManager, authenticator)
+	keys, err := authenticator.NewKeySet(config.TokenConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authenticator := authenticator.NewAccessToken(config.TokenConfig, keys, client)
+	repositoryManager, err := manager.NewRepositoryManager(config.StorageConfig, authenticator)
+	if err != nil {
+		log.Fatal(err)
+	}
 	host := fmt.Sprintf("%s:%s", config.ServerHost, config.ServerPort)
+	gatewayConfig := config.GatewayConfig
+	if gatewayConfig.CallbackUrl == "" {
+		gatewayConfig.CallbackUrl = "http://" + host + "/v1/topups/callback"
+	}
+	usecaseManager := manager.NewUsecaseManager(repositoryManager, authenticator, config.SecurityConfig, config.HoldConfig, config.WebhookConfig,
+		config.TopUpConfig, gateway.NewSimulator(gatewayConfig), config.PayoutConfig,
+		loginguard.NewLoginGuard(config.SecurityConfig, client), loginchallenge.NewLoginChallenge(config.SecurityConfig, client),
+		pinguard.NewPinGuard(config.SecurityConfig, client), replayguard.NewReplayGuard(config.SecurityConfig, client))
+	if config.FxConfig.RatesFile != "" {
+		loaded, err := usecaseManager.FxUsecase().LoadFile(config.FxConfig.RatesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Loaded %d fx rates from %s", loaded, config.FxConfig.RatesFile)
+	}
+	holdExpiryInterval := config.HoldConfig.ExpiryInterval
+	if holdExpiryInterval <= 0 {
+		holdExpiryInterval = time.Minute
+	}
+	webhookInterval := config.WebhookConfig.DispatchInterval
+	if webhookInterval <= 0 {
+		webhookInterval = 5 * time.Second
+	}
+	auditLogFile := config.EventConfig.AuditLogFile
+	if auditLogFile == "" {
+		auditLogFile = ".audit.log"
+	}
+	auditLog, err := os.OpenFile(auditLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventBus := eventbus.NewEventBus(repositoryManager.EventRepository(), config.EventConfig.PollInterval)
+	eventBus.Subscribe("audit", eventbus.AuditLog(auditLog))
+	eventBus.Subscribe("webhooks", usecaseManager.WebhookUsecase().Handle)
+	shutdownTimeout := config.ApiConfig.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 10 * time.Second
+	}
+	reconcileInterval := config.TopUpConfig.ReconcileInterval
+	if reconcileInterval <= 0 {
+		reconcileInterval = time.Minute
+	}
 	return &AppServer{
-		usecaseManager: usecaseManager,
-		engine:         router,
-		host:           host,
-		authenticator:  authenticator,
+		repositoryManager:  repositoryManager,
+		usecaseManager:     usecaseManager,
+		eventBus:           eventBus,
+		auditLog:           auditLog,
+		engine:             router,
+		host:               host,
+		authenticator:      authenticator,
+		keys:               keys,
+		idempotency:        idempotency.NewIdempotency(config.IdempotencyConfig, client),
+		adminApiKey:        config.AdminApiKey,
+		holdExpiryInterval: holdExpiryInterval,
+		webhookInterval:    webhookInterval,
+		reconcileInterval:  reconcileInterval,
+		shutdownTimeout:    shutdownTimeout,
 	}
 }
@@ -0,0 +1,23 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+func AuditLog(w io.Writer) Handler {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(event entity.OutboxEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := encoder.Encode(event); err != nil {
+			return app_error.InternalServerError("Failed to write audit log: " + err.Error())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+const (
+	DefaultPollInterval = time.Second
+	defaultBatchSize    = 100
+)
+
+type Handler func(event entity.OutboxEvent) error
+
+type EventBus interface {
+	Subscribe(consumer string, handler Handler)
+	Poll() (int, error)
+	Run(ctx context.Context)
+}
+
+type subscription struct {
+	consumer string
+	handler  Handler
+}
+
+type eventBus struct {
+	eventRepository repository.EventRepository
+	interval        time.Duration
+	mu              sync.Mutex
+	subscriptions   []subscription
+}
+
+func (e *eventBus) Subscribe(consumer string, handler Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.subscriptions = append(e.subscriptions, subscription{consumer: consumer, handler: handler})
+}
+
+func (e *eventBus) Poll() (int, error) {
+	e.mu.Lock()
+	subscriptions := append([]subscription(nil), e.subscriptions...)
+	e.mu.Unlock()
+
+	var handled int
+	var firstErr error
+	for _, subscription := range subscriptions {
+		count, err := e.deliver(subscription)
+		handled += count
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return handled, firstErr
+}
+
+func (e *eventBus) deliver(subscription subscription) (int, error) {
+	checkpoint, err := e.eventRepository.Checkpoint(subscription.consumer)
+	if err != nil {
+		return 0, err
+	}
+	events, err := e.eventRepository.FindAfter(checkpoint, defaultBatchSize)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	var handled int
+	var handlerErr error
+	for _, event := range events {
+		if handlerErr = subscription.handler(event); handlerErr != nil {
+			break
+		}
+		checkpoint = event.Sequence
+		handled++
+	}
+	if handled > 0 {
+		if err = e.eventRepository.SaveCheckpoint(subscription.consumer, checkpoint); err != nil {
+			return handled, err
+		}
+	}
+	return handled, handlerErr
+}
+
+func (e *eventBus) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.Poll(); err != nil {
+				log.Println("Failed to deliver events:", err)
+			}
+		}
+	}
+}
+
+func NewEventBus(eventRepository repository.EventRepository, interval time.Duration) EventBus {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &eventBus{
+		eventRepository: eventRepository,
+		interval:        interval,
+	}
+}
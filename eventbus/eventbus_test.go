@@ -0,0 +1,146 @@
+package eventbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EventBusTestSuite struct {
+	suite.Suite
+	storage         storage.Storage
+	eventRepository repository.EventRepository
+}
+
+func (suite *EventBusTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.eventRepository = repository.NewEventRepository(suite.storage)
+}
+
+func (suite *EventBusTestSuite) append(eventIds ...string) {
+	for _, eventId := range eventIds {
+		_, err := suite.storage.Outbox().Append(entity.Event{
+			EventId:      eventId,
+			Type:         entity.EventPaymentSucceeded,
+			MerchantCode: "MRC125",
+			CreatedAt:    time.Now().UTC(),
+			Data:         json.RawMessage(`{}`),
+		})
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *EventBusTestSuite) TestPoll_DeliversInOrderOnce() {
+	bus := NewEventBus(suite.eventRepository, 0)
+	var received []string
+	bus.Subscribe("audit", func(event entity.OutboxEvent) error {
+		received = append(received, event.EventId)
+		return nil
+	})
+	suite.append("evt_1", "evt_2", "evt_3")
+
+	handled, err := bus.Poll()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 3, handled)
+	handled, err = bus.Poll()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, handled)
+
+	assert.Equal(suite.T(), []string{"evt_1", "evt_2", "evt_3"}, received)
+	checkpoint, err := suite.eventRepository.Checkpoint("audit")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(3), checkpoint)
+}
+
+func (suite *EventBusTestSuite) TestPoll_RetriesFailedEvent() {
+	bus := NewEventBus(suite.eventRepository, 0)
+	var received []string
+	failing := true
+	bus.Subscribe("webhooks", func(event entity.OutboxEvent) error {
+		if event.EventId == "evt_2" && failing {
+			return errors.New("handler unavailable")
+		}
+		received = append(received, event.EventId)
+		return nil
+	})
+	suite.append("evt_1", "evt_2", "evt_3")
+
+	handled, err := bus.Poll()
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), 1, handled)
+	checkpoint, err := suite.eventRepository.Checkpoint("webhooks")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(1), checkpoint)
+
+	failing = false
+	handled, err = bus.Poll()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, handled)
+	assert.Equal(suite.T(), []string{"evt_1", "evt_2", "evt_3"}, received)
+}
+
+func (suite *EventBusTestSuite) TestPoll_IndependentConsumers() {
+	bus := NewEventBus(suite.eventRepository, 0)
+	suite.Require().NoError(suite.eventRepository.SaveCheckpoint("webhooks", 2))
+	var audited, webhooks int
+	bus.Subscribe("audit", func(event entity.OutboxEvent) error {
+		audited++
+		return nil
+	})
+	bus.Subscribe("webhooks", func(event entity.OutboxEvent) error {
+		webhooks++
+		return nil
+	})
+	suite.append("evt_1", "evt_2", "evt_3")
+
+	handled, err := bus.Poll()
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 4, handled)
+	assert.Equal(suite.T(), 3, audited)
+	assert.Equal(suite.T(), 1, webhooks)
+}
+
+func (suite *EventBusTestSuite) TestAuditLog() {
+	var buffer bytes.Buffer
+	bus := NewEventBus(suite.eventRepository, 0)
+	bus.Subscribe("audit", AuditLog(&buffer))
+	suite.append("evt_1", "evt_2")
+
+	_, err := bus.Poll()
+
+	suite.Require().NoError(err)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	suite.Require().Len(lines, 2)
+	var event entity.OutboxEvent
+	suite.Require().NoError(json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(suite.T(), int64(2), event.Sequence)
+	assert.Equal(suite.T(), "evt_2", event.EventId)
+}
+
+func TestEventBusTestSuite(t *testing.T) {
+	suite.Run(t, new(EventBusTestSuite))
+}
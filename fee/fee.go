@@ -0,0 +1,145 @@
+package fee
+
+import (
+	"errors"
+	"fmt"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const MaxPercentageBps = 10000
+
+var ErrInvalidRule = errors.New("invalid fee rule")
+
+func Validate(rule entity.FeeRule) error {
+	if (rule.MerchantCode == "") == (rule.Category == "") {
+		return fmt.Errorf("%w: exactly one of merchant code or category is required", ErrInvalidRule)
+	}
+	if rule.ChargedTo != entity.FeeChargedToCustomer && rule.ChargedTo != entity.FeeChargedToMerchant {
+		return fmt.Errorf("%w: charged to must be customer or merchant", ErrInvalidRule)
+	}
+	if rule.MinFee < 0 || rule.MaxFee < 0 {
+		return fmt.Errorf("%w: fee caps must not be negative", ErrInvalidRule)
+	}
+	if rule.MaxFee > 0 && rule.MinFee > rule.MaxFee {
+		return fmt.Errorf("%w: min fee is greater than max fee", ErrInvalidRule)
+	}
+	switch rule.Type {
+	case entity.FeeTypeFixed:
+		if rule.PercentageBps != 0 || len(rule.Tiers) > 0 {
+			return fmt.Errorf("%w: fixed rules only take a fixed amount", ErrInvalidRule)
+		}
+		return validateCharge(rule.FixedAmount, 0)
+	case entity.FeeTypePercentage:
+		if len(rule.Tiers) > 0 {
+			return fmt.Errorf("%w: percentage rules do not take tiers", ErrInvalidRule)
+		}
+		return validateCharge(rule.FixedAmount, rule.PercentageBps)
+	case entity.FeeTypeTiered:
+		return validateTiers(rule)
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidRule, rule.Type)
+	}
+}
+
+func validateTiers(rule entity.FeeRule) error {
+	if rule.FixedAmount != 0 || rule.PercentageBps != 0 {
+		return fmt.Errorf("%w: tiered rules take their amounts from the tiers", ErrInvalidRule)
+	}
+	if len(rule.Tiers) == 0 {
+		return fmt.Errorf("%w: tiered rules need at least one tier", ErrInvalidRule)
+	}
+	var previous money.Amount
+	for i, tier := range rule.Tiers {
+		if err := validateCharge(tier.FixedAmount, tier.PercentageBps); err != nil {
+			return fmt.Errorf("%w (tier %d)", err, i+1)
+		}
+		if tier.UpTo == nil {
+			if i != len(rule.Tiers)-1 {
+				return fmt.Errorf("%w: only the last tier may be unbounded", ErrInvalidRule)
+			}
+			continue
+		}
+		if *tier.UpTo <= previous {
+			return fmt.Errorf("%w: tier bounds must be positive and increasing", ErrInvalidRule)
+		}
+		previous = *tier.UpTo
+	}
+	return nil
+}
+
+func validateCharge(fixedAmount money.Amount, percentageBps int64) error {
+	if fixedAmount < 0 {
+		return fmt.Errorf("%w: fixed amount must not be negative", ErrInvalidRule)
+	}
+	if percentageBps < 0 || percentageBps > MaxPercentageBps {
+		return fmt.Errorf("%w: percentage must be between 0 and %d basis points", ErrInvalidRule, MaxPercentageBps)
+	}
+	return nil
+}
+
+// Select picks the merchant's rule for a payment in the given currency,
+// preferring a merchant rule over a category rule. Rules in other currencies
+// never apply, so a fixed amount is not charged in a currency it was not
+// set in.
+func Select(rules []entity.FeeRule, merchant entity.Merchant, currency string) (entity.FeeRule, bool) {
+	var categoryRule *entity.FeeRule
+	for i, rule := range rules {
+		if !rule.Active() || rule.CurrencyOrDefault() != currency {
+			continue
+		}
+		if rule.MerchantCode != "" && rule.MerchantCode == merchant.MerchantCode {
+			return rule, true
+		}
+		if categoryRule == nil && rule.Category != "" && rule.Category == merchant.Category {
+			categoryRule = &rules[i]
+		}
+	}
+	if categoryRule != nil {
+		return *categoryRule, true
+	}
+	return entity.FeeRule{}, false
+}
+
+func Calculate(rule entity.FeeRule, amount money.Amount) money.Amount {
+	var fee money.Amount
+	switch rule.Type {
+	case entity.FeeTypeFixed:
+		fee = rule.FixedAmount
+	case entity.FeeTypePercentage:
+		fee = rule.FixedAmount + percentage(amount, rule.PercentageBps)
+	case entity.FeeTypeTiered:
+		tier := selectTier(rule.Tiers, amount)
+		fee = tier.FixedAmount + percentage(amount, tier.PercentageBps)
+	}
+	if fee < rule.MinFee {
+		fee = rule.MinFee
+	}
+	if rule.MaxFee > 0 && fee > rule.MaxFee {
+		fee = rule.MaxFee
+	}
+	if rule.ChargedTo == entity.FeeChargedToMerchant && fee > amount {
+		fee = amount
+	}
+	return fee
+}
+
+func selectTier(tiers []entity.FeeTier, amount money.Amount) entity.FeeTier {
+	for _, tier := range tiers {
+		if tier.UpTo == nil || amount <= *tier.UpTo {
+			return tier
+		}
+	}
+	if len(tiers) == 0 {
+		return entity.FeeTier{}
+	}
+	return tiers[len(tiers)-1]
+}
+
+func percentage(amount money.Amount, percentageBps int64) money.Amount {
+	minor := amount.Minor()
+	whole := minor / MaxPercentageBps * percentageBps
+	rest := (minor%MaxPercentageBps*percentageBps + MaxPercentageBps/2) / MaxPercentageBps
+	return money.FromMinor(whole + rest)
+}
@@ -0,0 +1,149 @@
+package fee
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FeeTestSuite struct {
+	suite.Suite
+}
+
+func bound(value string) *money.Amount {
+	amount := money.MustParse(value)
+	return &amount
+}
+
+func (suite *FeeTestSuite) TestCalculate_Fixed() {
+	rule := entity.FeeRule{Type: entity.FeeTypeFixed, FixedAmount: money.MustParse("2500"), ChargedTo: entity.FeeChargedToCustomer}
+
+	assert.Equal(suite.T(), money.MustParse("2500"), Calculate(rule, money.MustParse("10")))
+	assert.Equal(suite.T(), money.MustParse("2500"), Calculate(rule, money.MustParse("1000000")))
+}
+
+func (suite *FeeTestSuite) TestCalculate_Percentage() {
+	rule := entity.FeeRule{Type: entity.FeeTypePercentage, PercentageBps: 150, ChargedTo: entity.FeeChargedToCustomer}
+
+	assert.Equal(suite.T(), money.MustParse("1500"), Calculate(rule, money.MustParse("100000")))
+	assert.Equal(suite.T(), money.MustParse("0.02"), Calculate(rule, money.MustParse("1")))
+	assert.Equal(suite.T(), money.MustParse("0.01"), Calculate(rule, money.MustParse("0.50")))
+
+	rule.FixedAmount = money.MustParse("100")
+	assert.Equal(suite.T(), money.MustParse("1600"), Calculate(rule, money.MustParse("100000")))
+}
+
+func (suite *FeeTestSuite) TestCalculate_Caps() {
+	rule := entity.FeeRule{
+		Type:          entity.FeeTypePercentage,
+		PercentageBps: 100,
+		MinFee:        money.MustParse("1000"),
+		MaxFee:        money.MustParse("5000"),
+		ChargedTo:     entity.FeeChargedToCustomer,
+	}
+
+	assert.Equal(suite.T(), money.MustParse("1000"), Calculate(rule, money.MustParse("20000")))
+	assert.Equal(suite.T(), money.MustParse("3000"), Calculate(rule, money.MustParse("300000")))
+	assert.Equal(suite.T(), money.MustParse("5000"), Calculate(rule, money.MustParse("10000000")))
+}
+
+func (suite *FeeTestSuite) TestCalculate_Tiered() {
+	rule := entity.FeeRule{
+		Type: entity.FeeTypeTiered,
+		Tiers: []entity.FeeTier{
+			{UpTo: bound("100000"), FixedAmount: money.MustParse("1000")},
+			{UpTo: bound("1000000"), PercentageBps: 100},
+			{FixedAmount: money.MustParse("5000"), PercentageBps: 50},
+		},
+		ChargedTo: entity.FeeChargedToCustomer,
+	}
+
+	assert.Equal(suite.T(), money.MustParse("1000"), Calculate(rule, money.MustParse("100000")))
+	assert.Equal(suite.T(), money.MustParse("5000"), Calculate(rule, money.MustParse("500000")))
+	assert.Equal(suite.T(), money.MustParse("15000"), Calculate(rule, money.MustParse("2000000")))
+}
+
+func (suite *FeeTestSuite) TestCalculate_MerchantFeeNeverExceedsAmount() {
+	rule := entity.FeeRule{Type: entity.FeeTypeFixed, FixedAmount: money.MustParse("2500"), ChargedTo: entity.FeeChargedToMerchant}
+
+	assert.Equal(suite.T(), money.MustParse("1000"), Calculate(rule, money.MustParse("1000")))
+}
+
+func (suite *FeeTestSuite) TestSelect() {
+	disabledAt := time.Now()
+	rules := []entity.FeeRule{
+		{RuleId: "category", Category: "food"},
+		{RuleId: "disabled", MerchantCode: "MRC125", DisabledAt: &disabledAt},
+		{RuleId: "merchant", MerchantCode: "MRC125"},
+	}
+
+	rule, found := Select(rules, entity.Merchant{MerchantCode: "MRC125", Category: "food"}, money.DefaultCurrency)
+	assert.True(suite.T(), found)
+	assert.Equal(suite.T(), "merchant", rule.RuleId)
+
+	rule, found = Select(rules, entity.Merchant{MerchantCode: "MRC226", Category: "food"}, money.DefaultCurrency)
+	assert.True(suite.T(), found)
+	assert.Equal(suite.T(), "category", rule.RuleId)
+
+	_, found = Select(rules, entity.Merchant{MerchantCode: "MRC920"}, money.DefaultCurrency)
+	assert.False(suite.T(), found)
+}
+
+func (suite *FeeTestSuite) TestSelect_MatchesCurrency() {
+	rules := []entity.FeeRule{
+		{RuleId: "idr", MerchantCode: "MRC125", Currency: "IDR"},
+		{RuleId: "usd", Category: "food", Currency: "USD"},
+	}
+	merchant := entity.Merchant{MerchantCode: "MRC125", Category: "food"}
+
+	rule, found := Select(rules, merchant, "USD")
+	assert.True(suite.T(), found)
+	assert.Equal(suite.T(), "usd", rule.RuleId)
+
+	_, found = Select(rules, merchant, "SGD")
+	assert.False(suite.T(), found)
+}
+
+func (suite *FeeTestSuite) TestValidate() {
+	valid := entity.FeeRule{
+		MerchantCode: "MRC125",
+		Type:         entity.FeeTypeTiered,
+		Tiers: []entity.FeeTier{
+			{UpTo: bound("100000"), FixedAmount: money.MustParse("1000")},
+			{PercentageBps: 100},
+		},
+		ChargedTo: entity.FeeChargedToMerchant,
+	}
+	assert.NoError(suite.T(), Validate(valid))
+
+	invalid := []func(rule *entity.FeeRule){
+		func(rule *entity.FeeRule) { rule.Category = "food" },
+		func(rule *entity.FeeRule) { rule.MerchantCode = "" },
+		func(rule *entity.FeeRule) { rule.ChargedTo = "bank" },
+		func(rule *entity.FeeRule) { rule.Type = "flat" },
+		func(rule *entity.FeeRule) { rule.MinFee, rule.MaxFee = money.MustParse("10"), money.MustParse("5") },
+		func(rule *entity.FeeRule) { rule.Tiers = nil },
+		func(rule *entity.FeeRule) { rule.Tiers = []entity.FeeTier{{}, {UpTo: bound("10")}} },
+		func(rule *entity.FeeRule) { rule.Tiers = []entity.FeeTier{{UpTo: bound("10")}, {UpTo: bound("10")}} },
+		func(rule *entity.FeeRule) { rule.Tiers = []entity.FeeTier{{PercentageBps: 10001}} },
+		func(rule *entity.FeeRule) {
+			rule.Type, rule.Tiers = entity.FeeTypePercentage, nil
+			rule.PercentageBps = -1
+		},
+		func(rule *entity.FeeRule) { rule.Type = entity.FeeTypeFixed },
+	}
+	for _, change := range invalid {
+		rule := valid
+		change(&rule)
+		assert.True(suite.T(), errors.Is(Validate(rule), ErrInvalidRule), "%+v", rule)
+	}
+}
+
+func TestFeeTestSuite(t *testing.T) {
+	suite.Run(t, new(FeeTestSuite))
+}
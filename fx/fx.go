@@ -0,0 +1,115 @@
+package fx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const MaxSpreadBps = 10000
+
+var ErrInvalidRate = errors.New("invalid fx rate")
+
+func Validate(rate entity.FxRate) error {
+	if money.ValidateCurrency(rate.BaseCurrency) != nil || money.ValidateCurrency(rate.QuoteCurrency) != nil {
+		return fmt.Errorf("%w: currencies must be three uppercase letters", ErrInvalidRate)
+	}
+	if rate.BaseCurrency == rate.QuoteCurrency {
+		return fmt.Errorf("%w: base and quote currency must differ", ErrInvalidRate)
+	}
+	if _, err := parseRate(rate.Rate); err != nil {
+		return err
+	}
+	if rate.SpreadBps < 0 || rate.SpreadBps >= MaxSpreadBps {
+		return fmt.Errorf("%w: spread must be between 0 and %d basis points", ErrInvalidRate, MaxSpreadBps-1)
+	}
+	return nil
+}
+
+func parseRate(value string) (*big.Rat, error) {
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("%w: rate must be a positive decimal number", ErrInvalidRate)
+	}
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: rate must be a positive decimal number", ErrInvalidRate)
+	}
+	return rate, nil
+}
+
+func Effective(rate string, spreadBps int64) (*big.Rat, error) {
+	mid, err := parseRate(rate)
+	if err != nil {
+		return nil, err
+	}
+	spread := big.NewRat(MaxSpreadBps-spreadBps, MaxSpreadBps)
+	return mid.Mul(mid, spread), nil
+}
+
+func Convert(amount money.Amount, rate string, spreadBps int64) (money.Amount, error) {
+	effective, err := Effective(rate, spreadBps)
+	if err != nil {
+		return 0, err
+	}
+	converted := new(big.Rat).Mul(big.NewRat(amount.Minor(), 1), effective)
+	rounded, ok := round(converted)
+	if !ok {
+		return 0, money.ErrInvalidAmount
+	}
+	return money.FromMinor(rounded), nil
+}
+
+// Invert converts an amount in the quote currency of a rate back into its base
+// currency at the mid rate.
+func Invert(amount money.Amount, rate string) (money.Amount, error) {
+	mid, err := parseRate(rate)
+	if err != nil {
+		return 0, err
+	}
+	converted := new(big.Rat).Quo(big.NewRat(amount.Minor(), 1), mid)
+	rounded, ok := round(converted)
+	if !ok {
+		return 0, money.ErrInvalidAmount
+	}
+	return money.FromMinor(rounded), nil
+}
+
+func round(value *big.Rat) (int64, bool) {
+	numerator := new(big.Int).Abs(value.Num())
+	denominator := value.Denom()
+	numerator.Mul(numerator, big.NewInt(2)).Add(numerator, denominator)
+	numerator.Quo(numerator, new(big.Int).Mul(denominator, big.NewInt(2)))
+	if value.Sign() < 0 {
+		numerator.Neg(numerator)
+	}
+	return numerator.Int64(), numerator.IsInt64()
+}
+
+func ReadRates(reader io.Reader) ([]entity.FxRate, error) {
+	var rates []entity.FxRate
+	if err := json.NewDecoder(reader).Decode(&rates); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRate, err.Error())
+	}
+	for i, rate := range rates {
+		if err := Validate(rate); err != nil {
+			return nil, fmt.Errorf("%w (entry %d)", err, i+1)
+		}
+	}
+	return rates, nil
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package fx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FxTestSuite struct {
+	suite.Suite
+}
+
+func (suite *FxTestSuite) TestConvert_Success() {
+	converted, err := Convert(money.MustParse("10"), "15650.25", 0)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("156502.50"), converted)
+
+	converted, err = Convert(money.MustParse("10"), "15650.25", 100)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("154937.48"), converted)
+}
+
+func (suite *FxTestSuite) TestConvert_RoundsHalfUp() {
+	converted, err := Convert(money.MustParse("0.01"), "0.5", 0)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("0.01"), converted)
+
+	converted, err = Convert(money.MustParse("100000"), "0.0000639", 0)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("6.39"), converted)
+
+	converted, err = Convert(money.MustParse("0.01"), "0.49", 0)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("0"), converted)
+}
+
+func (suite *FxTestSuite) TestConvert_Overflow() {
+	_, err := Convert(money.MustParse("1000000000000"), "100000000000", 0)
+	assert.ErrorIs(suite.T(), err, money.ErrInvalidAmount)
+}
+
+func (suite *FxTestSuite) TestInvert() {
+	converted, err := Invert(money.MustParse("150000"), "15000")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("10"), converted)
+
+	_, err = Invert(money.MustParse("1"), "0")
+	assert.ErrorIs(suite.T(), err, ErrInvalidRate)
+}
+
+func (suite *FxTestSuite) TestValidate() {
+	valid := entity.FxRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15650.25", SpreadBps: 50}
+	assert.Nil(suite.T(), Validate(valid))
+
+	invalid := []entity.FxRate{
+		{BaseCurrency: "usd", QuoteCurrency: "IDR", Rate: "1"},
+		{BaseCurrency: "USD", QuoteCurrency: "USD", Rate: "1"},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "0"},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "-1"},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "1/3"},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "1e3"},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "1", SpreadBps: -1},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "1", SpreadBps: MaxSpreadBps},
+	}
+	for _, rate := range invalid {
+		assert.True(suite.T(), errors.Is(Validate(rate), ErrInvalidRate), rate)
+	}
+}
+
+func (suite *FxTestSuite) TestReadRates() {
+	rates, err := ReadRates(strings.NewReader(`[{"base_currency":"USD","quote_currency":"IDR","rate":"15650.25","spread_bps":50}]`))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []entity.FxRate{{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15650.25", SpreadBps: 50}}, rates)
+
+	_, err = ReadRates(strings.NewReader(`[{"base_currency":"USD","quote_currency":"IDR","rate":"abc"}]`))
+	assert.ErrorIs(suite.T(), err, ErrInvalidRate)
+	_, err = ReadRates(strings.NewReader(`{`))
+	assert.ErrorIs(suite.T(), err, ErrInvalidRate)
+}
+
+func TestFxTestSuite(t *testing.T) {
+	suite.Run(t, new(FxTestSuite))
+}
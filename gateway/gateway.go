@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/hmacsign"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+
+	TimestampHeader = "X-Gateway-Timestamp"
+	SignatureHeader = "X-Gateway-Signature"
+	CallbackWindow  = 5 * time.Minute
+)
+
+var (
+	ErrChargeNotFound   = errors.New("charge not found")
+	ErrInvalidSignature = errors.New("invalid gateway signature")
+)
+
+type ChargeRequest struct {
+	OrderId  string
+	Amount   money.Amount
+	Currency string
+	Scenario string
+}
+
+type Charge struct {
+	Reference     string       `json:"reference"`
+	OrderId       string       `json:"order_id"`
+	Amount        money.Amount `json:"amount"`
+	Currency      string       `json:"currency"`
+	Status        string       `json:"status"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+}
+
+type Gateway interface {
+	Name() string
+	CreateCharge(request ChargeRequest) (Charge, error)
+	FindCharge(reference string) (Charge, error)
+	VerifyCallback(header http.Header, body []byte) (Charge, error)
+}
+
+func SignCallback(secret string, timestamp string, body []byte) string {
+	return hmacsign.Sign(secret, timestamp+"."+string(body))
+}
+
+func VerifyCallbackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get(TimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > CallbackWindow || age < -CallbackWindow {
+		return ErrInvalidSignature
+	}
+	if !hmacsign.Verify(secret, timestamp+"."+string(body), header.Get(SignatureHeader)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/google/uuid"
+)
+
+const (
+	SimulatorName = "simulator"
+
+	ScenarioSuccess = "success"
+	ScenarioFailure = "failure"
+	ScenarioDelayed = "delayed"
+
+	DefaultCallbackDelay        = 2 * time.Second
+	DefaultDelayedCallbackDelay = 10 * time.Minute
+)
+
+var Scenarios = []string{ScenarioSuccess, ScenarioFailure, ScenarioDelayed}
+
+type simulatedCharge struct {
+	charge   Charge
+	outcome  string
+	settleAt time.Time
+}
+
+type simulator struct {
+	secret               string
+	callbackUrl          string
+	callbackDelay        time.Duration
+	delayedCallbackDelay time.Duration
+	client               *http.Client
+	mu                   sync.Mutex
+	charges              map[string]*simulatedCharge
+}
+
+func (s *simulator) Name() string {
+	return SimulatorName
+}
+
+func (s *simulator) CreateCharge(request ChargeRequest) (Charge, error) {
+	outcome := StatusSucceeded
+	callbackDelay := s.callbackDelay
+	switch request.Scenario {
+	case "", ScenarioSuccess:
+	case ScenarioFailure:
+		outcome = StatusFailed
+	case ScenarioDelayed:
+		callbackDelay = s.delayedCallbackDelay
+	default:
+		return Charge{}, fmt.Errorf("unknown scenario %q", request.Scenario)
+	}
+
+	charge := Charge{
+		Reference: "sim_" + uuid.New().String(),
+		OrderId:   request.OrderId,
+		Amount:    request.Amount,
+		Currency:  request.Currency,
+		Status:    StatusPending,
+	}
+	s.mu.Lock()
+	s.charges[charge.Reference] = &simulatedCharge{
+		charge:   charge,
+		outcome:  outcome,
+		settleAt: time.Now().Add(s.callbackDelay),
+	}
+	s.mu.Unlock()
+
+	time.AfterFunc(callbackDelay, func() {
+		if err := s.notify(charge.Reference); err != nil {
+			log.Println("Failed to send simulated gateway callback:", err)
+		}
+	})
+	return charge, nil
+}
+
+func (s *simulator) FindCharge(reference string) (Charge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	simulated, ok := s.charges[reference]
+	if !ok {
+		return Charge{}, ErrChargeNotFound
+	}
+	return s.settle(simulated, time.Now()), nil
+}
+
+func (s *simulator) VerifyCallback(header http.Header, body []byte) (Charge, error) {
+	if err := VerifyCallbackSignature(s.secret, header, body, time.Now()); err != nil {
+		return Charge{}, err
+	}
+	var charge Charge
+	if err := json.Unmarshal(body, &charge); err != nil {
+		return Charge{}, err
+	}
+	return charge, nil
+}
+
+func (s *simulator) settle(simulated *simulatedCharge, now time.Time) Charge {
+	charge := simulated.charge
+	if !now.Before(simulated.settleAt) {
+		charge.Status = simulated.outcome
+		if charge.Status == StatusFailed {
+			charge.FailureReason = "card declined"
+		}
+	}
+	return charge
+}
+
+func (s *simulator) notify(reference string) error {
+	s.mu.Lock()
+	simulated, ok := s.charges[reference]
+	var charge Charge
+	if ok {
+		charge = s.settle(simulated, simulated.settleAt)
+	}
+	s.mu.Unlock()
+	if !ok || s.callbackUrl == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(charge)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequest(http.MethodPost, s.callbackUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, SignCallback(s.secret, timestamp, body))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return nil
+}
+
+func NewSimulator(gatewayConfig config.GatewayConfig) Gateway {
+	if gatewayConfig.CallbackDelay <= 0 {
+		gatewayConfig.CallbackDelay = DefaultCallbackDelay
+	}
+	if gatewayConfig.DelayedCallbackDelay <= 0 {
+		gatewayConfig.DelayedCallbackDelay = DefaultDelayedCallbackDelay
+	}
+	return &simulator{
+		secret:               gatewayConfig.Secret,
+		callbackUrl:          gatewayConfig.CallbackUrl,
+		callbackDelay:        gatewayConfig.CallbackDelay,
+		delayedCallbackDelay: gatewayConfig.DelayedCallbackDelay,
+		client:               &http.Client{Timeout: 10 * time.Second},
+		charges:              map[string]*simulatedCharge{},
+	}
+}
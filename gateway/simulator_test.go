@@ -0,0 +1,49 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulator_FindCharge(t *testing.T) {
+	simulator := NewSimulator(config.GatewayConfig{CallbackDelay: 20 * time.Millisecond, DelayedCallbackDelay: time.Hour})
+
+	charge, err := simulator.CreateCharge(ChargeRequest{OrderId: "order-1", Amount: money.MustParse("50"), Currency: money.DefaultCurrency, Scenario: ScenarioDelayed})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, charge.Status)
+	found, err := simulator.FindCharge(charge.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, found.Status)
+
+	time.Sleep(30 * time.Millisecond)
+	found, err = simulator.FindCharge(charge.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, found.Status)
+	assert.Equal(t, "order-1", found.OrderId)
+
+	_, err = simulator.FindCharge("sim_unknown")
+	assert.ErrorIs(t, err, ErrChargeNotFound)
+	_, err = simulator.CreateCharge(ChargeRequest{OrderId: "order-2", Scenario: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestVerifyCallbackSignature(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"reference":"sim_1"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	header := http.Header{}
+	header.Set(TimestampHeader, timestamp)
+	header.Set(SignatureHeader, SignCallback("secret", timestamp, body))
+
+	assert.NoError(t, VerifyCallbackSignature("secret", header, body, now))
+	assert.ErrorIs(t, VerifyCallbackSignature("other", header, body, now), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifyCallbackSignature("secret", header, []byte(`{}`), now), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifyCallbackSignature("secret", header, body, now.Add(CallbackWindow+time.Second)), ErrInvalidSignature)
+}
@@ -0,0 +1,291 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	SystemOpening = "system:opening"
+	SystemFunding = "system:funding"
+	SystemFees    = "system:fees"
+	SystemPayouts = "system:payouts"
+	SystemFx      = "system:fx"
+
+	customerPrefix       = "customer:"
+	merchantPrefix       = "merchant:"
+	holdPrefix           = "hold:"
+	payoutPrefix         = "payout:"
+	openingTransactionId = "opening-balance"
+)
+
+type Leg struct {
+	Account string
+	Amount  money.Amount
+}
+
+type Mismatch struct {
+	Account  string
+	Currency string
+	Cached   money.Amount
+	Derived  money.Amount
+}
+
+type Report struct {
+	Totals     map[string]money.Amount
+	Mismatches []Mismatch
+}
+
+func CustomerAccount(username string) string {
+	return customerPrefix + username
+}
+
+func MerchantAccount(merchantCode string) string {
+	return merchantPrefix + merchantCode
+}
+
+func HoldAccount(username string) string {
+	return holdPrefix + username
+}
+
+func PayoutAccount(account string) string {
+	return payoutPrefix + account
+}
+
+func Post(tx storage.Storage, transactionId string, currency string, legs ...Leg) error {
+	if len(legs) < 2 {
+		return app_error.InternalServerError("Ledger entry needs at least two legs")
+	}
+	var sum money.Amount
+	for _, leg := range legs {
+		sum += leg.Amount
+	}
+	if sum != 0 {
+		return app_error.InternalServerError("Unbalanced ledger entry for transaction " + transactionId)
+	}
+
+	entryId := uuid.New().String()
+	date := time.Now()
+	for _, leg := range legs {
+		if leg.Amount == 0 {
+			continue
+		}
+		if err := applyBalance(tx, leg.Account, currency, leg.Amount); err != nil {
+			return err
+		}
+		err := tx.Postings().Insert(entity.Posting{
+			EntryId:       entryId,
+			TransactionId: transactionId,
+			Account:       leg.Account,
+			Amount:        leg.Amount,
+			Currency:      currency,
+			Date:          date,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Transfer(tx storage.Storage, transactionId string, currency string, from string, to string, amount money.Amount) error {
+	return Post(tx, transactionId, currency, Leg{Account: from, Amount: -amount}, Leg{Account: to, Amount: amount})
+}
+
+func applyBalance(tx storage.Storage, account string, currency string, amount money.Amount) error {
+	switch {
+	case strings.HasPrefix(account, customerPrefix):
+		customer, err := tx.Customers().FindByUsername(strings.TrimPrefix(account, customerPrefix))
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid username")
+		}
+		if err != nil {
+			return err
+		}
+		if customer.Currency != currency {
+			return applyWalletBalance(tx, customer.Username, currency, amount)
+		}
+		customer.Balance += amount
+		if customer.Balance < 0 {
+			return app_error.InvalidError("Balance insufficient")
+		}
+		return tx.Customers().Update(customer)
+	case strings.HasPrefix(account, merchantPrefix):
+		merchant, err := tx.Merchants().FindByCode(strings.TrimPrefix(account, merchantPrefix))
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid merchant code")
+		}
+		if err != nil {
+			return err
+		}
+		if merchant.Currency != currency {
+			return app_error.InvalidError("Currency mismatch")
+		}
+		merchant.Balance += amount
+		if merchant.Balance < 0 {
+			return app_error.InvalidError("Merchant balance insufficient")
+		}
+		return tx.Merchants().Update(merchant)
+	}
+	return nil
+}
+
+func applyWalletBalance(tx storage.Storage, username string, currency string, amount money.Amount) error {
+	wallet, err := tx.Wallets().Find(username, currency)
+	if errors.Is(err, storage.ErrNotFound) {
+		return app_error.InvalidError("Currency mismatch")
+	}
+	if err != nil {
+		return err
+	}
+	wallet.Balance += amount
+	if wallet.Balance < 0 {
+		return app_error.InvalidError("Balance insufficient")
+	}
+	return tx.Wallets().Update(wallet)
+}
+
+func Backfill(store storage.Storage) error {
+	return store.Atomic(func(tx storage.Storage) error {
+		balances, err := tx.Postings().Balances()
+		if err != nil {
+			return err
+		}
+		posted := map[[2]string]bool{}
+		for _, balance := range balances {
+			posted[[2]string{balance.Account, balance.Currency}] = true
+		}
+
+		customers, err := tx.Customers().FindAll()
+		if err != nil {
+			return err
+		}
+		wallets, err := tx.Wallets().FindAll()
+		if err != nil {
+			return err
+		}
+		merchants, err := tx.Merchants().FindAll()
+		if err != nil {
+			return err
+		}
+		var cached []storage.AccountBalance
+		for _, customer := range customers {
+			cached = append(cached, storage.AccountBalance{Account: CustomerAccount(customer.Username), Currency: customer.Currency, Balance: customer.Balance})
+		}
+		for _, wallet := range wallets {
+			cached = append(cached, storage.AccountBalance{Account: CustomerAccount(wallet.Username), Currency: wallet.Currency, Balance: wallet.Balance})
+		}
+		for _, merchant := range merchants {
+			cached = append(cached, storage.AccountBalance{Account: MerchantAccount(merchant.MerchantCode), Currency: merchant.Currency, Balance: merchant.Balance})
+		}
+
+		date := time.Now()
+		for _, account := range cached {
+			if account.Balance == 0 || posted[[2]string{account.Account, account.Currency}] {
+				continue
+			}
+			entryId := uuid.New().String()
+			for _, leg := range []Leg{{Account: SystemOpening, Amount: -account.Balance}, {Account: account.Account, Amount: account.Balance}} {
+				err = tx.Postings().Insert(entity.Posting{
+					EntryId:       entryId,
+					TransactionId: openingTransactionId,
+					Account:       leg.Account,
+					Amount:        leg.Amount,
+					Currency:      account.Currency,
+					Date:          date,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func Check(store storage.Storage) (Report, error) {
+	report := Report{Totals: map[string]money.Amount{}}
+	balances, err := store.Postings().Balances()
+	if err != nil {
+		return Report{}, err
+	}
+	customers, err := store.Customers().FindAll()
+	if err != nil {
+		return Report{}, err
+	}
+	merchants, err := store.Merchants().FindAll()
+	if err != nil {
+		return Report{}, err
+	}
+	wallets, err := store.Wallets().FindAll()
+	if err != nil {
+		return Report{}, err
+	}
+
+	derived := map[[2]string]money.Amount{}
+	for _, balance := range balances {
+		report.Totals[balance.Currency] += balance.Balance
+		if strings.HasPrefix(balance.Account, customerPrefix) || strings.HasPrefix(balance.Account, merchantPrefix) {
+			derived[[2]string{balance.Account, balance.Currency}] = balance.Balance
+		}
+	}
+
+	compare := func(account string, currency string, cached money.Amount) {
+		key := [2]string{account, currency}
+		if derived[key] != cached {
+			report.Mismatches = append(report.Mismatches, Mismatch{Account: account, Currency: currency, Cached: cached, Derived: derived[key]})
+		}
+		delete(derived, key)
+	}
+	for _, customer := range customers {
+		compare(CustomerAccount(customer.Username), customer.Currency, customer.Balance)
+	}
+	for _, wallet := range wallets {
+		compare(CustomerAccount(wallet.Username), wallet.Currency, wallet.Balance)
+	}
+	for _, merchant := range merchants {
+		compare(MerchantAccount(merchant.MerchantCode), merchant.Currency, merchant.Balance)
+	}
+	for key, amount := range derived {
+		if amount != 0 {
+			report.Mismatches = append(report.Mismatches, Mismatch{Account: key[0], Currency: key[1], Derived: amount})
+		}
+	}
+	return report, nil
+}
+
+func (r Report) Balanced() bool {
+	if len(r.Mismatches) > 0 {
+		return false
+	}
+	for _, total := range r.Totals {
+		if total != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Report) String() string {
+	var problems []string
+	for currency, total := range r.Totals {
+		if total != 0 {
+			problems = append(problems, fmt.Sprintf("postings in %s sum to %s", currency, total))
+		}
+	}
+	for _, mismatch := range r.Mismatches {
+		problems = append(problems, fmt.Sprintf("%s cached %s %s but postings derive %s", mismatch.Account, mismatch.Cached, mismatch.Currency, mismatch.Derived))
+	}
+	if len(problems) == 0 {
+		return "ledger balanced"
+	}
+	return strings.Join(problems, "; ")
+}
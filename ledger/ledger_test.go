@@ -0,0 +1,168 @@
+package ledger
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LedgerTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *LedgerTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{{MerchantCode: "MRC125", Currency: money.DefaultCurrency}}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(Backfill(suite.storage))
+}
+
+func (suite *LedgerTestSuite) check() Report {
+	report, err := Check(suite.storage)
+	suite.Require().NoError(err)
+	return report
+}
+
+func (suite *LedgerTestSuite) TestBackfill_OpeningBalance() {
+	postings, err := suite.storage.Postings().FindByAccount(CustomerAccount("dummyUsername"))
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), postings, 1)
+	assert.Equal(suite.T(), money.MustParse("100"), postings[0].Amount)
+	assert.True(suite.T(), suite.check().Balanced())
+
+	assert.Nil(suite.T(), Backfill(suite.storage))
+	postings, _ = suite.storage.Postings().FindByAccount(SystemOpening)
+	assert.Len(suite.T(), postings, 1)
+}
+
+func (suite *LedgerTestSuite) TestBackfill_WalletOpeningBalance() {
+	suite.Require().NoError(suite.storage.Wallets().Insert(entity.Wallet{Username: "dummyUsername", Currency: "USD", Balance: money.MustParse("25")}))
+	assert.False(suite.T(), suite.check().Balanced())
+
+	assert.Nil(suite.T(), Backfill(suite.storage))
+	postings, err := suite.storage.Postings().FindByAccount(CustomerAccount("dummyUsername"))
+	assert.Nil(suite.T(), err)
+	suite.Require().Len(postings, 2)
+	assert.Equal(suite.T(), "USD", postings[1].Currency)
+	assert.Equal(suite.T(), money.MustParse("25"), postings[1].Amount)
+	report := suite.check()
+	assert.True(suite.T(), report.Balanced(), report.String())
+
+	assert.Nil(suite.T(), Backfill(suite.storage))
+	postings, _ = suite.storage.Postings().FindByAccount(SystemOpening)
+	assert.Len(suite.T(), postings, 2)
+}
+
+func (suite *LedgerTestSuite) TestTransfer_Success() {
+	err := suite.storage.Atomic(func(tx storage.Storage) error {
+		return Transfer(tx, "tx-1", money.DefaultCurrency, CustomerAccount("dummyUsername"), MerchantAccount("MRC125"), money.MustParse("40"))
+	})
+	assert.Nil(suite.T(), err)
+	customer, _ := suite.storage.Customers().FindByUsername("dummyUsername")
+	merchant, _ := suite.storage.Merchants().FindByCode("MRC125")
+	assert.Equal(suite.T(), money.MustParse("60"), customer.Balance)
+	assert.Equal(suite.T(), money.MustParse("40"), merchant.Balance)
+	assert.True(suite.T(), suite.check().Balanced())
+}
+
+func (suite *LedgerTestSuite) TestTransfer_Insufficient() {
+	err := suite.storage.Atomic(func(tx storage.Storage) error {
+		return Transfer(tx, "tx-1", money.DefaultCurrency, CustomerAccount("dummyUsername"), MerchantAccount("MRC125"), money.MustParse("100.01"))
+	})
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusBadRequest, appError.ErrorType)
+	postings, _ := suite.storage.Postings().FindByAccount(MerchantAccount("MRC125"))
+	assert.Empty(suite.T(), postings)
+}
+
+func (suite *LedgerTestSuite) TestTransfer_MerchantInsufficient() {
+	err := suite.storage.Atomic(func(tx storage.Storage) error {
+		return Transfer(tx, "tx-1", money.DefaultCurrency, MerchantAccount("MRC125"), CustomerAccount("dummyUsername"), money.MustParse("0.01"))
+	})
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusBadRequest, appError.ErrorType)
+	merchant, _ := suite.storage.Merchants().FindByCode("MRC125")
+	assert.Equal(suite.T(), money.Amount(0), merchant.Balance)
+	assert.True(suite.T(), suite.check().Balanced())
+}
+
+func (suite *LedgerTestSuite) TestPost_Unbalanced() {
+	err := suite.storage.Atomic(func(tx storage.Storage) error {
+		return Post(tx, "tx-1", money.DefaultCurrency,
+			Leg{Account: CustomerAccount("dummyUsername"), Amount: money.MustParse("-10")},
+			Leg{Account: MerchantAccount("MRC125"), Amount: money.MustParse("9")})
+	})
+	assert.NotNil(suite.T(), err)
+	assert.True(suite.T(), suite.check().Balanced())
+}
+
+func (suite *LedgerTestSuite) TestCheck_DetectsDrift() {
+	customer, _ := suite.storage.Customers().FindByUsername("dummyUsername")
+	customer.Balance += money.MustParse("1")
+	suite.Require().NoError(suite.storage.Customers().Update(customer))
+
+	report := suite.check()
+	assert.False(suite.T(), report.Balanced())
+	assert.Equal(suite.T(), []Mismatch{{
+		Account:  CustomerAccount("dummyUsername"),
+		Currency: money.DefaultCurrency,
+		Cached:   money.MustParse("101"),
+		Derived:  money.MustParse("100"),
+	}}, report.Mismatches)
+}
+
+func (suite *LedgerTestSuite) TestPost_WalletCurrency() {
+	suite.Require().NoError(suite.storage.Wallets().Insert(entity.Wallet{Username: "dummyUsername", Currency: "USD"}))
+	err := suite.storage.Atomic(func(tx storage.Storage) error {
+		return Transfer(tx, "tx-1", "USD", SystemFunding, CustomerAccount("dummyUsername"), money.MustParse("5"))
+	})
+	assert.Nil(suite.T(), err)
+
+	wallet, _ := suite.storage.Wallets().Find("dummyUsername", "USD")
+	customer, _ := suite.storage.Customers().FindByUsername("dummyUsername")
+	assert.Equal(suite.T(), money.MustParse("5"), wallet.Balance)
+	assert.Equal(suite.T(), money.MustParse("100"), customer.Balance)
+	assert.True(suite.T(), suite.check().Balanced())
+
+	err = suite.storage.Atomic(func(tx storage.Storage) error {
+		return Transfer(tx, "tx-2", "USD", CustomerAccount("dummyUsername"), SystemFunding, money.MustParse("5.01"))
+	})
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *LedgerTestSuite) TestPost_MissingWallet() {
+	err := suite.storage.Atomic(func(tx storage.Storage) error {
+		return Transfer(tx, "tx-1", "USD", SystemFunding, CustomerAccount("dummyUsername"), money.MustParse("5"))
+	})
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), "Currency mismatch", appError.ErrorMessage)
+}
+
+func TestLedgerTestSuite(t *testing.T) {
+	suite.Run(t, new(LedgerTestSuite))
+}
@@ -1,8 +1,13 @@
 package manager
 
 import (
+	"fmt"
+	"log"
+
 	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
 	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/storage"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
 )
 
@@ -10,15 +15,36 @@ type RepositoryManager interface {
 	LoginRepository() repository.LoginRepository
 	LogoutRepository() repository.LogoutRepository
 	PaymentRepository() repository.PaymentRepository
+	HistoryRepository() repository.HistoryRepository
+	RefundRepository() repository.RefundRepository
+	RegisterRepository() repository.RegisterRepository
+	RefreshRepository() repository.RefreshRepository
+	SessionRepository() repository.SessionRepository
+	TwoFactorRepository() repository.TwoFactorRepository
+	PinRepository() repository.PinRepository
+	RoleRepository() repository.RoleRepository
+	MerchantKeyRepository() repository.MerchantKeyRepository
+	PaymentRequestRepository() repository.PaymentRequestRepository
+	HoldRepository() repository.HoldRepository
+	WebhookRepository() repository.WebhookRepository
+	EventRepository() repository.EventRepository
+	TopUpRepository() repository.TopUpRepository
+	TransferRepository() repository.TransferRepository
+	WithdrawalRepository() repository.WithdrawalRepository
+	FeeRepository() repository.FeeRepository
+	WalletRepository() repository.WalletRepository
+	FxRepository() repository.FxRepository
+	LedgerRepository() repository.LedgerRepository
+	Close() error
 }
 
 type repositoryManager struct {
-	config        config.JsonFileConfig
+	storage       storage.Storage
 	authenticator authenticator.AccessToken
 }
 
 func (r *repositoryManager) LoginRepository() repository.LoginRepository {
-	return repository.NewLoginRepository(r.config)
+	return repository.NewLoginRepository(r.storage)
 }
 
 func (r *repositoryManager) LogoutRepository() repository.LogoutRepository {
@@ -26,12 +52,116 @@ func (r *repositoryManager) LogoutRepository() repository.LogoutRepository {
 }
 
 func (r *repositoryManager) PaymentRepository() repository.PaymentRepository {
-	return repository.NewPaymentRepository(r.config)
+	return repository.NewPaymentRepository(r.storage)
+}
+
+func (r *repositoryManager) HistoryRepository() repository.HistoryRepository {
+	return repository.NewHistoryRepository(r.storage)
+}
+
+func (r *repositoryManager) RefundRepository() repository.RefundRepository {
+	return repository.NewRefundRepository(r.storage)
+}
+
+func (r *repositoryManager) RegisterRepository() repository.RegisterRepository {
+	return repository.NewRegisterRepository(r.storage)
+}
+
+func (r *repositoryManager) RefreshRepository() repository.RefreshRepository {
+	return repository.NewRefreshRepository(r.storage, r.authenticator)
+}
+
+func (r *repositoryManager) SessionRepository() repository.SessionRepository {
+	return repository.NewSessionRepository(r.authenticator)
+}
+
+func (r *repositoryManager) TwoFactorRepository() repository.TwoFactorRepository {
+	return repository.NewTwoFactorRepository(r.storage)
+}
+
+func (r *repositoryManager) PinRepository() repository.PinRepository {
+	return repository.NewPinRepository(r.storage)
+}
+
+func (r *repositoryManager) RoleRepository() repository.RoleRepository {
+	return repository.NewRoleRepository(r.storage, r.authenticator)
+}
+
+func (r *repositoryManager) MerchantKeyRepository() repository.MerchantKeyRepository {
+	return repository.NewMerchantKeyRepository(r.storage)
+}
+
+func (r *repositoryManager) PaymentRequestRepository() repository.PaymentRequestRepository {
+	return repository.NewPaymentRequestRepository(r.storage)
+}
+
+func (r *repositoryManager) HoldRepository() repository.HoldRepository {
+	return repository.NewHoldRepository(r.storage)
+}
+
+func (r *repositoryManager) WebhookRepository() repository.WebhookRepository {
+	return repository.NewWebhookRepository(r.storage)
+}
+
+func (r *repositoryManager) EventRepository() repository.EventRepository {
+	return repository.NewEventRepository(r.storage)
+}
+
+func (r *repositoryManager) TopUpRepository() repository.TopUpRepository {
+	return repository.NewTopUpRepository(r.storage)
+}
+
+func (r *repositoryManager) TransferRepository() repository.TransferRepository {
+	return repository.NewTransferRepository(r.storage)
+}
+
+func (r *repositoryManager) WithdrawalRepository() repository.WithdrawalRepository {
+	return repository.NewWithdrawalRepository(r.storage)
+}
+
+func (r *repositoryManager) FeeRepository() repository.FeeRepository {
+	return repository.NewFeeRepository(r.storage)
+}
+
+func (r *repositoryManager) WalletRepository() repository.WalletRepository {
+	return repository.NewWalletRepository(r.storage)
+}
+
+func (r *repositoryManager) FxRepository() repository.FxRepository {
+	return repository.NewFxRepository(r.storage)
+}
+
+func (r *repositoryManager) LedgerRepository() repository.LedgerRepository {
+	return repository.NewLedgerRepository(r.storage)
+}
+
+func (r *repositoryManager) Close() error {
+	return r.storage.Close()
 }
 
-func NewRepositoryManager(config config.JsonFileConfig, authenticator authenticator.AccessToken) RepositoryManager {
+func NewRepositoryManager(config config.StorageConfig, authenticator authenticator.AccessToken) (RepositoryManager, error) {
+	storage, err := storage.NewStorage(config)
+	if err != nil {
+		return nil, err
+	}
+	if err = ledger.Backfill(storage); err != nil {
+		storage.Close()
+		return nil, err
+	}
+	report, err := ledger.Check(storage)
+	if err != nil {
+		storage.Close()
+		return nil, err
+	}
+	if !report.Balanced() {
+		if !config.AllowLedgerImbalance {
+			storage.Close()
+			return nil, fmt.Errorf("ledger invariant violated: %s", report)
+		}
+		log.Println("Ledger invariant violated, starting anyway because LEDGER_ALLOW_IMBALANCE is set:", report)
+	}
 	return &repositoryManager{
-		config:        config,
+		storage:       storage,
 		authenticator: authenticator,
-	}
+	}, nil
 }
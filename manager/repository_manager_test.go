@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RepositoryManagerTestSuite struct {
+	suite.Suite
+	storageConfig config.StorageConfig
+}
+
+func (suite *RepositoryManagerTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	suite.storageConfig = config.StorageConfig{
+		JsonFileConfig: config.JsonFileConfig{
+			Customer: filepath.Join(dir, "customer.json"),
+			Merchant: filepath.Join(dir, "merchant.json"),
+			History:  filepath.Join(dir, "history.json"),
+			Posting:  filepath.Join(dir, "posting.json"),
+		},
+	}
+	suite.Require().NoError(utils.WriteJSON(suite.storageConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(suite.storageConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(suite.storageConfig.History, []entity.History{}))
+	suite.Require().NoError(utils.WriteJSON(suite.storageConfig.Posting, []entity.Posting{
+		{
+			EntryId:       "entry-1",
+			TransactionId: "payment-1",
+			Account:       "customer:dummyUsername",
+			Amount:        money.MustParse("50"),
+			Currency:      money.DefaultCurrency,
+			Date:          time.Now(),
+		},
+	}))
+}
+
+func (suite *RepositoryManagerTestSuite) TestNewRepositoryManager_FailedImbalancedLedger() {
+	repositoryManager, err := NewRepositoryManager(suite.storageConfig, nil)
+	suite.Require().Error(err)
+	assert.Contains(suite.T(), err.Error(), "ledger invariant violated")
+	assert.Nil(suite.T(), repositoryManager)
+}
+
+func (suite *RepositoryManagerTestSuite) TestNewRepositoryManager_AllowImbalance() {
+	suite.storageConfig.AllowLedgerImbalance = true
+	repositoryManager, err := NewRepositoryManager(suite.storageConfig, nil)
+	suite.Require().NoError(err)
+	defer repositoryManager.Close()
+
+	report, err := repositoryManager.LedgerRepository().Check()
+	suite.Require().NoError(err)
+	assert.False(suite.T(), report.Balanced())
+}
+
+func TestRepositoryManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(RepositoryManagerTestSuite))
+}
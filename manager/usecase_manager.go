@@ -1,23 +1,59 @@
 package manager
 
 import (
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/gateway"
 	"github.com/febriansr/simple-payment-api/usecase"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/loginchallenge"
+	"github.com/febriansr/simple-payment-api/utils/loginguard"
+	"github.com/febriansr/simple-payment-api/utils/pinguard"
+	"github.com/febriansr/simple-payment-api/utils/replayguard"
+	"github.com/febriansr/simple-payment-api/utils/secretbox"
 )
 
 type UsecaseManager interface {
 	LoginUsecase() usecase.LoginUsecase
 	LogoutUsecase() usecase.LogoutUsecase
 	PaymentUsecase() usecase.PaymentUsecase
+	HistoryUsecase() usecase.HistoryUsecase
+	RefundUsecase() usecase.RefundUsecase
+	RegisterUsecase() usecase.RegisterUsecase
+	RefreshUsecase() usecase.RefreshUsecase
+	SessionUsecase() usecase.SessionUsecase
+	AdminUsecase() usecase.AdminUsecase
+	TwoFactorUsecase() usecase.TwoFactorUsecase
+	PinUsecase() usecase.PinUsecase
+	MerchantKeyUsecase() usecase.MerchantKeyUsecase
+	PaymentRequestUsecase() usecase.PaymentRequestUsecase
+	HoldUsecase() usecase.HoldUsecase
+	WebhookUsecase() usecase.WebhookUsecase
+	TopUpUsecase() usecase.TopUpUsecase
+	TransferUsecase() usecase.TransferUsecase
+	WithdrawalUsecase() usecase.WithdrawalUsecase
+	PayoutUsecase() usecase.PayoutUsecase
+	FeeUsecase() usecase.FeeUsecase
+	WalletUsecase() usecase.WalletUsecase
+	FxUsecase() usecase.FxUsecase
 }
 
 type usecaseManager struct {
 	repositoryManager RepositoryManager
 	authenticator     authenticator.AccessToken
+	securityConfig    config.SecurityConfig
+	holdConfig        config.HoldConfig
+	webhookConfig     config.WebhookConfig
+	topUpConfig       config.TopUpConfig
+	gateway           gateway.Gateway
+	payoutConfig      config.PayoutConfig
+	loginGuard        loginguard.LoginGuard
+	loginChallenge    loginchallenge.LoginChallenge
+	pinGuard          pinguard.PinGuard
+	replayGuard       replayguard.ReplayGuard
 }
 
 func (u *usecaseManager) LoginUsecase() usecase.LoginUsecase {
-	return usecase.NewLoginUsecase(u.repositoryManager.LoginRepository(), u.authenticator)
+	return usecase.NewLoginUsecase(u.repositoryManager.LoginRepository(), u.authenticator, u.loginGuard, u.loginChallenge)
 }
 
 func (u *usecaseManager) LogoutUsecase() usecase.LogoutUsecase {
@@ -25,12 +61,98 @@ func (u *usecaseManager) LogoutUsecase() usecase.LogoutUsecase {
 }
 
 func (u *usecaseManager) PaymentUsecase() usecase.PaymentUsecase {
-	return usecase.NewPaymentUsecase(u.repositoryManager.PaymentRepository())
+	return usecase.NewPaymentUsecase(u.repositoryManager.PaymentRepository(), u.PinUsecase())
 }
 
-func NewUsecaseManager(r RepositoryManager, a authenticator.AccessToken) UsecaseManager {
+func (u *usecaseManager) HistoryUsecase() usecase.HistoryUsecase {
+	return usecase.NewHistoryUsecase(u.repositoryManager.HistoryRepository())
+}
+
+func (u *usecaseManager) RefundUsecase() usecase.RefundUsecase {
+	return usecase.NewRefundUsecase(u.repositoryManager.RefundRepository())
+}
+
+func (u *usecaseManager) RegisterUsecase() usecase.RegisterUsecase {
+	return usecase.NewRegisterUsecase(u.repositoryManager.RegisterRepository(), u.securityConfig)
+}
+
+func (u *usecaseManager) RefreshUsecase() usecase.RefreshUsecase {
+	return usecase.NewRefreshUsecase(u.repositoryManager.RefreshRepository())
+}
+
+func (u *usecaseManager) SessionUsecase() usecase.SessionUsecase {
+	return usecase.NewSessionUsecase(u.repositoryManager.SessionRepository())
+}
+
+func (u *usecaseManager) AdminUsecase() usecase.AdminUsecase {
+	return usecase.NewAdminUsecase(u.loginGuard, u.repositoryManager.RoleRepository(), u.repositoryManager.LedgerRepository())
+}
+
+func (u *usecaseManager) TwoFactorUsecase() usecase.TwoFactorUsecase {
+	return usecase.NewTwoFactorUsecase(u.repositoryManager.TwoFactorRepository(), u.loginGuard, u.securityConfig.TotpIssuer)
+}
+
+func (u *usecaseManager) PinUsecase() usecase.PinUsecase {
+	return usecase.NewPinUsecase(u.repositoryManager.PinRepository(), u.pinGuard, u.securityConfig)
+}
+
+func (u *usecaseManager) MerchantKeyUsecase() usecase.MerchantKeyUsecase {
+	return usecase.NewMerchantKeyUsecase(u.repositoryManager.MerchantKeyRepository(), u.replayGuard, secretbox.NewSecretBox(u.securityConfig.MerchantKeyEncryptionKey))
+}
+
+func (u *usecaseManager) PaymentRequestUsecase() usecase.PaymentRequestUsecase {
+	return usecase.NewPaymentRequestUsecase(u.repositoryManager.PaymentRequestRepository(), u.PinUsecase())
+}
+
+func (u *usecaseManager) HoldUsecase() usecase.HoldUsecase {
+	return usecase.NewHoldUsecase(u.repositoryManager.HoldRepository(), u.PinUsecase(), u.holdConfig.Lifetime)
+}
+
+func (u *usecaseManager) WebhookUsecase() usecase.WebhookUsecase {
+	return usecase.NewWebhookUsecase(u.repositoryManager.WebhookRepository(), u.webhookConfig, secretbox.NewSecretBox(u.securityConfig.MerchantKeyEncryptionKey))
+}
+
+func (u *usecaseManager) TopUpUsecase() usecase.TopUpUsecase {
+	return usecase.NewTopUpUsecase(u.repositoryManager.TopUpRepository(), u.gateway, u.topUpConfig)
+}
+
+func (u *usecaseManager) TransferUsecase() usecase.TransferUsecase {
+	return usecase.NewTransferUsecase(u.repositoryManager.TransferRepository(), u.PinUsecase())
+}
+
+func (u *usecaseManager) WithdrawalUsecase() usecase.WithdrawalUsecase {
+	return usecase.NewWithdrawalUsecase(u.repositoryManager.WithdrawalRepository(), u.PinUsecase(), u.payoutConfig.MinWithdrawal)
+}
+
+func (u *usecaseManager) PayoutUsecase() usecase.PayoutUsecase {
+	return usecase.NewPayoutUsecase(u.repositoryManager.WithdrawalRepository(), u.payoutConfig)
+}
+
+func (u *usecaseManager) FeeUsecase() usecase.FeeUsecase {
+	return usecase.NewFeeUsecase(u.repositoryManager.FeeRepository())
+}
+
+func (u *usecaseManager) WalletUsecase() usecase.WalletUsecase {
+	return usecase.NewWalletUsecase(u.repositoryManager.WalletRepository())
+}
+
+func (u *usecaseManager) FxUsecase() usecase.FxUsecase {
+	return usecase.NewFxUsecase(u.repositoryManager.FxRepository())
+}
+
+func NewUsecaseManager(r RepositoryManager, a authenticator.AccessToken, c config.SecurityConfig, h config.HoldConfig, w config.WebhookConfig, t config.TopUpConfig, gw gateway.Gateway, po config.PayoutConfig, g loginguard.LoginGuard, l loginchallenge.LoginChallenge, p pinguard.PinGuard, rg replayguard.ReplayGuard) UsecaseManager {
 	return &usecaseManager{
 		repositoryManager: r,
 		authenticator:     a,
+		securityConfig:    c,
+		holdConfig:        h,
+		webhookConfig:     w,
+		topUpConfig:       t,
+		gateway:           gw,
+		payoutConfig:      po,
+		loginGuard:        g,
+		loginChallenge:    l,
+		pinGuard:          p,
+		replayGuard:       rg,
 	}
 }
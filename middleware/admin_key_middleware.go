@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AdminKeyHeader  = "X-Admin-Key"
+	AdminKeySubject = "admin-api-key"
+)
+
+type AdminKeyMiddleware interface {
+	IdentifyAdminKey() gin.HandlerFunc
+}
+
+type adminKeyMiddleware struct {
+	adminApiKey string
+}
+
+func (a *adminKeyMiddleware) IdentifyAdminKey() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(AdminKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		if a.adminApiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(a.adminApiKey)) != 1 {
+			res.NewErrorJsonResponse(ctx, app_error.Unauthorized("Invalid admin key")).Send()
+			ctx.Abort()
+			return
+		}
+		ctx.Set(IdentityKey, authenticator.AccessDetails{
+			Username: AdminKeySubject,
+			Role:     authenticator.RoleAdmin,
+			Scopes:   authenticator.ScopesFor(authenticator.RoleAdmin),
+		})
+		ctx.Next()
+	}
+}
+
+func NewAdminKeyMiddleware(adminApiKey string) AdminKeyMiddleware {
+	return &adminKeyMiddleware{
+		adminApiKey: adminApiKey,
+	}
+}
@@ -1,13 +1,18 @@
 package middleware
 
 import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
 	"github.com/febriansr/simple-payment-api/model/dto/res"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
 	"github.com/gin-gonic/gin"
 )
 
+const IdentityKey = "identity"
+
 type AuthTokenMiddleware interface {
 	RequireToken() gin.HandlerFunc
+	RequireRole(roles ...string) gin.HandlerFunc
+	RequireScope(scopes ...string) gin.HandlerFunc
 }
 
 type authTokenMiddleware struct {
@@ -16,29 +21,80 @@ type authTokenMiddleware struct {
 
 func (a *authTokenMiddleware) RequireToken() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		token, err := authenticator.BindAuthHeader(ctx)
-		if err != nil {
-			res.NewErrorJsonResponse(ctx, err).Send()
-			ctx.Abort()
+		if _, ok := a.identify(ctx); ok {
+			ctx.Next()
+		}
+	}
+}
+
+func (a *authTokenMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		accessDetails, ok := a.identify(ctx)
+		if !ok {
 			return
 		}
-		accountDetails, err := a.authenticator.VerifyAccessToken(token)
-		if err != nil {
-			res.NewErrorJsonResponse(ctx, err).Send()
+		if !accessDetails.HasRole(roles...) {
+			res.NewErrorJsonResponse(ctx, app_error.Forbidden("Insufficient role")).Send()
 			ctx.Abort()
 			return
 		}
+		ctx.Next()
+	}
+}
 
-		err = a.authenticator.FetchAccessToken(accountDetails)
-		if err != nil {
-			res.NewErrorJsonResponse(ctx, err).Send()
-			ctx.Abort()
+func (a *authTokenMiddleware) RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		accessDetails, ok := a.identify(ctx)
+		if !ok {
 			return
 		}
+		for _, scope := range scopes {
+			if !accessDetails.HasScope(scope) {
+				res.NewErrorJsonResponse(ctx, app_error.Forbidden("Missing scope "+scope)).Send()
+				ctx.Abort()
+				return
+			}
+		}
 		ctx.Next()
 	}
 }
 
+func (a *authTokenMiddleware) identify(ctx *gin.Context) (authenticator.AccessDetails, bool) {
+	if accessDetails, ok := Identity(ctx); ok {
+		return accessDetails, true
+	}
+	token, err := authenticator.BindAuthHeader(ctx)
+	if err != nil {
+		res.NewErrorJsonResponse(ctx, err).Send()
+		ctx.Abort()
+		return authenticator.AccessDetails{}, false
+	}
+	accessDetails, err := a.authenticator.VerifyAccessToken(token)
+	if err != nil {
+		res.NewErrorJsonResponse(ctx, err).Send()
+		ctx.Abort()
+		return authenticator.AccessDetails{}, false
+	}
+
+	err = a.authenticator.FetchAccessToken(accessDetails)
+	if err != nil {
+		res.NewErrorJsonResponse(ctx, err).Send()
+		ctx.Abort()
+		return authenticator.AccessDetails{}, false
+	}
+	ctx.Set(IdentityKey, accessDetails)
+	return accessDetails, true
+}
+
+func Identity(ctx *gin.Context) (authenticator.AccessDetails, bool) {
+	value, exists := ctx.Get(IdentityKey)
+	if !exists {
+		return authenticator.AccessDetails{}, false
+	}
+	accessDetails, ok := value.(authenticator.AccessDetails)
+	return accessDetails, ok
+}
+
 func NewAuthTokenMiddleware(authenticator authenticator.AccessToken) AuthTokenMiddleware {
 	return &authTokenMiddleware{
 		authenticator: authenticator,
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthTokenMiddlewareTestSuite struct {
+	suite.Suite
+	authenticator authenticator.AccessToken
+	router        *gin.Engine
+}
+
+func (suite *AuthTokenMiddlewareTestSuite) SetupTest() {
+	server := miniredis.RunT(suite.T())
+	tokenConfig := config.TokenConfig{
+		ApplicationName:     "simplepayment",
+		JwtSignatureKey:     "secretkey",
+		AccessTokenLifetime: time.Minute,
+	}
+	keys, err := authenticator.NewKeySet(tokenConfig)
+	suite.Require().NoError(err)
+	suite.authenticator = authenticator.NewAccessToken(tokenConfig, keys, redis.NewClient(&redis.Options{Addr: server.Addr()}))
+
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+	m := NewAuthTokenMiddleware(suite.authenticator)
+	ok := func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "OK")
+	}
+	suite.router.GET("/token", m.RequireToken(), ok)
+	suite.router.GET("/payments", m.RequireScope(authenticator.ScopePaymentsWrite), ok)
+	suite.router.GET("/admin", NewAdminKeyMiddleware("Dummy Admin Key").IdentifyAdminKey(), m.RequireRole(authenticator.RoleAdmin), ok)
+}
+
+func (suite *AuthTokenMiddlewareTestSuite) login(role string) string {
+	customer := &entity.Customer{Username: "dummy" + role, Role: role}
+	tokenDetails, err := suite.authenticator.CreateAccessToken(customer)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.authenticator.StoreAccessToken(customer.Username, tokenDetails))
+	return tokenDetails.AccessToken
+}
+
+func (suite *AuthTokenMiddlewareTestSuite) serve(path string, headers map[string]string) int {
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, path, nil)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	suite.router.ServeHTTP(r, request)
+	return r.Code
+}
+
+func (suite *AuthTokenMiddlewareTestSuite) TestRequireToken() {
+	assert.Equal(suite.T(), http.StatusOK, suite.serve("/token", map[string]string{"Authorization": "Bearer " + suite.login(authenticator.RoleMerchant)}))
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.serve("/token", map[string]string{"Authorization": "Bearer invalid"}))
+}
+
+func (suite *AuthTokenMiddlewareTestSuite) TestRequireScope() {
+	assert.Equal(suite.T(), http.StatusOK, suite.serve("/payments", map[string]string{"Authorization": "Bearer " + suite.login(authenticator.RoleCustomer)}))
+	assert.Equal(suite.T(), http.StatusOK, suite.serve("/payments", map[string]string{"Authorization": "Bearer " + suite.login("")}))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.serve("/payments", map[string]string{"Authorization": "Bearer " + suite.login(authenticator.RoleMerchant)}))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.serve("/payments", map[string]string{"Authorization": "Bearer " + suite.login(authenticator.RoleAdmin)}))
+}
+
+func (suite *AuthTokenMiddlewareTestSuite) TestRequireRole() {
+	assert.Equal(suite.T(), http.StatusOK, suite.serve("/admin", map[string]string{"Authorization": "Bearer " + suite.login(authenticator.RoleAdmin)}))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.serve("/admin", map[string]string{"Authorization": "Bearer " + suite.login(authenticator.RoleCustomer)}))
+	assert.Equal(suite.T(), http.StatusOK, suite.serve("/admin", map[string]string{AdminKeyHeader: "Dummy Admin Key"}))
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.serve("/admin", map[string]string{AdminKeyHeader: "Wrong Admin Key"}))
+}
+
+func TestAuthTokenMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthTokenMiddlewareTestSuite))
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/usecase"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/hmacsign"
+	"github.com/gin-gonic/gin"
+)
+
+const MerchantKey = "merchant"
+
+type MerchantSignatureMiddleware interface {
+	RequireSignature() gin.HandlerFunc
+}
+
+type merchantSignatureMiddleware struct {
+	merchantKeyUsecase usecase.MerchantKeyUsecase
+}
+
+func (m *merchantSignatureMiddleware) RequireSignature() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body []byte
+		if ctx.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(ctx.Request.Body)
+			if err != nil {
+				res.NewErrorJsonResponse(ctx, app_error.InvalidError("invalid request body")).Send()
+				ctx.Abort()
+				return
+			}
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		merchant, err := m.merchantKeyUsecase.Authenticate(req.SignedRequest{
+			KeyId:     ctx.GetHeader(hmacsign.ApiKeyHeader),
+			Timestamp: ctx.GetHeader(hmacsign.TimestampHeader),
+			Nonce:     ctx.GetHeader(hmacsign.NonceHeader),
+			Signature: ctx.GetHeader(hmacsign.SignatureHeader),
+			Method:    ctx.Request.Method,
+			Path:      ctx.Request.URL.RequestURI(),
+			Body:      body,
+		})
+		if err != nil {
+			res.NewErrorJsonResponse(ctx, err).Send()
+			ctx.Abort()
+			return
+		}
+		ctx.Set(MerchantKey, merchant)
+		ctx.Set(IdentityKey, authenticator.AccessDetails{
+			Username: merchant.MerchantCode,
+			Role:     authenticator.RoleMerchant,
+			Scopes:   authenticator.ScopesFor(authenticator.RoleMerchant),
+		})
+		ctx.Next()
+	}
+}
+
+func Merchant(ctx *gin.Context) (entity.Merchant, bool) {
+	value, exists := ctx.Get(MerchantKey)
+	if !exists {
+		return entity.Merchant{}, false
+	}
+	merchant, ok := value.(entity.Merchant)
+	return merchant, ok
+}
+
+func NewMerchantSignatureMiddleware(merchantKeyUsecase usecase.MerchantKeyUsecase) MerchantSignatureMiddleware {
+	return &merchantSignatureMiddleware{
+		merchantKeyUsecase: merchantKeyUsecase,
+	}
+}
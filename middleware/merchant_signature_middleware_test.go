@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/hmacsign"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type merchantKeyUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *merchantKeyUsecaseMock) Issue(request req.ApiKeyRequest) (res.ApiKeySecret, error) {
+	args := m.Called(request)
+	return args.Get(0).(res.ApiKeySecret), args.Error(1)
+}
+
+func (m *merchantKeyUsecaseMock) List(merchantCode string) ([]res.ApiKey, error) {
+	args := m.Called(merchantCode)
+	return args.Get(0).([]res.ApiKey), args.Error(1)
+}
+
+func (m *merchantKeyUsecaseMock) Revoke(merchantCode string, keyId string) error {
+	args := m.Called(merchantCode, keyId)
+	return args.Error(0)
+}
+
+func (m *merchantKeyUsecaseMock) Authenticate(request req.SignedRequest) (entity.Merchant, error) {
+	args := m.Called(request)
+	return args.Get(0).(entity.Merchant), args.Error(1)
+}
+
+type MerchantSignatureMiddlewareTestSuite struct {
+	suite.Suite
+	usecaseMock *merchantKeyUsecaseMock
+	router      *gin.Engine
+}
+
+func (suite *MerchantSignatureMiddlewareTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.usecaseMock = new(merchantKeyUsecaseMock)
+	suite.router = gin.New()
+	suite.router.POST("/v1/merchant/echo", NewMerchantSignatureMiddleware(suite.usecaseMock).RequireSignature(), func(ctx *gin.Context) {
+		merchant, _ := Merchant(ctx)
+		identity, _ := Identity(ctx)
+		body, _ := io.ReadAll(ctx.Request.Body)
+		ctx.String(http.StatusOK, merchant.MerchantCode+"|"+identity.Role+"|"+string(body))
+	})
+}
+
+func (suite *MerchantSignatureMiddlewareTestSuite) serve() *httptest.ResponseRecorder {
+	r := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodPost, "/v1/merchant/echo?a=b", bytes.NewBufferString(`{"amount":"10"}`))
+	request.Header.Set(hmacsign.ApiKeyHeader, "mk_dummy")
+	request.Header.Set(hmacsign.TimestampHeader, "1687824000")
+	request.Header.Set(hmacsign.NonceHeader, "dummy-nonce")
+	request.Header.Set(hmacsign.SignatureHeader, "Dummy Signature")
+	suite.router.ServeHTTP(r, request)
+	return r
+}
+
+func (suite *MerchantSignatureMiddlewareTestSuite) TestRequireSignature_Success() {
+	suite.usecaseMock.On("Authenticate", req.SignedRequest{
+		KeyId:     "mk_dummy",
+		Timestamp: "1687824000",
+		Nonce:     "dummy-nonce",
+		Signature: "Dummy Signature",
+		Method:    http.MethodPost,
+		Path:      "/v1/merchant/echo?a=b",
+		Body:      []byte(`{"amount":"10"}`),
+	}).Return(entity.Merchant{MerchantCode: "MRC125"}, nil)
+
+	r := suite.serve()
+
+	assert.Equal(suite.T(), http.StatusOK, r.Code)
+	assert.Equal(suite.T(), "MRC125|"+authenticator.RoleMerchant+`|{"amount":"10"}`, r.Body.String())
+}
+
+func (suite *MerchantSignatureMiddlewareTestSuite) TestRequireSignature_Failed() {
+	suite.usecaseMock.On("Authenticate", mock.Anything).Return(entity.Merchant{}, app_error.Unauthorized("Invalid request signature"))
+
+	r := suite.serve()
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, r.Code)
+}
+
+func TestMerchantSignatureMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantSignatureMiddlewareTestSuite))
+}
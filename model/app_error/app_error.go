@@ -79,3 +79,51 @@ func InternalServerError(msg string) error {
 		}
 	}
 }
+
+func Forbidden(msg string) error {
+	if msg == "" {
+		return &AppError{
+			ErrorMessage: "forbidden",
+			ErrorCode:    strconv.Itoa(http.StatusForbidden),
+			ErrorType:    http.StatusForbidden,
+		}
+	} else {
+		return &AppError{
+			ErrorMessage: msg,
+			ErrorCode:    strconv.Itoa(http.StatusForbidden),
+			ErrorType:    http.StatusForbidden,
+		}
+	}
+}
+
+func Conflict(msg string) error {
+	if msg == "" {
+		return &AppError{
+			ErrorMessage: "conflict",
+			ErrorCode:    strconv.Itoa(http.StatusConflict),
+			ErrorType:    http.StatusConflict,
+		}
+	} else {
+		return &AppError{
+			ErrorMessage: msg,
+			ErrorCode:    strconv.Itoa(http.StatusConflict),
+			ErrorType:    http.StatusConflict,
+		}
+	}
+}
+
+func TooManyRequests(msg string) error {
+	if msg == "" {
+		return &AppError{
+			ErrorMessage: "too many requests",
+			ErrorCode:    strconv.Itoa(http.StatusTooManyRequests),
+			ErrorType:    http.StatusTooManyRequests,
+		}
+	} else {
+		return &AppError{
+			ErrorMessage: msg,
+			ErrorCode:    strconv.Itoa(http.StatusTooManyRequests),
+			ErrorType:    http.StatusTooManyRequests,
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package req
+
+type ApiKeyRequest struct {
+	MerchantCode string `json:"-"`
+	Label        string `json:"label"`
+}
+
+type SignedRequest struct {
+	KeyId     string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
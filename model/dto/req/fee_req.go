@@ -0,0 +1,33 @@
+package req
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type FeeTierRequest struct {
+	UpTo          *money.Amount `json:"up_to"`
+	FixedAmount   money.Amount  `json:"fixed_amount"`
+	PercentageBps int64         `json:"percentage_bps"`
+}
+
+type FeeRuleRequest struct {
+	MerchantCode  string           `json:"merchant_code"`
+	Category      string           `json:"category"`
+	Currency      string           `json:"currency"`
+	Type          string           `json:"type"`
+	FixedAmount   money.Amount     `json:"fixed_amount"`
+	PercentageBps int64            `json:"percentage_bps"`
+	Tiers         []FeeTierRequest `json:"tiers"`
+	MinFee        money.Amount     `json:"min_fee"`
+	MaxFee        money.Amount     `json:"max_fee"`
+	ChargedTo     string           `json:"charged_to"`
+}
+
+type MerchantCategoryRequest struct {
+	MerchantCode string `json:"-"`
+	Category     string `json:"category"`
+}
+
+type FeeQuoteQuery struct {
+	MerchantCode string `form:"merchant_code"`
+	Amount       string `form:"amount"`
+	Currency     string `form:"currency"`
+}
@@ -0,0 +1,19 @@
+package req
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+type HistoryQuery struct {
+	CustomerUsername string        `form:"-"`
+	Cursor           string        `form:"cursor"`
+	Limit            int           `form:"limit"`
+	From             time.Time     `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To               time.Time     `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MerchantCode     string        `form:"merchant_code"`
+	MinAmount        *money.Amount `form:"min_amount"`
+	MaxAmount        *money.Amount `form:"max_amount"`
+	Sort             string        `form:"sort"`
+}
@@ -0,0 +1,17 @@
+package req
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type HoldRequest struct {
+	CustomerUsername string       `json:"-"`
+	MerchantCode     string       `json:"merchant_code"`
+	Amount           money.Amount `json:"amount"`
+	Currency         string       `json:"currency"`
+	Reference        string       `json:"reference"`
+}
+
+type CaptureRequest struct {
+	MerchantCode string       `json:"-"`
+	HoldId       string       `json:"-"`
+	Amount       money.Amount `json:"amount"`
+}
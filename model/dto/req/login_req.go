@@ -0,0 +1,6 @@
+package req
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
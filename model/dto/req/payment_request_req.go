@@ -0,0 +1,16 @@
+package req
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+type CreatePaymentRequest struct {
+	MerchantCode     string       `json:"-"`
+	CustomerUsername string       `json:"customer_username"`
+	Amount           money.Amount `json:"amount"`
+	Currency         string       `json:"currency"`
+	Reference        string       `json:"reference"`
+	ExpiresAt        *time.Time   `json:"expires_at"`
+}
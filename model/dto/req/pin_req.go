@@ -0,0 +1,17 @@
+package req
+
+type SetPinRequest struct {
+	CustomerUsername string `json:"-"`
+	Pin              string `json:"pin"`
+	Password         string `json:"password"`
+}
+
+type StepUpRequest struct {
+	CustomerUsername string `json:"-"`
+	Pin              string `json:"pin"`
+}
+
+type PaymentAuthorization struct {
+	Pin         string `header:"X-Transaction-Pin"`
+	StepUpToken string `header:"X-Step-Up-Token"`
+}
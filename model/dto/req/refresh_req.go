@@ -0,0 +1,5 @@
+package req
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
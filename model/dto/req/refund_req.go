@@ -0,0 +1,12 @@
+package req
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type RefundRequest struct {
+	// MerchantCode restricts the refund to payments received by the signing
+	// merchant. It is empty for refunds issued through the admin endpoint.
+	MerchantCode  string       `json:"-"`
+	TransactionId string       `json:"transaction_id"`
+	Amount        money.Amount `json:"amount"`
+	Reason        string       `json:"reason"`
+}
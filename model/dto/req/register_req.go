@@ -0,0 +1,7 @@
+package req
+
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Currency string `json:"currency"`
+}
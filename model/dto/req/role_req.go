@@ -0,0 +1,6 @@
+package req
+
+type RoleRequest struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
@@ -0,0 +1,10 @@
+package req
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type TopUpRequest struct {
+	CustomerUsername string       `json:"-"`
+	Amount           money.Amount `json:"amount"`
+	Currency         string       `json:"currency"`
+	Scenario         string       `json:"scenario"`
+}
@@ -0,0 +1,11 @@
+package req
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type TransferRequest struct {
+	CustomerUsername  string       `json:"-"`
+	RecipientUsername string       `json:"recipient_username"`
+	Amount            money.Amount `json:"amount"`
+	Currency          string       `json:"currency"`
+	Note              string       `json:"note"`
+}
@@ -0,0 +1,6 @@
+package req
+
+type UnlockRequest struct {
+	Username string `json:"username"`
+	Ip       string `json:"ip"`
+}
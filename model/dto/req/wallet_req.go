@@ -0,0 +1,21 @@
+package req
+
+import "time"
+
+type WalletRequest struct {
+	CustomerUsername string `json:"-"`
+	Currency         string `json:"currency"`
+}
+
+type FxRateRequest struct {
+	BaseCurrency  string     `json:"base_currency"`
+	QuoteCurrency string     `json:"quote_currency"`
+	Rate          string     `json:"rate"`
+	SpreadBps     int64      `json:"spread_bps"`
+	RateAt        *time.Time `json:"rate_at"`
+}
+
+type SettlementCurrencyRequest struct {
+	MerchantCode string `json:"-"`
+	Currency     string `json:"currency"`
+}
@@ -0,0 +1,7 @@
+package req
+
+type WebhookRequest struct {
+	MerchantCode string   `json:"-"`
+	Url          string   `json:"url"`
+	EventTypes   []string `json:"event_types"`
+}
@@ -0,0 +1,18 @@
+package req
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type BankAccountRequest struct {
+	OwnerType     string `json:"-"`
+	Owner         string `json:"-"`
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+}
+
+type WithdrawalRequest struct {
+	OwnerType     string       `json:"-"`
+	Owner         string       `json:"-"`
+	BankAccountId string       `json:"bank_account_id"`
+	Amount        money.Amount `json:"amount"`
+}
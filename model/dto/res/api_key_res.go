@@ -0,0 +1,16 @@
+package res
+
+import "time"
+
+type ApiKey struct {
+	KeyId     string     `json:"key_id"`
+	Label     string     `json:"label"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type ApiKeySecret struct {
+	ApiKey
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,17 @@
+package res
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type CustomerProfile struct {
+	Uuid     string       `json:"uuid"`
+	Username string       `json:"username"`
+	Balance  money.Amount `json:"balance"`
+	Currency string       `json:"currency"`
+}
+
+type Balance struct {
+	Currency  string       `json:"currency"`
+	Available money.Amount `json:"available"`
+	Held      money.Amount `json:"held"`
+	Total     money.Amount `json:"total"`
+}
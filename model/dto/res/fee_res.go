@@ -0,0 +1,22 @@
+package res
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+type FeeQuote struct {
+	MerchantCode       string       `json:"merchant_code"`
+	Amount             money.Amount `json:"amount"`
+	Fee                money.Amount `json:"fee"`
+	FeeChargedTo       string       `json:"fee_charged_to,omitempty"`
+	FeeRuleId          string       `json:"fee_rule_id,omitempty"`
+	TotalDebit         money.Amount `json:"total_debit"`
+	MerchantCredit     money.Amount `json:"merchant_credit"`
+	Currency           string       `json:"currency"`
+	SettlementCurrency string       `json:"settlement_currency"`
+	FxRate             string       `json:"fx_rate,omitempty"`
+	FxSpreadBps        int64        `json:"fx_spread_bps,omitempty"`
+	FxRateAt           *time.Time   `json:"fx_rate_at,omitempty"`
+}
@@ -0,0 +1,8 @@
+package res
+
+import entity "github.com/febriansr/simple-payment-api/model/entity"
+
+type HistoryPage struct {
+	Items      []entity.History `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,18 @@
+package res
+
+import (
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+type LedgerMismatch struct {
+	Account  string       `json:"account"`
+	Currency string       `json:"currency"`
+	Cached   money.Amount `json:"cached"`
+	Derived  money.Amount `json:"derived"`
+}
+
+type LedgerReconciliation struct {
+	Balanced   bool                    `json:"balanced"`
+	Totals     map[string]money.Amount `json:"totals"`
+	Mismatches []LedgerMismatch        `json:"mismatches"`
+}
@@ -0,0 +1,6 @@
+package res
+
+type StepUpToken struct {
+	StepUpToken string `json:"step_up_token"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
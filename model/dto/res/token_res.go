@@ -0,0 +1,26 @@
+package res
+
+import "github.com/febriansr/simple-payment-api/utils/authenticator"
+
+type TokenPair struct {
+	Token            string `json:"token"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
+}
+
+func NewTokenPair(tokenDetails authenticator.TokenDetails) TokenPair {
+	return TokenPair{
+		Token:            tokenDetails.AccessToken,
+		ExpiresAt:        tokenDetails.AtExpires,
+		RefreshToken:     tokenDetails.RefreshToken,
+		RefreshExpiresAt: tokenDetails.RtExpires,
+	}
+}
+
+type LoginResponse struct {
+	*TokenPair
+	TwoFactorRequired  bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	ChallengeExpiresAt int64  `json:"challenge_expires_at,omitempty"`
+}
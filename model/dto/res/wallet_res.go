@@ -0,0 +1,9 @@
+package res
+
+import "github.com/febriansr/simple-payment-api/model/money"
+
+type Wallet struct {
+	Currency string       `json:"currency"`
+	Balance  money.Amount `json:"balance"`
+	Primary  bool         `json:"primary"`
+}
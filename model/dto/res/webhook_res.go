@@ -0,0 +1,17 @@
+package res
+
+import "time"
+
+type Webhook struct {
+	WebhookId  string     `json:"webhook_id"`
+	Url        string     `json:"url"`
+	EventTypes []string   `json:"event_types"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
+
+type WebhookSecret struct {
+	Webhook
+	Secret string `json:"secret"`
+}
@@ -1,8 +1,17 @@
 package model
 
+import "github.com/febriansr/simple-payment-api/model/money"
+
 type Customer struct {
-	Uuid     string  `json:"uuid"`
-	Username string  `json:"username"`
-	Password string  `json:"password"`
-	Balance  float64 `json:"balance"`
+	Uuid          string       `json:"uuid"`
+	Username      string       `json:"username"`
+	Password      string       `json:"password"`
+	Balance       money.Amount `json:"balance"`
+	Currency      string       `json:"currency"`
+	TotpSecret    string       `json:"totp_secret,omitempty"`
+	TotpEnabled   bool         `json:"totp_enabled,omitempty"`
+	TotpLastStep  int64        `json:"totp_last_step,omitempty"`
+	RecoveryCodes []string     `json:"recovery_codes,omitempty"`
+	PinHash       string       `json:"pin_hash,omitempty"`
+	Role          string       `json:"role,omitempty"`
 }
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventPaymentSucceeded       = "payment.succeeded"
+	EventRefundCreated          = "refund.created"
+	EventHoldAuthorized         = "hold.authorized"
+	EventHoldCaptured           = "hold.captured"
+	EventHoldVoided             = "hold.voided"
+	EventHoldExpired            = "hold.expired"
+	EventPaymentRequestCreated  = "payment_request.created"
+	EventPaymentRequestDeclined = "payment_request.declined"
+	EventPaymentRequestApproved = "payment_request.approved"
+	EventTopUpSucceeded         = "topup.succeeded"
+	EventTopUpFailed            = "topup.failed"
+	EventTransferCompleted      = "transfer.completed"
+	EventWithdrawalRequested    = "withdrawal.requested"
+	EventWithdrawalPaid         = "withdrawal.paid"
+	EventWithdrawalFailed       = "withdrawal.failed"
+)
+
+var EventTypes = []string{
+	EventPaymentSucceeded, EventRefundCreated, EventHoldAuthorized, EventHoldCaptured, EventHoldVoided, EventHoldExpired,
+	EventPaymentRequestCreated, EventPaymentRequestDeclined, EventPaymentRequestApproved,
+	EventWithdrawalRequested, EventWithdrawalPaid, EventWithdrawalFailed,
+}
+
+type Event struct {
+	EventId      string          `json:"event_id"`
+	Type         string          `json:"type"`
+	MerchantCode string          `json:"merchant_code"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Data         json.RawMessage `json:"data"`
+}
+
+type OutboxEvent struct {
+	Sequence int64 `json:"sequence"`
+	Event
+}
+
+type ConsumerCheckpoint struct {
+	Consumer  string    `json:"consumer"`
+	Sequence  int64     `json:"sequence"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	FeeTypeFixed      = "fixed"
+	FeeTypePercentage = "percentage"
+	FeeTypeTiered     = "tiered"
+
+	FeeChargedToCustomer = "customer"
+	FeeChargedToMerchant = "merchant"
+)
+
+type FeeTier struct {
+	UpTo          *money.Amount `json:"up_to,omitempty"`
+	FixedAmount   money.Amount  `json:"fixed_amount"`
+	PercentageBps int64         `json:"percentage_bps"`
+}
+
+type FeeRule struct {
+	RuleId        string       `json:"rule_id"`
+	MerchantCode  string       `json:"merchant_code,omitempty"`
+	Category      string       `json:"category,omitempty"`
+	Currency      string       `json:"currency"`
+	Type          string       `json:"type"`
+	FixedAmount   money.Amount `json:"fixed_amount"`
+	PercentageBps int64        `json:"percentage_bps"`
+	Tiers         []FeeTier    `json:"tiers,omitempty"`
+	MinFee        money.Amount `json:"min_fee"`
+	MaxFee        money.Amount `json:"max_fee"`
+	ChargedTo     string       `json:"charged_to"`
+	CreatedAt     time.Time    `json:"created_at"`
+	DisabledAt    *time.Time   `json:"disabled_at,omitempty"`
+}
+
+func (r FeeRule) Active() bool {
+	return r.DisabledAt == nil
+}
+
+// CurrencyOrDefault is the currency the rule's amounts are in. Rules saved
+// before rules carried a currency are in the default currency.
+func (r FeeRule) CurrencyOrDefault() string {
+	if r.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return r.Currency
+}
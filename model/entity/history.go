@@ -1,11 +1,37 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	HistoryTypePayment    = "payment"
+	HistoryTypeRefund     = "refund"
+	HistoryTypeTopUp      = "topup"
+	HistoryTypeTransfer   = "transfer"
+	HistoryTypeWithdrawal = "withdrawal"
+)
 
 type History struct {
-	TransactionId    string    `json:"transaction_id"`
-	CustomerUsername string    `json:"customer_username"`
-	MerchantCode     string    `json:"merchant_code"`
-	Amount           float64   `json:"amount"`
-	Date             time.Time `json:"date"`
+	TransactionId      string       `json:"transaction_id"`
+	Type               string       `json:"type"`
+	CustomerUsername   string       `json:"customer_username"`
+	MerchantCode       string       `json:"merchant_code"`
+	RecipientUsername  string       `json:"recipient_username,omitempty"`
+	Amount             money.Amount `json:"amount"`
+	Fee                money.Amount `json:"fee,omitempty"`
+	FeeChargedTo       string       `json:"fee_charged_to,omitempty"`
+	Currency           string       `json:"currency"`
+	SettlementAmount   money.Amount `json:"settlement_amount,omitempty"`
+	SettlementCurrency string       `json:"settlement_currency,omitempty"`
+	FxRate             string       `json:"fx_rate,omitempty"`
+	FxSpreadBps        int64        `json:"fx_spread_bps,omitempty"`
+	FxRateAt           *time.Time   `json:"fx_rate_at,omitempty"`
+	RefundOf           string       `json:"refund_of,omitempty"`
+	Reason             string       `json:"reason,omitempty"`
+	Reference          string       `json:"reference,omitempty"`
+	Note               string       `json:"note,omitempty"`
+	Date               time.Time    `json:"date"`
 }
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	HoldStatusAuthorized = "authorized"
+	HoldStatusCaptured   = "captured"
+	HoldStatusVoided     = "voided"
+	HoldStatusExpired    = "expired"
+)
+
+type Hold struct {
+	HoldId           string       `json:"hold_id"`
+	CustomerUsername string       `json:"customer_username"`
+	MerchantCode     string       `json:"merchant_code"`
+	Amount           money.Amount `json:"amount"`
+	CapturedAmount   money.Amount `json:"captured_amount"`
+	Currency         string       `json:"currency"`
+	Reference        string       `json:"reference,omitempty"`
+	Status           string       `json:"status"`
+	TransactionId    string       `json:"transaction_id,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	ResolvedAt       *time.Time   `json:"resolved_at,omitempty"`
+}
+
+func (h Hold) Active() bool {
+	return h.Status == HoldStatusAuthorized
+}
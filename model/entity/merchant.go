@@ -1,7 +1,12 @@
 package model
 
+import "github.com/febriansr/simple-payment-api/model/money"
+
 type Merchant struct {
-	Uuid         string `json:"uuid"`
-	MerchantCode string `json:"merchant_code"`
-	Name         string `json:"name"`
+	Uuid         string       `json:"uuid"`
+	MerchantCode string       `json:"merchant_code"`
+	Name         string       `json:"merchant_name"`
+	Balance      money.Amount `json:"balance"`
+	Currency     string       `json:"currency"`
+	Category     string       `json:"category,omitempty"`
 }
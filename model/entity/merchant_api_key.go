@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type MerchantApiKey struct {
+	KeyId        string `json:"key_id"`
+	MerchantCode string `json:"merchant_code"`
+	Label        string `json:"label"`
+	// EncryptedSecret is the HMAC signing key sealed with the server-side
+	// encryption key. It is never marshalled with the entity.
+	EncryptedSecret string     `json:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (k MerchantApiKey) Active() bool {
+	return k.RevokedAt == nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	PaymentRequestStatusPending  = "pending"
+	PaymentRequestStatusApproved = "approved"
+	PaymentRequestStatusDeclined = "declined"
+	PaymentRequestStatusExpired  = "expired"
+)
+
+type PaymentRequest struct {
+	RequestId        string       `json:"request_id"`
+	MerchantCode     string       `json:"merchant_code"`
+	CustomerUsername string       `json:"customer_username"`
+	Amount           money.Amount `json:"amount"`
+	Currency         string       `json:"currency"`
+	Reference        string       `json:"reference"`
+	Status           string       `json:"status"`
+	CreatedAt        time.Time    `json:"created_at"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	ResolvedAt       *time.Time   `json:"resolved_at,omitempty"`
+	TransactionId    string       `json:"transaction_id,omitempty"`
+}
+
+func (p PaymentRequest) StatusAt(now time.Time) string {
+	if p.Status == PaymentRequestStatusPending && !now.Before(p.ExpiresAt) {
+		return PaymentRequestStatusExpired
+	}
+	return p.Status
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+type Posting struct {
+	EntryId       string       `json:"entry_id"`
+	TransactionId string       `json:"transaction_id"`
+	Account       string       `json:"account"`
+	Amount        money.Amount `json:"amount"`
+	Currency      string       `json:"currency"`
+	Date          time.Time    `json:"date"`
+}
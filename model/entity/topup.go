@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	TopUpStatusPending   = "pending"
+	TopUpStatusSucceeded = "succeeded"
+	TopUpStatusFailed    = "failed"
+)
+
+type TopUp struct {
+	TopUpId          string       `json:"topup_id"`
+	CustomerUsername string       `json:"customer_username"`
+	Amount           money.Amount `json:"amount"`
+	Currency         string       `json:"currency"`
+	Gateway          string       `json:"gateway"`
+	GatewayReference string       `json:"gateway_reference,omitempty"`
+	Status           string       `json:"status"`
+	FailureReason    string       `json:"failure_reason,omitempty"`
+	TransactionId    string       `json:"transaction_id,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+	ResolvedAt       *time.Time   `json:"resolved_at,omitempty"`
+}
+
+func (t TopUp) Pending() bool {
+	return t.Status == TopUpStatusPending
+}
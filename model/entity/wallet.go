@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	FxRateSourceFile  = "file"
+	FxRateSourceAdmin = "admin"
+)
+
+type Wallet struct {
+	Username  string       `json:"username"`
+	Currency  string       `json:"currency"`
+	Balance   money.Amount `json:"balance"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type FxRate struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	SpreadBps     int64     `json:"spread_bps"`
+	RateAt        time.Time `json:"rate_at"`
+	Source        string    `json:"source"`
+}
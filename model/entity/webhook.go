@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusDead      = "dead"
+)
+
+type Webhook struct {
+	WebhookId    string   `json:"webhook_id"`
+	MerchantCode string   `json:"merchant_code"`
+	Url          string   `json:"url"`
+	EventTypes   []string `json:"event_types"`
+	// EncryptedSigningKey is the HMAC signing key sealed with the server-side
+	// encryption key. Keys stored before sealing was introduced are plain.
+	EncryptedSigningKey string     `json:"signing_key"`
+	CreatedAt           time.Time  `json:"created_at"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+}
+
+type WebhookDelivery struct {
+	DeliveryId     string     `json:"delivery_id"`
+	WebhookId      string     `json:"webhook_id"`
+	MerchantCode   string     `json:"merchant_code"`
+	EventId        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+func (w Webhook) Active() bool {
+	return w.DisabledAt == nil
+}
+
+func (w Webhook) Subscribed(eventType string) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
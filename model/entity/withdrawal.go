@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	OwnerTypeCustomer = "customer"
+	OwnerTypeMerchant = "merchant"
+
+	WithdrawalStatusPending = "pending"
+	WithdrawalStatusBatched = "batched"
+	WithdrawalStatusPaid    = "paid"
+	WithdrawalStatusFailed  = "failed"
+
+	PayoutBatchStatusExported = "exported"
+	PayoutBatchStatusSettled  = "settled"
+)
+
+type BankAccount struct {
+	BankAccountId string    `json:"bank_account_id"`
+	OwnerType     string    `json:"owner_type"`
+	Owner         string    `json:"owner"`
+	BankCode      string    `json:"bank_code"`
+	AccountNumber string    `json:"account_number"`
+	AccountName   string    `json:"account_name"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Withdrawal struct {
+	WithdrawalId  string       `json:"withdrawal_id"`
+	OwnerType     string       `json:"owner_type"`
+	Owner         string       `json:"owner"`
+	BankAccountId string       `json:"bank_account_id"`
+	BankCode      string       `json:"bank_code"`
+	AccountNumber string       `json:"account_number"`
+	AccountName   string       `json:"account_name"`
+	Amount        money.Amount `json:"amount"`
+	Currency      string       `json:"currency"`
+	Status        string       `json:"status"`
+	BatchId       string       `json:"batch_id,omitempty"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	TransactionId string       `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	ResolvedAt    *time.Time   `json:"resolved_at,omitempty"`
+}
+
+type PayoutBatch struct {
+	BatchId     string       `json:"batch_id"`
+	Currency    string       `json:"currency"`
+	Count       int          `json:"count"`
+	Total       money.Amount `json:"total"`
+	PaidCount   int          `json:"paid_count"`
+	FailedCount int          `json:"failed_count"`
+	Status      string       `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	SettledAt   *time.Time   `json:"settled_at,omitempty"`
+}
+
+type PayoutResult struct {
+	WithdrawalId  string
+	Amount        money.Amount
+	Status        string
+	FailureReason string
+}
+
+func (w Withdrawal) Active() bool {
+	return w.Status == WithdrawalStatusPending || w.Status == WithdrawalStatusBatched
+}
@@ -0,0 +1,128 @@
+package money
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	Scale           = 2
+	DefaultCurrency = "IDR"
+)
+
+var (
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrInvalidCurrency = errors.New("invalid currency")
+)
+
+var minorUnits = int64(math.Pow10(Scale))
+
+type Amount int64
+
+func Parse(value string) (Amount, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+	if value == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if whole == "" || len(fraction) > Scale || !isDigits(whole) || !isDigits(fraction) {
+		return 0, ErrInvalidAmount
+	}
+	fraction += strings.Repeat("0", Scale-len(fraction))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (math.MaxInt64-minorUnits)/minorUnits {
+		return 0, ErrInvalidAmount
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+	amount := units*minorUnits + cents
+	if negative {
+		amount = -amount
+	}
+	return Amount(amount), nil
+}
+
+func MustParse(value string) Amount {
+	amount, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+func (a Amount) String() string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	fraction := strconv.FormatInt(minor%minorUnits, 10)
+	fraction = strings.Repeat("0", Scale-len(fraction)) + fraction
+	return sign + strconv.FormatInt(minor/minorUnits, 10) + "." + fraction
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	data = bytes.Trim(data, `"`)
+	if strings.ContainsAny(string(data), "eE") {
+		return ErrInvalidAmount
+	}
+	amount, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func (a *Amount) UnmarshalParam(param string) error {
+	amount, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func ValidateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return ErrInvalidCurrency
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return ErrInvalidCurrency
+		}
+	}
+	return nil
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MoneyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MoneyTestSuite) TestParse_Success() {
+	cases := map[string]Amount{
+		"476723.77": 47672377,
+		"3000":      300000,
+		"0.5":       50,
+		"12.10":     1210,
+		"-30000.00": -3000000,
+	}
+	for value, expected := range cases {
+		amount, err := Parse(value)
+		assert.Nil(suite.T(), err, value)
+		assert.Equal(suite.T(), expected, amount, value)
+	}
+}
+
+func (suite *MoneyTestSuite) TestParse_Failed() {
+	for _, value := range []string{"", "-", "abc", "1.234", ".5", "1e3", "99999999999999999999"} {
+		_, err := Parse(value)
+		assert.ErrorIs(suite.T(), err, ErrInvalidAmount, value)
+	}
+}
+
+func (suite *MoneyTestSuite) TestString() {
+	assert.Equal(suite.T(), "476723.77", Amount(47672377).String())
+	assert.Equal(suite.T(), "0.05", Amount(5).String())
+	assert.Equal(suite.T(), "-12.30", Amount(-1230).String())
+}
+
+func (suite *MoneyTestSuite) TestJSON_RoundTrip() {
+	var target struct {
+		Balance Amount `json:"balance"`
+	}
+	err := json.Unmarshal([]byte(`{"balance": 476723.77}`), &target)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), Amount(47672377), target.Balance)
+
+	err = json.Unmarshal([]byte(`{"balance": "12.5"}`), &target)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), Amount(1250), target.Balance)
+
+	data, err := json.Marshal(target)
+	assert.Nil(suite.T(), err)
+	assert.JSONEq(suite.T(), `{"balance": 12.50}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"balance": 1.001}`), &target)
+	assert.NotNil(suite.T(), err)
+	err = json.Unmarshal([]byte(`{"balance": 1e2}`), &target)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *MoneyTestSuite) TestValidateCurrency() {
+	assert.Nil(suite.T(), ValidateCurrency("IDR"))
+	assert.NotNil(suite.T(), ValidateCurrency("idr"))
+	assert.NotNil(suite.T(), ValidateCurrency("RUPIAH"))
+}
+
+func TestMoneyTestSuite(t *testing.T) {
+	suite.Run(t, new(MoneyTestSuite))
+}
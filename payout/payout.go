@@ -0,0 +1,109 @@
+package payout
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	RecordHeader  = "H"
+	RecordDetail  = "D"
+	RecordTrailer = "T"
+
+	StatusPaid   = "PAID"
+	StatusFailed = "FAILED"
+
+	dateFormat = "20060102"
+)
+
+var ErrInvalidFile = errors.New("invalid settlement file")
+
+func FileName(batch entity.PayoutBatch) string {
+	return "payout_" + batch.CreatedAt.Format(dateFormat) + "_" + batch.BatchId + ".csv"
+}
+
+func WriteBatchFile(w io.Writer, batch entity.PayoutBatch, withdrawals []entity.Withdrawal) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{
+		RecordHeader, batch.BatchId, batch.CreatedAt.Format(dateFormat), batch.Currency,
+		strconv.Itoa(batch.Count), batch.Total.String(),
+	}}
+	for _, withdrawal := range withdrawals {
+		records = append(records, []string{
+			RecordDetail, withdrawal.WithdrawalId, withdrawal.BankCode, withdrawal.AccountNumber,
+			withdrawal.AccountName, withdrawal.Amount.String(), withdrawal.Currency,
+		})
+	}
+	records = append(records, []string{RecordTrailer, strconv.Itoa(batch.Count), batch.Total.String()})
+	return writer.WriteAll(records)
+}
+
+func ReadSettlementFile(r io.Reader, batchId string) ([]entity.PayoutResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%w: missing header or trailer record", ErrInvalidFile)
+	}
+
+	header := records[0]
+	if len(header) < 2 || header[0] != RecordHeader {
+		return nil, fmt.Errorf("%w: line 1: expected a header record", ErrInvalidFile)
+	}
+	if header[1] != batchId {
+		return nil, fmt.Errorf("%w: line 1: file belongs to batch %s", ErrInvalidFile, header[1])
+	}
+
+	trailer := records[len(records)-1]
+	if len(trailer) < 2 || trailer[0] != RecordTrailer {
+		return nil, fmt.Errorf("%w: line %d: expected a trailer record", ErrInvalidFile, len(records))
+	}
+	count, err := strconv.Atoi(trailer[1])
+	if err != nil || count != len(records)-2 {
+		return nil, fmt.Errorf("%w: line %d: trailer count does not match the detail records", ErrInvalidFile, len(records))
+	}
+
+	results := make([]entity.PayoutResult, 0, count)
+	for i, record := range records[1 : len(records)-1] {
+		result, err := parseDetail(record)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, i+2, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func parseDetail(record []string) (entity.PayoutResult, error) {
+	if len(record) < 4 || len(record) > 5 || record[0] != RecordDetail {
+		return entity.PayoutResult{}, errors.New("expected a detail record")
+	}
+	amount, err := money.Parse(record[2])
+	if err != nil {
+		return entity.PayoutResult{}, errors.New("invalid amount")
+	}
+	result := entity.PayoutResult{WithdrawalId: record[1], Amount: amount}
+	if len(record) == 5 {
+		result.FailureReason = strings.TrimSpace(record[4])
+	}
+	switch strings.ToUpper(record[3]) {
+	case StatusPaid:
+		result.Status = entity.WithdrawalStatusPaid
+	case StatusFailed:
+		result.Status = entity.WithdrawalStatusFailed
+	default:
+		return entity.PayoutResult{}, errors.New("status must be " + StatusPaid + " or " + StatusFailed)
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package payout
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PayoutTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PayoutTestSuite) TestWriteBatchFile() {
+	batch := entity.PayoutBatch{
+		BatchId:   "batch-1",
+		Currency:  "IDR",
+		Count:     2,
+		Total:     money.MustParse("150000"),
+		CreatedAt: time.Date(2023, 6, 27, 10, 0, 0, 0, time.UTC),
+	}
+	withdrawals := []entity.Withdrawal{
+		{WithdrawalId: "wd-1", BankCode: "014", AccountNumber: "1234567890", AccountName: "Dummy, Customer", Amount: money.MustParse("100000"), Currency: "IDR"},
+		{WithdrawalId: "wd-2", BankCode: "008", AccountNumber: "1234567890123", AccountName: "Dummy Merchant", Amount: money.MustParse("50000"), Currency: "IDR"},
+	}
+	var file bytes.Buffer
+
+	err := WriteBatchFile(&file, batch, withdrawals)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "H,batch-1,20230627,IDR,2,150000.00\n"+
+		"D,wd-1,014,1234567890,\"Dummy, Customer\",100000.00,IDR\n"+
+		"D,wd-2,008,1234567890123,Dummy Merchant,50000.00,IDR\n"+
+		"T,2,150000.00\n", file.String())
+	assert.Equal(suite.T(), "payout_20230627_batch-1.csv", FileName(batch))
+}
+
+func (suite *PayoutTestSuite) TestReadSettlementFile_Success() {
+	file := "H,batch-1,20230628\n" +
+		"D,wd-1,100000.00,PAID\n" +
+		"D,wd-2,50000,failed,Account closed\n" +
+		"T,2\n"
+
+	results, err := ReadSettlementFile(strings.NewReader(file), "batch-1")
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []entity.PayoutResult{
+		{WithdrawalId: "wd-1", Amount: money.MustParse("100000"), Status: entity.WithdrawalStatusPaid},
+		{WithdrawalId: "wd-2", Amount: money.MustParse("50000"), Status: entity.WithdrawalStatusFailed, FailureReason: "Account closed"},
+	}, results)
+}
+
+func (suite *PayoutTestSuite) TestReadSettlementFile_Invalid() {
+	files := []string{
+		"",
+		"H,batch-1,20230628\n",
+		"H,batch-2,20230628\nD,wd-1,100000,PAID\nT,1\n",
+		"D,wd-1,100000,PAID\nT,1\n",
+		"H,batch-1,20230628\nD,wd-1,100000,PAID\n",
+		"H,batch-1,20230628\nD,wd-1,100000,PAID\nT,2\n",
+		"H,batch-1,20230628\nD,wd-1,abc,PAID\nT,1\n",
+		"H,batch-1,20230628\nD,wd-1,100000,RETURNED\nT,1\n",
+		"H,batch-1,20230628\nX,wd-1,100000,PAID\nT,1\n",
+	}
+	for _, file := range files {
+		_, err := ReadSettlementFile(strings.NewReader(file), "batch-1")
+		assert.True(suite.T(), errors.Is(err, ErrInvalidFile), file)
+	}
+}
+
+func TestPayoutTestSuite(t *testing.T) {
+	suite.Run(t, new(PayoutTestSuite))
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+type EventRepository interface {
+	FindAfter(sequence int64, limit int) ([]entity.OutboxEvent, error)
+	Checkpoint(consumer string) (int64, error)
+	SaveCheckpoint(consumer string, sequence int64) error
+}
+
+type eventRepository struct {
+	storage storage.Storage
+}
+
+func (e *eventRepository) FindAfter(sequence int64, limit int) ([]entity.OutboxEvent, error) {
+	return e.storage.Outbox().FindAfter(sequence, limit)
+}
+
+func (e *eventRepository) Checkpoint(consumer string) (int64, error) {
+	checkpoint, err := e.storage.Checkpoints().Find(consumer)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return checkpoint.Sequence, nil
+}
+
+func (e *eventRepository) SaveCheckpoint(consumer string, sequence int64) error {
+	return e.storage.Checkpoints().Save(entity.ConsumerCheckpoint{
+		Consumer:  consumer,
+		Sequence:  sequence,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
+func emit(tx storage.Storage, eventType string, merchantCode string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return app_error.InternalServerError("Failed to marshal event: " + err.Error())
+	}
+	_, err = tx.Outbox().Append(entity.Event{
+		EventId:      uuid.New().String(),
+		Type:         eventType,
+		MerchantCode: merchantCode,
+		CreatedAt:    time.Now().UTC(),
+		Data:         payload,
+	})
+	return err
+}
+
+func NewEventRepository(storage storage.Storage) EventRepository {
+	return &eventRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EventRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *EventRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{
+		{MerchantCode: "MRC125", Name: "Dummy Merchant", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+}
+
+func (suite *EventRepoTestSuite) TestPayTransaction_EmitsEvent() {
+	paymentRepo := NewPaymentRepository(suite.storage)
+	eventRepo := NewEventRepository(suite.storage)
+
+	payment, err := paymentRepo.PayTransaction(entity.History{CustomerUsername: "dummyUsername", MerchantCode: "MRC125", Amount: money.MustParse("40")})
+	suite.Require().NoError(err)
+	_, err = paymentRepo.PayTransaction(entity.History{CustomerUsername: "dummyUsername", MerchantCode: "MRC125", Amount: money.MustParse("400")})
+	suite.Require().Error(err)
+
+	events, err := eventRepo.FindAfter(0, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 1)
+	assert.Equal(suite.T(), entity.EventPaymentSucceeded, events[0].Type)
+	assert.Equal(suite.T(), "MRC125", events[0].MerchantCode)
+	var data entity.History
+	suite.Require().NoError(json.Unmarshal(events[0].Data, &data))
+	assert.Equal(suite.T(), payment.TransactionId, data.TransactionId)
+}
+
+func (suite *EventRepoTestSuite) TestCheckpoint() {
+	eventRepo := NewEventRepository(suite.storage)
+
+	sequence, err := eventRepo.Checkpoint("webhooks")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(0), sequence)
+
+	suite.Require().NoError(eventRepo.SaveCheckpoint("webhooks", 7))
+	sequence, err = eventRepo.Checkpoint("webhooks")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(7), sequence)
+	sequence, err = eventRepo.Checkpoint("audit")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int64(0), sequence)
+}
+
+func TestEventRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(EventRepoTestSuite))
+}
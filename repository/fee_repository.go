@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/fee"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+type FeeRepository interface {
+	SaveRule(rule entity.FeeRule) (entity.FeeRule, error)
+	FindRules() ([]entity.FeeRule, error)
+	DisableRule(ruleId string) (entity.FeeRule, error)
+	SetCategory(merchantCode string, category string) (entity.Merchant, error)
+	Quote(merchantCode string, currency string, amount money.Amount) (res.FeeQuote, error)
+}
+
+type feeRepository struct {
+	storage storage.Storage
+}
+
+func (f *feeRepository) SaveRule(rule entity.FeeRule) (entity.FeeRule, error) {
+	err := f.storage.Atomic(func(tx storage.Storage) error {
+		if rule.MerchantCode != "" {
+			_, err := tx.Merchants().FindByCode(rule.MerchantCode)
+			if errors.Is(err, storage.ErrNotFound) {
+				return app_error.InvalidError("Invalid merchant code")
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		rules, err := tx.FeeRules().FindActive()
+		if err != nil {
+			return err
+		}
+		for _, existing := range rules {
+			if existing.MerchantCode != rule.MerchantCode || existing.Category != rule.Category || existing.CurrencyOrDefault() != rule.CurrencyOrDefault() {
+				continue
+			}
+			disabledAt := rule.CreatedAt
+			existing.DisabledAt = &disabledAt
+			if err = tx.FeeRules().Update(existing); err != nil {
+				return err
+			}
+		}
+
+		err = tx.FeeRules().Insert(rule)
+		if errors.Is(err, storage.ErrDuplicate) {
+			return app_error.InternalServerError("Failed to save fee rule: duplicate rule id")
+		}
+		return err
+	})
+	if err != nil {
+		return entity.FeeRule{}, err
+	}
+	return rule, nil
+}
+
+func (f *feeRepository) FindRules() ([]entity.FeeRule, error) {
+	return f.storage.FeeRules().FindAll()
+}
+
+func (f *feeRepository) DisableRule(ruleId string) (entity.FeeRule, error) {
+	var rule entity.FeeRule
+	err := f.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		rule, err = tx.FeeRules().FindById(ruleId)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.DataNotFound("fee rule not found")
+		}
+		if err != nil {
+			return err
+		}
+		if !rule.Active() {
+			return nil
+		}
+		disabledAt := time.Now().UTC()
+		rule.DisabledAt = &disabledAt
+		return tx.FeeRules().Update(rule)
+	})
+	if err != nil {
+		return entity.FeeRule{}, err
+	}
+	return rule, nil
+}
+
+func (f *feeRepository) SetCategory(merchantCode string, category string) (entity.Merchant, error) {
+	var merchant entity.Merchant
+	err := f.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		merchant, err = tx.Merchants().FindByCode(merchantCode)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.DataNotFound("merchant not found")
+		}
+		if err != nil {
+			return err
+		}
+		merchant.Category = category
+		return tx.Merchants().Update(merchant)
+	})
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+	return merchant, nil
+}
+
+func (f *feeRepository) Quote(merchantCode string, currency string, amount money.Amount) (res.FeeQuote, error) {
+	merchant, err := f.storage.Merchants().FindByCode(merchantCode)
+	if errors.Is(err, storage.ErrNotFound) {
+		return res.FeeQuote{}, app_error.InvalidError("Invalid merchant code")
+	}
+	if err != nil {
+		return res.FeeQuote{}, err
+	}
+	if currency == "" {
+		currency = merchant.Currency
+	}
+	rule, charge, err := paymentFee(f.storage, merchant, currency, amount)
+	if err != nil {
+		return res.FeeQuote{}, err
+	}
+	quote := res.FeeQuote{
+		MerchantCode:       merchant.MerchantCode,
+		Amount:             amount,
+		Fee:                charge,
+		FeeRuleId:          rule.RuleId,
+		TotalDebit:         amount,
+		MerchantCredit:     amount,
+		Currency:           currency,
+		SettlementCurrency: merchant.Currency,
+	}
+	if charge > 0 {
+		quote.FeeChargedTo = rule.ChargedTo
+	}
+	if quote.FeeChargedTo == entity.FeeChargedToCustomer {
+		quote.TotalDebit += charge
+	} else {
+		quote.MerchantCredit -= charge
+	}
+	if currency != merchant.Currency {
+		rate, settlement, err := convert(f.storage, currency, merchant.Currency, quote.MerchantCredit)
+		if err != nil {
+			return res.FeeQuote{}, err
+		}
+		rateAt := rate.RateAt
+		quote.MerchantCredit = settlement
+		quote.FxRate = rate.Rate
+		quote.FxSpreadBps = rate.SpreadBps
+		quote.FxRateAt = &rateAt
+	}
+	return quote, nil
+}
+
+func paymentFee(tx storage.Storage, merchant entity.Merchant, currency string, amount money.Amount) (entity.FeeRule, money.Amount, error) {
+	rules, err := tx.FeeRules().FindActive()
+	if err != nil {
+		return entity.FeeRule{}, 0, err
+	}
+	rule, found := fee.Select(rules, merchant, currency)
+	if !found {
+		return entity.FeeRule{}, 0, nil
+	}
+	return rule, fee.Calculate(rule, amount), nil
+}
+
+func NewFeeRepository(storage storage.Storage) FeeRepository {
+	return &feeRepository{
+		storage: storage,
+	}
+}
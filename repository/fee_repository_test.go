@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FeeRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *FeeRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100000"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{
+		{MerchantCode: "MRC125", Currency: money.DefaultCurrency, Category: "food"},
+		{MerchantCode: "MRC226", Currency: money.DefaultCurrency, Category: "food"},
+		{MerchantCode: "MRC920", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+
+	feeRepo := NewFeeRepository(suite.storage)
+	_, err = feeRepo.SaveRule(entity.FeeRule{
+		RuleId:        "rule-food",
+		Category:      "food",
+		Type:          entity.FeeTypePercentage,
+		PercentageBps: 100,
+		MinFee:        money.MustParse("500"),
+		ChargedTo:     entity.FeeChargedToCustomer,
+		CreatedAt:     time.Now().UTC(),
+	})
+	suite.Require().NoError(err)
+	_, err = feeRepo.SaveRule(entity.FeeRule{
+		RuleId:       "rule-mrc226",
+		MerchantCode: "MRC226",
+		Type:         entity.FeeTypeFixed,
+		FixedAmount:  money.MustParse("1000"),
+		ChargedTo:    entity.FeeChargedToMerchant,
+		CreatedAt:    time.Now().UTC(),
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *FeeRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *FeeRepoTestSuite) balances(merchantCode string) (money.Amount, money.Amount) {
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	merchant, err := suite.storage.Merchants().FindByCode(merchantCode)
+	suite.Require().NoError(err)
+	return customer.Balance, merchant.Balance
+}
+
+func (suite *FeeRepoTestSuite) assertBalanced() {
+	report, err := ledger.Check(suite.storage)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), report.Balanced())
+}
+
+func (suite *FeeRepoTestSuite) TestQuote() {
+	feeRepo := NewFeeRepository(suite.storage)
+
+	quote, err := feeRepo.Quote("MRC125", "", money.MustParse("20000"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("500"), quote.Fee)
+	assert.Equal(suite.T(), entity.FeeChargedToCustomer, quote.FeeChargedTo)
+	assert.Equal(suite.T(), "rule-food", quote.FeeRuleId)
+	assert.Equal(suite.T(), money.MustParse("20500"), quote.TotalDebit)
+	assert.Equal(suite.T(), money.MustParse("20000"), quote.MerchantCredit)
+
+	quote, err = feeRepo.Quote("MRC226", "", money.MustParse("20000"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("1000"), quote.Fee)
+	assert.Equal(suite.T(), entity.FeeChargedToMerchant, quote.FeeChargedTo)
+	assert.Equal(suite.T(), money.MustParse("20000"), quote.TotalDebit)
+	assert.Equal(suite.T(), money.MustParse("19000"), quote.MerchantCredit)
+
+	quote, err = feeRepo.Quote("MRC920", "", money.MustParse("20000"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.Amount(0), quote.Fee)
+	assert.Empty(suite.T(), quote.FeeChargedTo)
+	assert.Equal(suite.T(), money.MustParse("20000"), quote.TotalDebit)
+
+	_, err = feeRepo.Quote("MRC999", "", money.MustParse("20000"))
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func (suite *FeeRepoTestSuite) TestPayTransaction_CustomerPaysFee() {
+	payment, err := NewPaymentRepository(suite.storage).PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC125",
+		Amount:           money.MustParse("80000"),
+	})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("800"), payment.Fee)
+	assert.Equal(suite.T(), entity.FeeChargedToCustomer, payment.FeeChargedTo)
+	customerBalance, merchantBalance := suite.balances("MRC125")
+	assert.Equal(suite.T(), money.MustParse("19200"), customerBalance)
+	assert.Equal(suite.T(), money.MustParse("80000"), merchantBalance)
+	history, err := suite.storage.Histories().FindById(payment.TransactionId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("800"), history.Fee)
+	fees, err := suite.storage.Postings().FindByAccount(ledger.SystemFees)
+	suite.Require().NoError(err)
+	suite.Require().Len(fees, 1)
+	assert.Equal(suite.T(), money.MustParse("800"), fees[0].Amount)
+	suite.assertBalanced()
+}
+
+func (suite *FeeRepoTestSuite) TestPayTransaction_CustomerCannotCoverFee() {
+	_, err := NewPaymentRepository(suite.storage).PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC125",
+		Amount:           money.MustParse("100000"),
+	})
+
+	suite.assertStatus(err, http.StatusBadRequest)
+	customerBalance, merchantBalance := suite.balances("MRC125")
+	assert.Equal(suite.T(), money.MustParse("100000"), customerBalance)
+	assert.Equal(suite.T(), money.Amount(0), merchantBalance)
+}
+
+func (suite *FeeRepoTestSuite) TestPayTransaction_MerchantPaysFee() {
+	payment, err := NewPaymentRepository(suite.storage).PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC226",
+		Amount:           money.MustParse("100000"),
+	})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("1000"), payment.Fee)
+	assert.Equal(suite.T(), entity.FeeChargedToMerchant, payment.FeeChargedTo)
+	customerBalance, merchantBalance := suite.balances("MRC226")
+	assert.Equal(suite.T(), money.Amount(0), customerBalance)
+	assert.Equal(suite.T(), money.MustParse("99000"), merchantBalance)
+	suite.assertBalanced()
+}
+
+func (suite *FeeRepoTestSuite) TestPayTransaction_NoRule() {
+	payment, err := NewPaymentRepository(suite.storage).PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC920",
+		Amount:           money.MustParse("100000"),
+	})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.Amount(0), payment.Fee)
+	assert.Empty(suite.T(), payment.FeeChargedTo)
+	customerBalance, merchantBalance := suite.balances("MRC920")
+	assert.Equal(suite.T(), money.Amount(0), customerBalance)
+	assert.Equal(suite.T(), money.MustParse("100000"), merchantBalance)
+}
+
+func (suite *FeeRepoTestSuite) TestPayTransaction_RuleInOtherCurrency() {
+	_, err := NewWalletRepository(suite.storage).Open(entity.Wallet{Username: "dummyUsername", Currency: "USD", CreatedAt: time.Now().UTC()})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.storage.Atomic(func(tx storage.Storage) error {
+		return ledger.Transfer(tx, "funding-1", "USD", ledger.SystemFunding, ledger.CustomerAccount("dummyUsername"), money.MustParse("100"))
+	}))
+	suite.Require().NoError(NewFxRepository(suite.storage).SaveRates([]entity.FxRate{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15000", RateAt: time.Now().UTC(), Source: entity.FxRateSourceFile},
+	}))
+	paymentRepo := NewPaymentRepository(suite.storage)
+
+	payment, err := paymentRepo.PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC226",
+		Amount:           money.MustParse("10"),
+		Currency:         "USD",
+	})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.Amount(0), payment.Fee)
+	assert.Equal(suite.T(), money.MustParse("150000"), payment.SettlementAmount)
+
+	_, err = NewFeeRepository(suite.storage).SaveRule(entity.FeeRule{
+		RuleId:       "rule-mrc226-usd",
+		MerchantCode: "MRC226",
+		Currency:     "USD",
+		Type:         entity.FeeTypeFixed,
+		FixedAmount:  money.MustParse("0.5"),
+		ChargedTo:    entity.FeeChargedToMerchant,
+		CreatedAt:    time.Now().UTC(),
+	})
+	suite.Require().NoError(err)
+	payment, err = paymentRepo.PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC226",
+		Amount:           money.MustParse("10"),
+		Currency:         "USD",
+	})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("0.5"), payment.Fee)
+	assert.Equal(suite.T(), money.MustParse("142500"), payment.SettlementAmount)
+	quote, err := NewFeeRepository(suite.storage).Quote("MRC226", "", money.MustParse("20000"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "rule-mrc226", quote.FeeRuleId)
+	suite.assertBalanced()
+}
+
+func (suite *FeeRepoTestSuite) feesCollected() money.Amount {
+	postings, err := suite.storage.Postings().FindByAccount(ledger.SystemFees)
+	suite.Require().NoError(err)
+	var total money.Amount
+	for _, posting := range postings {
+		total += posting.Amount
+	}
+	return total
+}
+
+func (suite *FeeRepoTestSuite) TestRefund_CustomerPaysFee() {
+	payment, err := NewPaymentRepository(suite.storage).PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC125",
+		Amount:           money.MustParse("80000"),
+	})
+	suite.Require().NoError(err)
+
+	refund, err := NewRefundRepository(suite.storage).Refund(req.RefundRequest{TransactionId: payment.TransactionId})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("80000"), refund.Amount)
+	assert.Equal(suite.T(), money.MustParse("800"), refund.Fee)
+	assert.Equal(suite.T(), entity.FeeChargedToCustomer, refund.FeeChargedTo)
+	customerBalance, merchantBalance := suite.balances("MRC125")
+	assert.Equal(suite.T(), money.MustParse("100000"), customerBalance)
+	assert.Equal(suite.T(), money.Amount(0), merchantBalance)
+	assert.Equal(suite.T(), money.Amount(0), suite.feesCollected())
+	suite.assertBalanced()
+}
+
+func (suite *FeeRepoTestSuite) TestRefund_MerchantPaysFee() {
+	payment, err := NewPaymentRepository(suite.storage).PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC226",
+		Amount:           money.MustParse("100000"),
+	})
+	suite.Require().NoError(err)
+	refundRepo := NewRefundRepository(suite.storage)
+
+	refund, err := refundRepo.Refund(req.RefundRequest{TransactionId: payment.TransactionId, Amount: money.MustParse("30000")})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("300"), refund.Fee)
+	customerBalance, merchantBalance := suite.balances("MRC226")
+	assert.Equal(suite.T(), money.MustParse("30000"), customerBalance)
+	assert.Equal(suite.T(), money.MustParse("69300"), merchantBalance)
+	assert.Equal(suite.T(), money.MustParse("700"), suite.feesCollected())
+	suite.assertBalanced()
+
+	refund, err = refundRepo.Refund(req.RefundRequest{TransactionId: payment.TransactionId})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("70000"), refund.Amount)
+	assert.Equal(suite.T(), money.MustParse("700"), refund.Fee)
+	customerBalance, merchantBalance = suite.balances("MRC226")
+	assert.Equal(suite.T(), money.MustParse("100000"), customerBalance)
+	assert.Equal(suite.T(), money.Amount(0), merchantBalance)
+	assert.Equal(suite.T(), money.Amount(0), suite.feesCollected())
+	suite.assertBalanced()
+}
+
+func (suite *FeeRepoTestSuite) TestSaveRule_ReplacesRuleForSameScope() {
+	feeRepo := NewFeeRepository(suite.storage)
+
+	_, err := feeRepo.SaveRule(entity.FeeRule{
+		RuleId:      "rule-food-2",
+		Category:    "food",
+		Type:        entity.FeeTypeFixed,
+		FixedAmount: money.MustParse("250"),
+		ChargedTo:   entity.FeeChargedToCustomer,
+		CreatedAt:   time.Now().UTC(),
+	})
+	suite.Require().NoError(err)
+
+	rules, err := feeRepo.FindRules()
+	suite.Require().NoError(err)
+	suite.Require().Len(rules, 3)
+	assert.False(suite.T(), rules[0].Active())
+	assert.True(suite.T(), rules[1].Active())
+	assert.True(suite.T(), rules[2].Active())
+	quote, err := feeRepo.Quote("MRC125", "", money.MustParse("20000"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("250"), quote.Fee)
+
+	_, err = feeRepo.SaveRule(entity.FeeRule{RuleId: "rule-unknown", MerchantCode: "MRC999", Type: entity.FeeTypeFixed, ChargedTo: entity.FeeChargedToCustomer})
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func (suite *FeeRepoTestSuite) TestDisableRule() {
+	feeRepo := NewFeeRepository(suite.storage)
+
+	rule, err := feeRepo.DisableRule("rule-mrc226")
+	suite.Require().NoError(err)
+	assert.False(suite.T(), rule.Active())
+
+	quote, err := feeRepo.Quote("MRC226", "", money.MustParse("20000"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "rule-food", quote.FeeRuleId)
+
+	_, err = feeRepo.DisableRule("rule-unknown")
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func (suite *FeeRepoTestSuite) TestSetCategory() {
+	feeRepo := NewFeeRepository(suite.storage)
+
+	merchant, err := feeRepo.SetCategory("MRC920", "food")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "food", merchant.Category)
+	quote, err := feeRepo.Quote("MRC920", "", money.MustParse("20000"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "rule-food", quote.FeeRuleId)
+
+	_, err = feeRepo.SetCategory("MRC999", "food")
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func TestFeeRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(FeeRepoTestSuite))
+}
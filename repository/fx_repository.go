@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/febriansr/simple-payment-api/fx"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+type FxRepository interface {
+	SaveRates(rates []entity.FxRate) error
+	FindRates() ([]entity.FxRate, error)
+	SetSettlementCurrency(merchantCode string, currency string) (entity.Merchant, error)
+}
+
+type fxRepository struct {
+	storage storage.Storage
+}
+
+func (f *fxRepository) SaveRates(rates []entity.FxRate) error {
+	return f.storage.Atomic(func(tx storage.Storage) error {
+		for _, rate := range rates {
+			if err := tx.FxRates().Save(rate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (f *fxRepository) FindRates() ([]entity.FxRate, error) {
+	return f.storage.FxRates().FindAll()
+}
+
+func (f *fxRepository) SetSettlementCurrency(merchantCode string, currency string) (entity.Merchant, error) {
+	var merchant entity.Merchant
+	err := f.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		merchant, err = tx.Merchants().FindByCode(merchantCode)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.DataNotFound("merchant not found")
+		}
+		if err != nil {
+			return err
+		}
+		if merchant.Currency == currency {
+			return nil
+		}
+		if merchant.Balance != 0 {
+			return app_error.Conflict("Settlement currency can only be changed with a zero balance")
+		}
+		holds, err := tx.Holds().FindByMerchant(merchantCode)
+		if err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			if hold.Active() {
+				return app_error.Conflict("Settlement currency cannot be changed while holds are active")
+			}
+		}
+		withdrawals, err := tx.Withdrawals().FindByOwner(entity.OwnerTypeMerchant, merchantCode)
+		if err != nil {
+			return err
+		}
+		for _, withdrawal := range withdrawals {
+			if withdrawal.Active() {
+				return app_error.Conflict("Settlement currency cannot be changed while withdrawals are pending")
+			}
+		}
+		merchant.Currency = currency
+		return tx.Merchants().Update(merchant)
+	})
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+	return merchant, nil
+}
+
+func convert(tx storage.Storage, from string, to string, amount money.Amount) (entity.FxRate, money.Amount, error) {
+	rate, err := tx.FxRates().Find(from, to)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.FxRate{}, 0, app_error.InvalidError("No exchange rate from " + from + " to " + to)
+	}
+	if err != nil {
+		return entity.FxRate{}, 0, err
+	}
+	converted, err := fx.Convert(amount, rate.Rate, rate.SpreadBps)
+	if err != nil {
+		return entity.FxRate{}, 0, app_error.InvalidError("Amount cannot be converted to " + to)
+	}
+	if amount > 0 && converted == 0 {
+		return entity.FxRate{}, 0, app_error.InvalidError("Amount is too small to convert to " + to)
+	}
+	return rate, converted, nil
+}
+
+// value converts an amount at the mid rate of the stored exchange rates,
+// inverting the rate when only the opposite pair is stored. It reports false
+// when no rate between the currencies exists.
+func value(tx storage.Storage, from string, to string, amount money.Amount) (money.Amount, bool, error) {
+	if from == to {
+		return amount, true, nil
+	}
+	rate, err := tx.FxRates().Find(from, to)
+	if err == nil {
+		converted, err := fx.Convert(amount, rate.Rate, 0)
+		return converted, err == nil, nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return 0, false, err
+	}
+	rate, err = tx.FxRates().Find(to, from)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	converted, err := fx.Invert(amount, rate.Rate)
+	return converted, err == nil, nil
+}
+
+func NewFxRepository(storage storage.Storage) FxRepository {
+	return &fxRepository{
+		storage: storage,
+	}
+}
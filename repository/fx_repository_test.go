@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FxRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+	rateAt  time.Time
+}
+
+func (suite *FxRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100000"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{
+		{MerchantCode: "MRC125", Currency: money.DefaultCurrency},
+		{MerchantCode: "MRC226", Currency: "USD"},
+		{MerchantCode: "MRC920", Currency: "EUR"},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+
+	_, err = NewWalletRepository(suite.storage).Open(entity.Wallet{Username: "dummyUsername", Currency: "USD", CreatedAt: time.Now().UTC()})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.storage.Atomic(func(tx storage.Storage) error {
+		return ledger.Transfer(tx, "funding-1", "USD", ledger.SystemFunding, ledger.CustomerAccount("dummyUsername"), money.MustParse("100"))
+	}))
+
+	suite.rateAt = time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	suite.Require().NoError(NewFxRepository(suite.storage).SaveRates([]entity.FxRate{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15000", SpreadBps: 100, RateAt: suite.rateAt, Source: entity.FxRateSourceFile},
+		{BaseCurrency: "IDR", QuoteCurrency: "USD", Rate: "0.00006", RateAt: suite.rateAt, Source: entity.FxRateSourceFile},
+	}))
+}
+
+func (suite *FxRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *FxRepoTestSuite) assertBalanced() {
+	report, err := ledger.Check(suite.storage)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), report.Balanced(), report.String())
+}
+
+func (suite *FxRepoTestSuite) walletBalance(currency string) money.Amount {
+	wallet, err := suite.storage.Wallets().Find("dummyUsername", currency)
+	suite.Require().NoError(err)
+	return wallet.Balance
+}
+
+func (suite *FxRepoTestSuite) pay(merchantCode string, amount string, currency string) (entity.History, error) {
+	return NewPaymentRepository(suite.storage).PayTransaction(entity.History{
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     merchantCode,
+		Amount:           money.MustParse(amount),
+		Currency:         currency,
+	})
+}
+
+func (suite *FxRepoTestSuite) TestSaveRates_Upsert() {
+	fxRepo := NewFxRepository(suite.storage)
+	suite.Require().NoError(fxRepo.SaveRates([]entity.FxRate{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15500", SpreadBps: 50, RateAt: suite.rateAt.Add(time.Hour), Source: entity.FxRateSourceAdmin},
+	}))
+
+	rates, err := fxRepo.FindRates()
+	suite.Require().NoError(err)
+	suite.Require().Len(rates, 2)
+	assert.Equal(suite.T(), "15500", rates[0].Rate)
+	assert.Equal(suite.T(), entity.FxRateSourceAdmin, rates[0].Source)
+}
+
+func (suite *FxRepoTestSuite) TestPayTransaction_ConvertsToSettlementCurrency() {
+	payment, err := suite.pay("MRC125", "10", "USD")
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), "USD", payment.Currency)
+	assert.Equal(suite.T(), money.MustParse("148500"), payment.SettlementAmount)
+	assert.Equal(suite.T(), money.DefaultCurrency, payment.SettlementCurrency)
+	assert.Equal(suite.T(), "15000", payment.FxRate)
+	assert.Equal(suite.T(), int64(100), payment.FxSpreadBps)
+	suite.Require().NotNil(payment.FxRateAt)
+	assert.True(suite.T(), suite.rateAt.Equal(*payment.FxRateAt))
+	assert.Equal(suite.T(), money.MustParse("90"), suite.walletBalance("USD"))
+	merchant, _ := suite.storage.Merchants().FindByCode("MRC125")
+	assert.Equal(suite.T(), money.MustParse("148500"), merchant.Balance)
+	customer, _ := suite.storage.Customers().FindByUsername("dummyUsername")
+	assert.Equal(suite.T(), money.MustParse("100000"), customer.Balance)
+	stored, err := suite.storage.Histories().FindById(payment.TransactionId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), payment.SettlementAmount, stored.SettlementAmount)
+	suite.assertBalanced()
+}
+
+func (suite *FxRepoTestSuite) TestPayTransaction_SameCurrencyWallet() {
+	payment, err := suite.pay("MRC226", "10", "USD")
+	suite.Require().NoError(err)
+
+	assert.Empty(suite.T(), payment.SettlementCurrency)
+	assert.Empty(suite.T(), payment.FxRate)
+	merchant, _ := suite.storage.Merchants().FindByCode("MRC226")
+	assert.Equal(suite.T(), money.MustParse("10"), merchant.Balance)
+	suite.assertBalanced()
+}
+
+func (suite *FxRepoTestSuite) TestPayTransaction_FailedMissingRateOrWallet() {
+	_, err := suite.pay("MRC920", "10", "")
+	suite.assertStatus(err, http.StatusBadRequest)
+	_, err = suite.pay("MRC125", "10", "EUR")
+	suite.assertStatus(err, http.StatusBadRequest)
+	_, err = suite.pay("MRC226", "0.01", "IDR")
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	assert.Equal(suite.T(), money.MustParse("100"), suite.walletBalance("USD"))
+	suite.assertBalanced()
+}
+
+func (suite *FxRepoTestSuite) TestRefund_ReversesAtOriginalRate() {
+	payment, err := suite.pay("MRC125", "10", "USD")
+	suite.Require().NoError(err)
+	suite.Require().NoError(NewFxRepository(suite.storage).SaveRates([]entity.FxRate{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16000", RateAt: time.Now().UTC(), Source: entity.FxRateSourceAdmin},
+	}))
+
+	refund, err := NewRefundRepository(suite.storage).Refund(req.RefundRequest{
+		MerchantCode:  "MRC125",
+		TransactionId: payment.TransactionId,
+		Amount:        money.MustParse("4"),
+	})
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), "USD", refund.Currency)
+	assert.Equal(suite.T(), money.MustParse("59400"), refund.SettlementAmount)
+	assert.Equal(suite.T(), "15000", refund.FxRate)
+	assert.Equal(suite.T(), money.MustParse("94"), suite.walletBalance("USD"))
+	merchant, _ := suite.storage.Merchants().FindByCode("MRC125")
+	assert.Equal(suite.T(), money.MustParse("89100"), merchant.Balance)
+	suite.assertBalanced()
+}
+
+func (suite *FxRepoTestSuite) TestSetSettlementCurrency() {
+	fxRepo := NewFxRepository(suite.storage)
+
+	merchant, err := fxRepo.SetSettlementCurrency("MRC226", "IDR")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.DefaultCurrency, merchant.Currency)
+
+	_, err = suite.pay("MRC226", "10", "")
+	suite.Require().NoError(err)
+	_, err = fxRepo.SetSettlementCurrency("MRC226", "USD")
+	suite.assertStatus(err, http.StatusConflict)
+
+	_, err = fxRepo.SetSettlementCurrency("MRC999", "USD")
+	suite.assertStatus(err, http.StatusNotFound)
+	suite.assertBalanced()
+}
+
+func (suite *FxRepoTestSuite) TestSetSettlementCurrency_FailedActiveHold() {
+	_, err := NewFxRepository(suite.storage).SetSettlementCurrency("MRC920", "IDR")
+	suite.Require().NoError(err)
+	_, err = NewHoldRepository(suite.storage).Authorize(entity.Hold{
+		HoldId:           "hold-1",
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC920",
+		Amount:           money.MustParse("10"),
+		Status:           entity.HoldStatusAuthorized,
+		CreatedAt:        time.Now().UTC(),
+		ExpiresAt:        time.Now().Add(time.Hour),
+	})
+	suite.Require().NoError(err)
+
+	_, err = NewFxRepository(suite.storage).SetSettlementCurrency("MRC920", "EUR")
+	suite.assertStatus(err, http.StatusConflict)
+}
+
+func (suite *FxRepoTestSuite) TestQuote_ConvertsMerchantCredit() {
+	quote, err := NewFeeRepository(suite.storage).Quote("MRC125", "USD", money.MustParse("10"))
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), "USD", quote.Currency)
+	assert.Equal(suite.T(), money.DefaultCurrency, quote.SettlementCurrency)
+	assert.Equal(suite.T(), money.MustParse("10"), quote.TotalDebit)
+	assert.Equal(suite.T(), money.MustParse("148500"), quote.MerchantCredit)
+	assert.Equal(suite.T(), "15000", quote.FxRate)
+
+	_, err = NewFeeRepository(suite.storage).Quote("MRC920", "USD", money.MustParse("10"))
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func TestFxRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(FxRepoTestSuite))
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+type HistoryRepository interface {
+	FindHistories(query req.HistoryQuery) (res.HistoryPage, error)
+}
+
+type historyRepository struct {
+	storage storage.Storage
+}
+
+func (h *historyRepository) FindHistories(query req.HistoryQuery) (res.HistoryPage, error) {
+	filter := storage.HistoryFilter{
+		CustomerUsername: query.CustomerUsername,
+		MerchantCode:     query.MerchantCode,
+		From:             query.From,
+		To:               query.To,
+		MinAmount:        query.MinAmount,
+		MaxAmount:        query.MaxAmount,
+		Descending:       query.Sort != SortAscending,
+		Limit:            query.Limit + 1,
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeHistoryCursor(query.Cursor)
+		if err != nil {
+			return res.HistoryPage{}, err
+		}
+		filter.After = &cursor
+	}
+
+	histories, err := h.storage.Histories().Find(filter)
+	if err != nil {
+		return res.HistoryPage{}, err
+	}
+
+	page := res.HistoryPage{Items: histories}
+	if len(histories) > query.Limit {
+		page.Items = histories[:query.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeHistoryCursor(storage.HistoryCursor{
+			Date:          last.Date,
+			TransactionId: last.TransactionId,
+		})
+	}
+	return page, nil
+}
+
+func encodeHistoryCursor(cursor storage.HistoryCursor) string {
+	raw := cursor.Date.Format(time.RFC3339Nano) + "|" + cursor.TransactionId
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeHistoryCursor(encoded string) (storage.HistoryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return storage.HistoryCursor{}, app_error.InvalidError("invalid cursor")
+	}
+	date, transactionId, found := strings.Cut(string(raw), "|")
+	if !found {
+		return storage.HistoryCursor{}, app_error.InvalidError("invalid cursor")
+	}
+	parsedDate, err := time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return storage.HistoryCursor{}, app_error.InvalidError("invalid cursor")
+	}
+	return storage.HistoryCursor{
+		Date:          parsedDate,
+		TransactionId: transactionId,
+	}, nil
+}
+
+func NewHistoryRepository(storage storage.Storage) HistoryRepository {
+	return &historyRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+type HoldRepository interface {
+	Authorize(hold entity.Hold) (entity.Hold, error)
+	Capture(merchantCode string, holdId string, amount money.Amount) (entity.Hold, error)
+	Void(merchantCode string, holdId string) (entity.Hold, error)
+	FindByCustomer(customerUsername string) ([]entity.Hold, error)
+	FindByMerchant(merchantCode string) ([]entity.Hold, error)
+	ExpireStale() (int, error)
+	Balance(customerUsername string) (res.Balance, error)
+}
+
+type holdRepository struct {
+	storage storage.Storage
+}
+
+func (h *holdRepository) Authorize(hold entity.Hold) (entity.Hold, error) {
+	err := h.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(hold.CustomerUsername)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid username")
+		}
+		if err != nil {
+			return err
+		}
+
+		merchant, err := tx.Merchants().FindByCode(hold.MerchantCode)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid merchant code")
+		}
+		if err != nil {
+			return err
+		}
+
+		if hold.Currency == "" {
+			hold.Currency = customer.Currency
+		}
+		if hold.Currency != customer.Currency || hold.Currency != merchant.Currency {
+			return app_error.InvalidError("Currency mismatch")
+		}
+
+		err = ledger.Transfer(tx, hold.HoldId, hold.Currency,
+			ledger.CustomerAccount(hold.CustomerUsername), ledger.HoldAccount(hold.CustomerUsername), hold.Amount)
+		if err != nil {
+			return err
+		}
+		err = tx.Holds().Insert(hold)
+		if errors.Is(err, storage.ErrDuplicate) {
+			return app_error.InternalServerError("Failed to create hold: duplicate hold id")
+		}
+		if err != nil {
+			return err
+		}
+		return emit(tx, entity.EventHoldAuthorized, hold.MerchantCode, hold)
+	})
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	return hold, nil
+}
+
+func (h *holdRepository) Capture(merchantCode string, holdId string, amount money.Amount) (entity.Hold, error) {
+	var hold entity.Hold
+	err := h.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		hold, err = h.findActive(tx, merchantCode, holdId)
+		if err != nil {
+			return err
+		}
+		if amount > hold.Amount {
+			return app_error.InvalidError("capture amount exceeds the authorized amount")
+		}
+		merchant, err := tx.Merchants().FindByCode(hold.MerchantCode)
+		if err != nil {
+			return err
+		}
+
+		rule, charge, err := paymentFee(tx, merchant, hold.Currency, amount)
+		if err != nil {
+			return err
+		}
+		var feeChargedTo string
+		debit, credit := amount, amount
+		if charge > 0 {
+			feeChargedTo = rule.ChargedTo
+			if rule.ChargedTo == entity.FeeChargedToCustomer {
+				debit += charge
+			} else {
+				credit -= charge
+			}
+		}
+
+		// A fee charged to the customer comes out of the hold first and out
+		// of the available balance when the hold does not cover it.
+		now := time.Now()
+		transactionId := uuid.New().String()
+		err = ledger.Post(tx, transactionId, hold.Currency,
+			ledger.Leg{Account: ledger.HoldAccount(hold.CustomerUsername), Amount: -hold.Amount},
+			ledger.Leg{Account: ledger.MerchantAccount(hold.MerchantCode), Amount: credit},
+			ledger.Leg{Account: ledger.SystemFees, Amount: charge},
+			ledger.Leg{Account: ledger.CustomerAccount(hold.CustomerUsername), Amount: hold.Amount - debit})
+		if err != nil {
+			return err
+		}
+		err = tx.Histories().Insert(entity.History{
+			TransactionId:    transactionId,
+			Type:             entity.HistoryTypePayment,
+			CustomerUsername: hold.CustomerUsername,
+			MerchantCode:     hold.MerchantCode,
+			Amount:           amount,
+			Currency:         hold.Currency,
+			Fee:              charge,
+			FeeChargedTo:     feeChargedTo,
+			Reference:        hold.Reference,
+			Date:             now,
+		})
+		if err != nil {
+			return err
+		}
+
+		resolvedAt := now.UTC()
+		hold.Status = entity.HoldStatusCaptured
+		hold.CapturedAmount = amount
+		hold.TransactionId = transactionId
+		hold.ResolvedAt = &resolvedAt
+		if err = tx.Holds().Update(hold); err != nil {
+			return err
+		}
+		return emit(tx, entity.EventHoldCaptured, hold.MerchantCode, hold)
+	})
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	return hold, nil
+}
+
+func (h *holdRepository) Void(merchantCode string, holdId string) (entity.Hold, error) {
+	var hold entity.Hold
+	err := h.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		hold, err = h.findActive(tx, merchantCode, holdId)
+		if err != nil {
+			return err
+		}
+		hold, err = h.release(tx, hold, entity.HoldStatusVoided)
+		if err != nil {
+			return err
+		}
+		return emit(tx, entity.EventHoldVoided, hold.MerchantCode, hold)
+	})
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	return hold, nil
+}
+
+func (h *holdRepository) FindByCustomer(customerUsername string) ([]entity.Hold, error) {
+	return h.storage.Holds().FindByCustomer(customerUsername)
+}
+
+func (h *holdRepository) FindByMerchant(merchantCode string) ([]entity.Hold, error) {
+	return h.storage.Holds().FindByMerchant(merchantCode)
+}
+
+func (h *holdRepository) ExpireStale() (int, error) {
+	var expired int
+	err := h.storage.Atomic(func(tx storage.Storage) error {
+		holds, err := tx.Holds().FindExpired(time.Now())
+		if err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			if hold, err = h.release(tx, hold, entity.HoldStatusExpired); err != nil {
+				return err
+			}
+			if err = emit(tx, entity.EventHoldExpired, hold.MerchantCode, hold); err != nil {
+				return err
+			}
+		}
+		expired = len(holds)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+func (h *holdRepository) Balance(customerUsername string) (res.Balance, error) {
+	customer, err := h.storage.Customers().FindByUsername(customerUsername)
+	if errors.Is(err, storage.ErrNotFound) {
+		return res.Balance{}, app_error.DataNotFound("customer not found")
+	}
+	if err != nil {
+		return res.Balance{}, err
+	}
+	holds, err := h.storage.Holds().FindByCustomer(customerUsername)
+	if err != nil {
+		return res.Balance{}, err
+	}
+
+	withdrawals, err := h.storage.Withdrawals().FindByOwner(entity.OwnerTypeCustomer, customerUsername)
+	if err != nil {
+		return res.Balance{}, err
+	}
+
+	balance := res.Balance{Currency: customer.Currency, Available: customer.Balance}
+	for _, hold := range holds {
+		if hold.Active() {
+			balance.Held += hold.Amount
+		}
+	}
+	for _, withdrawal := range withdrawals {
+		if withdrawal.Active() {
+			balance.Held += withdrawal.Amount
+		}
+	}
+	balance.Total = balance.Available + balance.Held
+	return balance, nil
+}
+
+func (h *holdRepository) findActive(tx storage.Storage, merchantCode string, holdId string) (entity.Hold, error) {
+	hold, err := tx.Holds().FindById(holdId)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && hold.MerchantCode != merchantCode) {
+		return entity.Hold{}, app_error.DataNotFound("hold not found")
+	}
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	if !hold.Active() {
+		return entity.Hold{}, app_error.InvalidError("hold is no longer active")
+	}
+	if !hold.ExpiresAt.After(time.Now()) {
+		return entity.Hold{}, app_error.InvalidError("hold has expired")
+	}
+	return hold, nil
+}
+
+func (h *holdRepository) release(tx storage.Storage, hold entity.Hold, status string) (entity.Hold, error) {
+	err := ledger.Transfer(tx, hold.HoldId, hold.Currency,
+		ledger.HoldAccount(hold.CustomerUsername), ledger.CustomerAccount(hold.CustomerUsername), hold.Amount)
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	resolvedAt := time.Now().UTC()
+	hold.Status = status
+	hold.ResolvedAt = &resolvedAt
+	return hold, tx.Holds().Update(hold)
+}
+
+func NewHoldRepository(storage storage.Storage) HoldRepository {
+	return &holdRepository{
+		storage: storage,
+	}
+}
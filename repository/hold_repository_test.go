@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HoldRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *HoldRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{
+		{MerchantCode: "MRC125", Name: "Dummy Merchant", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+}
+
+func (suite *HoldRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *HoldRepoTestSuite) assertBalance(available string, held string) {
+	balance, err := NewHoldRepository(suite.storage).Balance("dummyUsername")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse(available), balance.Available)
+	assert.Equal(suite.T(), money.MustParse(held), balance.Held)
+	assert.Equal(suite.T(), balance.Available+balance.Held, balance.Total)
+}
+
+func (suite *HoldRepoTestSuite) assertLedgerBalanced() {
+	report, err := ledger.Check(suite.storage)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), report.Balanced(), report.String())
+}
+
+func (suite *HoldRepoTestSuite) authorize(holdId string, amount string, expiresAt time.Time) (entity.Hold, error) {
+	return NewHoldRepository(suite.storage).Authorize(entity.Hold{
+		HoldId:           holdId,
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC125",
+		Amount:           money.MustParse(amount),
+		Reference:        "ROOM-" + holdId,
+		Status:           entity.HoldStatusAuthorized,
+		CreatedAt:        time.Now().UTC(),
+		ExpiresAt:        expiresAt,
+	})
+}
+
+func (suite *HoldRepoTestSuite) TestAuthorizeAndCapture() {
+	holdRepo := NewHoldRepository(suite.storage)
+	hold, err := suite.authorize("hold-1", "60", time.Now().Add(time.Hour))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.DefaultCurrency, hold.Currency)
+	suite.assertBalance("40", "60")
+
+	_, err = holdRepo.Capture("MRC126", "hold-1", money.MustParse("45"))
+	suite.assertStatus(err, http.StatusNotFound)
+	_, err = holdRepo.Capture("MRC125", "hold-1", money.MustParse("61"))
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	hold, err = holdRepo.Capture("MRC125", "hold-1", money.MustParse("45"))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.HoldStatusCaptured, hold.Status)
+	assert.Equal(suite.T(), money.MustParse("45"), hold.CapturedAmount)
+	suite.assertBalance("55", "0")
+
+	history, err := suite.storage.Histories().FindById(hold.TransactionId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("45"), history.Amount)
+	assert.Equal(suite.T(), "ROOM-hold-1", history.Reference)
+	merchant, err := suite.storage.Merchants().FindByCode("MRC125")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("45"), merchant.Balance)
+
+	_, err = holdRepo.Void("MRC125", "hold-1")
+	suite.assertStatus(err, http.StatusBadRequest)
+	suite.assertLedgerBalanced()
+}
+
+func (suite *HoldRepoTestSuite) saveFeeRule(feeType string, amount string, chargedTo string) {
+	_, err := NewFeeRepository(suite.storage).SaveRule(entity.FeeRule{
+		RuleId:       "rule-mrc125",
+		MerchantCode: "MRC125",
+		Type:         feeType,
+		FixedAmount:  money.MustParse(amount),
+		ChargedTo:    chargedTo,
+		CreatedAt:    time.Now().UTC(),
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *HoldRepoTestSuite) TestCapture_FeeChargedToMerchant() {
+	suite.saveFeeRule(entity.FeeTypeFixed, "5", entity.FeeChargedToMerchant)
+	_, err := suite.authorize("hold-1", "60", time.Now().Add(time.Hour))
+	suite.Require().NoError(err)
+
+	hold, err := NewHoldRepository(suite.storage).Capture("MRC125", "hold-1", money.MustParse("45"))
+	suite.Require().NoError(err)
+	suite.assertBalance("55", "0")
+
+	history, err := suite.storage.Histories().FindById(hold.TransactionId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("5"), history.Fee)
+	assert.Equal(suite.T(), entity.FeeChargedToMerchant, history.FeeChargedTo)
+	merchant, err := suite.storage.Merchants().FindByCode("MRC125")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("40"), merchant.Balance)
+	suite.assertLedgerBalanced()
+}
+
+func (suite *HoldRepoTestSuite) TestCapture_FeeChargedToCustomer() {
+	suite.saveFeeRule(entity.FeeTypeFixed, "5", entity.FeeChargedToCustomer)
+	_, err := suite.authorize("hold-1", "60", time.Now().Add(time.Hour))
+	suite.Require().NoError(err)
+
+	hold, err := NewHoldRepository(suite.storage).Capture("MRC125", "hold-1", money.MustParse("60"))
+	suite.Require().NoError(err)
+	suite.assertBalance("35", "0")
+
+	history, err := suite.storage.Histories().FindById(hold.TransactionId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("5"), history.Fee)
+	assert.Equal(suite.T(), entity.FeeChargedToCustomer, history.FeeChargedTo)
+	merchant, err := suite.storage.Merchants().FindByCode("MRC125")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("60"), merchant.Balance)
+	suite.assertLedgerBalanced()
+}
+
+func (suite *HoldRepoTestSuite) TestCapture_FailedFeeExceedsBalance() {
+	suite.saveFeeRule(entity.FeeTypeFixed, "5", entity.FeeChargedToCustomer)
+	_, err := suite.authorize("hold-1", "100", time.Now().Add(time.Hour))
+	suite.Require().NoError(err)
+
+	_, err = NewHoldRepository(suite.storage).Capture("MRC125", "hold-1", money.MustParse("100"))
+	suite.assertStatus(err, http.StatusBadRequest)
+	suite.assertBalance("0", "100")
+	suite.assertLedgerBalanced()
+}
+
+func (suite *HoldRepoTestSuite) TestAuthorize_FailedInsufficientBalance() {
+	_, err := suite.authorize("hold-1", "101", time.Now().Add(time.Hour))
+	suite.assertStatus(err, http.StatusBadRequest)
+	suite.assertBalance("100", "0")
+}
+
+func (suite *HoldRepoTestSuite) TestVoid() {
+	_, err := suite.authorize("hold-1", "60", time.Now().Add(time.Hour))
+	suite.Require().NoError(err)
+
+	hold, err := NewHoldRepository(suite.storage).Void("MRC125", "hold-1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.HoldStatusVoided, hold.Status)
+	suite.assertBalance("100", "0")
+	suite.assertLedgerBalanced()
+}
+
+func (suite *HoldRepoTestSuite) TestExpireStale() {
+	holdRepo := NewHoldRepository(suite.storage)
+	_, err := suite.authorize("hold-1", "30", time.Now().Add(-time.Minute))
+	suite.Require().NoError(err)
+	_, err = suite.authorize("hold-2", "20", time.Now().Add(time.Hour))
+	suite.Require().NoError(err)
+
+	_, err = holdRepo.Capture("MRC125", "hold-1", money.MustParse("10"))
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	expired, err := holdRepo.ExpireStale()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, expired)
+	suite.assertBalance("80", "20")
+
+	holds, err := holdRepo.FindByCustomer("dummyUsername")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.HoldStatusExpired, holds[0].Status)
+	assert.Equal(suite.T(), entity.HoldStatusAuthorized, holds[1].Status)
+	suite.assertLedgerBalanced()
+}
+
+func TestHoldRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(HoldRepoTestSuite))
+}
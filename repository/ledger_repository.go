@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+type LedgerRepository interface {
+	Check() (ledger.Report, error)
+}
+
+type ledgerRepository struct {
+	storage storage.Storage
+}
+
+func (l *ledgerRepository) Check() (ledger.Report, error) {
+	return ledger.Check(l.storage)
+}
+
+func NewLedgerRepository(storage storage.Storage) LedgerRepository {
+	return &ledgerRepository{
+		storage: storage,
+	}
+}
@@ -1,43 +1,75 @@
 package repository
 
 import (
-	"github.com/febriansr/simple-payment-api/config"
+	"errors"
+	"sync"
+
 	"github.com/febriansr/simple-payment-api/model/app_error"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
-	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/febriansr/simple-payment-api/storage"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const InvalidCredentialsMessage = "Invalid username or password"
+
+var (
+	dummyPasswordHash     []byte
+	dummyPasswordHashOnce sync.Once
+)
+
 type LoginRepository interface {
-	FindCustomer(iCustomer entity.Customer) error
+	FindCustomer(iCustomer entity.Customer) (entity.Customer, error)
+	VerifyTwoFactorCode(username string, code string) (entity.Customer, error)
 }
 
 type loginRepository struct {
-	config config.JsonFileConfig
+	storage storage.Storage
 }
 
-func (l *loginRepository) FindCustomer(iCustomer entity.Customer) error {
-	var customers []entity.Customer
-	err := utils.ReadParseJSON(l.config.Customer, &customers)
+func (l *loginRepository) FindCustomer(iCustomer entity.Customer) (entity.Customer, error) {
+	customer, err := l.storage.Customers().FindByUsername(iCustomer.Username)
+	if errors.Is(err, storage.ErrNotFound) {
+		dummyPasswordHashOnce.Do(func() {
+			dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+		})
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(iCustomer.Password))
+		return entity.Customer{}, app_error.Unauthorized(InvalidCredentialsMessage)
+	}
 	if err != nil {
-		return app_error.InternalServerError("Failed to read and parse customer data: " + err.Error())
+		return entity.Customer{}, err
 	}
 
-	for _, customer := range customers {
-		if customer.Username == iCustomer.Username {
-			err := bcrypt.CompareHashAndPassword([]byte(customer.Password), []byte(iCustomer.Password))
-			if err != nil {
-				return app_error.Unauthorized("Invalid credentials: " + err.Error())
-			}
-			return nil
-		}
+	err = bcrypt.CompareHashAndPassword([]byte(customer.Password), []byte(iCustomer.Password))
+	if err != nil {
+		return entity.Customer{}, app_error.Unauthorized(InvalidCredentialsMessage)
 	}
+	return customer, nil
+}
 
-	return app_error.DataNotFound("user not found")
+func (l *loginRepository) VerifyTwoFactorCode(username string, code string) (entity.Customer, error) {
+	var verified entity.Customer
+	err := l.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(username)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.Unauthorized(InvalidTwoFactorCodeMessage)
+		}
+		if err != nil {
+			return err
+		}
+		if !customer.TotpEnabled || !useTwoFactorCode(&customer, code, true) {
+			return app_error.Unauthorized(InvalidTwoFactorCodeMessage)
+		}
+		if err = tx.Customers().Update(customer); err != nil {
+			return err
+		}
+		verified = customer
+		return nil
+	})
+	return verified, err
 }
 
-func NewLoginRepository(config config.JsonFileConfig) LoginRepository {
+func NewLoginRepository(storage storage.Storage) LoginRepository {
 	return &loginRepository{
-		config: config,
+		storage: storage,
 	}
 }
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/febriansr/simple-payment-api/utils/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const dummyTotpSecret = "JBSWY3DPEHPK3PXP"
+
+type LoginRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *LoginRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte("dummyPassword1"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Password: string(hash), Currency: money.DefaultCurrency},
+		{
+			Username:      "twoFactorUsername",
+			Password:      string(hash),
+			Currency:      money.DefaultCurrency,
+			TotpSecret:    dummyTotpSecret,
+			TotpEnabled:   true,
+			RecoveryCodes: []string{totp.HashRecoveryCode("abcd-efgh")},
+			Role:          "admin",
+		},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+}
+
+func (suite *LoginRepoTestSuite) assertInvalidCredentials(err error) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusUnauthorized, appError.ErrorType)
+	assert.Equal(suite.T(), InvalidCredentialsMessage, appError.ErrorMessage)
+}
+
+func (suite *LoginRepoTestSuite) TestFindCustomer_Success() {
+	loginRepo := NewLoginRepository(suite.storage)
+	customer, err := loginRepo.FindCustomer(entity.Customer{Username: "dummyUsername", Password: "dummyPassword1"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "dummyUsername", customer.Username)
+}
+
+func (suite *LoginRepoTestSuite) TestFindCustomer_WrongPassword() {
+	loginRepo := NewLoginRepository(suite.storage)
+	_, err := loginRepo.FindCustomer(entity.Customer{Username: "dummyUsername", Password: "wrongPassword1"})
+	suite.assertInvalidCredentials(err)
+}
+
+func (suite *LoginRepoTestSuite) TestFindCustomer_UnknownUsername() {
+	loginRepo := NewLoginRepository(suite.storage)
+	_, err := loginRepo.FindCustomer(entity.Customer{Username: "unknown", Password: "dummyPassword1"})
+	suite.assertInvalidCredentials(err)
+}
+
+func (suite *LoginRepoTestSuite) TestVerifyTwoFactorCode_Totp() {
+	loginRepo := NewLoginRepository(suite.storage)
+	code, _ := totp.Code(dummyTotpSecret, totp.Step(time.Now()))
+	customer, err := loginRepo.VerifyTwoFactorCode("twoFactorUsername", code)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "twoFactorUsername", customer.Username)
+	assert.Equal(suite.T(), "admin", customer.Role)
+	_, err = loginRepo.VerifyTwoFactorCode("twoFactorUsername", code)
+	suite.assertInvalidTwoFactorCode(err)
+}
+
+func (suite *LoginRepoTestSuite) TestVerifyTwoFactorCode_RecoveryCode() {
+	loginRepo := NewLoginRepository(suite.storage)
+	_, err := loginRepo.VerifyTwoFactorCode("twoFactorUsername", "ABCD-EFGH")
+	assert.Nil(suite.T(), err)
+	_, err = loginRepo.VerifyTwoFactorCode("twoFactorUsername", "abcd-efgh")
+	suite.assertInvalidTwoFactorCode(err)
+	customer, _ := suite.storage.Customers().FindByUsername("twoFactorUsername")
+	assert.Empty(suite.T(), customer.RecoveryCodes)
+}
+
+func (suite *LoginRepoTestSuite) TestVerifyTwoFactorCode_NotEnabled() {
+	loginRepo := NewLoginRepository(suite.storage)
+	_, err := loginRepo.VerifyTwoFactorCode("dummyUsername", "123456")
+	suite.assertInvalidTwoFactorCode(err)
+	_, err = loginRepo.VerifyTwoFactorCode("unknown", "123456")
+	suite.assertInvalidTwoFactorCode(err)
+}
+
+func (suite *LoginRepoTestSuite) assertInvalidTwoFactorCode(err error) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusUnauthorized, appError.ErrorType)
+	assert.Equal(suite.T(), InvalidTwoFactorCodeMessage, appError.ErrorMessage)
+}
+
+func TestLoginRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginRepoTestSuite))
+}
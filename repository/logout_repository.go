@@ -1,8 +1,6 @@
 package repository
 
 import (
-	"log"
-
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
 )
 
@@ -15,7 +13,6 @@ type logoutRepository struct {
 }
 
 func (a *logoutRepository) Logout(token string) error {
-	log.Print(token)
 	accountDetails, err := a.authenticator.VerifyAccessToken(token)
 	if err != nil {
 		return err
@@ -24,6 +21,9 @@ func (a *logoutRepository) Logout(token string) error {
 	if err != nil {
 		return err
 	}
+	if accountDetails.FamilyId != "" {
+		return a.authenticator.RevokeTokenFamily(accountDetails.FamilyId)
+	}
 	return nil
 }
 
@@ -70,6 +70,31 @@ func (a *authMock) DeleteAccessToken(accessUuid string) error {
 	return nil
 }
 
+func (a *authMock) RefreshAccessToken(refreshToken string, currentRole func(username string) (string, error)) (authenticator.TokenDetails, error) {
+	args := a.Called(refreshToken)
+	return args.Get(0).(authenticator.TokenDetails), args.Error(1)
+}
+
+func (a *authMock) RevokeTokenFamily(familyId string) error {
+	args := a.Called(familyId)
+	return args.Error(0)
+}
+
+func (a *authMock) RevokeSessions(username string) error {
+	args := a.Called(username)
+	return args.Error(0)
+}
+
+func (a *authMock) ListSessions(username string) ([]authenticator.Session, error) {
+	args := a.Called(username)
+	return args.Get(0).([]authenticator.Session), args.Error(1)
+}
+
+func (a *authMock) RevokeSession(username string, sessionId string) error {
+	args := a.Called(username, sessionId)
+	return args.Error(0)
+}
+
 type LogoutRepoTestSuite struct {
 	authMock *authMock
 	suite.Suite
@@ -83,6 +108,18 @@ func (suite *LogoutRepoTestSuite) TestLogout_Success() {
 	assert.Nil(suite.T(), err)
 }
 
+func (suite *LogoutRepoTestSuite) TestLogout_RevokesRefreshToken() {
+	logoutRepo := NewLogoutRepository(suite.authMock)
+	accessDetails := dummyAccessDetails[0]
+	accessDetails.FamilyId = "Dummy Family Id"
+	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(accessDetails, nil)
+	suite.authMock.On("DeleteAccessToken", accessDetails.AccessUuid).Return(nil)
+	suite.authMock.On("RevokeTokenFamily", accessDetails.FamilyId).Return(nil)
+	err := logoutRepo.Logout(dummyTokenDetails[0].AccessToken)
+	assert.Nil(suite.T(), err)
+	suite.authMock.AssertCalled(suite.T(), "RevokeTokenFamily", accessDetails.FamilyId)
+}
+
 func (suite *LogoutRepoTestSuite) TestLogout_FailedVerifyAccessToken() {
 	logoutRepo := NewLogoutRepository(suite.authMock)
 	suite.authMock.On("VerifyAccessToken", dummyTokenDetails[0].AccessToken).Return(authenticator.AccessDetails{}, errors.New("Failed"))
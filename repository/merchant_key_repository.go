@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+const InvalidApiKeyMessage = "Invalid API key"
+
+type MerchantKeyRepository interface {
+	Create(apiKey entity.MerchantApiKey) error
+	FindByMerchant(merchantCode string) ([]entity.MerchantApiKey, error)
+	Revoke(merchantCode string, keyId string) error
+	FindActive(keyId string) (entity.MerchantApiKey, entity.Merchant, error)
+}
+
+type merchantKeyRepository struct {
+	storage storage.Storage
+}
+
+func (m *merchantKeyRepository) Create(apiKey entity.MerchantApiKey) error {
+	return m.storage.Atomic(func(tx storage.Storage) error {
+		if _, err := m.findMerchant(tx, apiKey.MerchantCode); err != nil {
+			return err
+		}
+		err := tx.ApiKeys().Insert(apiKey)
+		if errors.Is(err, storage.ErrDuplicate) {
+			return app_error.InternalServerError("Failed to create API key: duplicate key id")
+		}
+		return err
+	})
+}
+
+func (m *merchantKeyRepository) FindByMerchant(merchantCode string) ([]entity.MerchantApiKey, error) {
+	if _, err := m.findMerchant(m.storage, merchantCode); err != nil {
+		return nil, err
+	}
+	return m.storage.ApiKeys().FindByMerchant(merchantCode)
+}
+
+func (m *merchantKeyRepository) Revoke(merchantCode string, keyId string) error {
+	return m.storage.Atomic(func(tx storage.Storage) error {
+		apiKey, err := tx.ApiKeys().FindById(keyId)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && apiKey.MerchantCode != merchantCode) {
+			return app_error.DataNotFound("API key not found")
+		}
+		if err != nil {
+			return err
+		}
+		if !apiKey.Active() {
+			return nil
+		}
+		revokedAt := time.Now().UTC()
+		apiKey.RevokedAt = &revokedAt
+		return tx.ApiKeys().Update(apiKey)
+	})
+}
+
+func (m *merchantKeyRepository) FindActive(keyId string) (entity.MerchantApiKey, entity.Merchant, error) {
+	apiKey, err := m.storage.ApiKeys().FindById(keyId)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && !apiKey.Active()) {
+		return entity.MerchantApiKey{}, entity.Merchant{}, app_error.Unauthorized(InvalidApiKeyMessage)
+	}
+	if err != nil {
+		return entity.MerchantApiKey{}, entity.Merchant{}, err
+	}
+	merchant, err := m.storage.Merchants().FindByCode(apiKey.MerchantCode)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.MerchantApiKey{}, entity.Merchant{}, app_error.Unauthorized(InvalidApiKeyMessage)
+	}
+	if err != nil {
+		return entity.MerchantApiKey{}, entity.Merchant{}, err
+	}
+	return apiKey, merchant, nil
+}
+
+func (m *merchantKeyRepository) findMerchant(store storage.Storage, merchantCode string) (entity.Merchant, error) {
+	merchant, err := store.Merchants().FindByCode(merchantCode)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.Merchant{}, app_error.DataNotFound("merchant not found")
+	}
+	return merchant, err
+}
+
+func NewMerchantKeyRepository(storage storage.Storage) MerchantKeyRepository {
+	return &merchantKeyRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyApiKey = entity.MerchantApiKey{
+	KeyId:           "mk_dummy",
+	MerchantCode:    "MRC125",
+	Label:           "primary",
+	EncryptedSecret: "Dummy Secret Hash",
+	CreatedAt:       time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC),
+}
+
+type MerchantKeyRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *MerchantKeyRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{
+		{MerchantCode: "MRC125", Name: "Dummy Merchant", Currency: money.DefaultCurrency},
+		{MerchantCode: "MRC126", Name: "Other Merchant", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+}
+
+func (suite *MerchantKeyRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *MerchantKeyRepoTestSuite) TestCreateAndFindActive() {
+	merchantKeyRepo := NewMerchantKeyRepository(suite.storage)
+	assert.Nil(suite.T(), merchantKeyRepo.Create(dummyApiKey))
+
+	apiKey, merchant, err := merchantKeyRepo.FindActive(dummyApiKey.KeyId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), dummyApiKey, apiKey)
+	assert.Equal(suite.T(), "Dummy Merchant", merchant.Name)
+
+	apiKeys, err := merchantKeyRepo.FindByMerchant("MRC125")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []entity.MerchantApiKey{dummyApiKey}, apiKeys)
+}
+
+func (suite *MerchantKeyRepoTestSuite) TestCreate_FailedUnknownMerchant() {
+	apiKey := dummyApiKey
+	apiKey.MerchantCode = "unknown"
+	suite.assertStatus(NewMerchantKeyRepository(suite.storage).Create(apiKey), http.StatusNotFound)
+	_, err := NewMerchantKeyRepository(suite.storage).FindByMerchant("unknown")
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func (suite *MerchantKeyRepoTestSuite) TestRevoke() {
+	merchantKeyRepo := NewMerchantKeyRepository(suite.storage)
+	suite.Require().NoError(merchantKeyRepo.Create(dummyApiKey))
+
+	suite.assertStatus(merchantKeyRepo.Revoke("MRC126", dummyApiKey.KeyId), http.StatusNotFound)
+	suite.assertStatus(merchantKeyRepo.Revoke("MRC125", "unknown"), http.StatusNotFound)
+	assert.Nil(suite.T(), merchantKeyRepo.Revoke("MRC125", dummyApiKey.KeyId))
+	assert.Nil(suite.T(), merchantKeyRepo.Revoke("MRC125", dummyApiKey.KeyId))
+
+	_, _, err := merchantKeyRepo.FindActive(dummyApiKey.KeyId)
+	suite.assertStatus(err, http.StatusUnauthorized)
+	apiKeys, err := merchantKeyRepo.FindByMerchant("MRC125")
+	suite.Require().NoError(err)
+	assert.False(suite.T(), apiKeys[0].Active())
+}
+
+func (suite *MerchantKeyRepoTestSuite) TestFindActive_FailedUnknownKey() {
+	_, _, err := NewMerchantKeyRepository(suite.storage).FindActive("unknown")
+	suite.assertStatus(err, http.StatusUnauthorized)
+}
+
+func TestMerchantKeyRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantKeyRepoTestSuite))
+}
@@ -1,87 +1,129 @@
 package repository
 
 import (
+	"errors"
 	"time"
 
-	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
 	"github.com/febriansr/simple-payment-api/model/app_error"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
-	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
 	"github.com/google/uuid"
 )
 
 type PaymentRepository interface {
-	PayTransaction(transaction entity.History) error
+	PayTransaction(transaction entity.History) (entity.History, error)
 }
 
 type paymentRepository struct {
-	config config.JsonFileConfig
+	storage storage.Storage
 }
 
-func (p *paymentRepository) PayTransaction(transaction entity.History) error {
-	var customers []entity.Customer
-	var merchants []entity.Merchant
-	var histories []entity.History
-	err := utils.ReadParseJSON(p.config.Customer, &customers)
+func (p *paymentRepository) PayTransaction(transaction entity.History) (entity.History, error) {
+	err := p.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		transaction, err = pay(tx, transaction)
+		return err
+	})
 	if err != nil {
-		return app_error.InternalServerError("Failed to read and parse customer data: " + err.Error())
+		return entity.History{}, err
 	}
+	return transaction, nil
+}
 
-	err = utils.ReadParseJSON(p.config.Merchant, &merchants)
+// pay posts a payment with its fee and records it in the history. It runs in
+// the caller's transaction so that other records can change with the payment.
+func pay(tx storage.Storage, transaction entity.History) (entity.History, error) {
+	customer, err := tx.Customers().FindByUsername(transaction.CustomerUsername)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.History{}, app_error.InvalidError("Invalid username")
+	}
 	if err != nil {
-		return app_error.InternalServerError("Failed to read and parse merchant data: " + err.Error())
+		return entity.History{}, err
 	}
 
-	err = utils.ReadParseJSON(p.config.History, &histories)
+	merchant, err := tx.Merchants().FindByCode(transaction.MerchantCode)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.History{}, app_error.InvalidError("Invalid merchant code")
+	}
 	if err != nil {
-		return app_error.InternalServerError("Failed to read and parse history data: " + err.Error())
+		return entity.History{}, err
 	}
 
-	isCustomer := false
-	isMerchant := false
+	transaction.Currency, err = walletCurrency(tx, customer, transaction.Currency)
+	if err != nil {
+		return entity.History{}, err
+	}
+	transaction.Date = time.Now()
+	transaction.TransactionId = uuid.New().String()
+	transaction.Type = entity.HistoryTypePayment
+	transaction.RecipientUsername = ""
 
-	for i, customer := range customers {
-		if customer.Username == transaction.CustomerUsername {
-			isCustomer = true
-			for _, merchant := range merchants {
-				if merchant.MerchantCode == transaction.MerchantCode {
-					isMerchant = true
-					if customer.Balance >= transaction.Amount {
-						transaction.Date = time.Now()
-						transaction.TransactionId = uuid.New().String()
-						customers[i].Balance -= transaction.Amount
-						break
-					} else {
-						return app_error.InvalidError("Balance insufficient")
-					}
-				}
-			}
+	rule, charge, err := paymentFee(tx, merchant, transaction.Currency, transaction.Amount)
+	if err != nil {
+		return entity.History{}, err
+	}
+	transaction.Fee = charge
+	transaction.FeeChargedTo = ""
+	debit, credit := transaction.Amount, transaction.Amount
+	if charge > 0 {
+		transaction.FeeChargedTo = rule.ChargedTo
+		if rule.ChargedTo == entity.FeeChargedToCustomer {
+			debit += charge
+		} else {
+			credit -= charge
 		}
 	}
 
-	if !isCustomer {
-		return app_error.InvalidError("Invalid username")
+	if transaction.Currency == merchant.Currency {
+		err = ledger.Post(tx, transaction.TransactionId, transaction.Currency,
+			ledger.Leg{Account: ledger.CustomerAccount(customer.Username), Amount: -debit},
+			ledger.Leg{Account: ledger.MerchantAccount(transaction.MerchantCode), Amount: credit},
+			ledger.Leg{Account: ledger.SystemFees, Amount: charge})
+	} else {
+		err = settle(tx, &transaction, merchant, debit, credit)
 	}
-
-	if !isMerchant {
-		return app_error.InvalidError("Invalid merchant code")
+	if err != nil {
+		return entity.History{}, err
+	}
+	if err = tx.Histories().Insert(transaction); err != nil {
+		return entity.History{}, err
 	}
+	if err = emit(tx, entity.EventPaymentSucceeded, transaction.MerchantCode, transaction); err != nil {
+		return entity.History{}, err
+	}
+	return transaction, nil
+}
 
-	err = utils.WriteJSON(p.config.Customer, customers)
+func settle(tx storage.Storage, transaction *entity.History, merchant entity.Merchant, debit money.Amount, credit money.Amount) error {
+	rate, settlement, err := convert(tx, transaction.Currency, merchant.Currency, credit)
 	if err != nil {
-		return app_error.InternalServerError("Failed to write updated customer data to file: " + err.Error())
+		return err
 	}
-
-	err = utils.WriteJSON(p.config.History, histories)
+	err = ledger.Post(tx, transaction.TransactionId, transaction.Currency,
+		ledger.Leg{Account: ledger.CustomerAccount(transaction.CustomerUsername), Amount: -debit},
+		ledger.Leg{Account: ledger.SystemFx, Amount: credit},
+		ledger.Leg{Account: ledger.SystemFees, Amount: debit - credit})
 	if err != nil {
-		return app_error.InternalServerError("Failed to write updated history data to file: " + err.Error())
+		return err
 	}
-
+	err = ledger.Transfer(tx, transaction.TransactionId, merchant.Currency,
+		ledger.SystemFx, ledger.MerchantAccount(merchant.MerchantCode), settlement)
+	if err != nil {
+		return err
+	}
+	rateAt := rate.RateAt
+	transaction.SettlementAmount = settlement
+	transaction.SettlementCurrency = merchant.Currency
+	transaction.FxRate = rate.Rate
+	transaction.FxSpreadBps = rate.SpreadBps
+	transaction.FxRateAt = &rateAt
 	return nil
 }
 
-func NewPaymentRepository(config config.JsonFileConfig) PaymentRepository {
+func NewPaymentRepository(storage storage.Storage) PaymentRepository {
 	return &paymentRepository{
-		config: config,
+		storage: storage,
 	}
 }
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+type PaymentRequestRepository interface {
+	Create(paymentRequest entity.PaymentRequest) (entity.PaymentRequest, error)
+	FindByCustomer(customerUsername string) ([]entity.PaymentRequest, error)
+	FindByMerchant(merchantCode string) ([]entity.PaymentRequest, error)
+	FindById(customerUsername string, requestId string) (entity.PaymentRequest, error)
+	Approve(customerUsername string, requestId string) (entity.PaymentRequest, error)
+	Decline(customerUsername string, requestId string) (entity.PaymentRequest, error)
+}
+
+type paymentRequestRepository struct {
+	storage storage.Storage
+}
+
+func (p *paymentRequestRepository) Create(paymentRequest entity.PaymentRequest) (entity.PaymentRequest, error) {
+	err := p.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(paymentRequest.CustomerUsername)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid username")
+		}
+		if err != nil {
+			return err
+		}
+		paymentRequest.Currency, err = walletCurrency(tx, customer, paymentRequest.Currency)
+		if err != nil {
+			return err
+		}
+		err = tx.PaymentRequests().Insert(paymentRequest)
+		if errors.Is(err, storage.ErrDuplicate) {
+			return app_error.InternalServerError("Failed to create payment request: duplicate request id")
+		}
+		if err != nil {
+			return err
+		}
+		return emit(tx, entity.EventPaymentRequestCreated, paymentRequest.MerchantCode, paymentRequest)
+	})
+	if err != nil {
+		return entity.PaymentRequest{}, err
+	}
+	return paymentRequest, nil
+}
+
+func (p *paymentRequestRepository) FindByCustomer(customerUsername string) ([]entity.PaymentRequest, error) {
+	return p.storage.PaymentRequests().FindByCustomer(customerUsername)
+}
+
+func (p *paymentRequestRepository) FindByMerchant(merchantCode string) ([]entity.PaymentRequest, error) {
+	return p.storage.PaymentRequests().FindByMerchant(merchantCode)
+}
+
+func (p *paymentRequestRepository) FindById(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	paymentRequest, err := p.storage.PaymentRequests().FindById(requestId)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && paymentRequest.CustomerUsername != customerUsername) {
+		return entity.PaymentRequest{}, app_error.DataNotFound("payment request not found")
+	}
+	return paymentRequest, err
+}
+
+// Approve pays a pending request and marks it approved with the id of the
+// payment in one transaction, so a failed payment leaves the request pending.
+func (p *paymentRequestRepository) Approve(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	var paymentRequest entity.PaymentRequest
+	err := p.storage.Atomic(func(tx storage.Storage) error {
+		now := time.Now().UTC()
+		var err error
+		paymentRequest, err = findPendingRequest(tx, customerUsername, requestId, now)
+		if err != nil {
+			return err
+		}
+		payment, err := pay(tx, entity.History{
+			CustomerUsername: paymentRequest.CustomerUsername,
+			MerchantCode:     paymentRequest.MerchantCode,
+			Amount:           paymentRequest.Amount,
+			Currency:         paymentRequest.Currency,
+			Reference:        paymentRequest.Reference,
+		})
+		if err != nil {
+			return err
+		}
+		paymentRequest.Status = entity.PaymentRequestStatusApproved
+		paymentRequest.ResolvedAt = &now
+		paymentRequest.TransactionId = payment.TransactionId
+		if err = tx.PaymentRequests().Update(paymentRequest); err != nil {
+			return err
+		}
+		return emit(tx, entity.EventPaymentRequestApproved, paymentRequest.MerchantCode, paymentRequest)
+	})
+	if err != nil {
+		return entity.PaymentRequest{}, err
+	}
+	return paymentRequest, nil
+}
+
+func (p *paymentRequestRepository) Decline(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	var paymentRequest entity.PaymentRequest
+	err := p.storage.Atomic(func(tx storage.Storage) error {
+		now := time.Now().UTC()
+		var err error
+		paymentRequest, err = findPendingRequest(tx, customerUsername, requestId, now)
+		if err != nil {
+			return err
+		}
+		paymentRequest.Status = entity.PaymentRequestStatusDeclined
+		paymentRequest.ResolvedAt = &now
+		if err = tx.PaymentRequests().Update(paymentRequest); err != nil {
+			return err
+		}
+		return emit(tx, entity.EventPaymentRequestDeclined, paymentRequest.MerchantCode, paymentRequest)
+	})
+	if err != nil {
+		return entity.PaymentRequest{}, err
+	}
+	return paymentRequest, nil
+}
+
+func findPendingRequest(tx storage.Storage, customerUsername string, requestId string, now time.Time) (entity.PaymentRequest, error) {
+	paymentRequest, err := tx.PaymentRequests().FindById(requestId)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && paymentRequest.CustomerUsername != customerUsername) {
+		return entity.PaymentRequest{}, app_error.DataNotFound("payment request not found")
+	}
+	if err != nil {
+		return entity.PaymentRequest{}, err
+	}
+	switch paymentRequest.StatusAt(now) {
+	case entity.PaymentRequestStatusPending:
+		return paymentRequest, nil
+	case entity.PaymentRequestStatusExpired:
+		return entity.PaymentRequest{}, app_error.InvalidError("payment request has expired")
+	default:
+		return entity.PaymentRequest{}, app_error.InvalidError("payment request is no longer pending")
+	}
+}
+
+func NewPaymentRequestRepository(storage storage.Storage) PaymentRequestRepository {
+	return &paymentRequestRepository{
+		storage: storage,
+	}
+}
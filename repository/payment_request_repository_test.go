@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaymentRequestRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *PaymentRequestRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{
+		{MerchantCode: "MRC125", Name: "Dummy Merchant", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+}
+
+func (suite *PaymentRequestRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *PaymentRequestRepoTestSuite) dummyPaymentRequest(requestId string, expiresAt time.Time) entity.PaymentRequest {
+	return entity.PaymentRequest{
+		RequestId:        requestId,
+		MerchantCode:     "MRC125",
+		CustomerUsername: "dummyUsername",
+		Amount:           money.MustParse("10"),
+		Reference:        "INV-" + requestId,
+		Status:           entity.PaymentRequestStatusPending,
+		CreatedAt:        time.Now().UTC(),
+		ExpiresAt:        expiresAt,
+	}
+}
+
+func (suite *PaymentRequestRepoTestSuite) TestCreate() {
+	paymentRequestRepo := NewPaymentRequestRepository(suite.storage)
+
+	paymentRequest, err := paymentRequestRepo.Create(suite.dummyPaymentRequest("pr-1", time.Now().Add(time.Hour)))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.DefaultCurrency, paymentRequest.Currency)
+
+	paymentRequests, err := paymentRequestRepo.FindByMerchant("MRC125")
+	assert.Nil(suite.T(), err)
+	suite.Require().Len(paymentRequests, 1)
+	assert.Equal(suite.T(), paymentRequest.RequestId, paymentRequests[0].RequestId)
+	assert.Equal(suite.T(), paymentRequest.Reference, paymentRequests[0].Reference)
+}
+
+func (suite *PaymentRequestRepoTestSuite) TestCreate_FailedValidation() {
+	paymentRequestRepo := NewPaymentRequestRepository(suite.storage)
+
+	paymentRequest := suite.dummyPaymentRequest("pr-1", time.Now().Add(time.Hour))
+	paymentRequest.CustomerUsername = "unknown"
+	_, err := paymentRequestRepo.Create(paymentRequest)
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	paymentRequest = suite.dummyPaymentRequest("pr-1", time.Now().Add(time.Hour))
+	paymentRequest.Currency = "USD"
+	_, err = paymentRequestRepo.Create(paymentRequest)
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func (suite *PaymentRequestRepoTestSuite) TestApprove() {
+	paymentRequestRepo := NewPaymentRequestRepository(suite.storage)
+	_, err := paymentRequestRepo.Create(suite.dummyPaymentRequest("pr-1", time.Now().Add(time.Hour)))
+	suite.Require().NoError(err)
+
+	_, err = paymentRequestRepo.FindById("otherUsername", "pr-1")
+	suite.assertStatus(err, http.StatusNotFound)
+	_, err = paymentRequestRepo.Approve("otherUsername", "pr-1")
+	suite.assertStatus(err, http.StatusNotFound)
+
+	paymentRequest, err := paymentRequestRepo.Approve("dummyUsername", "pr-1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.PaymentRequestStatusApproved, paymentRequest.Status)
+	assert.NotNil(suite.T(), paymentRequest.ResolvedAt)
+	history, err := suite.storage.Histories().FindById(paymentRequest.TransactionId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "INV-pr-1", history.Reference)
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("90"), customer.Balance)
+	stored, err := paymentRequestRepo.FindById("dummyUsername", "pr-1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), paymentRequest.TransactionId, stored.TransactionId)
+
+	_, err = paymentRequestRepo.Approve("dummyUsername", "pr-1")
+	suite.assertStatus(err, http.StatusBadRequest)
+	_, err = paymentRequestRepo.Decline("dummyUsername", "pr-1")
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func (suite *PaymentRequestRepoTestSuite) TestApprove_FailedPaymentStaysPending() {
+	paymentRequestRepo := NewPaymentRequestRepository(suite.storage)
+	paymentRequest := suite.dummyPaymentRequest("pr-1", time.Now().Add(time.Hour))
+	paymentRequest.Amount = money.MustParse("1000")
+	_, err := paymentRequestRepo.Create(paymentRequest)
+	suite.Require().NoError(err)
+
+	_, err = paymentRequestRepo.Approve("dummyUsername", "pr-1")
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	stored, err := paymentRequestRepo.FindById("dummyUsername", "pr-1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.PaymentRequestStatusPending, stored.Status)
+	assert.Empty(suite.T(), stored.TransactionId)
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("100"), customer.Balance)
+}
+
+func (suite *PaymentRequestRepoTestSuite) TestDecline() {
+	paymentRequestRepo := NewPaymentRequestRepository(suite.storage)
+	_, err := paymentRequestRepo.Create(suite.dummyPaymentRequest("pr-1", time.Now().Add(time.Hour)))
+	suite.Require().NoError(err)
+	_, err = paymentRequestRepo.Create(suite.dummyPaymentRequest("pr-2", time.Now().Add(-time.Minute)))
+	suite.Require().NoError(err)
+
+	paymentRequest, err := paymentRequestRepo.Decline("dummyUsername", "pr-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), entity.PaymentRequestStatusDeclined, paymentRequest.Status)
+
+	_, err = paymentRequestRepo.Decline("dummyUsername", "pr-2")
+	suite.assertStatus(err, http.StatusBadRequest)
+	_, err = paymentRequestRepo.Decline("dummyUsername", "unknown")
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func TestPaymentRequestRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentRequestRepoTestSuite))
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const InvalidPinMessage = "Invalid transaction PIN"
+
+type PinRepository interface {
+	SetPin(username string, password string, pinHash string) error
+	VerifyPin(username string, pin string) error
+	ConvertAmount(username string, amount money.Amount, currency string, target string) (money.Amount, bool, error)
+}
+
+type pinRepository struct {
+	storage storage.Storage
+}
+
+func (p *pinRepository) SetPin(username string, password string, pinHash string) error {
+	return p.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := p.findCustomer(tx, username)
+		if err != nil {
+			return err
+		}
+		if bcrypt.CompareHashAndPassword([]byte(customer.Password), []byte(password)) != nil {
+			return app_error.Forbidden("Invalid password")
+		}
+		customer.PinHash = pinHash
+		return tx.Customers().Update(customer)
+	})
+}
+
+func (p *pinRepository) VerifyPin(username string, pin string) error {
+	customer, err := p.findCustomer(p.storage, username)
+	if err != nil {
+		return err
+	}
+	if customer.PinHash == "" {
+		return app_error.InvalidError("Transaction PIN not set")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(customer.PinHash), []byte(pin)) != nil {
+		return app_error.Forbidden(InvalidPinMessage)
+	}
+	return nil
+}
+
+// ConvertAmount values an amount in the target currency at the mid rate of
+// the stored exchange rates. An empty currency is the customer's primary
+// currency. It reports false when no rate between the currencies exists.
+func (p *pinRepository) ConvertAmount(username string, amount money.Amount, currency string, target string) (money.Amount, bool, error) {
+	if currency == "" {
+		customer, err := p.findCustomer(p.storage, username)
+		if err != nil {
+			return 0, false, err
+		}
+		currency = customer.Currency
+	}
+	return value(p.storage, currency, target, amount)
+}
+
+func (p *pinRepository) findCustomer(store storage.Storage, username string) (entity.Customer, error) {
+	customer, err := store.Customers().FindByUsername(username)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.Customer{}, app_error.DataNotFound("customer not found")
+	}
+	return customer, err
+}
+
+func NewPinRepository(storage storage.Storage) PinRepository {
+	return &pinRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type PinRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *PinRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte("dummyPassword1"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Password: string(hash), Currency: money.DefaultCurrency},
+		{Username: "usdUsername", Password: string(hash), Currency: "USD"},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+}
+
+func (suite *PinRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *PinRepoTestSuite) TestSetAndVerifyPin() {
+	pinRepo := NewPinRepository(suite.storage)
+	suite.assertStatus(pinRepo.VerifyPin("dummyUsername", "123456"), http.StatusBadRequest)
+
+	pinHash, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	suite.assertStatus(pinRepo.SetPin("dummyUsername", "wrongPassword1", string(pinHash)), http.StatusForbidden)
+	assert.Nil(suite.T(), pinRepo.SetPin("dummyUsername", "dummyPassword1", string(pinHash)))
+
+	assert.Nil(suite.T(), pinRepo.VerifyPin("dummyUsername", "123456"))
+	suite.assertStatus(pinRepo.VerifyPin("dummyUsername", "654321"), http.StatusForbidden)
+}
+
+func (suite *PinRepoTestSuite) TestSetPin_FailedUnknownCustomer() {
+	pinRepo := NewPinRepository(suite.storage)
+	suite.assertStatus(pinRepo.SetPin("unknown", "dummyPassword1", "hash"), http.StatusNotFound)
+	suite.assertStatus(pinRepo.VerifyPin("unknown", "123456"), http.StatusNotFound)
+}
+
+func (suite *PinRepoTestSuite) TestConvertAmount_CrossCurrency() {
+	suite.Require().NoError(suite.storage.FxRates().Save(entity.FxRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15000", SpreadBps: 100, RateAt: time.Now().UTC()}))
+	suite.Require().NoError(suite.storage.FxRates().Save(entity.FxRate{BaseCurrency: "IDR", QuoteCurrency: "JPY", Rate: "0.01", RateAt: time.Now().UTC()}))
+	pinRepo := NewPinRepository(suite.storage)
+
+	converted, ok, err := pinRepo.ConvertAmount("usdUsername", money.MustParse("100"), "", money.DefaultCurrency)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), money.MustParse("1500000"), converted)
+
+	converted, ok, err = pinRepo.ConvertAmount("dummyUsername", money.MustParse("100"), "", money.DefaultCurrency)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), money.MustParse("100"), converted)
+
+	converted, ok, err = pinRepo.ConvertAmount("dummyUsername", money.MustParse("10000"), "JPY", money.DefaultCurrency)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), money.MustParse("1000000"), converted)
+
+	_, ok, err = pinRepo.ConvertAmount("dummyUsername", money.MustParse("100"), "EUR", money.DefaultCurrency)
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), ok)
+}
+
+func TestPinRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(PinRepoTestSuite))
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+)
+
+type RefreshRepository interface {
+	Refresh(refreshToken string) (authenticator.TokenDetails, error)
+}
+
+type refreshRepository struct {
+	storage       storage.Storage
+	authenticator authenticator.AccessToken
+}
+
+func (r *refreshRepository) Refresh(refreshToken string) (authenticator.TokenDetails, error) {
+	return r.authenticator.RefreshAccessToken(refreshToken, r.currentRole)
+}
+
+func (r *refreshRepository) currentRole(username string) (string, error) {
+	customer, err := r.storage.Customers().FindByUsername(username)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", app_error.Unauthorized("Invalid access method")
+	}
+	if err != nil {
+		return "", err
+	}
+	return customer.Role, nil
+}
+
+func NewRefreshRepository(storage storage.Storage, authenticator authenticator.AccessToken) RefreshRepository {
+	return &refreshRepository{
+		storage:       storage,
+		authenticator: authenticator,
+	}
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/fx"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+type RefundRepository interface {
+	Refund(request req.RefundRequest) (entity.History, error)
+}
+
+type refundRepository struct {
+	storage storage.Storage
+}
+
+func (r *refundRepository) Refund(request req.RefundRequest) (entity.History, error) {
+	var refund entity.History
+	err := r.storage.Atomic(func(tx storage.Storage) error {
+		payment, err := tx.Histories().FindById(request.TransactionId)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && request.MerchantCode != "" && payment.MerchantCode != request.MerchantCode) {
+			return app_error.DataNotFound("transaction not found")
+		}
+		if err != nil {
+			return err
+		}
+		if payment.Type != entity.HistoryTypePayment {
+			return app_error.InvalidError("Only payments can be refunded")
+		}
+
+		refunds, err := tx.Histories().Find(storage.HistoryFilter{
+			Type:     entity.HistoryTypeRefund,
+			RefundOf: payment.TransactionId,
+		})
+		if err != nil {
+			return err
+		}
+		var refunded, refundedFee money.Amount
+		for _, previous := range refunds {
+			refunded += previous.Amount
+			refundedFee += previous.Fee
+		}
+		refundable := payment.Amount - refunded
+		if refundable <= 0 {
+			return app_error.Conflict("Transaction already fully refunded")
+		}
+		amount := request.Amount
+		if amount == 0 {
+			amount = refundable
+		}
+		if amount > refundable {
+			return app_error.InvalidError("Refund amount exceeds refundable amount")
+		}
+
+		fee := payment.Fee - refundedFee
+		if amount < refundable {
+			fee = proRata(payment.Fee, amount, payment.Amount)
+		}
+		refund = entity.History{
+			TransactionId:    uuid.New().String(),
+			Type:             entity.HistoryTypeRefund,
+			CustomerUsername: payment.CustomerUsername,
+			MerchantCode:     payment.MerchantCode,
+			Amount:           amount,
+			Currency:         payment.Currency,
+			RefundOf:         payment.TransactionId,
+			Reason:           request.Reason,
+			Date:             time.Now(),
+		}
+		if fee > 0 {
+			refund.Fee = fee
+			refund.FeeChargedTo = payment.FeeChargedTo
+		}
+
+		// The refund reverses the payment postings pro rata, fee leg included,
+		// so the party that bore the fee gets its share of it back.
+		debit, credit := amount, amount
+		if payment.FeeChargedTo == entity.FeeChargedToCustomer {
+			credit += fee
+		} else {
+			debit -= fee
+		}
+		if payment.SettlementCurrency == "" {
+			err = ledger.Post(tx, refund.TransactionId, refund.Currency,
+				ledger.Leg{Account: ledger.MerchantAccount(payment.MerchantCode), Amount: -debit},
+				ledger.Leg{Account: ledger.SystemFees, Amount: -fee},
+				ledger.Leg{Account: ledger.CustomerAccount(payment.CustomerUsername), Amount: credit})
+		} else {
+			err = r.reverseSettlement(tx, &refund, payment, debit, credit)
+		}
+		if err != nil {
+			return err
+		}
+		if err = tx.Histories().Insert(refund); err != nil {
+			return err
+		}
+		return emit(tx, entity.EventRefundCreated, refund.MerchantCode, refund)
+	})
+	if err != nil {
+		return entity.History{}, err
+	}
+	return refund, nil
+}
+
+func (r *refundRepository) reverseSettlement(tx storage.Storage, refund *entity.History, payment entity.History, debit money.Amount, credit money.Amount) error {
+	settlement, err := fx.Convert(debit, payment.FxRate, payment.FxSpreadBps)
+	if err != nil {
+		return app_error.InternalServerError("Failed to convert refund amount: " + err.Error())
+	}
+	err = ledger.Transfer(tx, refund.TransactionId, payment.SettlementCurrency,
+		ledger.MerchantAccount(payment.MerchantCode), ledger.SystemFx, settlement)
+	if err != nil {
+		return err
+	}
+	err = ledger.Post(tx, refund.TransactionId, refund.Currency,
+		ledger.Leg{Account: ledger.SystemFx, Amount: -debit},
+		ledger.Leg{Account: ledger.SystemFees, Amount: -refund.Fee},
+		ledger.Leg{Account: ledger.CustomerAccount(payment.CustomerUsername), Amount: credit})
+	if err != nil {
+		return err
+	}
+	refund.SettlementAmount = settlement
+	refund.SettlementCurrency = payment.SettlementCurrency
+	refund.FxRate = payment.FxRate
+	refund.FxSpreadBps = payment.FxSpreadBps
+	refund.FxRateAt = payment.FxRateAt
+	return nil
+}
+
+// proRata returns the share of total that part is of whole, rounded down. The
+// product is computed in a big.Int, so large amounts cannot overflow it.
+func proRata(total money.Amount, part money.Amount, whole money.Amount) money.Amount {
+	share := new(big.Int).Mul(big.NewInt(total.Minor()), big.NewInt(part.Minor()))
+	return money.FromMinor(share.Quo(share, big.NewInt(whole.Minor())).Int64())
+}
+
+func NewRefundRepository(storage storage.Storage) RefundRepository {
+	return &refundRepository{
+		storage: storage,
+	}
+}
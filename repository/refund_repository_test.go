@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefundRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *RefundRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{{MerchantCode: "MRC125", Balance: money.MustParse("50"), Currency: money.DefaultCurrency}}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{
+		{
+			TransactionId:    "payment-1",
+			Type:             entity.HistoryTypePayment,
+			CustomerUsername: "dummyUsername",
+			MerchantCode:     "MRC125",
+			Amount:           money.MustParse("50"),
+			Currency:         money.DefaultCurrency,
+			Date:             time.Now(),
+		},
+	}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+}
+
+func (suite *RefundRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *RefundRepoTestSuite) balance() money.Amount {
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	return customer.Balance
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_FullByDefault() {
+	refundRepo := NewRefundRepository(suite.storage)
+	refund, err := refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-1", Reason: "Dummy Reason"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("50"), refund.Amount)
+	assert.Equal(suite.T(), entity.HistoryTypeRefund, refund.Type)
+	assert.Equal(suite.T(), "payment-1", refund.RefundOf)
+	assert.Equal(suite.T(), "Dummy Reason", refund.Reason)
+	assert.NotEmpty(suite.T(), refund.TransactionId)
+	assert.Equal(suite.T(), money.MustParse("150"), suite.balance())
+
+	_, err = refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-1"})
+	suite.assertStatus(err, http.StatusConflict)
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_MultiplePartial() {
+	refundRepo := NewRefundRepository(suite.storage)
+	_, err := refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-1", Amount: money.MustParse("20")})
+	assert.Nil(suite.T(), err)
+	_, err = refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-1", Amount: money.MustParse("30.01")})
+	suite.assertStatus(err, http.StatusBadRequest)
+	refund, err := refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-1", Amount: money.MustParse("30")})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("30"), refund.Amount)
+	assert.Equal(suite.T(), money.MustParse("150"), suite.balance())
+
+	histories, err := suite.storage.Histories().Find(storage.HistoryFilter{RefundOf: "payment-1"})
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), histories, 2)
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_PartialFeeOfLargePayment() {
+	merchant, err := suite.storage.Merchants().FindByCode("MRC125")
+	suite.Require().NoError(err)
+	merchant.Balance = money.FromMinor(4_000_000_000_000_000_000)
+	suite.Require().NoError(suite.storage.Merchants().Update(merchant))
+	suite.Require().NoError(suite.storage.Histories().Insert(entity.History{
+		TransactionId:    "payment-2",
+		Type:             entity.HistoryTypePayment,
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "MRC125",
+		Amount:           money.FromMinor(4_000_000_000_000_000_000),
+		Currency:         money.DefaultCurrency,
+		Fee:              money.FromMinor(40_000_000_000_000_003),
+		FeeChargedTo:     entity.FeeChargedToMerchant,
+		Date:             time.Now(),
+	}))
+
+	refundRepo := NewRefundRepository(suite.storage)
+	refund, err := refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-2", Amount: money.FromMinor(2_000_000_000_000_000_000)})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.FromMinor(20_000_000_000_000_001), refund.Fee)
+
+	refund, err = refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-2"})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.FromMinor(20_000_000_000_000_002), refund.Fee)
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_FailedUnknownTransaction() {
+	refundRepo := NewRefundRepository(suite.storage)
+	_, err := refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "unknown"})
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_FailedOtherMerchant() {
+	refundRepo := NewRefundRepository(suite.storage)
+	_, err := refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC226", TransactionId: "payment-1"})
+	suite.assertStatus(err, http.StatusNotFound)
+	assert.Equal(suite.T(), money.MustParse("100"), suite.balance())
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_Admin() {
+	refundRepo := NewRefundRepository(suite.storage)
+	refund, err := refundRepo.Refund(req.RefundRequest{TransactionId: "payment-1", Amount: money.MustParse("10")})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "MRC125", refund.MerchantCode)
+	assert.Equal(suite.T(), money.MustParse("110"), suite.balance())
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_FailedMerchantBalanceInsufficient() {
+	merchant, err := suite.storage.Merchants().FindByCode("MRC125")
+	suite.Require().NoError(err)
+	merchant.Balance = money.MustParse("20")
+	suite.Require().NoError(suite.storage.Merchants().Update(merchant))
+
+	refundRepo := NewRefundRepository(suite.storage)
+	_, err = refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-1", Amount: money.MustParse("30")})
+	suite.assertStatus(err, http.StatusBadRequest)
+	assert.Equal(suite.T(), money.MustParse("100"), suite.balance())
+	merchant, _ = suite.storage.Merchants().FindByCode("MRC125")
+	assert.Equal(suite.T(), money.MustParse("20"), merchant.Balance)
+
+	histories, err := suite.storage.Histories().Find(storage.HistoryFilter{RefundOf: "payment-1"})
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), histories)
+}
+
+func (suite *RefundRepoTestSuite) TestRefund_FailedRefundOfRefund() {
+	refundRepo := NewRefundRepository(suite.storage)
+	refund, err := refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: "payment-1", Amount: money.MustParse("10")})
+	suite.Require().NoError(err)
+	_, err = refundRepo.Refund(req.RefundRequest{MerchantCode: "MRC125", TransactionId: refund.TransactionId})
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func TestRefundRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundRepoTestSuite))
+}
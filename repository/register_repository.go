@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+type RegisterRepository interface {
+	CreateCustomer(customer entity.Customer) error
+}
+
+type registerRepository struct {
+	storage storage.Storage
+}
+
+func (r *registerRepository) CreateCustomer(customer entity.Customer) error {
+	err := r.storage.Customers().Insert(customer)
+	if errors.Is(err, storage.ErrDuplicate) {
+		return app_error.Conflict("Username already taken")
+	}
+	return err
+}
+
+func NewRegisterRepository(storage storage.Storage) RegisterRepository {
+	return &registerRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+)
+
+type RoleRepository interface {
+	SetRole(username string, role string) error
+}
+
+type roleRepository struct {
+	storage       storage.Storage
+	authenticator authenticator.AccessToken
+}
+
+// SetRole also revokes every session of the account, so tokens carrying the
+// old role stop working instead of living on until they expire.
+func (r *roleRepository) SetRole(username string, role string) error {
+	err := r.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(username)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.DataNotFound("customer not found")
+		}
+		if err != nil {
+			return err
+		}
+		customer.Role = role
+		return tx.Customers().Update(customer)
+	})
+	if err != nil {
+		return err
+	}
+	return r.authenticator.RevokeSessions(username)
+}
+
+func NewRoleRepository(storage storage.Storage, authenticator authenticator.AccessToken) RoleRepository {
+	return &roleRepository{
+		storage:       storage,
+		authenticator: authenticator,
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleRepoTestSuite struct {
+	suite.Suite
+	storage       storage.Storage
+	authenticator authenticator.AccessToken
+}
+
+func (suite *RoleRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.authenticator = newTestAuthenticator(suite.T())
+}
+
+func newTestAuthenticator(t *testing.T) authenticator.AccessToken {
+	server := miniredis.RunT(t)
+	tokenConfig := config.TokenConfig{
+		ApplicationName:      "simplepayment",
+		JwtSignatureKey:      "secretkey",
+		JwtSigningMethod:     "HS256",
+		AccessTokenLifetime:  time.Minute,
+		RefreshTokenLifetime: time.Hour,
+	}
+	keys, err := authenticator.NewKeySet(tokenConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authenticator.NewAccessToken(tokenConfig, keys, redis.NewClient(&redis.Options{Addr: server.Addr()}))
+}
+
+func (suite *RoleRepoTestSuite) login(customer entity.Customer) authenticator.TokenDetails {
+	tokenDetails, err := suite.authenticator.CreateAccessToken(&customer)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.authenticator.StoreAccessToken(customer.Username, tokenDetails))
+	return tokenDetails
+}
+
+func (suite *RoleRepoTestSuite) TestSetRole_Success() {
+	err := NewRoleRepository(suite.storage, suite.authenticator).SetRole("dummyUsername", "admin")
+	assert.Nil(suite.T(), err)
+
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "admin", customer.Role)
+}
+
+func (suite *RoleRepoTestSuite) TestSetRole_RevokesSessions() {
+	tokenDetails := suite.login(entity.Customer{Username: "dummyUsername", Role: "admin"})
+
+	err := NewRoleRepository(suite.storage, suite.authenticator).SetRole("dummyUsername", "customer")
+	assert.Nil(suite.T(), err)
+
+	sessions, err := suite.authenticator.ListSessions("dummyUsername")
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), sessions)
+	_, err = NewRefreshRepository(suite.storage, suite.authenticator).Refresh(tokenDetails.RefreshToken)
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusUnauthorized, appError.ErrorType)
+}
+
+func (suite *RoleRepoTestSuite) TestRefresh_UsesStoredRole() {
+	tokenDetails := suite.login(entity.Customer{Username: "dummyUsername", Role: "admin"})
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	customer.Role = "customer"
+	suite.Require().NoError(suite.storage.Customers().Update(customer))
+
+	refreshed, err := NewRefreshRepository(suite.storage, suite.authenticator).Refresh(tokenDetails.RefreshToken)
+	suite.Require().NoError(err)
+	accessDetails, err := suite.authenticator.VerifyAccessToken(refreshed.AccessToken)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), authenticator.RoleCustomer, accessDetails.Role)
+	assert.Equal(suite.T(), authenticator.ScopesFor(authenticator.RoleCustomer), accessDetails.Scopes)
+}
+
+func (suite *RoleRepoTestSuite) TestSetRole_FailedUnknownCustomer() {
+	err := NewRoleRepository(suite.storage, suite.authenticator).SetRole("unknown", "admin")
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusNotFound, appError.ErrorType)
+}
+
+func TestRoleRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleRepoTestSuite))
+}
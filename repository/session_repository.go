@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+)
+
+type SessionRepository interface {
+	FindSessions(username string) ([]authenticator.Session, error)
+	RevokeSession(username string, sessionId string) error
+}
+
+type sessionRepository struct {
+	authenticator authenticator.AccessToken
+}
+
+func (s *sessionRepository) FindSessions(username string) ([]authenticator.Session, error) {
+	return s.authenticator.ListSessions(username)
+}
+
+func (s *sessionRepository) RevokeSession(username string, sessionId string) error {
+	return s.authenticator.RevokeSession(username, sessionId)
+}
+
+func NewSessionRepository(authenticator authenticator.AccessToken) SessionRepository {
+	return &sessionRepository{
+		authenticator: authenticator,
+	}
+}
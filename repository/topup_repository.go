@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+type TopUpRepository interface {
+	Create(topUp entity.TopUp, maxAmount money.Amount) (entity.TopUp, error)
+	AttachCharge(topUpId string, reference string) (entity.TopUp, error)
+	Complete(topUpId string, amount money.Amount, currency string) (entity.TopUp, error)
+	Fail(topUpId string, reason string) (entity.TopUp, error)
+	FindById(topUpId string) (entity.TopUp, error)
+	FindByCustomer(customerUsername string) ([]entity.TopUp, error)
+	FindPending(createdBefore time.Time) ([]entity.TopUp, error)
+}
+
+type topUpRepository struct {
+	storage storage.Storage
+}
+
+// Create records a pending top-up in the customer's wallet currency. The
+// maximum amount is in the default currency, so the top-up is valued at the
+// mid rate before it is compared.
+func (t *topUpRepository) Create(topUp entity.TopUp, maxAmount money.Amount) (entity.TopUp, error) {
+	err := t.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(topUp.CustomerUsername)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid username")
+		}
+		if err != nil {
+			return err
+		}
+		topUp.Currency, err = walletCurrency(tx, customer, topUp.Currency)
+		if err != nil {
+			return err
+		}
+		if maxAmount > 0 {
+			valued, ok, err := value(tx, topUp.Currency, money.DefaultCurrency, topUp.Amount)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return app_error.InvalidError("No exchange rate to check the top-up limit")
+			}
+			if valued > maxAmount {
+				return app_error.InvalidError("amount exceeds the top-up limit")
+			}
+		}
+
+		err = tx.TopUps().Insert(topUp)
+		if errors.Is(err, storage.ErrDuplicate) {
+			return app_error.InternalServerError("Failed to create top-up: duplicate top-up id")
+		}
+		return err
+	})
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	return topUp, nil
+}
+
+func (t *topUpRepository) AttachCharge(topUpId string, reference string) (entity.TopUp, error) {
+	var topUp entity.TopUp
+	err := t.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		if topUp, err = t.find(tx, topUpId); err != nil {
+			return err
+		}
+		topUp.GatewayReference = reference
+		return tx.TopUps().Update(topUp)
+	})
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	return topUp, nil
+}
+
+func (t *topUpRepository) Complete(topUpId string, amount money.Amount, currency string) (entity.TopUp, error) {
+	var topUp entity.TopUp
+	err := t.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		if topUp, err = t.find(tx, topUpId); err != nil {
+			return err
+		}
+		switch topUp.Status {
+		case entity.TopUpStatusSucceeded:
+			return nil
+		case entity.TopUpStatusFailed:
+			return app_error.InvalidError("top-up has already failed")
+		}
+		if amount != topUp.Amount || currency != topUp.Currency {
+			return app_error.InvalidError("charge does not match the top-up amount")
+		}
+
+		now := time.Now()
+		transactionId := uuid.New().String()
+		err = ledger.Transfer(tx, transactionId, topUp.Currency, ledger.SystemFunding, ledger.CustomerAccount(topUp.CustomerUsername), topUp.Amount)
+		if err != nil {
+			return err
+		}
+		err = tx.Histories().Insert(entity.History{
+			TransactionId:    transactionId,
+			Type:             entity.HistoryTypeTopUp,
+			CustomerUsername: topUp.CustomerUsername,
+			Amount:           topUp.Amount,
+			Currency:         topUp.Currency,
+			Reference:        topUp.TopUpId,
+			Date:             now,
+		})
+		if err != nil {
+			return err
+		}
+
+		resolvedAt := now.UTC()
+		topUp.Status = entity.TopUpStatusSucceeded
+		topUp.TransactionId = transactionId
+		topUp.ResolvedAt = &resolvedAt
+		if err = tx.TopUps().Update(topUp); err != nil {
+			return err
+		}
+		return emit(tx, entity.EventTopUpSucceeded, "", topUp)
+	})
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	return topUp, nil
+}
+
+func (t *topUpRepository) Fail(topUpId string, reason string) (entity.TopUp, error) {
+	var topUp entity.TopUp
+	err := t.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		if topUp, err = t.find(tx, topUpId); err != nil {
+			return err
+		}
+		switch topUp.Status {
+		case entity.TopUpStatusFailed:
+			return nil
+		case entity.TopUpStatusSucceeded:
+			return app_error.InvalidError("top-up has already succeeded")
+		}
+
+		resolvedAt := time.Now().UTC()
+		topUp.Status = entity.TopUpStatusFailed
+		topUp.FailureReason = reason
+		topUp.ResolvedAt = &resolvedAt
+		if err = tx.TopUps().Update(topUp); err != nil {
+			return err
+		}
+		return emit(tx, entity.EventTopUpFailed, "", topUp)
+	})
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	return topUp, nil
+}
+
+func (t *topUpRepository) FindById(topUpId string) (entity.TopUp, error) {
+	return t.find(t.storage, topUpId)
+}
+
+func (t *topUpRepository) FindByCustomer(customerUsername string) ([]entity.TopUp, error) {
+	return t.storage.TopUps().FindByCustomer(customerUsername)
+}
+
+func (t *topUpRepository) FindPending(createdBefore time.Time) ([]entity.TopUp, error) {
+	return t.storage.TopUps().FindPending(createdBefore)
+}
+
+func (t *topUpRepository) find(tx storage.Storage, topUpId string) (entity.TopUp, error) {
+	topUp, err := tx.TopUps().FindById(topUpId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.TopUp{}, app_error.DataNotFound("top-up not found")
+	}
+	return topUp, err
+}
+
+func NewTopUpRepository(storage storage.Storage) TopUpRepository {
+	return &topUpRepository{
+		storage: storage,
+	}
+}
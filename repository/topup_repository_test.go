@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TopUpRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *TopUpRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+}
+
+func (suite *TopUpRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *TopUpRepoTestSuite) assertBalance(expected string) {
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse(expected), customer.Balance)
+	report, err := ledger.Check(suite.storage)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), report.Balanced(), report.String())
+}
+
+func (suite *TopUpRepoTestSuite) create(topUpId string) entity.TopUp {
+	topUp, err := NewTopUpRepository(suite.storage).Create(entity.TopUp{
+		TopUpId:          topUpId,
+		CustomerUsername: "dummyUsername",
+		Amount:           money.MustParse("50"),
+		Gateway:          "simulator",
+		Status:           entity.TopUpStatusPending,
+		CreatedAt:        time.Now().UTC(),
+	}, 0)
+	suite.Require().NoError(err)
+	return topUp
+}
+
+func (suite *TopUpRepoTestSuite) TestComplete_CreditsOnce() {
+	topUpRepo := NewTopUpRepository(suite.storage)
+	topUp := suite.create("topup-1")
+	assert.Equal(suite.T(), money.DefaultCurrency, topUp.Currency)
+	_, err := topUpRepo.AttachCharge("topup-1", "sim_1")
+	suite.Require().NoError(err)
+
+	completed, err := topUpRepo.Complete("topup-1", money.MustParse("50"), money.DefaultCurrency)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.TopUpStatusSucceeded, completed.Status)
+	assert.Equal(suite.T(), "sim_1", completed.GatewayReference)
+	assert.NotEmpty(suite.T(), completed.TransactionId)
+	again, err := topUpRepo.Complete("topup-1", money.MustParse("50"), money.DefaultCurrency)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), completed.TransactionId, again.TransactionId)
+	suite.assertBalance("150")
+
+	history, err := suite.storage.Histories().FindById(completed.TransactionId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.HistoryTypeTopUp, history.Type)
+	events, err := suite.storage.Outbox().FindAfter(0, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 1)
+	assert.Equal(suite.T(), entity.EventTopUpSucceeded, events[0].Type)
+
+	_, err = topUpRepo.Fail("topup-1", "card declined")
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func (suite *TopUpRepoTestSuite) TestComplete_FailedAmountMismatch() {
+	suite.create("topup-1")
+
+	_, err := NewTopUpRepository(suite.storage).Complete("topup-1", money.MustParse("500"), money.DefaultCurrency)
+
+	suite.assertStatus(err, http.StatusBadRequest)
+	suite.assertBalance("100")
+}
+
+func (suite *TopUpRepoTestSuite) TestFail() {
+	topUpRepo := NewTopUpRepository(suite.storage)
+	suite.create("topup-1")
+
+	failed, err := topUpRepo.Fail("topup-1", "card declined")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.TopUpStatusFailed, failed.Status)
+	assert.Equal(suite.T(), "card declined", failed.FailureReason)
+	_, err = topUpRepo.Fail("topup-1", "card declined")
+	suite.Require().NoError(err)
+
+	_, err = topUpRepo.Complete("topup-1", money.MustParse("50"), money.DefaultCurrency)
+	suite.assertStatus(err, http.StatusBadRequest)
+	suite.assertBalance("100")
+	pending, err := topUpRepo.FindPending(time.Now().Add(time.Minute))
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), pending)
+}
+
+func (suite *TopUpRepoTestSuite) TestCreate_FailedUnknownCustomer() {
+	_, err := NewTopUpRepository(suite.storage).Create(entity.TopUp{TopUpId: "topup-1", CustomerUsername: "unknown", Amount: money.MustParse("50")}, 0)
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	_, err = NewTopUpRepository(suite.storage).FindById("topup-1")
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func (suite *TopUpRepoTestSuite) TestCreate_LimitInDefaultCurrency() {
+	topUpRepo := NewTopUpRepository(suite.storage)
+	_, err := NewWalletRepository(suite.storage).Open(entity.Wallet{Username: "dummyUsername", Currency: "USD", CreatedAt: time.Now().UTC()})
+	suite.Require().NoError(err)
+	limit := money.MustParse("1000000")
+
+	_, err = topUpRepo.Create(entity.TopUp{TopUpId: "topup-1", CustomerUsername: "dummyUsername", Amount: money.MustParse("50"), Currency: "USD"}, limit)
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	suite.Require().NoError(NewFxRepository(suite.storage).SaveRates([]entity.FxRate{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15000", RateAt: time.Now().UTC(), Source: entity.FxRateSourceFile},
+	}))
+	topUp, err := topUpRepo.Create(entity.TopUp{TopUpId: "topup-2", CustomerUsername: "dummyUsername", Amount: money.MustParse("50"), Currency: "USD"}, limit)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "USD", topUp.Currency)
+
+	_, err = topUpRepo.Create(entity.TopUp{TopUpId: "topup-3", CustomerUsername: "dummyUsername", Amount: money.MustParse("100"), Currency: "USD"}, limit)
+	suite.assertStatus(err, http.StatusBadRequest)
+	_, err = topUpRepo.Create(entity.TopUp{TopUpId: "topup-4", CustomerUsername: "dummyUsername", Amount: money.MustParse("1000001")}, limit)
+	suite.assertStatus(err, http.StatusBadRequest)
+}
+
+func TestTopUpRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(TopUpRepoTestSuite))
+}
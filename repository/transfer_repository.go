@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+type TransferRepository interface {
+	Transfer(transfer entity.History) (entity.History, error)
+}
+
+type transferRepository struct {
+	storage storage.Storage
+}
+
+func (t *transferRepository) Transfer(transfer entity.History) (entity.History, error) {
+	err := t.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(transfer.CustomerUsername)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid username")
+		}
+		if err != nil {
+			return err
+		}
+
+		recipient, err := tx.Customers().FindByUsername(transfer.RecipientUsername)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.InvalidError("Invalid recipient username")
+		}
+		if err != nil {
+			return err
+		}
+
+		transfer.Currency, err = walletCurrency(tx, customer, transfer.Currency)
+		if err != nil {
+			return err
+		}
+		if _, err = walletCurrency(tx, recipient, transfer.Currency); err != nil {
+			return err
+		}
+		transfer.Date = time.Now()
+		transfer.TransactionId = uuid.New().String()
+		transfer.Type = entity.HistoryTypeTransfer
+
+		err = ledger.Transfer(tx, transfer.TransactionId, transfer.Currency,
+			ledger.CustomerAccount(customer.Username), ledger.CustomerAccount(recipient.Username), transfer.Amount)
+		if err != nil {
+			return err
+		}
+		if err = tx.Histories().Insert(transfer); err != nil {
+			return err
+		}
+		return emit(tx, entity.EventTransferCompleted, "", transfer)
+	})
+	if err != nil {
+		return entity.History{}, err
+	}
+	return transfer, nil
+}
+
+func NewTransferRepository(storage storage.Storage) TransferRepository {
+	return &transferRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransferRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *TransferRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummySender", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+		{Username: "dummyRecipient", Balance: money.MustParse("10"), Currency: money.DefaultCurrency},
+		{Username: "dummyForeign", Balance: money.MustParse("10"), Currency: "USD"},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+}
+
+func (suite *TransferRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *TransferRepoTestSuite) assertBalances(sender string, recipient string) {
+	for username, expected := range map[string]string{"dummySender": sender, "dummyRecipient": recipient} {
+		customer, err := suite.storage.Customers().FindByUsername(username)
+		suite.Require().NoError(err)
+		assert.Equal(suite.T(), money.MustParse(expected), customer.Balance, username)
+	}
+	report, err := ledger.Check(suite.storage)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), report.Balanced(), report.String())
+}
+
+func (suite *TransferRepoTestSuite) TestTransfer_Success() {
+	transfer, err := NewTransferRepository(suite.storage).Transfer(entity.History{
+		CustomerUsername:  "dummySender",
+		RecipientUsername: "dummyRecipient",
+		Amount:            money.MustParse("40"),
+		Note:              "dinner",
+	})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.HistoryTypeTransfer, transfer.Type)
+	assert.Equal(suite.T(), money.DefaultCurrency, transfer.Currency)
+	assert.NotEmpty(suite.T(), transfer.TransactionId)
+	suite.assertBalances("60", "50")
+
+	historyRepo := NewHistoryRepository(suite.storage)
+	for _, username := range []string{"dummySender", "dummyRecipient"} {
+		page, err := historyRepo.FindHistories(req.HistoryQuery{CustomerUsername: username, Limit: 10})
+		suite.Require().NoError(err)
+		suite.Require().Len(page.Items, 1, username)
+		assert.Equal(suite.T(), transfer.TransactionId, page.Items[0].TransactionId, username)
+		assert.Equal(suite.T(), "dinner", page.Items[0].Note, username)
+	}
+	events, err := suite.storage.Outbox().FindAfter(0, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 1)
+	assert.Equal(suite.T(), entity.EventTransferCompleted, events[0].Type)
+}
+
+func (suite *TransferRepoTestSuite) TestTransfer_Failed() {
+	transfers := []entity.History{
+		{CustomerUsername: "dummySender", RecipientUsername: "dummyRecipient", Amount: money.MustParse("400")},
+		{CustomerUsername: "dummySender", RecipientUsername: "unknown", Amount: money.MustParse("40")},
+		{CustomerUsername: "unknown", RecipientUsername: "dummyRecipient", Amount: money.MustParse("40")},
+		{CustomerUsername: "dummySender", RecipientUsername: "dummyForeign", Amount: money.MustParse("40")},
+		{CustomerUsername: "dummySender", RecipientUsername: "dummyRecipient", Amount: money.MustParse("40"), Currency: "USD"},
+	}
+	for _, transfer := range transfers {
+		_, err := NewTransferRepository(suite.storage).Transfer(transfer)
+		suite.assertStatus(err, http.StatusBadRequest)
+	}
+
+	suite.assertBalances("100", "10")
+	histories, err := suite.storage.Histories().Find(storage.HistoryFilter{})
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), histories)
+}
+
+func TestTransferRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferRepoTestSuite))
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils/totp"
+)
+
+const InvalidTwoFactorCodeMessage = "Invalid verification code"
+
+type TwoFactorRepository interface {
+	SaveSecret(username string, secret string) error
+	Enable(username string, code string, recoveryCodeHashes []string) error
+	Disable(username string, code string) error
+}
+
+type twoFactorRepository struct {
+	storage storage.Storage
+}
+
+func (t *twoFactorRepository) SaveSecret(username string, secret string) error {
+	return t.update(username, func(customer *entity.Customer) error {
+		if customer.TotpEnabled {
+			return app_error.Conflict("Two-factor authentication already enabled")
+		}
+		customer.TotpSecret = secret
+		customer.TotpLastStep = 0
+		customer.RecoveryCodes = nil
+		return nil
+	})
+}
+
+func (t *twoFactorRepository) Enable(username string, code string, recoveryCodeHashes []string) error {
+	return t.update(username, func(customer *entity.Customer) error {
+		if customer.TotpEnabled {
+			return app_error.Conflict("Two-factor authentication already enabled")
+		}
+		if customer.TotpSecret == "" {
+			return app_error.InvalidError("Two-factor authentication enrolment not started")
+		}
+		if !useTwoFactorCode(customer, code, false) {
+			return app_error.InvalidError(InvalidTwoFactorCodeMessage)
+		}
+		customer.TotpEnabled = true
+		customer.RecoveryCodes = recoveryCodeHashes
+		return nil
+	})
+}
+
+func (t *twoFactorRepository) Disable(username string, code string) error {
+	return t.update(username, func(customer *entity.Customer) error {
+		if !customer.TotpEnabled {
+			return app_error.InvalidError("Two-factor authentication not enabled")
+		}
+		if !useTwoFactorCode(customer, code, true) {
+			return app_error.InvalidError(InvalidTwoFactorCodeMessage)
+		}
+		customer.TotpSecret = ""
+		customer.TotpEnabled = false
+		customer.TotpLastStep = 0
+		customer.RecoveryCodes = nil
+		return nil
+	})
+}
+
+func (t *twoFactorRepository) update(username string, fn func(customer *entity.Customer) error) error {
+	return t.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(username)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.DataNotFound("customer not found")
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(&customer); err != nil {
+			return err
+		}
+		return tx.Customers().Update(customer)
+	})
+}
+
+func useTwoFactorCode(customer *entity.Customer, code string, allowRecovery bool) bool {
+	if step, ok := totp.Validate(customer.TotpSecret, code, time.Now(), customer.TotpLastStep); ok {
+		customer.TotpLastStep = step
+		return true
+	}
+	if !allowRecovery {
+		return false
+	}
+	hash := totp.HashRecoveryCode(code)
+	for i, recoveryCode := range customer.RecoveryCodes {
+		if recoveryCode == hash {
+			customer.RecoveryCodes = append(customer.RecoveryCodes[:i:i], customer.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func NewTwoFactorRepository(storage storage.Storage) TwoFactorRepository {
+	return &twoFactorRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/febriansr/simple-payment-api/utils/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *TwoFactorRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+}
+
+func (suite *TwoFactorRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *TwoFactorRepoTestSuite) customer() entity.Customer {
+	customer, err := suite.storage.Customers().FindByUsername("dummyUsername")
+	suite.Require().NoError(err)
+	return customer
+}
+
+func (suite *TwoFactorRepoTestSuite) currentCode() string {
+	code, _ := totp.Code(dummyTotpSecret, totp.Step(time.Now()))
+	return code
+}
+
+func (suite *TwoFactorRepoTestSuite) TestEnableAndDisable() {
+	twoFactorRepo := NewTwoFactorRepository(suite.storage)
+	suite.Require().NoError(twoFactorRepo.SaveSecret("dummyUsername", dummyTotpSecret))
+	assert.False(suite.T(), suite.customer().TotpEnabled)
+
+	suite.assertStatus(twoFactorRepo.Enable("dummyUsername", "000000", []string{"hash"}), http.StatusBadRequest)
+	code := suite.currentCode()
+	assert.Nil(suite.T(), twoFactorRepo.Enable("dummyUsername", code, []string{totp.HashRecoveryCode("abcd-efgh")}))
+	customer := suite.customer()
+	assert.True(suite.T(), customer.TotpEnabled)
+	assert.Len(suite.T(), customer.RecoveryCodes, 1)
+
+	suite.assertStatus(twoFactorRepo.SaveSecret("dummyUsername", "OTHERSECRET"), http.StatusConflict)
+	suite.assertStatus(twoFactorRepo.Disable("dummyUsername", code), http.StatusBadRequest)
+	assert.Nil(suite.T(), twoFactorRepo.Disable("dummyUsername", "abcd-efgh"))
+	customer = suite.customer()
+	assert.False(suite.T(), customer.TotpEnabled)
+	assert.Empty(suite.T(), customer.TotpSecret)
+	assert.Empty(suite.T(), customer.RecoveryCodes)
+}
+
+func (suite *TwoFactorRepoTestSuite) TestEnable_FailedNotEnrolled() {
+	twoFactorRepo := NewTwoFactorRepository(suite.storage)
+	suite.assertStatus(twoFactorRepo.Enable("dummyUsername", "123456", nil), http.StatusBadRequest)
+	suite.assertStatus(twoFactorRepo.Disable("dummyUsername", "123456"), http.StatusBadRequest)
+	suite.assertStatus(twoFactorRepo.SaveSecret("unknown", dummyTotpSecret), http.StatusNotFound)
+}
+
+func TestTwoFactorRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorRepoTestSuite))
+}
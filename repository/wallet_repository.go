@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+)
+
+type WalletRepository interface {
+	Open(wallet entity.Wallet) (entity.Wallet, error)
+	FindByCustomer(customerUsername string) ([]res.Wallet, error)
+}
+
+type walletRepository struct {
+	storage storage.Storage
+}
+
+func (w *walletRepository) Open(wallet entity.Wallet) (entity.Wallet, error) {
+	err := w.storage.Atomic(func(tx storage.Storage) error {
+		customer, err := tx.Customers().FindByUsername(wallet.Username)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.DataNotFound("customer not found")
+		}
+		if err != nil {
+			return err
+		}
+		if wallet.Currency == customer.Currency {
+			return app_error.Conflict("Wallet already exists")
+		}
+		err = tx.Wallets().Insert(wallet)
+		if errors.Is(err, storage.ErrDuplicate) {
+			return app_error.Conflict("Wallet already exists")
+		}
+		return err
+	})
+	if err != nil {
+		return entity.Wallet{}, err
+	}
+	return wallet, nil
+}
+
+func (w *walletRepository) FindByCustomer(customerUsername string) ([]res.Wallet, error) {
+	customer, err := w.storage.Customers().FindByUsername(customerUsername)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, app_error.DataNotFound("customer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := w.storage.Wallets().FindByCustomer(customerUsername)
+	if err != nil {
+		return nil, err
+	}
+	result := []res.Wallet{{Currency: customer.Currency, Balance: customer.Balance, Primary: true}}
+	for _, wallet := range wallets {
+		result = append(result, res.Wallet{Currency: wallet.Currency, Balance: wallet.Balance})
+	}
+	return result, nil
+}
+
+func walletCurrency(tx storage.Storage, customer entity.Customer, currency string) (string, error) {
+	if currency == "" || currency == customer.Currency {
+		return customer.Currency, nil
+	}
+	_, err := tx.Wallets().Find(customer.Username, currency)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", app_error.InvalidError("Currency mismatch")
+	}
+	if err != nil {
+		return "", err
+	}
+	return currency, nil
+}
+
+func NewWalletRepository(storage storage.Storage) WalletRepository {
+	return &walletRepository{
+		storage: storage,
+	}
+}
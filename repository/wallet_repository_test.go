@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WalletRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *WalletRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyUsername", Balance: money.MustParse("100"), Currency: money.DefaultCurrency},
+		{Username: "dummyRecipient", Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+}
+
+func (suite *WalletRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *WalletRepoTestSuite) open(username string, currency string) error {
+	_, err := NewWalletRepository(suite.storage).Open(entity.Wallet{Username: username, Currency: currency, CreatedAt: time.Now().UTC()})
+	return err
+}
+
+func (suite *WalletRepoTestSuite) TestOpen() {
+	suite.Require().NoError(suite.open("dummyUsername", "USD"))
+
+	suite.assertStatus(suite.open("dummyUsername", "USD"), http.StatusConflict)
+	suite.assertStatus(suite.open("dummyUsername", money.DefaultCurrency), http.StatusConflict)
+	suite.assertStatus(suite.open("unknownUsername", "USD"), http.StatusNotFound)
+}
+
+func (suite *WalletRepoTestSuite) TestFindByCustomer() {
+	suite.Require().NoError(suite.open("dummyUsername", "USD"))
+
+	wallets, err := NewWalletRepository(suite.storage).FindByCustomer("dummyUsername")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []res.Wallet{
+		{Currency: money.DefaultCurrency, Balance: money.MustParse("100"), Primary: true},
+		{Currency: "USD"},
+	}, wallets)
+
+	_, err = NewWalletRepository(suite.storage).FindByCustomer("unknownUsername")
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func (suite *WalletRepoTestSuite) TestTransfer_WalletCurrency() {
+	suite.Require().NoError(suite.open("dummyUsername", "USD"))
+	suite.Require().NoError(suite.storage.Atomic(func(tx storage.Storage) error {
+		return ledger.Transfer(tx, "funding-1", "USD", ledger.SystemFunding, ledger.CustomerAccount("dummyUsername"), money.MustParse("20"))
+	}))
+	transferRepo := NewTransferRepository(suite.storage)
+	transfer := entity.History{CustomerUsername: "dummyUsername", RecipientUsername: "dummyRecipient", Amount: money.MustParse("5"), Currency: "USD"}
+
+	_, err := transferRepo.Transfer(transfer)
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	suite.Require().NoError(suite.open("dummyRecipient", "USD"))
+	_, err = transferRepo.Transfer(transfer)
+	suite.Require().NoError(err)
+	wallet, err := suite.storage.Wallets().Find("dummyRecipient", "USD")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("5"), wallet.Balance)
+	report, err := ledger.Check(suite.storage)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), report.Balanced(), report.String())
+}
+
+func TestWalletRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletRepoTestSuite))
+}
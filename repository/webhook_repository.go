@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	Create(webhook entity.Webhook) error
+	FindByMerchant(merchantCode string) ([]entity.Webhook, error)
+	FindById(webhookId string) (entity.Webhook, error)
+	Disable(merchantCode string, webhookId string) error
+	Enqueue(event entity.Event) (int, error)
+	FindDeliveries(merchantCode string, status string) ([]entity.WebhookDelivery, error)
+	FindDue(limit int) ([]entity.WebhookDelivery, error)
+	UpdateDelivery(delivery entity.WebhookDelivery) error
+	Redeliver(merchantCode string, deliveryId string) (entity.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	storage storage.Storage
+}
+
+func (w *webhookRepository) Create(webhook entity.Webhook) error {
+	err := w.storage.Webhooks().Insert(webhook)
+	if errors.Is(err, storage.ErrDuplicate) {
+		return app_error.InternalServerError("Failed to create webhook: duplicate webhook id")
+	}
+	return err
+}
+
+func (w *webhookRepository) FindByMerchant(merchantCode string) ([]entity.Webhook, error) {
+	return w.storage.Webhooks().FindByMerchant(merchantCode)
+}
+
+func (w *webhookRepository) FindById(webhookId string) (entity.Webhook, error) {
+	webhook, err := w.storage.Webhooks().FindById(webhookId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.Webhook{}, app_error.DataNotFound("webhook not found")
+	}
+	return webhook, err
+}
+
+func (w *webhookRepository) Disable(merchantCode string, webhookId string) error {
+	return w.storage.Atomic(func(tx storage.Storage) error {
+		webhook, err := tx.Webhooks().FindById(webhookId)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && webhook.MerchantCode != merchantCode) {
+			return app_error.DataNotFound("webhook not found")
+		}
+		if err != nil {
+			return err
+		}
+		if !webhook.Active() {
+			return nil
+		}
+		disabledAt := time.Now().UTC()
+		webhook.DisabledAt = &disabledAt
+		return tx.Webhooks().Update(webhook)
+	})
+}
+
+func (w *webhookRepository) Enqueue(event entity.Event) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, app_error.InternalServerError("Failed to marshal event: " + err.Error())
+	}
+	var enqueued int
+	err = w.storage.Atomic(func(tx storage.Storage) error {
+		webhooks, err := tx.Webhooks().FindByMerchant(event.MerchantCode)
+		if err != nil {
+			return err
+		}
+		for _, webhook := range webhooks {
+			if !webhook.Active() || !webhook.Subscribed(event.Type) {
+				continue
+			}
+			err = tx.WebhookDeliveries().Insert(entity.WebhookDelivery{
+				DeliveryId:    uuid.NewSHA1(uuid.NameSpaceURL, []byte(event.EventId+"/"+webhook.WebhookId)).String(),
+				WebhookId:     webhook.WebhookId,
+				MerchantCode:  event.MerchantCode,
+				EventId:       event.EventId,
+				EventType:     event.Type,
+				Payload:       string(payload),
+				Status:        entity.DeliveryStatusPending,
+				CreatedAt:     event.CreatedAt,
+				NextAttemptAt: event.CreatedAt,
+			})
+			if errors.Is(err, storage.ErrDuplicate) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			enqueued++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return enqueued, nil
+}
+
+func (w *webhookRepository) FindDeliveries(merchantCode string, status string) ([]entity.WebhookDelivery, error) {
+	return w.storage.WebhookDeliveries().FindByMerchant(merchantCode, status)
+}
+
+func (w *webhookRepository) FindDue(limit int) ([]entity.WebhookDelivery, error) {
+	return w.storage.WebhookDeliveries().FindDue(time.Now(), limit)
+}
+
+func (w *webhookRepository) UpdateDelivery(delivery entity.WebhookDelivery) error {
+	return w.storage.WebhookDeliveries().Update(delivery)
+}
+
+func (w *webhookRepository) Redeliver(merchantCode string, deliveryId string) (entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := w.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		delivery, err = tx.WebhookDeliveries().FindById(deliveryId)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && delivery.MerchantCode != merchantCode) {
+			return app_error.DataNotFound("webhook delivery not found")
+		}
+		if err != nil {
+			return err
+		}
+		delivery.Status = entity.DeliveryStatusPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now().UTC()
+		return tx.WebhookDeliveries().Update(delivery)
+	})
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+func NewWebhookRepository(storage storage.Storage) WebhookRepository {
+	return &webhookRepository{
+		storage: storage,
+	}
+}
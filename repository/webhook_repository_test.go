@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *WebhookRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+
+	webhookRepo := NewWebhookRepository(suite.storage)
+	suite.Require().NoError(webhookRepo.Create(entity.Webhook{
+		WebhookId:    "wh_payments",
+		MerchantCode: "MRC125",
+		Url:          "https://merchant.example/payments",
+		EventTypes:   []string{entity.EventPaymentSucceeded},
+		CreatedAt:    time.Now().UTC(),
+	}))
+	suite.Require().NoError(webhookRepo.Create(entity.Webhook{
+		WebhookId:    "wh_refunds",
+		MerchantCode: "MRC125",
+		Url:          "https://merchant.example/refunds",
+		EventTypes:   []string{entity.EventRefundCreated},
+		CreatedAt:    time.Now().UTC(),
+	}))
+	suite.Require().NoError(webhookRepo.Create(entity.Webhook{
+		WebhookId:    "wh_other",
+		MerchantCode: "MRC126",
+		Url:          "https://other.example/payments",
+		EventTypes:   []string{entity.EventPaymentSucceeded},
+		CreatedAt:    time.Now().UTC(),
+	}))
+}
+
+func (suite *WebhookRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *WebhookRepoTestSuite) enqueue(eventType string) {
+	enqueued, err := NewWebhookRepository(suite.storage).Enqueue(entity.Event{
+		EventId:      "evt_" + eventType,
+		Type:         eventType,
+		MerchantCode: "MRC125",
+		CreatedAt:    time.Now().UTC(),
+		Data:         json.RawMessage(`{"amount":10}`),
+	})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, enqueued)
+}
+
+func (suite *WebhookRepoTestSuite) TestEnqueue_OnlySubscribedWebhooks() {
+	webhookRepo := NewWebhookRepository(suite.storage)
+
+	suite.enqueue(entity.EventPaymentSucceeded)
+
+	deliveries, err := webhookRepo.FindDeliveries("MRC125", entity.DeliveryStatusPending)
+	suite.Require().NoError(err)
+	suite.Require().Len(deliveries, 1)
+	assert.Equal(suite.T(), "wh_payments", deliveries[0].WebhookId)
+	assert.Equal(suite.T(), entity.EventPaymentSucceeded, deliveries[0].EventType)
+	var event entity.Event
+	suite.Require().NoError(json.Unmarshal([]byte(deliveries[0].Payload), &event))
+	assert.Equal(suite.T(), "evt_"+entity.EventPaymentSucceeded, event.EventId)
+	assert.JSONEq(suite.T(), `{"amount":10}`, string(event.Data))
+
+	due, err := webhookRepo.FindDue(10)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), due, 1)
+}
+
+func (suite *WebhookRepoTestSuite) TestEnqueue_SkipsRedeliveredEvent() {
+	webhookRepo := NewWebhookRepository(suite.storage)
+	suite.enqueue(entity.EventPaymentSucceeded)
+
+	enqueued, err := webhookRepo.Enqueue(entity.Event{
+		EventId:      "evt_" + entity.EventPaymentSucceeded,
+		Type:         entity.EventPaymentSucceeded,
+		MerchantCode: "MRC125",
+		CreatedAt:    time.Now().UTC(),
+	})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, enqueued)
+	deliveries, err := webhookRepo.FindDeliveries("MRC125", "")
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), deliveries, 1)
+}
+
+func (suite *WebhookRepoTestSuite) TestDisable() {
+	webhookRepo := NewWebhookRepository(suite.storage)
+
+	suite.assertStatus(webhookRepo.Disable("MRC126", "wh_payments"), http.StatusNotFound)
+	suite.Require().NoError(webhookRepo.Disable("MRC125", "wh_payments"))
+
+	webhook, err := webhookRepo.FindById("wh_payments")
+	suite.Require().NoError(err)
+	assert.False(suite.T(), webhook.Active())
+
+	enqueued, err := webhookRepo.Enqueue(entity.Event{EventId: "evt_1", Type: entity.EventPaymentSucceeded, MerchantCode: "MRC125", CreatedAt: time.Now().UTC()})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, enqueued)
+}
+
+func (suite *WebhookRepoTestSuite) TestRedeliver() {
+	webhookRepo := NewWebhookRepository(suite.storage)
+	suite.enqueue(entity.EventRefundCreated)
+	deliveries, err := webhookRepo.FindDeliveries("MRC125", "")
+	suite.Require().NoError(err)
+	suite.Require().Len(deliveries, 1)
+	dead := deliveries[0]
+	dead.Status = entity.DeliveryStatusDead
+	dead.Attempts = 8
+	dead.NextAttemptAt = time.Now().Add(time.Hour)
+	suite.Require().NoError(webhookRepo.UpdateDelivery(dead))
+
+	due, err := webhookRepo.FindDue(10)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), due, 0)
+
+	_, err = webhookRepo.Redeliver("MRC126", dead.DeliveryId)
+	suite.assertStatus(err, http.StatusNotFound)
+	redelivered, err := webhookRepo.Redeliver("MRC125", dead.DeliveryId)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.DeliveryStatusPending, redelivered.Status)
+	assert.Equal(suite.T(), 0, redelivered.Attempts)
+
+	due, err = webhookRepo.FindDue(10)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), due, 1)
+}
+
+func (suite *WebhookRepoTestSuite) TestFindById_NotFound() {
+	_, err := NewWebhookRepository(suite.storage).FindById("wh_unknown")
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func TestWebhookRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepoTestSuite))
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/google/uuid"
+)
+
+type WithdrawalRepository interface {
+	AddBankAccount(bankAccount entity.BankAccount) (entity.BankAccount, error)
+	FindBankAccounts(ownerType string, owner string) ([]entity.BankAccount, error)
+	Request(withdrawal entity.Withdrawal, minAmount money.Amount) (entity.Withdrawal, error)
+	FindByOwner(ownerType string, owner string) ([]entity.Withdrawal, error)
+	CreateBatches(limit int) ([]entity.PayoutBatch, error)
+	FindBatches() ([]entity.PayoutBatch, error)
+	FindBatch(batchId string) (entity.PayoutBatch, []entity.Withdrawal, error)
+	Settle(batchId string, results []entity.PayoutResult) (entity.PayoutBatch, error)
+}
+
+type withdrawalRepository struct {
+	storage storage.Storage
+}
+
+func (w *withdrawalRepository) AddBankAccount(bankAccount entity.BankAccount) (entity.BankAccount, error) {
+	err := w.storage.BankAccounts().Insert(bankAccount)
+	if errors.Is(err, storage.ErrDuplicate) {
+		return entity.BankAccount{}, app_error.Conflict("bank account already saved")
+	}
+	if err != nil {
+		return entity.BankAccount{}, err
+	}
+	return bankAccount, nil
+}
+
+func (w *withdrawalRepository) FindBankAccounts(ownerType string, owner string) ([]entity.BankAccount, error) {
+	return w.storage.BankAccounts().FindByOwner(ownerType, owner)
+}
+
+// Request moves the amount to the owner's payout account. The minimum amount
+// is in the default currency, so the withdrawal is valued at the mid rate
+// before it is compared.
+func (w *withdrawalRepository) Request(withdrawal entity.Withdrawal, minAmount money.Amount) (entity.Withdrawal, error) {
+	err := w.storage.Atomic(func(tx storage.Storage) error {
+		bankAccount, err := tx.BankAccounts().FindById(withdrawal.BankAccountId)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && (bankAccount.OwnerType != withdrawal.OwnerType || bankAccount.Owner != withdrawal.Owner)) {
+			return app_error.DataNotFound("bank account not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		balance, currency, err := w.ownerBalance(tx, withdrawal.OwnerType, withdrawal.Owner)
+		if err != nil {
+			return err
+		}
+		if minAmount > 0 {
+			valued, ok, err := value(tx, currency, money.DefaultCurrency, withdrawal.Amount)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return app_error.InvalidError("No exchange rate to check the minimum withdrawal")
+			}
+			if valued < minAmount {
+				return app_error.InvalidError("amount is below the minimum withdrawal")
+			}
+		}
+		if balance < withdrawal.Amount {
+			return app_error.InvalidError("Balance insufficient")
+		}
+
+		withdrawal.BankCode = bankAccount.BankCode
+		withdrawal.AccountNumber = bankAccount.AccountNumber
+		withdrawal.AccountName = bankAccount.AccountName
+		withdrawal.Currency = currency
+		account := ownerAccount(withdrawal.OwnerType, withdrawal.Owner)
+		err = ledger.Transfer(tx, withdrawal.WithdrawalId, currency, account, ledger.PayoutAccount(account), withdrawal.Amount)
+		if err != nil {
+			return err
+		}
+		err = tx.Withdrawals().Insert(withdrawal)
+		if errors.Is(err, storage.ErrDuplicate) {
+			return app_error.InternalServerError("Failed to create withdrawal: duplicate withdrawal id")
+		}
+		if err != nil {
+			return err
+		}
+		return emit(tx, entity.EventWithdrawalRequested, withdrawalMerchantCode(withdrawal), withdrawal)
+	})
+	if err != nil {
+		return entity.Withdrawal{}, err
+	}
+	return withdrawal, nil
+}
+
+func (w *withdrawalRepository) FindByOwner(ownerType string, owner string) ([]entity.Withdrawal, error) {
+	return w.storage.Withdrawals().FindByOwner(ownerType, owner)
+}
+
+func (w *withdrawalRepository) CreateBatches(limit int) ([]entity.PayoutBatch, error) {
+	batches := []entity.PayoutBatch{}
+	err := w.storage.Atomic(func(tx storage.Storage) error {
+		withdrawals, err := tx.Withdrawals().FindPending()
+		if err != nil {
+			return err
+		}
+		if limit > 0 && len(withdrawals) > limit {
+			withdrawals = withdrawals[:limit]
+		}
+
+		now := time.Now().UTC()
+		byCurrency := map[string]int{}
+		for _, withdrawal := range withdrawals {
+			index, ok := byCurrency[withdrawal.Currency]
+			if !ok {
+				index = len(batches)
+				byCurrency[withdrawal.Currency] = index
+				batches = append(batches, entity.PayoutBatch{
+					BatchId:   uuid.New().String(),
+					Currency:  withdrawal.Currency,
+					Status:    entity.PayoutBatchStatusExported,
+					CreatedAt: now,
+				})
+			}
+			batches[index].Count++
+			batches[index].Total += withdrawal.Amount
+
+			withdrawal.Status = entity.WithdrawalStatusBatched
+			withdrawal.BatchId = batches[index].BatchId
+			if err = tx.Withdrawals().Update(withdrawal); err != nil {
+				return err
+			}
+		}
+		for _, batch := range batches {
+			if err = tx.PayoutBatches().Insert(batch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+func (w *withdrawalRepository) FindBatches() ([]entity.PayoutBatch, error) {
+	return w.storage.PayoutBatches().FindAll()
+}
+
+func (w *withdrawalRepository) FindBatch(batchId string) (entity.PayoutBatch, []entity.Withdrawal, error) {
+	batch, err := w.storage.PayoutBatches().FindById(batchId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.PayoutBatch{}, nil, app_error.DataNotFound("payout batch not found")
+	}
+	if err != nil {
+		return entity.PayoutBatch{}, nil, err
+	}
+	withdrawals, err := w.storage.Withdrawals().FindByBatch(batchId)
+	if err != nil {
+		return entity.PayoutBatch{}, nil, err
+	}
+	return batch, withdrawals, nil
+}
+
+func (w *withdrawalRepository) Settle(batchId string, results []entity.PayoutResult) (entity.PayoutBatch, error) {
+	var batch entity.PayoutBatch
+	err := w.storage.Atomic(func(tx storage.Storage) error {
+		var err error
+		batch, err = tx.PayoutBatches().FindById(batchId)
+		if errors.Is(err, storage.ErrNotFound) {
+			return app_error.DataNotFound("payout batch not found")
+		}
+		if err != nil {
+			return err
+		}
+		withdrawals, err := tx.Withdrawals().FindByBatch(batchId)
+		if err != nil {
+			return err
+		}
+		byId := map[string]entity.Withdrawal{}
+		for _, withdrawal := range withdrawals {
+			byId[withdrawal.WithdrawalId] = withdrawal
+		}
+
+		seen := map[string]bool{}
+		for _, result := range results {
+			withdrawal, ok := byId[result.WithdrawalId]
+			if !ok {
+				return app_error.InvalidError("withdrawal " + result.WithdrawalId + " is not part of the batch")
+			}
+			if seen[result.WithdrawalId] {
+				return app_error.InvalidError("withdrawal " + result.WithdrawalId + " is listed more than once")
+			}
+			seen[result.WithdrawalId] = true
+			if result.Amount != withdrawal.Amount {
+				return app_error.InvalidError("amount mismatch for withdrawal " + result.WithdrawalId)
+			}
+			if withdrawal.Status == result.Status {
+				continue
+			}
+			if withdrawal.Status != entity.WithdrawalStatusBatched {
+				return app_error.Conflict("withdrawal " + result.WithdrawalId + " is already " + withdrawal.Status)
+			}
+
+			switch result.Status {
+			case entity.WithdrawalStatusPaid:
+				withdrawal, err = w.pay(tx, withdrawal)
+				batch.PaidCount++
+			case entity.WithdrawalStatusFailed:
+				withdrawal, err = w.fail(tx, withdrawal, result.FailureReason)
+				batch.FailedCount++
+			default:
+				return app_error.InvalidError("invalid status for withdrawal " + result.WithdrawalId)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if batch.Status != entity.PayoutBatchStatusSettled && batch.PaidCount+batch.FailedCount == batch.Count {
+			settledAt := time.Now().UTC()
+			batch.Status = entity.PayoutBatchStatusSettled
+			batch.SettledAt = &settledAt
+		}
+		return tx.PayoutBatches().Update(batch)
+	})
+	if err != nil {
+		return entity.PayoutBatch{}, err
+	}
+	return batch, nil
+}
+
+func (w *withdrawalRepository) pay(tx storage.Storage, withdrawal entity.Withdrawal) (entity.Withdrawal, error) {
+	now := time.Now()
+	transactionId := uuid.New().String()
+	account := ownerAccount(withdrawal.OwnerType, withdrawal.Owner)
+	err := ledger.Transfer(tx, transactionId, withdrawal.Currency, ledger.PayoutAccount(account), ledger.SystemPayouts, withdrawal.Amount)
+	if err != nil {
+		return entity.Withdrawal{}, err
+	}
+	history := entity.History{
+		TransactionId: transactionId,
+		Type:          entity.HistoryTypeWithdrawal,
+		Amount:        withdrawal.Amount,
+		Currency:      withdrawal.Currency,
+		Reference:     withdrawal.WithdrawalId,
+		Date:          now,
+	}
+	if withdrawal.OwnerType == entity.OwnerTypeMerchant {
+		history.MerchantCode = withdrawal.Owner
+	} else {
+		history.CustomerUsername = withdrawal.Owner
+	}
+	if err = tx.Histories().Insert(history); err != nil {
+		return entity.Withdrawal{}, err
+	}
+
+	resolvedAt := now.UTC()
+	withdrawal.Status = entity.WithdrawalStatusPaid
+	withdrawal.TransactionId = transactionId
+	withdrawal.ResolvedAt = &resolvedAt
+	if err = tx.Withdrawals().Update(withdrawal); err != nil {
+		return entity.Withdrawal{}, err
+	}
+	return withdrawal, emit(tx, entity.EventWithdrawalPaid, withdrawalMerchantCode(withdrawal), withdrawal)
+}
+
+func (w *withdrawalRepository) fail(tx storage.Storage, withdrawal entity.Withdrawal, reason string) (entity.Withdrawal, error) {
+	account := ownerAccount(withdrawal.OwnerType, withdrawal.Owner)
+	err := ledger.Transfer(tx, withdrawal.WithdrawalId, withdrawal.Currency, ledger.PayoutAccount(account), account, withdrawal.Amount)
+	if err != nil {
+		return entity.Withdrawal{}, err
+	}
+	if reason == "" {
+		reason = "rejected by the bank"
+	}
+	resolvedAt := time.Now().UTC()
+	withdrawal.Status = entity.WithdrawalStatusFailed
+	withdrawal.FailureReason = reason
+	withdrawal.ResolvedAt = &resolvedAt
+	if err = tx.Withdrawals().Update(withdrawal); err != nil {
+		return entity.Withdrawal{}, err
+	}
+	return withdrawal, emit(tx, entity.EventWithdrawalFailed, withdrawalMerchantCode(withdrawal), withdrawal)
+}
+
+func (w *withdrawalRepository) ownerBalance(tx storage.Storage, ownerType string, owner string) (money.Amount, string, error) {
+	if ownerType == entity.OwnerTypeMerchant {
+		merchant, err := tx.Merchants().FindByCode(owner)
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, "", app_error.InvalidError("Invalid merchant code")
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		return merchant.Balance, merchant.Currency, nil
+	}
+	customer, err := tx.Customers().FindByUsername(owner)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, "", app_error.InvalidError("Invalid username")
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	return customer.Balance, customer.Currency, nil
+}
+
+func ownerAccount(ownerType string, owner string) string {
+	if ownerType == entity.OwnerTypeMerchant {
+		return ledger.MerchantAccount(owner)
+	}
+	return ledger.CustomerAccount(owner)
+}
+
+func withdrawalMerchantCode(withdrawal entity.Withdrawal) string {
+	if withdrawal.OwnerType == entity.OwnerTypeMerchant {
+		return withdrawal.Owner
+	}
+	return ""
+}
+
+func NewWithdrawalRepository(storage storage.Storage) WithdrawalRepository {
+	return &withdrawalRepository{
+		storage: storage,
+	}
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/storage"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WithdrawalRepoTestSuite struct {
+	suite.Suite
+	storage storage.Storage
+}
+
+func (suite *WithdrawalRepoTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	jsonConfig := config.JsonFileConfig{
+		Customer: filepath.Join(dir, "customer.json"),
+		Merchant: filepath.Join(dir, "merchant.json"),
+		History:  filepath.Join(dir, "history.json"),
+	}
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Customer, []entity.Customer{
+		{Username: "dummyCustomer", Balance: money.MustParse("100000"), Currency: money.DefaultCurrency},
+		{Username: "dummyForeign", Balance: money.MustParse("100"), Currency: "USD"},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.Merchant, []entity.Merchant{
+		{MerchantCode: "MRC125", Balance: money.MustParse("30000"), Currency: money.DefaultCurrency},
+	}))
+	suite.Require().NoError(utils.WriteJSON(jsonConfig.History, []entity.History{}))
+	var err error
+	suite.storage, err = storage.NewJsonStorage(jsonConfig)
+	suite.Require().NoError(err)
+	suite.Require().NoError(ledger.Backfill(suite.storage))
+
+	withdrawalRepo := NewWithdrawalRepository(suite.storage)
+	for _, bankAccount := range []entity.BankAccount{
+		{BankAccountId: "ba-customer", OwnerType: entity.OwnerTypeCustomer, Owner: "dummyCustomer", BankCode: "014", AccountNumber: "1234567890", AccountName: "Dummy Customer"},
+		{BankAccountId: "ba-foreign", OwnerType: entity.OwnerTypeCustomer, Owner: "dummyForeign", BankCode: "014", AccountNumber: "0987654321", AccountName: "Dummy Foreign"},
+		{BankAccountId: "ba-merchant", OwnerType: entity.OwnerTypeMerchant, Owner: "MRC125", BankCode: "008", AccountNumber: "1234567890123", AccountName: "Dummy Merchant"},
+	} {
+		_, err = withdrawalRepo.AddBankAccount(bankAccount)
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *WithdrawalRepoTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError), err)
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *WithdrawalRepoTestSuite) assertBalanced() {
+	report, err := ledger.Check(suite.storage)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), report.Balanced(), report.String())
+}
+
+func (suite *WithdrawalRepoTestSuite) request(id string, ownerType string, owner string, bankAccountId string, amount string) (entity.Withdrawal, error) {
+	return NewWithdrawalRepository(suite.storage).Request(entity.Withdrawal{
+		WithdrawalId:  id,
+		OwnerType:     ownerType,
+		Owner:         owner,
+		BankAccountId: bankAccountId,
+		Amount:        money.MustParse(amount),
+		Status:        entity.WithdrawalStatusPending,
+		CreatedAt:     time.Now().UTC(),
+	}, 0)
+}
+
+func (suite *WithdrawalRepoTestSuite) TestAddBankAccount_Duplicate() {
+	_, err := NewWithdrawalRepository(suite.storage).AddBankAccount(entity.BankAccount{
+		BankAccountId: "ba-other", OwnerType: entity.OwnerTypeCustomer, Owner: "dummyCustomer", BankCode: "014", AccountNumber: "1234567890", AccountName: "Dummy Customer",
+	})
+
+	suite.assertStatus(err, http.StatusConflict)
+}
+
+func (suite *WithdrawalRepoTestSuite) TestRequest_HoldsFunds() {
+	withdrawal, err := suite.request("wd-1", entity.OwnerTypeCustomer, "dummyCustomer", "ba-customer", "40000")
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "014", withdrawal.BankCode)
+	assert.Equal(suite.T(), "1234567890", withdrawal.AccountNumber)
+	assert.Equal(suite.T(), money.DefaultCurrency, withdrawal.Currency)
+	balance, err := NewHoldRepository(suite.storage).Balance("dummyCustomer")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("60000"), balance.Available)
+	assert.Equal(suite.T(), money.MustParse("40000"), balance.Held)
+	suite.assertBalanced()
+	events, err := suite.storage.Outbox().FindAfter(0, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 1)
+	assert.Equal(suite.T(), entity.EventWithdrawalRequested, events[0].Type)
+	assert.Empty(suite.T(), events[0].MerchantCode)
+}
+
+func (suite *WithdrawalRepoTestSuite) TestRequest_Failed() {
+	_, err := suite.request("wd-1", entity.OwnerTypeCustomer, "dummyCustomer", "ba-unknown", "100")
+	suite.assertStatus(err, http.StatusNotFound)
+	_, err = suite.request("wd-1", entity.OwnerTypeCustomer, "dummyCustomer", "ba-merchant", "100")
+	suite.assertStatus(err, http.StatusNotFound)
+	_, err = suite.request("wd-1", entity.OwnerTypeCustomer, "dummyCustomer", "ba-customer", "100000.01")
+	suite.assertStatus(err, http.StatusBadRequest)
+	_, err = suite.request("wd-1", entity.OwnerTypeMerchant, "MRC125", "ba-merchant", "30000.01")
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	withdrawals, err := suite.storage.Withdrawals().FindPending()
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), withdrawals)
+	suite.assertBalanced()
+}
+
+func (suite *WithdrawalRepoTestSuite) TestRequest_MinimumInDefaultCurrency() {
+	withdrawalRepo := NewWithdrawalRepository(suite.storage)
+	withdrawal := func(id string, amount string) entity.Withdrawal {
+		return entity.Withdrawal{
+			WithdrawalId:  id,
+			OwnerType:     entity.OwnerTypeCustomer,
+			Owner:         "dummyForeign",
+			BankAccountId: "ba-foreign",
+			Amount:        money.MustParse(amount),
+			Status:        entity.WithdrawalStatusPending,
+			CreatedAt:     time.Now().UTC(),
+		}
+	}
+	minimum := money.MustParse("10000")
+
+	_, err := withdrawalRepo.Request(withdrawal("wd-1", "1"), minimum)
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	suite.Require().NoError(NewFxRepository(suite.storage).SaveRates([]entity.FxRate{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15000", RateAt: time.Now().UTC(), Source: entity.FxRateSourceFile},
+	}))
+	_, err = withdrawalRepo.Request(withdrawal("wd-2", "0.5"), minimum)
+	suite.assertStatus(err, http.StatusBadRequest)
+	requested, err := withdrawalRepo.Request(withdrawal("wd-3", "1"), minimum)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "USD", requested.Currency)
+	suite.assertBalanced()
+}
+
+func (suite *WithdrawalRepoTestSuite) TestBatchAndSettle() {
+	withdrawalRepo := NewWithdrawalRepository(suite.storage)
+	_, err := suite.request("wd-1", entity.OwnerTypeCustomer, "dummyCustomer", "ba-customer", "40000")
+	suite.Require().NoError(err)
+	_, err = suite.request("wd-2", entity.OwnerTypeCustomer, "dummyCustomer", "ba-customer", "10000")
+	suite.Require().NoError(err)
+	_, err = suite.request("wd-3", entity.OwnerTypeMerchant, "MRC125", "ba-merchant", "30000")
+	suite.Require().NoError(err)
+	_, err = suite.request("wd-4", entity.OwnerTypeCustomer, "dummyForeign", "ba-foreign", "50")
+	suite.Require().NoError(err)
+
+	batches, err := withdrawalRepo.CreateBatches(0)
+	suite.Require().NoError(err)
+	suite.Require().Len(batches, 2)
+	assert.Equal(suite.T(), money.DefaultCurrency, batches[0].Currency)
+	assert.Equal(suite.T(), 3, batches[0].Count)
+	assert.Equal(suite.T(), money.MustParse("80000"), batches[0].Total)
+	assert.Equal(suite.T(), "USD", batches[1].Currency)
+	assert.Equal(suite.T(), 1, batches[1].Count)
+	again, err := withdrawalRepo.CreateBatches(0)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), again)
+
+	batchId := batches[0].BatchId
+	_, err = withdrawalRepo.Settle(batchId, []entity.PayoutResult{{WithdrawalId: "wd-4", Amount: money.MustParse("50"), Status: entity.WithdrawalStatusPaid}})
+	suite.assertStatus(err, http.StatusBadRequest)
+	_, err = withdrawalRepo.Settle(batchId, []entity.PayoutResult{{WithdrawalId: "wd-1", Amount: money.MustParse("4000"), Status: entity.WithdrawalStatusPaid}})
+	suite.assertStatus(err, http.StatusBadRequest)
+
+	batch, err := withdrawalRepo.Settle(batchId, []entity.PayoutResult{
+		{WithdrawalId: "wd-1", Amount: money.MustParse("40000"), Status: entity.WithdrawalStatusPaid},
+		{WithdrawalId: "wd-2", Amount: money.MustParse("10000"), Status: entity.WithdrawalStatusFailed, FailureReason: "Account closed"},
+	})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.PayoutBatchStatusExported, batch.Status)
+	assert.Equal(suite.T(), 1, batch.PaidCount)
+	assert.Equal(suite.T(), 1, batch.FailedCount)
+
+	batch, err = withdrawalRepo.Settle(batchId, []entity.PayoutResult{
+		{WithdrawalId: "wd-1", Amount: money.MustParse("40000"), Status: entity.WithdrawalStatusPaid},
+		{WithdrawalId: "wd-3", Amount: money.MustParse("30000"), Status: entity.WithdrawalStatusPaid},
+	})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.PayoutBatchStatusSettled, batch.Status)
+	assert.Equal(suite.T(), 2, batch.PaidCount)
+	suite.Require().NotNil(batch.SettledAt)
+	_, err = withdrawalRepo.Settle(batchId, []entity.PayoutResult{{WithdrawalId: "wd-1", Amount: money.MustParse("40000"), Status: entity.WithdrawalStatusFailed}})
+	suite.assertStatus(err, http.StatusConflict)
+
+	customer, err := suite.storage.Customers().FindByUsername("dummyCustomer")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("60000"), customer.Balance)
+	merchant, err := suite.storage.Merchants().FindByCode("MRC125")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), money.MustParse("0"), merchant.Balance)
+	suite.assertBalanced()
+
+	_, withdrawals, err := withdrawalRepo.FindBatch(batchId)
+	suite.Require().NoError(err)
+	statuses := map[string]string{}
+	for _, withdrawal := range withdrawals {
+		statuses[withdrawal.WithdrawalId] = withdrawal.Status
+	}
+	assert.Equal(suite.T(), map[string]string{"wd-1": entity.WithdrawalStatusPaid, "wd-2": entity.WithdrawalStatusFailed, "wd-3": entity.WithdrawalStatusPaid}, statuses)
+
+	histories, err := suite.storage.Histories().Find(storage.HistoryFilter{Type: entity.HistoryTypeWithdrawal})
+	suite.Require().NoError(err)
+	suite.Require().Len(histories, 2)
+	merchantHistories, err := suite.storage.Histories().Find(storage.HistoryFilter{MerchantCode: "MRC125"})
+	suite.Require().NoError(err)
+	suite.Require().Len(merchantHistories, 1)
+	assert.Equal(suite.T(), "wd-3", merchantHistories[0].Reference)
+}
+
+func (suite *WithdrawalRepoTestSuite) TestCreateBatches_Limit() {
+	for _, id := range []string{"wd-1", "wd-2", "wd-3"} {
+		_, err := suite.request(id, entity.OwnerTypeCustomer, "dummyCustomer", "ba-customer", "10000")
+		suite.Require().NoError(err)
+	}
+
+	batches, err := NewWithdrawalRepository(suite.storage).CreateBatches(2)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(batches, 1)
+	assert.Equal(suite.T(), 2, batches[0].Count)
+	pending, err := suite.storage.Withdrawals().FindPending()
+	suite.Require().NoError(err)
+	suite.Require().Len(pending, 1)
+	assert.Equal(suite.T(), "wd-3", pending[0].WithdrawalId)
+}
+
+func (suite *WithdrawalRepoTestSuite) TestFindBatch_NotFound() {
+	_, _, err := NewWithdrawalRepository(suite.storage).FindBatch("unknown")
+	suite.assertStatus(err, http.StatusNotFound)
+	_, err = NewWithdrawalRepository(suite.storage).Settle("unknown", nil)
+	suite.assertStatus(err, http.StatusNotFound)
+}
+
+func TestWithdrawalRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(WithdrawalRepoTestSuite))
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package storage
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+type fileLock struct {
+	file *os.File
+}
+
+func lockFile(fileName string) (*fileLock, error) {
+	file, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	overlapped := new(windows.Overlapped)
+	err = windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &fileLock{file: file}, nil
+}
+
+func (l *fileLock) Unlock() error {
+	defer l.file.Close()
+	overlapped := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(l.file.Fd()), 0, 1, 0, overlapped)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonApiKeyStore struct {
+	storage *jsonStorage
+}
+
+// jsonApiKey is the stored form of an API key. The entity hides its secret
+// from JSON, so the file row carries it explicitly.
+type jsonApiKey struct {
+	entity.MerchantApiKey
+	EncryptedSecret string `json:"encrypted_secret"`
+}
+
+func apiKeyTable(tx *jsonTx) *jsonTable[jsonApiKey] { return &tx.apiKeys }
+
+func toJsonApiKey(apiKey entity.MerchantApiKey) jsonApiKey {
+	return jsonApiKey{MerchantApiKey: apiKey, EncryptedSecret: apiKey.EncryptedSecret}
+}
+
+func (k jsonApiKey) entity() entity.MerchantApiKey {
+	apiKey := k.MerchantApiKey
+	apiKey.EncryptedSecret = k.EncryptedSecret
+	return apiKey
+}
+
+func (s *jsonApiKeyStore) FindById(keyId string) (entity.MerchantApiKey, error) {
+	row, err := jsonFirst(s.storage, apiKeyTable, func(apiKey jsonApiKey) bool {
+		return apiKey.KeyId == keyId
+	})
+	return row.entity(), err
+}
+
+func (s *jsonApiKeyStore) FindByMerchant(merchantCode string) ([]entity.MerchantApiKey, error) {
+	rows, err := jsonSelect(s.storage, apiKeyTable, func(apiKey jsonApiKey) bool {
+		return apiKey.MerchantCode == merchantCode
+	})
+	if err != nil {
+		return nil, err
+	}
+	apiKeys := make([]entity.MerchantApiKey, len(rows))
+	for i, row := range rows {
+		apiKeys[i] = row.entity()
+	}
+	return apiKeys, nil
+}
+
+func (s *jsonApiKeyStore) Insert(apiKey entity.MerchantApiKey) error {
+	return jsonInsertUnique(s.storage, apiKeyTable, func(existing jsonApiKey) bool {
+		return existing.KeyId == apiKey.KeyId
+	}, toJsonApiKey(apiKey))
+}
+
+func (s *jsonApiKeyStore) Update(apiKey entity.MerchantApiKey) error {
+	return jsonUpdate(s.storage, apiKeyTable, func(existing jsonApiKey) bool {
+		return existing.KeyId == apiKey.KeyId
+	}, toJsonApiKey(apiKey))
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonFeeRuleStore struct {
+	storage *jsonStorage
+}
+
+func feeRuleTable(tx *jsonTx) *jsonTable[entity.FeeRule] { return &tx.feeRules }
+
+func (s *jsonFeeRuleStore) FindById(ruleId string) (entity.FeeRule, error) {
+	return jsonFirst(s.storage, feeRuleTable, func(rule entity.FeeRule) bool {
+		return rule.RuleId == ruleId
+	})
+}
+
+func (s *jsonFeeRuleStore) FindAll() ([]entity.FeeRule, error) {
+	return jsonSelect(s.storage, feeRuleTable, matchAll[entity.FeeRule])
+}
+
+func (s *jsonFeeRuleStore) FindActive() ([]entity.FeeRule, error) {
+	return jsonSelect(s.storage, feeRuleTable, entity.FeeRule.Active)
+}
+
+func (s *jsonFeeRuleStore) Insert(rule entity.FeeRule) error {
+	return jsonInsertUnique(s.storage, feeRuleTable, func(existing entity.FeeRule) bool {
+		return existing.RuleId == rule.RuleId
+	}, rule)
+}
+
+func (s *jsonFeeRuleStore) Update(rule entity.FeeRule) error {
+	return jsonUpdate(s.storage, feeRuleTable, func(existing entity.FeeRule) bool {
+		return existing.RuleId == rule.RuleId
+	}, rule)
+}
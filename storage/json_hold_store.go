@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"time"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonHoldStore struct {
+	storage *jsonStorage
+}
+
+func holdTable(tx *jsonTx) *jsonTable[entity.Hold] { return &tx.holds }
+
+func (s *jsonHoldStore) FindById(holdId string) (entity.Hold, error) {
+	return jsonFirst(s.storage, holdTable, func(hold entity.Hold) bool {
+		return hold.HoldId == holdId
+	})
+}
+
+func (s *jsonHoldStore) FindByCustomer(customerUsername string) ([]entity.Hold, error) {
+	return jsonSelect(s.storage, holdTable, func(hold entity.Hold) bool {
+		return hold.CustomerUsername == customerUsername
+	})
+}
+
+func (s *jsonHoldStore) FindByMerchant(merchantCode string) ([]entity.Hold, error) {
+	return jsonSelect(s.storage, holdTable, func(hold entity.Hold) bool {
+		return hold.MerchantCode == merchantCode
+	})
+}
+
+func (s *jsonHoldStore) FindExpired(now time.Time) ([]entity.Hold, error) {
+	return jsonSelect(s.storage, holdTable, func(hold entity.Hold) bool {
+		return hold.Active() && !hold.ExpiresAt.After(now)
+	})
+}
+
+func (s *jsonHoldStore) Insert(hold entity.Hold) error {
+	return jsonInsertUnique(s.storage, holdTable, func(existing entity.Hold) bool {
+		return existing.HoldId == hold.HoldId
+	}, hold)
+}
+
+func (s *jsonHoldStore) Update(hold entity.Hold) error {
+	return jsonUpdate(s.storage, holdTable, func(existing entity.Hold) bool {
+		return existing.HoldId == hold.HoldId
+	}, hold)
+}
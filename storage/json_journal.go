@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/febriansr/simple-payment-api/utils"
+)
+
+type journalEntry struct {
+	FileName string `json:"file_name"`
+	Data     []byte `json:"data"`
+}
+
+func writeJournal(journalFile string, entries []journalEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(journalFile, data, 0644)
+}
+
+func replayJournal(journalFile string) error {
+	data, err := os.ReadFile(journalFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var entries []journalEntry
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = utils.WriteFileAtomic(entry.FileName, entry.Data, 0644); err != nil {
+			return err
+		}
+	}
+	return os.Remove(journalFile)
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"errors"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonOutboxStore struct {
+	storage *jsonStorage
+}
+
+type jsonCheckpointStore struct {
+	storage *jsonStorage
+}
+
+func outboxTable(tx *jsonTx) *jsonTable[entity.OutboxEvent]            { return &tx.outbox }
+func checkpointTable(tx *jsonTx) *jsonTable[entity.ConsumerCheckpoint] { return &tx.checkpoints }
+
+func (s *jsonOutboxStore) Append(event entity.Event) (entity.OutboxEvent, error) {
+	var appended entity.OutboxEvent
+	err := s.storage.within(func(tx *jsonTx) error {
+		rows, err := tx.outbox.load()
+		if err != nil {
+			return err
+		}
+		for _, existing := range rows {
+			if existing.EventId == event.EventId {
+				return ErrDuplicate
+			}
+		}
+		appended = entity.OutboxEvent{Sequence: 1, Event: event}
+		if len(rows) > 0 {
+			appended.Sequence = rows[len(rows)-1].Sequence + 1
+		}
+		tx.outbox.save(append(rows, appended))
+		return nil
+	})
+	if err != nil {
+		return entity.OutboxEvent{}, err
+	}
+	return appended, nil
+}
+
+func (s *jsonOutboxStore) FindAfter(sequence int64, limit int) ([]entity.OutboxEvent, error) {
+	events, err := jsonSelect(s.storage, outboxTable, func(event entity.OutboxEvent) bool {
+		return event.Sequence > sequence
+	})
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (s *jsonCheckpointStore) Find(consumer string) (entity.ConsumerCheckpoint, error) {
+	return jsonFirst(s.storage, checkpointTable, func(checkpoint entity.ConsumerCheckpoint) bool {
+		return checkpoint.Consumer == consumer
+	})
+}
+
+func (s *jsonCheckpointStore) Save(checkpoint entity.ConsumerCheckpoint) error {
+	err := jsonUpdate(s.storage, checkpointTable, func(existing entity.ConsumerCheckpoint) bool {
+		return existing.Consumer == checkpoint.Consumer
+	}, checkpoint)
+	if errors.Is(err, ErrNotFound) {
+		return jsonInsert(s.storage, checkpointTable, checkpoint)
+	}
+	return err
+}
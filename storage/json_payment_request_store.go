@@ -0,0 +1,41 @@
+package storage
+
+import (
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonPaymentRequestStore struct {
+	storage *jsonStorage
+}
+
+func paymentRequestTable(tx *jsonTx) *jsonTable[entity.PaymentRequest] { return &tx.payments }
+
+func (s *jsonPaymentRequestStore) FindById(requestId string) (entity.PaymentRequest, error) {
+	return jsonFirst(s.storage, paymentRequestTable, func(paymentRequest entity.PaymentRequest) bool {
+		return paymentRequest.RequestId == requestId
+	})
+}
+
+func (s *jsonPaymentRequestStore) FindByCustomer(customerUsername string) ([]entity.PaymentRequest, error) {
+	return jsonSelect(s.storage, paymentRequestTable, func(paymentRequest entity.PaymentRequest) bool {
+		return paymentRequest.CustomerUsername == customerUsername
+	})
+}
+
+func (s *jsonPaymentRequestStore) FindByMerchant(merchantCode string) ([]entity.PaymentRequest, error) {
+	return jsonSelect(s.storage, paymentRequestTable, func(paymentRequest entity.PaymentRequest) bool {
+		return paymentRequest.MerchantCode == merchantCode
+	})
+}
+
+func (s *jsonPaymentRequestStore) Insert(paymentRequest entity.PaymentRequest) error {
+	return jsonInsertUnique(s.storage, paymentRequestTable, func(existing entity.PaymentRequest) bool {
+		return existing.RequestId == paymentRequest.RequestId
+	}, paymentRequest)
+}
+
+func (s *jsonPaymentRequestStore) Update(paymentRequest entity.PaymentRequest) error {
+	return jsonUpdate(s.storage, paymentRequestTable, func(existing entity.PaymentRequest) bool {
+		return existing.RequestId == paymentRequest.RequestId
+	}, paymentRequest)
+}
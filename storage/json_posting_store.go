@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"sort"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonPostingStore struct {
+	storage *jsonStorage
+}
+
+func postingTable(tx *jsonTx) *jsonTable[entity.Posting] { return &tx.postings }
+
+func (s *jsonPostingStore) Insert(posting entity.Posting) error {
+	return jsonInsert(s.storage, postingTable, posting)
+}
+
+func (s *jsonPostingStore) FindByAccount(account string) ([]entity.Posting, error) {
+	return jsonSelect(s.storage, postingTable, func(posting entity.Posting) bool {
+		return posting.Account == account
+	})
+}
+
+func (s *jsonPostingStore) Balances() ([]AccountBalance, error) {
+	postings, err := jsonSelect(s.storage, postingTable, matchAll[entity.Posting])
+	if err != nil {
+		return nil, err
+	}
+	index := map[[2]string]int{}
+	balances := []AccountBalance{}
+	for _, posting := range postings {
+		key := [2]string{posting.Account, posting.Currency}
+		i, ok := index[key]
+		if !ok {
+			i = len(balances)
+			index[key] = i
+			balances = append(balances, AccountBalance{Account: posting.Account, Currency: posting.Currency})
+		}
+		balances[i].Balance += posting.Amount
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Account != balances[j].Account {
+			return balances[i].Account < balances[j].Account
+		}
+		return balances[i].Currency < balances[j].Currency
+	})
+	return balances, nil
+}
@@ -0,0 +1,513 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils"
+)
+
+const (
+	jsonLockFileName    = ".storage.lock"
+	jsonJournalFileName = ".storage.journal"
+)
+
+type jsonStorage struct {
+	config      config.JsonFileConfig
+	mu          sync.Mutex
+	lockFile    string
+	journalFile string
+	tx          *jsonTx
+}
+
+type jsonTable[T any] struct {
+	name     string
+	fileName string
+	optional bool
+	rows     []T
+	loaded   bool
+	dirty    bool
+}
+
+type jsonJournaler interface {
+	journalEntry() (journalEntry, bool, error)
+}
+
+type jsonTx struct {
+	customers    jsonTable[entity.Customer]
+	merchants    jsonTable[entity.Merchant]
+	histories    jsonTable[entity.History]
+	postings     jsonTable[entity.Posting]
+	apiKeys      jsonTable[jsonApiKey]
+	payments     jsonTable[entity.PaymentRequest]
+	holds        jsonTable[entity.Hold]
+	webhooks     jsonTable[entity.Webhook]
+	deliveries   jsonTable[entity.WebhookDelivery]
+	outbox       jsonTable[entity.OutboxEvent]
+	checkpoints  jsonTable[entity.ConsumerCheckpoint]
+	topUps       jsonTable[entity.TopUp]
+	bankAccounts jsonTable[entity.BankAccount]
+	withdrawals  jsonTable[entity.Withdrawal]
+	batches      jsonTable[entity.PayoutBatch]
+	feeRules     jsonTable[entity.FeeRule]
+	wallets      jsonTable[entity.Wallet]
+	fxRates      jsonTable[entity.FxRate]
+}
+
+type jsonCustomerStore struct {
+	storage *jsonStorage
+}
+
+type jsonMerchantStore struct {
+	storage *jsonStorage
+}
+
+type jsonHistoryStore struct {
+	storage *jsonStorage
+}
+
+func (t *jsonTable[T]) load() ([]T, error) {
+	if !t.loaded {
+		var rows []T
+		if _, err := os.Stat(t.fileName); !t.optional || !errors.Is(err, os.ErrNotExist) {
+			if err := utils.ReadParseJSON(t.fileName, &rows); err != nil {
+				return nil, app_error.InternalServerError("Failed to read and parse " + t.name + " data: " + err.Error())
+			}
+		}
+		t.rows = rows
+		t.loaded = true
+	}
+	return t.rows, nil
+}
+
+func (t *jsonTable[T]) save(rows []T) {
+	t.rows = rows
+	t.dirty = true
+}
+
+func (t *jsonTable[T]) journalEntry() (journalEntry, bool, error) {
+	if !t.dirty {
+		return journalEntry{}, false, nil
+	}
+	rows := t.rows
+	if rows == nil {
+		rows = []T{}
+	}
+	data, err := json.MarshalIndent(rows, "", " ")
+	if err != nil {
+		return journalEntry{}, false, err
+	}
+	return journalEntry{FileName: t.fileName, Data: data}, true, nil
+}
+
+func (j *jsonStorage) newTx() *jsonTx {
+	return &jsonTx{
+		customers:    jsonTable[entity.Customer]{name: "customer", fileName: j.config.Customer},
+		merchants:    jsonTable[entity.Merchant]{name: "merchant", fileName: j.config.Merchant},
+		histories:    jsonTable[entity.History]{name: "history", fileName: j.config.History},
+		postings:     jsonTable[entity.Posting]{name: "posting", fileName: j.config.Posting, optional: true},
+		apiKeys:      jsonTable[jsonApiKey]{name: "api key", fileName: j.config.ApiKey, optional: true},
+		payments:     jsonTable[entity.PaymentRequest]{name: "payment request", fileName: j.config.PaymentRequest, optional: true},
+		holds:        jsonTable[entity.Hold]{name: "hold", fileName: j.config.Hold, optional: true},
+		webhooks:     jsonTable[entity.Webhook]{name: "webhook", fileName: j.config.Webhook, optional: true},
+		deliveries:   jsonTable[entity.WebhookDelivery]{name: "webhook delivery", fileName: j.config.WebhookDelivery, optional: true},
+		outbox:       jsonTable[entity.OutboxEvent]{name: "outbox", fileName: j.config.Outbox, optional: true},
+		checkpoints:  jsonTable[entity.ConsumerCheckpoint]{name: "checkpoint", fileName: j.config.Checkpoint, optional: true},
+		topUps:       jsonTable[entity.TopUp]{name: "top-up", fileName: j.config.TopUp, optional: true},
+		bankAccounts: jsonTable[entity.BankAccount]{name: "bank account", fileName: j.config.BankAccount, optional: true},
+		withdrawals:  jsonTable[entity.Withdrawal]{name: "withdrawal", fileName: j.config.Withdrawal, optional: true},
+		batches:      jsonTable[entity.PayoutBatch]{name: "payout batch", fileName: j.config.PayoutBatch, optional: true},
+		feeRules:     jsonTable[entity.FeeRule]{name: "fee rule", fileName: j.config.FeeRule, optional: true},
+		wallets:      jsonTable[entity.Wallet]{name: "wallet", fileName: j.config.Wallet, optional: true},
+		fxRates:      jsonTable[entity.FxRate]{name: "fx rate", fileName: j.config.FxRate, optional: true},
+	}
+}
+
+func (tx *jsonTx) tables() []jsonJournaler {
+	return []jsonJournaler{&tx.customers, &tx.merchants, &tx.histories, &tx.postings, &tx.apiKeys, &tx.payments, &tx.holds, &tx.webhooks, &tx.deliveries, &tx.outbox, &tx.checkpoints, &tx.topUps, &tx.bankAccounts, &tx.withdrawals, &tx.batches, &tx.feeRules, &tx.wallets, &tx.fxRates}
+}
+
+func (j *jsonStorage) Customers() CustomerStore {
+	return &jsonCustomerStore{storage: j}
+}
+
+func (j *jsonStorage) Merchants() MerchantStore {
+	return &jsonMerchantStore{storage: j}
+}
+
+func (j *jsonStorage) Histories() HistoryStore {
+	return &jsonHistoryStore{storage: j}
+}
+
+func (j *jsonStorage) Postings() PostingStore {
+	return &jsonPostingStore{storage: j}
+}
+
+func (j *jsonStorage) ApiKeys() ApiKeyStore {
+	return &jsonApiKeyStore{storage: j}
+}
+
+func (j *jsonStorage) PaymentRequests() PaymentRequestStore {
+	return &jsonPaymentRequestStore{storage: j}
+}
+
+func (j *jsonStorage) Holds() HoldStore {
+	return &jsonHoldStore{storage: j}
+}
+
+func (j *jsonStorage) Webhooks() WebhookStore {
+	return &jsonWebhookStore{storage: j}
+}
+
+func (j *jsonStorage) WebhookDeliveries() WebhookDeliveryStore {
+	return &jsonWebhookDeliveryStore{storage: j}
+}
+
+func (j *jsonStorage) Outbox() OutboxStore {
+	return &jsonOutboxStore{storage: j}
+}
+
+func (j *jsonStorage) Checkpoints() CheckpointStore {
+	return &jsonCheckpointStore{storage: j}
+}
+
+func (j *jsonStorage) TopUps() TopUpStore {
+	return &jsonTopUpStore{storage: j}
+}
+
+func (j *jsonStorage) BankAccounts() BankAccountStore {
+	return &jsonBankAccountStore{storage: j}
+}
+
+func (j *jsonStorage) Withdrawals() WithdrawalStore {
+	return &jsonWithdrawalStore{storage: j}
+}
+
+func (j *jsonStorage) PayoutBatches() PayoutBatchStore {
+	return &jsonPayoutBatchStore{storage: j}
+}
+
+func (j *jsonStorage) FeeRules() FeeRuleStore {
+	return &jsonFeeRuleStore{storage: j}
+}
+
+func (j *jsonStorage) Wallets() WalletStore {
+	return &jsonWalletStore{storage: j}
+}
+
+func (j *jsonStorage) FxRates() FxRateStore {
+	return &jsonFxRateStore{storage: j}
+}
+
+func (j *jsonStorage) Atomic(fn func(tx Storage) error) error {
+	if j.tx != nil {
+		return fn(j)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	lock, err := lockFile(j.lockFile)
+	if err != nil {
+		return app_error.InternalServerError("Failed to lock storage: " + err.Error())
+	}
+	defer lock.Unlock()
+
+	tx := &jsonStorage{
+		config:      j.config,
+		lockFile:    j.lockFile,
+		journalFile: j.journalFile,
+		tx:          j.newTx(),
+	}
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.commit()
+}
+
+func (j *jsonStorage) commit() error {
+	var entries []journalEntry
+	for _, table := range j.tx.tables() {
+		entry, dirty, err := table.journalEntry()
+		if err != nil {
+			return app_error.InternalServerError("Failed to marshal JSON data: " + err.Error())
+		}
+		if dirty {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := writeJournal(j.journalFile, entries); err != nil {
+		return app_error.InternalServerError("Failed to write storage journal: " + err.Error())
+	}
+	if err := replayJournal(j.journalFile); err != nil {
+		return app_error.InternalServerError("Failed to apply storage journal: " + err.Error())
+	}
+	return nil
+}
+
+func (j *jsonStorage) within(fn func(tx *jsonTx) error) error {
+	if j.tx != nil {
+		return fn(j.tx)
+	}
+	return j.Atomic(func(tx Storage) error {
+		return fn(tx.(*jsonStorage).tx)
+	})
+}
+
+func jsonFirst[T any](storage *jsonStorage, table func(tx *jsonTx) *jsonTable[T], match func(row T) bool) (T, error) {
+	var result T
+	err := storage.within(func(tx *jsonTx) error {
+		rows, err := table(tx).load()
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if match(row) {
+				result = row
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return result, err
+}
+
+func jsonSelect[T any](storage *jsonStorage, table func(tx *jsonTx) *jsonTable[T], match func(row T) bool) ([]T, error) {
+	result := []T{}
+	err := storage.within(func(tx *jsonTx) error {
+		rows, err := table(tx).load()
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if match(row) {
+				result = append(result, row)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func jsonInsert[T any](storage *jsonStorage, table func(tx *jsonTx) *jsonTable[T], row T) error {
+	return storage.within(func(tx *jsonTx) error {
+		rows, err := table(tx).load()
+		if err != nil {
+			return err
+		}
+		table(tx).save(append(rows, row))
+		return nil
+	})
+}
+
+func jsonInsertUnique[T any](storage *jsonStorage, table func(tx *jsonTx) *jsonTable[T], duplicate func(row T) bool, row T) error {
+	return storage.within(func(tx *jsonTx) error {
+		rows, err := table(tx).load()
+		if err != nil {
+			return err
+		}
+		for _, existing := range rows {
+			if duplicate(existing) {
+				return ErrDuplicate
+			}
+		}
+		table(tx).save(append(rows, row))
+		return nil
+	})
+}
+
+func jsonUpdate[T any](storage *jsonStorage, table func(tx *jsonTx) *jsonTable[T], match func(row T) bool, row T) error {
+	return storage.within(func(tx *jsonTx) error {
+		rows, err := table(tx).load()
+		if err != nil {
+			return err
+		}
+		for i := range rows {
+			if match(rows[i]) {
+				rows[i] = row
+				table(tx).save(rows)
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+}
+
+func matchAll[T any](T) bool {
+	return true
+}
+
+func (j *jsonStorage) migrate() error {
+	return j.Atomic(func(tx Storage) error {
+		state := tx.(*jsonStorage).tx
+		customers, err := state.customers.load()
+		if err != nil {
+			return err
+		}
+		for i := range customers {
+			if customers[i].Currency == "" {
+				customers[i].Currency = money.DefaultCurrency
+				state.customers.save(customers)
+			}
+		}
+		merchants, err := state.merchants.load()
+		if err != nil {
+			return err
+		}
+		for i := range merchants {
+			if merchants[i].Currency == "" {
+				merchants[i].Currency = money.DefaultCurrency
+				state.merchants.save(merchants)
+			}
+		}
+		histories, err := state.histories.load()
+		if err != nil {
+			return err
+		}
+		for i := range histories {
+			if histories[i].Currency == "" {
+				histories[i].Currency = money.DefaultCurrency
+				state.histories.save(histories)
+			}
+			if histories[i].Type == "" {
+				histories[i].Type = entity.HistoryTypePayment
+				state.histories.save(histories)
+			}
+		}
+		return nil
+	})
+}
+
+func (j *jsonStorage) Close() error {
+	return nil
+}
+
+func customerTable(tx *jsonTx) *jsonTable[entity.Customer] { return &tx.customers }
+func merchantTable(tx *jsonTx) *jsonTable[entity.Merchant] { return &tx.merchants }
+func historyTable(tx *jsonTx) *jsonTable[entity.History]   { return &tx.histories }
+
+func (s *jsonCustomerStore) FindByUsername(username string) (entity.Customer, error) {
+	return jsonFirst(s.storage, customerTable, func(customer entity.Customer) bool {
+		return customer.Username == username
+	})
+}
+
+func (s *jsonCustomerStore) FindAll() ([]entity.Customer, error) {
+	return jsonSelect(s.storage, customerTable, matchAll[entity.Customer])
+}
+
+func (s *jsonCustomerStore) Insert(customer entity.Customer) error {
+	return jsonInsertUnique(s.storage, customerTable, func(row entity.Customer) bool {
+		return row.Username == customer.Username
+	}, customer)
+}
+
+func (s *jsonCustomerStore) Update(customer entity.Customer) error {
+	return jsonUpdate(s.storage, customerTable, func(row entity.Customer) bool {
+		return row.Username == customer.Username
+	}, customer)
+}
+
+func (s *jsonMerchantStore) FindByCode(merchantCode string) (entity.Merchant, error) {
+	return jsonFirst(s.storage, merchantTable, func(merchant entity.Merchant) bool {
+		return merchant.MerchantCode == merchantCode
+	})
+}
+
+func (s *jsonMerchantStore) FindAll() ([]entity.Merchant, error) {
+	return jsonSelect(s.storage, merchantTable, matchAll[entity.Merchant])
+}
+
+func (s *jsonMerchantStore) Update(merchant entity.Merchant) error {
+	return jsonUpdate(s.storage, merchantTable, func(row entity.Merchant) bool {
+		return row.MerchantCode == merchant.MerchantCode
+	}, merchant)
+}
+
+func (s *jsonHistoryStore) FindById(transactionId string) (entity.History, error) {
+	return jsonFirst(s.storage, historyTable, func(history entity.History) bool {
+		return history.TransactionId == transactionId
+	})
+}
+
+func (s *jsonHistoryStore) Insert(history entity.History) error {
+	return jsonInsert(s.storage, historyTable, history)
+}
+
+func (s *jsonHistoryStore) Find(filter HistoryFilter) ([]entity.History, error) {
+	result, err := jsonSelect(s.storage, historyTable, filter.Match)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		position := HistoryCursor{Date: result[j].Date, TransactionId: result[j].TransactionId}.Compare(result[i])
+		if filter.Descending {
+			return position > 0
+		}
+		return position < 0
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+func jsonDataFile(configured string, dir string, name string) string {
+	if configured != "" {
+		return configured
+	}
+	return filepath.Join(dir, name+".json")
+}
+
+func NewJsonStorage(config config.JsonFileConfig) (Storage, error) {
+	dir := filepath.Dir(config.Customer)
+	config.Posting = jsonDataFile(config.Posting, dir, "posting")
+	config.ApiKey = jsonDataFile(config.ApiKey, dir, "api_key")
+	config.PaymentRequest = jsonDataFile(config.PaymentRequest, dir, "payment_request")
+	config.Hold = jsonDataFile(config.Hold, dir, "hold")
+	config.Webhook = jsonDataFile(config.Webhook, dir, "webhook")
+	config.WebhookDelivery = jsonDataFile(config.WebhookDelivery, dir, "webhook_delivery")
+	config.Outbox = jsonDataFile(config.Outbox, dir, "outbox")
+	config.Checkpoint = jsonDataFile(config.Checkpoint, dir, "checkpoint")
+	config.TopUp = jsonDataFile(config.TopUp, dir, "topup")
+	config.BankAccount = jsonDataFile(config.BankAccount, dir, "bank_account")
+	config.Withdrawal = jsonDataFile(config.Withdrawal, dir, "withdrawal")
+	config.PayoutBatch = jsonDataFile(config.PayoutBatch, dir, "payout_batch")
+	config.FeeRule = jsonDataFile(config.FeeRule, dir, "fee_rule")
+	config.Wallet = jsonDataFile(config.Wallet, dir, "wallet")
+	config.FxRate = jsonDataFile(config.FxRate, dir, "fx_rate")
+	storage := &jsonStorage{
+		config:      config,
+		lockFile:    filepath.Join(dir, jsonLockFileName),
+		journalFile: filepath.Join(dir, jsonJournalFileName),
+	}
+
+	lock, err := lockFile(storage.lockFile)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to lock storage: " + err.Error())
+	}
+	err = replayJournal(storage.journalFile)
+	lock.Unlock()
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to replay storage journal: " + err.Error())
+	}
+
+	if err = storage.migrate(); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
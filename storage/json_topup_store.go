@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"time"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonTopUpStore struct {
+	storage *jsonStorage
+}
+
+func topUpTable(tx *jsonTx) *jsonTable[entity.TopUp] { return &tx.topUps }
+
+func (s *jsonTopUpStore) FindById(topUpId string) (entity.TopUp, error) {
+	return jsonFirst(s.storage, topUpTable, func(topUp entity.TopUp) bool {
+		return topUp.TopUpId == topUpId
+	})
+}
+
+func (s *jsonTopUpStore) FindByCustomer(customerUsername string) ([]entity.TopUp, error) {
+	return jsonSelect(s.storage, topUpTable, func(topUp entity.TopUp) bool {
+		return topUp.CustomerUsername == customerUsername
+	})
+}
+
+func (s *jsonTopUpStore) FindPending(createdBefore time.Time) ([]entity.TopUp, error) {
+	return jsonSelect(s.storage, topUpTable, func(topUp entity.TopUp) bool {
+		return topUp.Pending() && topUp.CreatedAt.Before(createdBefore)
+	})
+}
+
+func (s *jsonTopUpStore) Insert(topUp entity.TopUp) error {
+	return jsonInsertUnique(s.storage, topUpTable, func(existing entity.TopUp) bool {
+		return existing.TopUpId == topUp.TopUpId
+	}, topUp)
+}
+
+func (s *jsonTopUpStore) Update(topUp entity.TopUp) error {
+	return jsonUpdate(s.storage, topUpTable, func(existing entity.TopUp) bool {
+		return existing.TopUpId == topUp.TopUpId
+	}, topUp)
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"errors"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonWalletStore struct {
+	storage *jsonStorage
+}
+
+type jsonFxRateStore struct {
+	storage *jsonStorage
+}
+
+func walletTable(tx *jsonTx) *jsonTable[entity.Wallet] { return &tx.wallets }
+func fxRateTable(tx *jsonTx) *jsonTable[entity.FxRate] { return &tx.fxRates }
+
+func (s *jsonWalletStore) Find(username string, currency string) (entity.Wallet, error) {
+	return jsonFirst(s.storage, walletTable, func(wallet entity.Wallet) bool {
+		return wallet.Username == username && wallet.Currency == currency
+	})
+}
+
+func (s *jsonWalletStore) FindByCustomer(username string) ([]entity.Wallet, error) {
+	return jsonSelect(s.storage, walletTable, func(wallet entity.Wallet) bool {
+		return wallet.Username == username
+	})
+}
+
+func (s *jsonWalletStore) FindAll() ([]entity.Wallet, error) {
+	return jsonSelect(s.storage, walletTable, matchAll[entity.Wallet])
+}
+
+func (s *jsonWalletStore) Insert(wallet entity.Wallet) error {
+	return jsonInsertUnique(s.storage, walletTable, func(existing entity.Wallet) bool {
+		return existing.Username == wallet.Username && existing.Currency == wallet.Currency
+	}, wallet)
+}
+
+func (s *jsonWalletStore) Update(wallet entity.Wallet) error {
+	return jsonUpdate(s.storage, walletTable, func(existing entity.Wallet) bool {
+		return existing.Username == wallet.Username && existing.Currency == wallet.Currency
+	}, wallet)
+}
+
+func (s *jsonFxRateStore) Find(baseCurrency string, quoteCurrency string) (entity.FxRate, error) {
+	return jsonFirst(s.storage, fxRateTable, func(rate entity.FxRate) bool {
+		return rate.BaseCurrency == baseCurrency && rate.QuoteCurrency == quoteCurrency
+	})
+}
+
+func (s *jsonFxRateStore) FindAll() ([]entity.FxRate, error) {
+	return jsonSelect(s.storage, fxRateTable, matchAll[entity.FxRate])
+}
+
+func (s *jsonFxRateStore) Save(rate entity.FxRate) error {
+	err := jsonUpdate(s.storage, fxRateTable, func(existing entity.FxRate) bool {
+		return existing.BaseCurrency == rate.BaseCurrency && existing.QuoteCurrency == rate.QuoteCurrency
+	}, rate)
+	if errors.Is(err, ErrNotFound) {
+		return jsonInsert(s.storage, fxRateTable, rate)
+	}
+	return err
+}
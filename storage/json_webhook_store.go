@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonWebhookStore struct {
+	storage *jsonStorage
+}
+
+type jsonWebhookDeliveryStore struct {
+	storage *jsonStorage
+}
+
+func webhookTable(tx *jsonTx) *jsonTable[entity.Webhook]                 { return &tx.webhooks }
+func webhookDeliveryTable(tx *jsonTx) *jsonTable[entity.WebhookDelivery] { return &tx.deliveries }
+
+func (s *jsonWebhookStore) FindById(webhookId string) (entity.Webhook, error) {
+	return jsonFirst(s.storage, webhookTable, func(webhook entity.Webhook) bool {
+		return webhook.WebhookId == webhookId
+	})
+}
+
+func (s *jsonWebhookStore) FindByMerchant(merchantCode string) ([]entity.Webhook, error) {
+	return jsonSelect(s.storage, webhookTable, func(webhook entity.Webhook) bool {
+		return webhook.MerchantCode == merchantCode
+	})
+}
+
+func (s *jsonWebhookStore) Insert(webhook entity.Webhook) error {
+	return jsonInsertUnique(s.storage, webhookTable, func(existing entity.Webhook) bool {
+		return existing.WebhookId == webhook.WebhookId
+	}, webhook)
+}
+
+func (s *jsonWebhookStore) Update(webhook entity.Webhook) error {
+	return jsonUpdate(s.storage, webhookTable, func(existing entity.Webhook) bool {
+		return existing.WebhookId == webhook.WebhookId
+	}, webhook)
+}
+
+func (s *jsonWebhookDeliveryStore) FindById(deliveryId string) (entity.WebhookDelivery, error) {
+	return jsonFirst(s.storage, webhookDeliveryTable, func(delivery entity.WebhookDelivery) bool {
+		return delivery.DeliveryId == deliveryId
+	})
+}
+
+func (s *jsonWebhookDeliveryStore) FindByMerchant(merchantCode string, status string) ([]entity.WebhookDelivery, error) {
+	return jsonSelect(s.storage, webhookDeliveryTable, func(delivery entity.WebhookDelivery) bool {
+		return delivery.MerchantCode == merchantCode && (status == "" || delivery.Status == status)
+	})
+}
+
+func (s *jsonWebhookDeliveryStore) FindDue(now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	deliveries, err := jsonSelect(s.storage, webhookDeliveryTable, func(delivery entity.WebhookDelivery) bool {
+		return delivery.Status == entity.DeliveryStatusPending && !delivery.NextAttemptAt.After(now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+	})
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (s *jsonWebhookDeliveryStore) Insert(delivery entity.WebhookDelivery) error {
+	return jsonInsertUnique(s.storage, webhookDeliveryTable, func(existing entity.WebhookDelivery) bool {
+		return existing.DeliveryId == delivery.DeliveryId
+	}, delivery)
+}
+
+func (s *jsonWebhookDeliveryStore) Update(delivery entity.WebhookDelivery) error {
+	return jsonUpdate(s.storage, webhookDeliveryTable, func(existing entity.WebhookDelivery) bool {
+		return existing.DeliveryId == delivery.DeliveryId
+	}, delivery)
+}
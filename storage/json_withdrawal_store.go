@@ -0,0 +1,103 @@
+package storage
+
+import (
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+type jsonBankAccountStore struct {
+	storage *jsonStorage
+}
+
+type jsonWithdrawalStore struct {
+	storage *jsonStorage
+}
+
+type jsonPayoutBatchStore struct {
+	storage *jsonStorage
+}
+
+func bankAccountTable(tx *jsonTx) *jsonTable[entity.BankAccount] { return &tx.bankAccounts }
+
+func withdrawalTable(tx *jsonTx) *jsonTable[entity.Withdrawal] { return &tx.withdrawals }
+
+func payoutBatchTable(tx *jsonTx) *jsonTable[entity.PayoutBatch] { return &tx.batches }
+
+func (s *jsonBankAccountStore) FindById(bankAccountId string) (entity.BankAccount, error) {
+	return jsonFirst(s.storage, bankAccountTable, func(bankAccount entity.BankAccount) bool {
+		return bankAccount.BankAccountId == bankAccountId
+	})
+}
+
+func (s *jsonBankAccountStore) FindByOwner(ownerType string, owner string) ([]entity.BankAccount, error) {
+	return jsonSelect(s.storage, bankAccountTable, func(bankAccount entity.BankAccount) bool {
+		return bankAccount.OwnerType == ownerType && bankAccount.Owner == owner
+	})
+}
+
+func (s *jsonBankAccountStore) Insert(bankAccount entity.BankAccount) error {
+	return jsonInsertUnique(s.storage, bankAccountTable, func(existing entity.BankAccount) bool {
+		return existing.BankAccountId == bankAccount.BankAccountId ||
+			(existing.OwnerType == bankAccount.OwnerType && existing.Owner == bankAccount.Owner &&
+				existing.BankCode == bankAccount.BankCode && existing.AccountNumber == bankAccount.AccountNumber)
+	}, bankAccount)
+}
+
+func (s *jsonWithdrawalStore) FindById(withdrawalId string) (entity.Withdrawal, error) {
+	return jsonFirst(s.storage, withdrawalTable, func(withdrawal entity.Withdrawal) bool {
+		return withdrawal.WithdrawalId == withdrawalId
+	})
+}
+
+func (s *jsonWithdrawalStore) FindByOwner(ownerType string, owner string) ([]entity.Withdrawal, error) {
+	return jsonSelect(s.storage, withdrawalTable, func(withdrawal entity.Withdrawal) bool {
+		return withdrawal.OwnerType == ownerType && withdrawal.Owner == owner
+	})
+}
+
+func (s *jsonWithdrawalStore) FindByBatch(batchId string) ([]entity.Withdrawal, error) {
+	return jsonSelect(s.storage, withdrawalTable, func(withdrawal entity.Withdrawal) bool {
+		return withdrawal.BatchId == batchId
+	})
+}
+
+func (s *jsonWithdrawalStore) FindPending() ([]entity.Withdrawal, error) {
+	return jsonSelect(s.storage, withdrawalTable, func(withdrawal entity.Withdrawal) bool {
+		return withdrawal.Status == entity.WithdrawalStatusPending
+	})
+}
+
+func (s *jsonWithdrawalStore) Insert(withdrawal entity.Withdrawal) error {
+	return jsonInsertUnique(s.storage, withdrawalTable, func(existing entity.Withdrawal) bool {
+		return existing.WithdrawalId == withdrawal.WithdrawalId
+	}, withdrawal)
+}
+
+func (s *jsonWithdrawalStore) Update(withdrawal entity.Withdrawal) error {
+	return jsonUpdate(s.storage, withdrawalTable, func(existing entity.Withdrawal) bool {
+		return existing.WithdrawalId == withdrawal.WithdrawalId
+	}, withdrawal)
+}
+
+func (s *jsonPayoutBatchStore) FindById(batchId string) (entity.PayoutBatch, error) {
+	return jsonFirst(s.storage, payoutBatchTable, func(batch entity.PayoutBatch) bool {
+		return batch.BatchId == batchId
+	})
+}
+
+func (s *jsonPayoutBatchStore) FindAll() ([]entity.PayoutBatch, error) {
+	return jsonSelect(s.storage, payoutBatchTable, func(batch entity.PayoutBatch) bool {
+		return true
+	})
+}
+
+func (s *jsonPayoutBatchStore) Insert(batch entity.PayoutBatch) error {
+	return jsonInsertUnique(s.storage, payoutBatchTable, func(existing entity.PayoutBatch) bool {
+		return existing.BatchId == batch.BatchId
+	}, batch)
+}
+
+func (s *jsonPayoutBatchStore) Update(batch entity.PayoutBatch) error {
+	return jsonUpdate(s.storage, payoutBatchTable, func(existing entity.PayoutBatch) bool {
+		return existing.BatchId == batch.BatchId
+	}, batch)
+}
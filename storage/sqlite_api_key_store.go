@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const sqliteApiKeyColumns = "key_id, merchant_code, label, encrypted_secret, created_at, revoked_at"
+
+type sqliteApiKeyStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteApiKeyStore) FindById(keyId string) (entity.MerchantApiKey, error) {
+	apiKey, err := scanSqliteApiKey(s.db.QueryRow(`SELECT `+sqliteApiKeyColumns+` FROM api_keys WHERE key_id = ?`, keyId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.MerchantApiKey{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.MerchantApiKey{}, app_error.InternalServerError("Failed to read api key data: " + err.Error())
+	}
+	return apiKey, nil
+}
+
+func (s *sqliteApiKeyStore) FindByMerchant(merchantCode string) ([]entity.MerchantApiKey, error) {
+	rows, err := s.db.Query(`SELECT `+sqliteApiKeyColumns+` FROM api_keys WHERE merchant_code = ? ORDER BY created_at, rowid`, merchantCode)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read api key data: " + err.Error())
+	}
+	apiKeys, err := scanSqliteRows(rows, scanSqliteApiKey)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read api key data: " + err.Error())
+	}
+	return apiKeys, nil
+}
+
+func (s *sqliteApiKeyStore) Insert(apiKey entity.MerchantApiKey) error {
+	_, err := s.db.Exec(`INSERT INTO api_keys (`+sqliteApiKeyColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		apiKey.KeyId, apiKey.MerchantCode, apiKey.Label, apiKey.EncryptedSecret, formatSqliteTime(apiKey.CreatedAt), formatSqliteOptionalTime(apiKey.RevokedAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert api key data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteApiKeyStore) Update(apiKey entity.MerchantApiKey) error {
+	result, err := s.db.Exec(`UPDATE api_keys SET merchant_code = ?, label = ?, encrypted_secret = ?, created_at = ?, revoked_at = ? WHERE key_id = ?`,
+		apiKey.MerchantCode, apiKey.Label, apiKey.EncryptedSecret, formatSqliteTime(apiKey.CreatedAt), formatSqliteOptionalTime(apiKey.RevokedAt), apiKey.KeyId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update api key data: " + err.Error())
+	}
+	return requireSqliteRow(result, "api key")
+}
+
+func scanSqliteApiKey(row sqliteScanner) (entity.MerchantApiKey, error) {
+	var apiKey entity.MerchantApiKey
+	var createdAt, revokedAt string
+	err := row.Scan(&apiKey.KeyId, &apiKey.MerchantCode, &apiKey.Label, &apiKey.EncryptedSecret, &createdAt, &revokedAt)
+	if err != nil {
+		return entity.MerchantApiKey{}, err
+	}
+	apiKey.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	apiKey.RevokedAt = parseSqliteOptionalTime(revokedAt)
+	return apiKey, nil
+}
+
+func formatSqliteOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatSqliteTime(*t)
+}
+
+func parseSqliteOptionalTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(sqliteTimeFormat, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
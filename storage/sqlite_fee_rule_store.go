@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const sqliteFeeRuleColumns = "rule_id, merchant_code, category, currency, type, fixed_amount, percentage_bps, tiers, min_fee, max_fee, charged_to, created_at, disabled_at"
+
+type sqliteFeeRuleStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteFeeRuleStore) FindById(ruleId string) (entity.FeeRule, error) {
+	rule, err := scanSqliteFeeRule(s.db.QueryRow(`SELECT `+sqliteFeeRuleColumns+` FROM fee_rules WHERE rule_id = ?`, ruleId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.FeeRule{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.FeeRule{}, app_error.InternalServerError("Failed to read fee rule data: " + err.Error())
+	}
+	return rule, nil
+}
+
+func (s *sqliteFeeRuleStore) FindAll() ([]entity.FeeRule, error) {
+	return s.find(`SELECT ` + sqliteFeeRuleColumns + ` FROM fee_rules ORDER BY created_at, rowid`)
+}
+
+func (s *sqliteFeeRuleStore) FindActive() ([]entity.FeeRule, error) {
+	return s.find(`SELECT ` + sqliteFeeRuleColumns + ` FROM fee_rules WHERE disabled_at = '' ORDER BY created_at, rowid`)
+}
+
+func (s *sqliteFeeRuleStore) find(query string) ([]entity.FeeRule, error) {
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read fee rule data: " + err.Error())
+	}
+	rules, err := scanSqliteRows(rows, scanSqliteFeeRule)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read fee rule data: " + err.Error())
+	}
+	return rules, nil
+}
+
+func (s *sqliteFeeRuleStore) Insert(rule entity.FeeRule) error {
+	tiers, err := formatSqliteFeeTiers(rule.Tiers)
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert fee rule data: " + err.Error())
+	}
+	_, err = s.db.Exec(`INSERT INTO fee_rules (`+sqliteFeeRuleColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.RuleId, rule.MerchantCode, rule.Category, rule.Currency, rule.Type, rule.FixedAmount, rule.PercentageBps, tiers, rule.MinFee, rule.MaxFee,
+		rule.ChargedTo, formatSqliteTime(rule.CreatedAt), formatSqliteOptionalTime(rule.DisabledAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert fee rule data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteFeeRuleStore) Update(rule entity.FeeRule) error {
+	tiers, err := formatSqliteFeeTiers(rule.Tiers)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update fee rule data: " + err.Error())
+	}
+	result, err := s.db.Exec(`UPDATE fee_rules SET merchant_code = ?, category = ?, currency = ?, type = ?, fixed_amount = ?, percentage_bps = ?, tiers = ?, min_fee = ?, max_fee = ?, charged_to = ?, created_at = ?, disabled_at = ? WHERE rule_id = ?`,
+		rule.MerchantCode, rule.Category, rule.Currency, rule.Type, rule.FixedAmount, rule.PercentageBps, tiers, rule.MinFee, rule.MaxFee,
+		rule.ChargedTo, formatSqliteTime(rule.CreatedAt), formatSqliteOptionalTime(rule.DisabledAt), rule.RuleId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update fee rule data: " + err.Error())
+	}
+	return requireSqliteRow(result, "fee rule")
+}
+
+func formatSqliteFeeTiers(tiers []entity.FeeTier) (string, error) {
+	if len(tiers) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(tiers)
+	return string(data), err
+}
+
+func scanSqliteFeeRule(row sqliteScanner) (entity.FeeRule, error) {
+	var rule entity.FeeRule
+	var tiers, createdAt, disabledAt string
+	err := row.Scan(&rule.RuleId, &rule.MerchantCode, &rule.Category, &rule.Currency, &rule.Type, &rule.FixedAmount, &rule.PercentageBps, &tiers,
+		&rule.MinFee, &rule.MaxFee, &rule.ChargedTo, &createdAt, &disabledAt)
+	if err != nil {
+		return entity.FeeRule{}, err
+	}
+	if tiers != "" {
+		if err = json.Unmarshal([]byte(tiers), &rule.Tiers); err != nil {
+			return entity.FeeRule{}, err
+		}
+	}
+	rule.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	rule.DisabledAt = parseSqliteOptionalTime(disabledAt)
+	return rule, nil
+}
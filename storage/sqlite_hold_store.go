@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const sqliteHoldColumns = "hold_id, customer_username, merchant_code, amount, captured_amount, currency, reference, status, transaction_id, created_at, expires_at, resolved_at"
+
+type sqliteHoldStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteHoldStore) FindById(holdId string) (entity.Hold, error) {
+	hold, err := scanSqliteHold(s.db.QueryRow(`SELECT `+sqliteHoldColumns+` FROM holds WHERE hold_id = ?`, holdId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Hold{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Hold{}, app_error.InternalServerError("Failed to read hold data: " + err.Error())
+	}
+	return hold, nil
+}
+
+func (s *sqliteHoldStore) FindByCustomer(customerUsername string) ([]entity.Hold, error) {
+	return s.find(`customer_username = ?`, customerUsername)
+}
+
+func (s *sqliteHoldStore) FindByMerchant(merchantCode string) ([]entity.Hold, error) {
+	return s.find(`merchant_code = ?`, merchantCode)
+}
+
+func (s *sqliteHoldStore) FindExpired(now time.Time) ([]entity.Hold, error) {
+	return s.find(`status = ? AND expires_at <= ?`, entity.HoldStatusAuthorized, formatSqliteTime(now))
+}
+
+func (s *sqliteHoldStore) find(condition string, args ...any) ([]entity.Hold, error) {
+	rows, err := s.db.Query(`SELECT `+sqliteHoldColumns+` FROM holds WHERE `+condition+` ORDER BY created_at, rowid`, args...)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read hold data: " + err.Error())
+	}
+	holds, err := scanSqliteRows(rows, scanSqliteHold)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read hold data: " + err.Error())
+	}
+	return holds, nil
+}
+
+func (s *sqliteHoldStore) Insert(hold entity.Hold) error {
+	_, err := s.db.Exec(`INSERT INTO holds (`+sqliteHoldColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		hold.HoldId, hold.CustomerUsername, hold.MerchantCode, hold.Amount, hold.CapturedAmount, hold.Currency, hold.Reference, hold.Status,
+		hold.TransactionId, formatSqliteTime(hold.CreatedAt), formatSqliteTime(hold.ExpiresAt), formatSqliteOptionalTime(hold.ResolvedAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert hold data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteHoldStore) Update(hold entity.Hold) error {
+	result, err := s.db.Exec(`UPDATE holds SET customer_username = ?, merchant_code = ?, amount = ?, captured_amount = ?, currency = ?, reference = ?, status = ?, transaction_id = ?, created_at = ?, expires_at = ?, resolved_at = ? WHERE hold_id = ?`,
+		hold.CustomerUsername, hold.MerchantCode, hold.Amount, hold.CapturedAmount, hold.Currency, hold.Reference, hold.Status, hold.TransactionId,
+		formatSqliteTime(hold.CreatedAt), formatSqliteTime(hold.ExpiresAt), formatSqliteOptionalTime(hold.ResolvedAt), hold.HoldId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update hold data: " + err.Error())
+	}
+	return requireSqliteRow(result, "hold")
+}
+
+func scanSqliteHold(row sqliteScanner) (entity.Hold, error) {
+	var hold entity.Hold
+	var createdAt, expiresAt, resolvedAt string
+	err := row.Scan(&hold.HoldId, &hold.CustomerUsername, &hold.MerchantCode, &hold.Amount, &hold.CapturedAmount, &hold.Currency,
+		&hold.Reference, &hold.Status, &hold.TransactionId, &createdAt, &expiresAt, &resolvedAt)
+	if err != nil {
+		return entity.Hold{}, err
+	}
+	hold.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	hold.ExpiresAt, _ = time.Parse(sqliteTimeFormat, expiresAt)
+	hold.ResolvedAt = parseSqliteOptionalTime(resolvedAt)
+	return hold, nil
+}
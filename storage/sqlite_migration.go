@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+var sqliteMigrations = []string{
+	`
+CREATE TABLE IF NOT EXISTS customers (
+	uuid     TEXT NOT NULL,
+	username TEXT NOT NULL PRIMARY KEY,
+	password TEXT NOT NULL,
+	balance  REAL NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS merchants (
+	uuid          TEXT NOT NULL,
+	merchant_code TEXT NOT NULL PRIMARY KEY,
+	name          TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS histories (
+	transaction_id    TEXT NOT NULL,
+	customer_username TEXT NOT NULL,
+	merchant_code     TEXT NOT NULL,
+	amount            REAL NOT NULL,
+	date              TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_histories_customer ON histories (customer_username, date);
+`,
+	`
+ALTER TABLE customers RENAME TO customers_v1;
+CREATE TABLE customers (
+	uuid     TEXT NOT NULL,
+	username TEXT NOT NULL PRIMARY KEY,
+	password TEXT NOT NULL,
+	balance  INTEGER NOT NULL DEFAULT 0,
+	currency TEXT NOT NULL DEFAULT 'IDR'
+);
+INSERT INTO customers (uuid, username, password, balance, currency)
+	SELECT uuid, username, password, CAST(ROUND(balance * 100) AS INTEGER), 'IDR' FROM customers_v1;
+DROP TABLE customers_v1;
+
+DROP INDEX IF EXISTS idx_histories_customer;
+ALTER TABLE histories RENAME TO histories_v1;
+CREATE TABLE histories (
+	transaction_id    TEXT NOT NULL,
+	customer_username TEXT NOT NULL,
+	merchant_code     TEXT NOT NULL,
+	amount            INTEGER NOT NULL,
+	currency          TEXT NOT NULL DEFAULT 'IDR',
+	date              TEXT NOT NULL
+);
+INSERT INTO histories (transaction_id, customer_username, merchant_code, amount, currency, date)
+	SELECT transaction_id, customer_username, merchant_code, CAST(ROUND(amount * 100) AS INTEGER), 'IDR', date FROM histories_v1;
+DROP TABLE histories_v1;
+CREATE INDEX idx_histories_customer ON histories (customer_username, date);
+`,
+	`
+ALTER TABLE histories ADD COLUMN type TEXT NOT NULL DEFAULT 'payment';
+ALTER TABLE histories ADD COLUMN refund_of TEXT NOT NULL DEFAULT '';
+ALTER TABLE histories ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_histories_transaction ON histories (transaction_id);
+CREATE INDEX idx_histories_refund_of ON histories (refund_of);
+`,
+	`
+ALTER TABLE merchants ADD COLUMN balance INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE merchants ADD COLUMN currency TEXT NOT NULL DEFAULT 'IDR';
+CREATE TABLE postings (
+	entry_id       TEXT NOT NULL,
+	transaction_id TEXT NOT NULL,
+	account        TEXT NOT NULL,
+	amount         INTEGER NOT NULL,
+	currency       TEXT NOT NULL,
+	date           TEXT NOT NULL
+);
+CREATE INDEX idx_postings_account ON postings (account, date);
+CREATE INDEX idx_postings_transaction ON postings (transaction_id);
+`,
+	`
+ALTER TABLE customers ADD COLUMN totp_secret TEXT NOT NULL DEFAULT '';
+ALTER TABLE customers ADD COLUMN totp_enabled INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE customers ADD COLUMN totp_last_step INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE customers ADD COLUMN recovery_codes TEXT NOT NULL DEFAULT '';
+`,
+	`
+ALTER TABLE customers ADD COLUMN pin_hash TEXT NOT NULL DEFAULT '';
+`,
+	`
+ALTER TABLE customers ADD COLUMN role TEXT NOT NULL DEFAULT '';
+`,
+	`
+CREATE TABLE api_keys (
+	key_id           TEXT PRIMARY KEY,
+	merchant_code    TEXT NOT NULL,
+	label            TEXT NOT NULL DEFAULT '',
+	encrypted_secret TEXT NOT NULL,
+	created_at       TEXT NOT NULL,
+	revoked_at       TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_api_keys_merchant ON api_keys (merchant_code);
+`,
+	`
+ALTER TABLE histories ADD COLUMN reference TEXT NOT NULL DEFAULT '';
+CREATE TABLE payment_requests (
+	request_id        TEXT PRIMARY KEY,
+	merchant_code     TEXT NOT NULL,
+	customer_username TEXT NOT NULL,
+	amount            INTEGER NOT NULL,
+	currency          TEXT NOT NULL,
+	reference         TEXT NOT NULL,
+	status            TEXT NOT NULL,
+	created_at        TEXT NOT NULL,
+	expires_at        TEXT NOT NULL,
+	resolved_at       TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_payment_requests_customer ON payment_requests (customer_username, created_at);
+CREATE INDEX idx_payment_requests_merchant ON payment_requests (merchant_code, created_at);
+`,
+	`
+CREATE TABLE holds (
+	hold_id           TEXT PRIMARY KEY,
+	customer_username TEXT NOT NULL,
+	merchant_code     TEXT NOT NULL,
+	amount            INTEGER NOT NULL,
+	captured_amount   INTEGER NOT NULL DEFAULT 0,
+	currency          TEXT NOT NULL,
+	reference         TEXT NOT NULL DEFAULT '',
+	status            TEXT NOT NULL,
+	transaction_id    TEXT NOT NULL DEFAULT '',
+	created_at        TEXT NOT NULL,
+	expires_at        TEXT NOT NULL,
+	resolved_at       TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_holds_customer ON holds (customer_username, created_at);
+CREATE INDEX idx_holds_merchant ON holds (merchant_code, created_at);
+CREATE INDEX idx_holds_status ON holds (status, expires_at);
+`,
+	`
+CREATE TABLE webhooks (
+	webhook_id    TEXT PRIMARY KEY,
+	merchant_code TEXT NOT NULL,
+	url           TEXT NOT NULL,
+	event_types   TEXT NOT NULL,
+	signing_key   TEXT NOT NULL,
+	created_at    TEXT NOT NULL,
+	disabled_at   TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_webhooks_merchant ON webhooks (merchant_code);
+CREATE TABLE webhook_deliveries (
+	delivery_id      TEXT PRIMARY KEY,
+	webhook_id       TEXT NOT NULL,
+	merchant_code    TEXT NOT NULL,
+	event_id         TEXT NOT NULL,
+	event_type       TEXT NOT NULL,
+	payload          TEXT NOT NULL,
+	status           TEXT NOT NULL,
+	attempts         INTEGER NOT NULL DEFAULT 0,
+	last_status_code INTEGER NOT NULL DEFAULT 0,
+	last_error       TEXT NOT NULL DEFAULT '',
+	created_at       TEXT NOT NULL,
+	next_attempt_at  TEXT NOT NULL,
+	last_attempt_at  TEXT NOT NULL DEFAULT '',
+	delivered_at     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_webhook_deliveries_merchant ON webhook_deliveries (merchant_code, created_at);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+`,
+	`
+CREATE TABLE outbox (
+	sequence      INTEGER PRIMARY KEY AUTOINCREMENT,
+	event_id      TEXT NOT NULL UNIQUE,
+	type          TEXT NOT NULL,
+	merchant_code TEXT NOT NULL DEFAULT '',
+	created_at    TEXT NOT NULL,
+	data          TEXT NOT NULL
+);
+CREATE TABLE consumer_checkpoints (
+	consumer   TEXT PRIMARY KEY,
+	sequence   INTEGER NOT NULL,
+	updated_at TEXT NOT NULL
+);
+`,
+	`
+CREATE TABLE topups (
+	topup_id          TEXT PRIMARY KEY,
+	customer_username TEXT NOT NULL,
+	amount            INTEGER NOT NULL,
+	currency          TEXT NOT NULL,
+	gateway           TEXT NOT NULL,
+	gateway_reference TEXT NOT NULL DEFAULT '',
+	status            TEXT NOT NULL,
+	failure_reason    TEXT NOT NULL DEFAULT '',
+	transaction_id    TEXT NOT NULL DEFAULT '',
+	created_at        TEXT NOT NULL,
+	resolved_at       TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_topups_customer ON topups (customer_username, created_at);
+CREATE INDEX idx_topups_status ON topups (status, created_at);
+`,
+	`
+ALTER TABLE histories ADD COLUMN recipient_username TEXT NOT NULL DEFAULT '';
+ALTER TABLE histories ADD COLUMN note TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_histories_recipient ON histories (recipient_username, date);
+`,
+	`
+CREATE TABLE bank_accounts (
+	bank_account_id TEXT PRIMARY KEY,
+	owner_type      TEXT NOT NULL,
+	owner           TEXT NOT NULL,
+	bank_code       TEXT NOT NULL,
+	account_number  TEXT NOT NULL,
+	account_name    TEXT NOT NULL,
+	created_at      TEXT NOT NULL,
+	UNIQUE (owner_type, owner, bank_code, account_number)
+);
+CREATE TABLE withdrawals (
+	withdrawal_id   TEXT PRIMARY KEY,
+	owner_type      TEXT NOT NULL,
+	owner           TEXT NOT NULL,
+	bank_account_id TEXT NOT NULL,
+	bank_code       TEXT NOT NULL,
+	account_number  TEXT NOT NULL,
+	account_name    TEXT NOT NULL,
+	amount          INTEGER NOT NULL,
+	currency        TEXT NOT NULL,
+	status          TEXT NOT NULL,
+	batch_id        TEXT NOT NULL DEFAULT '',
+	failure_reason  TEXT NOT NULL DEFAULT '',
+	transaction_id  TEXT NOT NULL DEFAULT '',
+	created_at      TEXT NOT NULL,
+	resolved_at     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_withdrawals_owner ON withdrawals (owner_type, owner, created_at);
+CREATE INDEX idx_withdrawals_status ON withdrawals (status, created_at);
+CREATE INDEX idx_withdrawals_batch ON withdrawals (batch_id, created_at);
+CREATE TABLE payout_batches (
+	batch_id     TEXT PRIMARY KEY,
+	currency     TEXT NOT NULL,
+	count        INTEGER NOT NULL,
+	total        INTEGER NOT NULL,
+	paid_count   INTEGER NOT NULL DEFAULT 0,
+	failed_count INTEGER NOT NULL DEFAULT 0,
+	status       TEXT NOT NULL,
+	created_at   TEXT NOT NULL,
+	settled_at   TEXT NOT NULL DEFAULT ''
+);
+`,
+	`
+ALTER TABLE merchants ADD COLUMN category TEXT NOT NULL DEFAULT '';
+ALTER TABLE histories ADD COLUMN fee INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE histories ADD COLUMN fee_charged_to TEXT NOT NULL DEFAULT '';
+CREATE TABLE fee_rules (
+	rule_id        TEXT PRIMARY KEY,
+	merchant_code  TEXT NOT NULL DEFAULT '',
+	category       TEXT NOT NULL DEFAULT '',
+	type           TEXT NOT NULL,
+	fixed_amount   INTEGER NOT NULL DEFAULT 0,
+	percentage_bps INTEGER NOT NULL DEFAULT 0,
+	tiers          TEXT NOT NULL DEFAULT '',
+	min_fee        INTEGER NOT NULL DEFAULT 0,
+	max_fee        INTEGER NOT NULL DEFAULT 0,
+	charged_to     TEXT NOT NULL,
+	created_at     TEXT NOT NULL,
+	disabled_at    TEXT NOT NULL DEFAULT ''
+);
+`,
+	`
+ALTER TABLE histories ADD COLUMN settlement_amount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE histories ADD COLUMN settlement_currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE histories ADD COLUMN fx_rate TEXT NOT NULL DEFAULT '';
+ALTER TABLE histories ADD COLUMN fx_spread_bps INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE histories ADD COLUMN fx_rate_at TEXT NOT NULL DEFAULT '';
+CREATE TABLE wallets (
+	username   TEXT NOT NULL,
+	currency   TEXT NOT NULL,
+	balance    INTEGER NOT NULL DEFAULT 0,
+	created_at TEXT NOT NULL,
+	PRIMARY KEY (username, currency)
+);
+CREATE TABLE fx_rates (
+	base_currency  TEXT NOT NULL,
+	quote_currency TEXT NOT NULL,
+	rate           TEXT NOT NULL,
+	spread_bps     INTEGER NOT NULL DEFAULT 0,
+	rate_at        TEXT NOT NULL,
+	source         TEXT NOT NULL,
+	PRIMARY KEY (base_currency, quote_currency)
+);
+`,
+	`
+ALTER TABLE fee_rules ADD COLUMN currency TEXT NOT NULL DEFAULT '';
+`,
+	`
+ALTER TABLE payment_requests ADD COLUMN transaction_id TEXT NOT NULL DEFAULT '';
+`,
+}
+
+func migrateSqlite(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	for version < len(sqliteMigrations) {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(sqliteMigrations[version]); err != nil {
+			tx.Rollback()
+			return err
+		}
+		version++
+		if _, err = tx.Exec(`PRAGMA user_version = ` + strconv.Itoa(version)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const (
+	sqliteOutboxColumns     = "sequence, event_id, type, merchant_code, created_at, data"
+	sqliteCheckpointColumns = "consumer, sequence, updated_at"
+)
+
+type sqliteOutboxStore struct {
+	db sqlExecutor
+}
+
+type sqliteCheckpointStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteOutboxStore) Append(event entity.Event) (entity.OutboxEvent, error) {
+	result, err := s.db.Exec(`INSERT INTO outbox (event_id, type, merchant_code, created_at, data) VALUES (?, ?, ?, ?, ?)`,
+		event.EventId, event.Type, event.MerchantCode, formatSqliteTime(event.CreatedAt), string(event.Data))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return entity.OutboxEvent{}, ErrDuplicate
+	}
+	if err != nil {
+		return entity.OutboxEvent{}, app_error.InternalServerError("Failed to insert outbox data: " + err.Error())
+	}
+	sequence, err := result.LastInsertId()
+	if err != nil {
+		return entity.OutboxEvent{}, app_error.InternalServerError("Failed to insert outbox data: " + err.Error())
+	}
+	return entity.OutboxEvent{Sequence: sequence, Event: event}, nil
+}
+
+func (s *sqliteOutboxStore) FindAfter(sequence int64, limit int) ([]entity.OutboxEvent, error) {
+	query := `SELECT ` + sqliteOutboxColumns + ` FROM outbox WHERE sequence > ? ORDER BY sequence`
+	args := []any{sequence}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read outbox data: " + err.Error())
+	}
+	events, err := scanSqliteRows(rows, scanSqliteOutboxEvent)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read outbox data: " + err.Error())
+	}
+	return events, nil
+}
+
+func (s *sqliteCheckpointStore) Find(consumer string) (entity.ConsumerCheckpoint, error) {
+	var checkpoint entity.ConsumerCheckpoint
+	var updatedAt string
+	err := s.db.QueryRow(`SELECT `+sqliteCheckpointColumns+` FROM consumer_checkpoints WHERE consumer = ?`, consumer).
+		Scan(&checkpoint.Consumer, &checkpoint.Sequence, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ConsumerCheckpoint{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.ConsumerCheckpoint{}, app_error.InternalServerError("Failed to read checkpoint data: " + err.Error())
+	}
+	checkpoint.UpdatedAt, _ = time.Parse(sqliteTimeFormat, updatedAt)
+	return checkpoint, nil
+}
+
+func (s *sqliteCheckpointStore) Save(checkpoint entity.ConsumerCheckpoint) error {
+	_, err := s.db.Exec(`INSERT INTO consumer_checkpoints (`+sqliteCheckpointColumns+`) VALUES (?, ?, ?)
+		ON CONFLICT (consumer) DO UPDATE SET sequence = excluded.sequence, updated_at = excluded.updated_at`,
+		checkpoint.Consumer, checkpoint.Sequence, formatSqliteTime(checkpoint.UpdatedAt))
+	if err != nil {
+		return app_error.InternalServerError("Failed to save checkpoint data: " + err.Error())
+	}
+	return nil
+}
+
+func scanSqliteOutboxEvent(row sqliteScanner) (entity.OutboxEvent, error) {
+	var event entity.OutboxEvent
+	var createdAt, data string
+	err := row.Scan(&event.Sequence, &event.EventId, &event.Type, &event.MerchantCode, &createdAt, &data)
+	if err != nil {
+		return entity.OutboxEvent{}, err
+	}
+	event.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	event.Data = []byte(data)
+	return event, nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const sqlitePaymentRequestColumns = "request_id, merchant_code, customer_username, amount, currency, reference, status, created_at, expires_at, resolved_at, transaction_id"
+
+type sqlitePaymentRequestStore struct {
+	db sqlExecutor
+}
+
+func (s *sqlitePaymentRequestStore) FindById(requestId string) (entity.PaymentRequest, error) {
+	paymentRequest, err := scanSqlitePaymentRequest(s.db.QueryRow(`SELECT `+sqlitePaymentRequestColumns+` FROM payment_requests WHERE request_id = ?`, requestId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.PaymentRequest{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.PaymentRequest{}, app_error.InternalServerError("Failed to read payment request data: " + err.Error())
+	}
+	return paymentRequest, nil
+}
+
+func (s *sqlitePaymentRequestStore) FindByCustomer(customerUsername string) ([]entity.PaymentRequest, error) {
+	return s.find(`customer_username = ?`, customerUsername)
+}
+
+func (s *sqlitePaymentRequestStore) FindByMerchant(merchantCode string) ([]entity.PaymentRequest, error) {
+	return s.find(`merchant_code = ?`, merchantCode)
+}
+
+func (s *sqlitePaymentRequestStore) find(condition string, arg string) ([]entity.PaymentRequest, error) {
+	rows, err := s.db.Query(`SELECT `+sqlitePaymentRequestColumns+` FROM payment_requests WHERE `+condition+` ORDER BY created_at, rowid`, arg)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read payment request data: " + err.Error())
+	}
+	paymentRequests, err := scanSqliteRows(rows, scanSqlitePaymentRequest)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read payment request data: " + err.Error())
+	}
+	return paymentRequests, nil
+}
+
+func (s *sqlitePaymentRequestStore) Insert(paymentRequest entity.PaymentRequest) error {
+	_, err := s.db.Exec(`INSERT INTO payment_requests (`+sqlitePaymentRequestColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		paymentRequest.RequestId, paymentRequest.MerchantCode, paymentRequest.CustomerUsername, paymentRequest.Amount, paymentRequest.Currency,
+		paymentRequest.Reference, paymentRequest.Status, formatSqliteTime(paymentRequest.CreatedAt), formatSqliteTime(paymentRequest.ExpiresAt),
+		formatSqliteOptionalTime(paymentRequest.ResolvedAt), paymentRequest.TransactionId)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert payment request data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqlitePaymentRequestStore) Update(paymentRequest entity.PaymentRequest) error {
+	result, err := s.db.Exec(`UPDATE payment_requests SET merchant_code = ?, customer_username = ?, amount = ?, currency = ?, reference = ?, status = ?, created_at = ?, expires_at = ?, resolved_at = ?, transaction_id = ? WHERE request_id = ?`,
+		paymentRequest.MerchantCode, paymentRequest.CustomerUsername, paymentRequest.Amount, paymentRequest.Currency, paymentRequest.Reference,
+		paymentRequest.Status, formatSqliteTime(paymentRequest.CreatedAt), formatSqliteTime(paymentRequest.ExpiresAt),
+		formatSqliteOptionalTime(paymentRequest.ResolvedAt), paymentRequest.TransactionId, paymentRequest.RequestId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update payment request data: " + err.Error())
+	}
+	return requireSqliteRow(result, "payment request")
+}
+
+func scanSqlitePaymentRequest(row sqliteScanner) (entity.PaymentRequest, error) {
+	var paymentRequest entity.PaymentRequest
+	var createdAt, expiresAt, resolvedAt string
+	err := row.Scan(&paymentRequest.RequestId, &paymentRequest.MerchantCode, &paymentRequest.CustomerUsername, &paymentRequest.Amount,
+		&paymentRequest.Currency, &paymentRequest.Reference, &paymentRequest.Status, &createdAt, &expiresAt, &resolvedAt, &paymentRequest.TransactionId)
+	if err != nil {
+		return entity.PaymentRequest{}, err
+	}
+	paymentRequest.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	paymentRequest.ExpiresAt, _ = time.Parse(sqliteTimeFormat, expiresAt)
+	paymentRequest.ResolvedAt = parseSqliteOptionalTime(resolvedAt)
+	return paymentRequest, nil
+}
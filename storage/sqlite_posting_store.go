@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const sqlitePostingColumns = "entry_id, transaction_id, account, amount, currency, date"
+
+type sqlitePostingStore struct {
+	db sqlExecutor
+}
+
+func (s *sqlitePostingStore) Insert(posting entity.Posting) error {
+	_, err := s.db.Exec(`INSERT INTO postings (`+sqlitePostingColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		posting.EntryId, posting.TransactionId, posting.Account, posting.Amount, posting.Currency, formatSqliteTime(posting.Date))
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert posting data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqlitePostingStore) FindByAccount(account string) ([]entity.Posting, error) {
+	rows, err := s.db.Query(`SELECT `+sqlitePostingColumns+` FROM postings WHERE account = ? ORDER BY date, rowid`, account)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read posting data: " + err.Error())
+	}
+	postings, err := scanSqliteRows(rows, scanSqlitePosting)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read posting data: " + err.Error())
+	}
+	return postings, nil
+}
+
+func (s *sqlitePostingStore) Balances() ([]AccountBalance, error) {
+	rows, err := s.db.Query(`SELECT account, currency, SUM(amount) FROM postings GROUP BY account, currency ORDER BY account, currency`)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read posting data: " + err.Error())
+	}
+	balances, err := scanSqliteRows(rows, func(row sqliteScanner) (AccountBalance, error) {
+		var balance AccountBalance
+		err := row.Scan(&balance.Account, &balance.Currency, &balance.Balance)
+		return balance, err
+	})
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read posting data: " + err.Error())
+	}
+	return balances, nil
+}
+
+func scanSqlitePosting(row sqliteScanner) (entity.Posting, error) {
+	var posting entity.Posting
+	var date string
+	err := row.Scan(&posting.EntryId, &posting.TransactionId, &posting.Account, &posting.Amount, &posting.Currency, &date)
+	if err != nil {
+		return entity.Posting{}, err
+	}
+	posting.Date, _ = time.Parse(sqliteTimeFormat, date)
+	return posting, nil
+}
@@ -0,0 +1,490 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	sqliteTimeFormat      = "2006-01-02T15:04:05.000000000Z"
+	sqliteCustomerColumns = "uuid, username, password, balance, currency, totp_secret, totp_enabled, totp_last_step, recovery_codes, pin_hash, role"
+	sqliteMerchantColumns = "uuid, merchant_code, name, balance, currency, category"
+	sqliteHistoryColumns  = "transaction_id, type, customer_username, merchant_code, recipient_username, amount, fee, fee_charged_to, currency, settlement_amount, settlement_currency, fx_rate, fx_spread_bps, fx_rate_at, refund_of, reason, reference, note, date"
+)
+
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type sqliteStorage struct {
+	db *sql.DB
+	tx *sql.Tx
+	mu *sync.Mutex
+}
+
+type sqliteCustomerStore struct {
+	db sqlExecutor
+}
+
+type sqliteMerchantStore struct {
+	db sqlExecutor
+}
+
+type sqliteHistoryStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteStorage) executor() sqlExecutor {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+func (s *sqliteStorage) Customers() CustomerStore {
+	return &sqliteCustomerStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Merchants() MerchantStore {
+	return &sqliteMerchantStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Histories() HistoryStore {
+	return &sqliteHistoryStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Postings() PostingStore {
+	return &sqlitePostingStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) ApiKeys() ApiKeyStore {
+	return &sqliteApiKeyStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) PaymentRequests() PaymentRequestStore {
+	return &sqlitePaymentRequestStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Holds() HoldStore {
+	return &sqliteHoldStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Webhooks() WebhookStore {
+	return &sqliteWebhookStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) WebhookDeliveries() WebhookDeliveryStore {
+	return &sqliteWebhookDeliveryStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Outbox() OutboxStore {
+	return &sqliteOutboxStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Checkpoints() CheckpointStore {
+	return &sqliteCheckpointStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) TopUps() TopUpStore {
+	return &sqliteTopUpStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) BankAccounts() BankAccountStore {
+	return &sqliteBankAccountStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Withdrawals() WithdrawalStore {
+	return &sqliteWithdrawalStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) PayoutBatches() PayoutBatchStore {
+	return &sqlitePayoutBatchStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) FeeRules() FeeRuleStore {
+	return &sqliteFeeRuleStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Wallets() WalletStore {
+	return &sqliteWalletStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) FxRates() FxRateStore {
+	return &sqliteFxRateStore{db: s.executor()}
+}
+
+func (s *sqliteStorage) Atomic(fn func(tx Storage) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return app_error.InternalServerError("Failed to begin transaction: " + err.Error())
+	}
+	defer tx.Rollback()
+
+	if err = fn(&sqliteStorage{db: s.db, tx: tx, mu: s.mu}); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return app_error.InternalServerError("Failed to commit transaction: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteStorage) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqliteStorage) isEmpty() (bool, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT (SELECT COUNT(*) FROM customers) + (SELECT COUNT(*) FROM merchants)`).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+func (s *sqliteStorage) seedFromJson(jsonConfig config.JsonFileConfig) error {
+	var customers []entity.Customer
+	var merchants []entity.Merchant
+	var histories []entity.History
+	if err := readSeedFile(jsonConfig.Customer, &customers); err != nil {
+		return err
+	}
+	if err := readSeedFile(jsonConfig.Merchant, &merchants); err != nil {
+		return err
+	}
+	if err := readSeedFile(jsonConfig.History, &histories); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, customer := range customers {
+		_, err = tx.Exec(`INSERT INTO customers (uuid, username, password, balance, currency, role) VALUES (?, ?, ?, ?, ?, ?)`,
+			customer.Uuid, customer.Username, customer.Password, customer.Balance, defaultCurrency(customer.Currency), customer.Role)
+		if err != nil {
+			return err
+		}
+	}
+	for _, merchant := range merchants {
+		_, err = tx.Exec(`INSERT INTO merchants (`+sqliteMerchantColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+			merchant.Uuid, merchant.MerchantCode, merchant.Name, merchant.Balance, defaultCurrency(merchant.Currency), merchant.Category)
+		if err != nil {
+			return err
+		}
+	}
+	for _, history := range histories {
+		history.Currency = defaultCurrency(history.Currency)
+		if history.Type == "" {
+			history.Type = entity.HistoryTypePayment
+		}
+		if err = insertSqliteHistory(tx, history); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func readSeedFile(fileName string, target any) error {
+	if fileName == "" {
+		return nil
+	}
+	if _, err := os.Stat(fileName); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return utils.ReadParseJSON(fileName, target)
+}
+
+func (s *sqliteCustomerStore) FindByUsername(username string) (entity.Customer, error) {
+	customer, err := scanSqliteCustomer(s.db.QueryRow(`SELECT `+sqliteCustomerColumns+` FROM customers WHERE username = ?`, username))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Customer{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Customer{}, app_error.InternalServerError("Failed to read customer data: " + err.Error())
+	}
+	return customer, nil
+}
+
+func (s *sqliteCustomerStore) FindAll() ([]entity.Customer, error) {
+	rows, err := s.db.Query(`SELECT ` + sqliteCustomerColumns + ` FROM customers ORDER BY username`)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read customer data: " + err.Error())
+	}
+	customers, err := scanSqliteRows(rows, scanSqliteCustomer)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read customer data: " + err.Error())
+	}
+	return customers, nil
+}
+
+func (s *sqliteCustomerStore) Insert(customer entity.Customer) error {
+	_, err := s.db.Exec(`INSERT INTO customers (`+sqliteCustomerColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		customer.Uuid, customer.Username, customer.Password, customer.Balance, customer.Currency,
+		customer.TotpSecret, customer.TotpEnabled, customer.TotpLastStep, strings.Join(customer.RecoveryCodes, ","), customer.PinHash, customer.Role)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert customer data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteCustomerStore) Update(customer entity.Customer) error {
+	result, err := s.db.Exec(`UPDATE customers SET uuid = ?, password = ?, balance = ?, currency = ?, totp_secret = ?, totp_enabled = ?, totp_last_step = ?, recovery_codes = ?, pin_hash = ?, role = ? WHERE username = ?`,
+		customer.Uuid, customer.Password, customer.Balance, customer.Currency,
+		customer.TotpSecret, customer.TotpEnabled, customer.TotpLastStep, strings.Join(customer.RecoveryCodes, ","), customer.PinHash, customer.Role, customer.Username)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update customer data: " + err.Error())
+	}
+	return requireSqliteRow(result, "customer")
+}
+
+func (s *sqliteMerchantStore) FindByCode(merchantCode string) (entity.Merchant, error) {
+	merchant, err := scanSqliteMerchant(s.db.QueryRow(`SELECT `+sqliteMerchantColumns+` FROM merchants WHERE merchant_code = ?`, merchantCode))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Merchant{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Merchant{}, app_error.InternalServerError("Failed to read merchant data: " + err.Error())
+	}
+	return merchant, nil
+}
+
+func (s *sqliteMerchantStore) FindAll() ([]entity.Merchant, error) {
+	rows, err := s.db.Query(`SELECT ` + sqliteMerchantColumns + ` FROM merchants ORDER BY merchant_code`)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read merchant data: " + err.Error())
+	}
+	merchants, err := scanSqliteRows(rows, scanSqliteMerchant)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read merchant data: " + err.Error())
+	}
+	return merchants, nil
+}
+
+func (s *sqliteMerchantStore) Update(merchant entity.Merchant) error {
+	result, err := s.db.Exec(`UPDATE merchants SET uuid = ?, name = ?, balance = ?, currency = ?, category = ? WHERE merchant_code = ?`,
+		merchant.Uuid, merchant.Name, merchant.Balance, merchant.Currency, merchant.Category, merchant.MerchantCode)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update merchant data: " + err.Error())
+	}
+	return requireSqliteRow(result, "merchant")
+}
+
+func (s *sqliteHistoryStore) FindById(transactionId string) (entity.History, error) {
+	history, err := scanSqliteHistory(s.db.QueryRow(`SELECT `+sqliteHistoryColumns+` FROM histories WHERE transaction_id = ?`, transactionId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.History{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.History{}, app_error.InternalServerError("Failed to read history data: " + err.Error())
+	}
+	return history, nil
+}
+
+func (s *sqliteHistoryStore) Insert(history entity.History) error {
+	err := insertSqliteHistory(s.db, history)
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert history data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteHistoryStore) Find(filter HistoryFilter) ([]entity.History, error) {
+	var conditions []string
+	var args []any
+	if filter.CustomerUsername != "" {
+		conditions = append(conditions, "(customer_username = ? OR recipient_username = ?)")
+		args = append(args, filter.CustomerUsername, filter.CustomerUsername)
+	}
+	if filter.MerchantCode != "" {
+		conditions = append(conditions, "merchant_code = ?")
+		args = append(args, filter.MerchantCode)
+	}
+	if filter.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.RefundOf != "" {
+		conditions = append(conditions, "refund_of = ?")
+		args = append(args, filter.RefundOf)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "date >= ?")
+		args = append(args, formatSqliteTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "date <= ?")
+		args = append(args, formatSqliteTime(filter.To))
+	}
+	if filter.MinAmount != nil {
+		conditions = append(conditions, "amount >= ?")
+		args = append(args, *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		conditions = append(conditions, "amount <= ?")
+		args = append(args, *filter.MaxAmount)
+	}
+	order := "ASC"
+	if filter.Descending {
+		order = "DESC"
+	}
+	if filter.After != nil {
+		operator := ">"
+		if filter.Descending {
+			operator = "<"
+		}
+		conditions = append(conditions, "(date, transaction_id) "+operator+" (?, ?)")
+		args = append(args, formatSqliteTime(filter.After.Date), filter.After.TransactionId)
+	}
+
+	query := `SELECT ` + sqliteHistoryColumns + ` FROM histories`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY date " + order + ", transaction_id " + order
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read history data: " + err.Error())
+	}
+	histories, err := scanSqliteRows(rows, scanSqliteHistory)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read history data: " + err.Error())
+	}
+	return histories, nil
+}
+
+func insertSqliteHistory(db sqlExecutor, history entity.History) error {
+	_, err := db.Exec(`INSERT INTO histories (`+sqliteHistoryColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		history.TransactionId, history.Type, history.CustomerUsername, history.MerchantCode, history.RecipientUsername, history.Amount,
+		history.Fee, history.FeeChargedTo, history.Currency,
+		history.SettlementAmount, history.SettlementCurrency, history.FxRate, history.FxSpreadBps, formatSqliteOptionalTime(history.FxRateAt),
+		history.RefundOf, history.Reason, history.Reference, history.Note, formatSqliteTime(history.Date))
+	return err
+}
+
+type sqliteScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSqliteRows[T any](rows *sql.Rows, scan func(row sqliteScanner) (T, error)) ([]T, error) {
+	defer rows.Close()
+	result := []T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
+
+func requireSqliteRow(result sql.Result, name string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return app_error.InternalServerError("Failed to update " + name + " data: " + err.Error())
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanSqliteCustomer(row sqliteScanner) (entity.Customer, error) {
+	var customer entity.Customer
+	var recoveryCodes string
+	err := row.Scan(&customer.Uuid, &customer.Username, &customer.Password, &customer.Balance, &customer.Currency,
+		&customer.TotpSecret, &customer.TotpEnabled, &customer.TotpLastStep, &recoveryCodes, &customer.PinHash, &customer.Role)
+	if recoveryCodes != "" {
+		customer.RecoveryCodes = strings.Split(recoveryCodes, ",")
+	}
+	return customer, err
+}
+
+func scanSqliteMerchant(row sqliteScanner) (entity.Merchant, error) {
+	var merchant entity.Merchant
+	err := row.Scan(&merchant.Uuid, &merchant.MerchantCode, &merchant.Name, &merchant.Balance, &merchant.Currency, &merchant.Category)
+	return merchant, err
+}
+
+func scanSqliteHistory(row sqliteScanner) (entity.History, error) {
+	var history entity.History
+	var fxRateAt, date string
+	err := row.Scan(&history.TransactionId, &history.Type, &history.CustomerUsername, &history.MerchantCode, &history.RecipientUsername,
+		&history.Amount, &history.Fee, &history.FeeChargedTo, &history.Currency, &history.SettlementAmount, &history.SettlementCurrency,
+		&history.FxRate, &history.FxSpreadBps, &fxRateAt, &history.RefundOf, &history.Reason, &history.Reference, &history.Note, &date)
+	if err != nil {
+		return entity.History{}, err
+	}
+	history.FxRateAt = parseSqliteOptionalTime(fxRateAt)
+	history.Date, _ = time.Parse(sqliteTimeFormat, date)
+	return history, nil
+}
+
+func defaultCurrency(currency string) string {
+	if currency == "" {
+		return money.DefaultCurrency
+	}
+	return currency
+}
+
+func formatSqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+func NewSqliteStorage(fileName string, jsonConfig config.JsonFileConfig) (Storage, error) {
+	db, err := sql.Open("sqlite", fileName+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate")
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to open sqlite database: " + err.Error())
+	}
+	db.SetMaxOpenConns(1)
+
+	if err = migrateSqlite(db); err != nil {
+		db.Close()
+		return nil, app_error.InternalServerError("Failed to migrate sqlite schema: " + err.Error())
+	}
+
+	storage := &sqliteStorage{db: db, mu: &sync.Mutex{}}
+	empty, err := storage.isEmpty()
+	if err != nil {
+		db.Close()
+		return nil, app_error.InternalServerError("Failed to inspect sqlite database: " + err.Error())
+	}
+	if empty {
+		if err = storage.seedFromJson(jsonConfig); err != nil {
+			db.Close()
+			return nil, app_error.InternalServerError("Failed to seed sqlite database from json files: " + err.Error())
+		}
+	}
+	return storage, nil
+}
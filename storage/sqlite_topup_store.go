@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const sqliteTopUpColumns = "topup_id, customer_username, amount, currency, gateway, gateway_reference, status, failure_reason, transaction_id, created_at, resolved_at"
+
+type sqliteTopUpStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteTopUpStore) FindById(topUpId string) (entity.TopUp, error) {
+	topUp, err := scanSqliteTopUp(s.db.QueryRow(`SELECT `+sqliteTopUpColumns+` FROM topups WHERE topup_id = ?`, topUpId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.TopUp{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.TopUp{}, app_error.InternalServerError("Failed to read top-up data: " + err.Error())
+	}
+	return topUp, nil
+}
+
+func (s *sqliteTopUpStore) FindByCustomer(customerUsername string) ([]entity.TopUp, error) {
+	return s.find(`customer_username = ?`, customerUsername)
+}
+
+func (s *sqliteTopUpStore) FindPending(createdBefore time.Time) ([]entity.TopUp, error) {
+	return s.find(`status = ? AND created_at < ?`, entity.TopUpStatusPending, formatSqliteTime(createdBefore))
+}
+
+func (s *sqliteTopUpStore) find(condition string, args ...any) ([]entity.TopUp, error) {
+	rows, err := s.db.Query(`SELECT `+sqliteTopUpColumns+` FROM topups WHERE `+condition+` ORDER BY created_at, rowid`, args...)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read top-up data: " + err.Error())
+	}
+	topUps, err := scanSqliteRows(rows, scanSqliteTopUp)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read top-up data: " + err.Error())
+	}
+	return topUps, nil
+}
+
+func (s *sqliteTopUpStore) Insert(topUp entity.TopUp) error {
+	_, err := s.db.Exec(`INSERT INTO topups (`+sqliteTopUpColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		topUp.TopUpId, topUp.CustomerUsername, topUp.Amount, topUp.Currency, topUp.Gateway, topUp.GatewayReference,
+		topUp.Status, topUp.FailureReason, topUp.TransactionId, formatSqliteTime(topUp.CreatedAt), formatSqliteOptionalTime(topUp.ResolvedAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert top-up data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteTopUpStore) Update(topUp entity.TopUp) error {
+	result, err := s.db.Exec(`UPDATE topups SET customer_username = ?, amount = ?, currency = ?, gateway = ?, gateway_reference = ?, status = ?, failure_reason = ?, transaction_id = ?, created_at = ?, resolved_at = ? WHERE topup_id = ?`,
+		topUp.CustomerUsername, topUp.Amount, topUp.Currency, topUp.Gateway, topUp.GatewayReference, topUp.Status,
+		topUp.FailureReason, topUp.TransactionId, formatSqliteTime(topUp.CreatedAt), formatSqliteOptionalTime(topUp.ResolvedAt), topUp.TopUpId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update top-up data: " + err.Error())
+	}
+	return requireSqliteRow(result, "top-up")
+}
+
+func scanSqliteTopUp(row sqliteScanner) (entity.TopUp, error) {
+	var topUp entity.TopUp
+	var createdAt, resolvedAt string
+	err := row.Scan(&topUp.TopUpId, &topUp.CustomerUsername, &topUp.Amount, &topUp.Currency, &topUp.Gateway, &topUp.GatewayReference,
+		&topUp.Status, &topUp.FailureReason, &topUp.TransactionId, &createdAt, &resolvedAt)
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	topUp.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	topUp.ResolvedAt = parseSqliteOptionalTime(resolvedAt)
+	return topUp, nil
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const (
+	sqliteWalletColumns = "username, currency, balance, created_at"
+	sqliteFxRateColumns = "base_currency, quote_currency, rate, spread_bps, rate_at, source"
+)
+
+type sqliteWalletStore struct {
+	db sqlExecutor
+}
+
+type sqliteFxRateStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteWalletStore) Find(username string, currency string) (entity.Wallet, error) {
+	wallet, err := scanSqliteWallet(s.db.QueryRow(`SELECT `+sqliteWalletColumns+` FROM wallets WHERE username = ? AND currency = ?`, username, currency))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Wallet{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Wallet{}, app_error.InternalServerError("Failed to read wallet data: " + err.Error())
+	}
+	return wallet, nil
+}
+
+func (s *sqliteWalletStore) FindByCustomer(username string) ([]entity.Wallet, error) {
+	return s.find(`SELECT `+sqliteWalletColumns+` FROM wallets WHERE username = ? ORDER BY created_at, rowid`, username)
+}
+
+func (s *sqliteWalletStore) FindAll() ([]entity.Wallet, error) {
+	return s.find(`SELECT ` + sqliteWalletColumns + ` FROM wallets ORDER BY created_at, rowid`)
+}
+
+func (s *sqliteWalletStore) find(query string, args ...any) ([]entity.Wallet, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read wallet data: " + err.Error())
+	}
+	wallets, err := scanSqliteRows(rows, scanSqliteWallet)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read wallet data: " + err.Error())
+	}
+	return wallets, nil
+}
+
+func (s *sqliteWalletStore) Insert(wallet entity.Wallet) error {
+	_, err := s.db.Exec(`INSERT INTO wallets (`+sqliteWalletColumns+`) VALUES (?, ?, ?, ?)`,
+		wallet.Username, wallet.Currency, wallet.Balance, formatSqliteTime(wallet.CreatedAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert wallet data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteWalletStore) Update(wallet entity.Wallet) error {
+	result, err := s.db.Exec(`UPDATE wallets SET balance = ?, created_at = ? WHERE username = ? AND currency = ?`,
+		wallet.Balance, formatSqliteTime(wallet.CreatedAt), wallet.Username, wallet.Currency)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update wallet data: " + err.Error())
+	}
+	return requireSqliteRow(result, "wallet")
+}
+
+func scanSqliteWallet(row sqliteScanner) (entity.Wallet, error) {
+	var wallet entity.Wallet
+	var createdAt string
+	if err := row.Scan(&wallet.Username, &wallet.Currency, &wallet.Balance, &createdAt); err != nil {
+		return entity.Wallet{}, err
+	}
+	wallet.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	return wallet, nil
+}
+
+func (s *sqliteFxRateStore) Find(baseCurrency string, quoteCurrency string) (entity.FxRate, error) {
+	rate, err := scanSqliteFxRate(s.db.QueryRow(`SELECT `+sqliteFxRateColumns+` FROM fx_rates WHERE base_currency = ? AND quote_currency = ?`, baseCurrency, quoteCurrency))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.FxRate{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.FxRate{}, app_error.InternalServerError("Failed to read fx rate data: " + err.Error())
+	}
+	return rate, nil
+}
+
+func (s *sqliteFxRateStore) FindAll() ([]entity.FxRate, error) {
+	rows, err := s.db.Query(`SELECT ` + sqliteFxRateColumns + ` FROM fx_rates ORDER BY base_currency, quote_currency`)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read fx rate data: " + err.Error())
+	}
+	rates, err := scanSqliteRows(rows, scanSqliteFxRate)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read fx rate data: " + err.Error())
+	}
+	return rates, nil
+}
+
+func (s *sqliteFxRateStore) Save(rate entity.FxRate) error {
+	_, err := s.db.Exec(`INSERT INTO fx_rates (`+sqliteFxRateColumns+`) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (base_currency, quote_currency) DO UPDATE SET rate = excluded.rate, spread_bps = excluded.spread_bps, rate_at = excluded.rate_at, source = excluded.source`,
+		rate.BaseCurrency, rate.QuoteCurrency, rate.Rate, rate.SpreadBps, formatSqliteTime(rate.RateAt), rate.Source)
+	if err != nil {
+		return app_error.InternalServerError("Failed to save fx rate data: " + err.Error())
+	}
+	return nil
+}
+
+func scanSqliteFxRate(row sqliteScanner) (entity.FxRate, error) {
+	var rate entity.FxRate
+	var rateAt string
+	if err := row.Scan(&rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate, &rate.SpreadBps, &rateAt, &rate.Source); err != nil {
+		return entity.FxRate{}, err
+	}
+	rate.RateAt, _ = time.Parse(sqliteTimeFormat, rateAt)
+	return rate, nil
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const (
+	sqliteWebhookColumns         = "webhook_id, merchant_code, url, event_types, signing_key, created_at, disabled_at"
+	sqliteWebhookDeliveryColumns = "delivery_id, webhook_id, merchant_code, event_id, event_type, payload, status, attempts, last_status_code, last_error, created_at, next_attempt_at, last_attempt_at, delivered_at"
+)
+
+type sqliteWebhookStore struct {
+	db sqlExecutor
+}
+
+type sqliteWebhookDeliveryStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteWebhookStore) FindById(webhookId string) (entity.Webhook, error) {
+	webhook, err := scanSqliteWebhook(s.db.QueryRow(`SELECT `+sqliteWebhookColumns+` FROM webhooks WHERE webhook_id = ?`, webhookId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Webhook{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Webhook{}, app_error.InternalServerError("Failed to read webhook data: " + err.Error())
+	}
+	return webhook, nil
+}
+
+func (s *sqliteWebhookStore) FindByMerchant(merchantCode string) ([]entity.Webhook, error) {
+	rows, err := s.db.Query(`SELECT `+sqliteWebhookColumns+` FROM webhooks WHERE merchant_code = ? ORDER BY created_at, rowid`, merchantCode)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read webhook data: " + err.Error())
+	}
+	webhooks, err := scanSqliteRows(rows, scanSqliteWebhook)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read webhook data: " + err.Error())
+	}
+	return webhooks, nil
+}
+
+func (s *sqliteWebhookStore) Insert(webhook entity.Webhook) error {
+	_, err := s.db.Exec(`INSERT INTO webhooks (`+sqliteWebhookColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		webhook.WebhookId, webhook.MerchantCode, webhook.Url, strings.Join(webhook.EventTypes, ","), webhook.EncryptedSigningKey,
+		formatSqliteTime(webhook.CreatedAt), formatSqliteOptionalTime(webhook.DisabledAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert webhook data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteWebhookStore) Update(webhook entity.Webhook) error {
+	result, err := s.db.Exec(`UPDATE webhooks SET merchant_code = ?, url = ?, event_types = ?, signing_key = ?, created_at = ?, disabled_at = ? WHERE webhook_id = ?`,
+		webhook.MerchantCode, webhook.Url, strings.Join(webhook.EventTypes, ","), webhook.EncryptedSigningKey, formatSqliteTime(webhook.CreatedAt),
+		formatSqliteOptionalTime(webhook.DisabledAt), webhook.WebhookId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update webhook data: " + err.Error())
+	}
+	return requireSqliteRow(result, "webhook")
+}
+
+func (s *sqliteWebhookDeliveryStore) FindById(deliveryId string) (entity.WebhookDelivery, error) {
+	delivery, err := scanSqliteWebhookDelivery(s.db.QueryRow(`SELECT `+sqliteWebhookDeliveryColumns+` FROM webhook_deliveries WHERE delivery_id = ?`, deliveryId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.WebhookDelivery{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.WebhookDelivery{}, app_error.InternalServerError("Failed to read webhook delivery data: " + err.Error())
+	}
+	return delivery, nil
+}
+
+func (s *sqliteWebhookDeliveryStore) FindByMerchant(merchantCode string, status string) ([]entity.WebhookDelivery, error) {
+	query := `SELECT ` + sqliteWebhookDeliveryColumns + ` FROM webhook_deliveries WHERE merchant_code = ?`
+	args := []any{merchantCode}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	return s.find(query+` ORDER BY created_at, rowid`, args...)
+}
+
+func (s *sqliteWebhookDeliveryStore) FindDue(now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	query := `SELECT ` + sqliteWebhookDeliveryColumns + ` FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, rowid`
+	args := []any{entity.DeliveryStatusPending, formatSqliteTime(now)}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	return s.find(query, args...)
+}
+
+func (s *sqliteWebhookDeliveryStore) find(query string, args ...any) ([]entity.WebhookDelivery, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read webhook delivery data: " + err.Error())
+	}
+	deliveries, err := scanSqliteRows(rows, scanSqliteWebhookDelivery)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read webhook delivery data: " + err.Error())
+	}
+	return deliveries, nil
+}
+
+func (s *sqliteWebhookDeliveryStore) Insert(delivery entity.WebhookDelivery) error {
+	_, err := s.db.Exec(`INSERT INTO webhook_deliveries (`+sqliteWebhookDeliveryColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		delivery.DeliveryId, delivery.WebhookId, delivery.MerchantCode, delivery.EventId, delivery.EventType, delivery.Payload, delivery.Status,
+		delivery.Attempts, delivery.LastStatusCode, delivery.LastError, formatSqliteTime(delivery.CreatedAt), formatSqliteTime(delivery.NextAttemptAt),
+		formatSqliteOptionalTime(delivery.LastAttemptAt), formatSqliteOptionalTime(delivery.DeliveredAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert webhook delivery data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteWebhookDeliveryStore) Update(delivery entity.WebhookDelivery) error {
+	result, err := s.db.Exec(`UPDATE webhook_deliveries SET webhook_id = ?, merchant_code = ?, event_id = ?, event_type = ?, payload = ?, status = ?, attempts = ?, last_status_code = ?, last_error = ?, created_at = ?, next_attempt_at = ?, last_attempt_at = ?, delivered_at = ? WHERE delivery_id = ?`,
+		delivery.WebhookId, delivery.MerchantCode, delivery.EventId, delivery.EventType, delivery.Payload, delivery.Status, delivery.Attempts,
+		delivery.LastStatusCode, delivery.LastError, formatSqliteTime(delivery.CreatedAt), formatSqliteTime(delivery.NextAttemptAt),
+		formatSqliteOptionalTime(delivery.LastAttemptAt), formatSqliteOptionalTime(delivery.DeliveredAt), delivery.DeliveryId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update webhook delivery data: " + err.Error())
+	}
+	return requireSqliteRow(result, "webhook delivery")
+}
+
+func scanSqliteWebhook(row sqliteScanner) (entity.Webhook, error) {
+	var webhook entity.Webhook
+	var eventTypes, createdAt, disabledAt string
+	err := row.Scan(&webhook.WebhookId, &webhook.MerchantCode, &webhook.Url, &eventTypes, &webhook.EncryptedSigningKey, &createdAt, &disabledAt)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+	if eventTypes != "" {
+		webhook.EventTypes = strings.Split(eventTypes, ",")
+	}
+	webhook.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	webhook.DisabledAt = parseSqliteOptionalTime(disabledAt)
+	return webhook, nil
+}
+
+func scanSqliteWebhookDelivery(row sqliteScanner) (entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	var createdAt, nextAttemptAt, lastAttemptAt, deliveredAt string
+	err := row.Scan(&delivery.DeliveryId, &delivery.WebhookId, &delivery.MerchantCode, &delivery.EventId, &delivery.EventType, &delivery.Payload,
+		&delivery.Status, &delivery.Attempts, &delivery.LastStatusCode, &delivery.LastError, &createdAt, &nextAttemptAt, &lastAttemptAt, &deliveredAt)
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+	delivery.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	delivery.NextAttemptAt, _ = time.Parse(sqliteTimeFormat, nextAttemptAt)
+	delivery.LastAttemptAt = parseSqliteOptionalTime(lastAttemptAt)
+	delivery.DeliveredAt = parseSqliteOptionalTime(deliveredAt)
+	return delivery, nil
+}
@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+)
+
+const (
+	sqliteBankAccountColumns = "bank_account_id, owner_type, owner, bank_code, account_number, account_name, created_at"
+	sqliteWithdrawalColumns  = "withdrawal_id, owner_type, owner, bank_account_id, bank_code, account_number, account_name, amount, currency, status, batch_id, failure_reason, transaction_id, created_at, resolved_at"
+	sqlitePayoutBatchColumns = "batch_id, currency, count, total, paid_count, failed_count, status, created_at, settled_at"
+)
+
+type sqliteBankAccountStore struct {
+	db sqlExecutor
+}
+
+type sqliteWithdrawalStore struct {
+	db sqlExecutor
+}
+
+type sqlitePayoutBatchStore struct {
+	db sqlExecutor
+}
+
+func (s *sqliteBankAccountStore) FindById(bankAccountId string) (entity.BankAccount, error) {
+	bankAccount, err := scanSqliteBankAccount(s.db.QueryRow(`SELECT `+sqliteBankAccountColumns+` FROM bank_accounts WHERE bank_account_id = ?`, bankAccountId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.BankAccount{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.BankAccount{}, app_error.InternalServerError("Failed to read bank account data: " + err.Error())
+	}
+	return bankAccount, nil
+}
+
+func (s *sqliteBankAccountStore) FindByOwner(ownerType string, owner string) ([]entity.BankAccount, error) {
+	rows, err := s.db.Query(`SELECT `+sqliteBankAccountColumns+` FROM bank_accounts WHERE owner_type = ? AND owner = ? ORDER BY created_at, rowid`, ownerType, owner)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read bank account data: " + err.Error())
+	}
+	bankAccounts, err := scanSqliteRows(rows, scanSqliteBankAccount)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read bank account data: " + err.Error())
+	}
+	return bankAccounts, nil
+}
+
+func (s *sqliteBankAccountStore) Insert(bankAccount entity.BankAccount) error {
+	_, err := s.db.Exec(`INSERT INTO bank_accounts (`+sqliteBankAccountColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		bankAccount.BankAccountId, bankAccount.OwnerType, bankAccount.Owner, bankAccount.BankCode,
+		bankAccount.AccountNumber, bankAccount.AccountName, formatSqliteTime(bankAccount.CreatedAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert bank account data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteWithdrawalStore) FindById(withdrawalId string) (entity.Withdrawal, error) {
+	withdrawal, err := scanSqliteWithdrawal(s.db.QueryRow(`SELECT `+sqliteWithdrawalColumns+` FROM withdrawals WHERE withdrawal_id = ?`, withdrawalId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Withdrawal{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Withdrawal{}, app_error.InternalServerError("Failed to read withdrawal data: " + err.Error())
+	}
+	return withdrawal, nil
+}
+
+func (s *sqliteWithdrawalStore) FindByOwner(ownerType string, owner string) ([]entity.Withdrawal, error) {
+	return s.find(`owner_type = ? AND owner = ?`, ownerType, owner)
+}
+
+func (s *sqliteWithdrawalStore) FindByBatch(batchId string) ([]entity.Withdrawal, error) {
+	return s.find(`batch_id = ?`, batchId)
+}
+
+func (s *sqliteWithdrawalStore) FindPending() ([]entity.Withdrawal, error) {
+	return s.find(`status = ?`, entity.WithdrawalStatusPending)
+}
+
+func (s *sqliteWithdrawalStore) find(condition string, args ...any) ([]entity.Withdrawal, error) {
+	rows, err := s.db.Query(`SELECT `+sqliteWithdrawalColumns+` FROM withdrawals WHERE `+condition+` ORDER BY created_at, rowid`, args...)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read withdrawal data: " + err.Error())
+	}
+	withdrawals, err := scanSqliteRows(rows, scanSqliteWithdrawal)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read withdrawal data: " + err.Error())
+	}
+	return withdrawals, nil
+}
+
+func (s *sqliteWithdrawalStore) Insert(withdrawal entity.Withdrawal) error {
+	_, err := s.db.Exec(`INSERT INTO withdrawals (`+sqliteWithdrawalColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		withdrawal.WithdrawalId, withdrawal.OwnerType, withdrawal.Owner, withdrawal.BankAccountId, withdrawal.BankCode,
+		withdrawal.AccountNumber, withdrawal.AccountName, withdrawal.Amount, withdrawal.Currency, withdrawal.Status,
+		withdrawal.BatchId, withdrawal.FailureReason, withdrawal.TransactionId, formatSqliteTime(withdrawal.CreatedAt),
+		formatSqliteOptionalTime(withdrawal.ResolvedAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert withdrawal data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqliteWithdrawalStore) Update(withdrawal entity.Withdrawal) error {
+	result, err := s.db.Exec(`UPDATE withdrawals SET owner_type = ?, owner = ?, bank_account_id = ?, bank_code = ?, account_number = ?, account_name = ?, amount = ?, currency = ?, status = ?, batch_id = ?, failure_reason = ?, transaction_id = ?, created_at = ?, resolved_at = ? WHERE withdrawal_id = ?`,
+		withdrawal.OwnerType, withdrawal.Owner, withdrawal.BankAccountId, withdrawal.BankCode, withdrawal.AccountNumber,
+		withdrawal.AccountName, withdrawal.Amount, withdrawal.Currency, withdrawal.Status, withdrawal.BatchId,
+		withdrawal.FailureReason, withdrawal.TransactionId, formatSqliteTime(withdrawal.CreatedAt),
+		formatSqliteOptionalTime(withdrawal.ResolvedAt), withdrawal.WithdrawalId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update withdrawal data: " + err.Error())
+	}
+	return requireSqliteRow(result, "withdrawal")
+}
+
+func (s *sqlitePayoutBatchStore) FindById(batchId string) (entity.PayoutBatch, error) {
+	batch, err := scanSqlitePayoutBatch(s.db.QueryRow(`SELECT `+sqlitePayoutBatchColumns+` FROM payout_batches WHERE batch_id = ?`, batchId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.PayoutBatch{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.PayoutBatch{}, app_error.InternalServerError("Failed to read payout batch data: " + err.Error())
+	}
+	return batch, nil
+}
+
+func (s *sqlitePayoutBatchStore) FindAll() ([]entity.PayoutBatch, error) {
+	rows, err := s.db.Query(`SELECT ` + sqlitePayoutBatchColumns + ` FROM payout_batches ORDER BY created_at, rowid`)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read payout batch data: " + err.Error())
+	}
+	batches, err := scanSqliteRows(rows, scanSqlitePayoutBatch)
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to read payout batch data: " + err.Error())
+	}
+	return batches, nil
+}
+
+func (s *sqlitePayoutBatchStore) Insert(batch entity.PayoutBatch) error {
+	_, err := s.db.Exec(`INSERT INTO payout_batches (`+sqlitePayoutBatchColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		batch.BatchId, batch.Currency, batch.Count, batch.Total, batch.PaidCount, batch.FailedCount, batch.Status,
+		formatSqliteTime(batch.CreatedAt), formatSqliteOptionalTime(batch.SettledAt))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to insert payout batch data: " + err.Error())
+	}
+	return nil
+}
+
+func (s *sqlitePayoutBatchStore) Update(batch entity.PayoutBatch) error {
+	result, err := s.db.Exec(`UPDATE payout_batches SET currency = ?, count = ?, total = ?, paid_count = ?, failed_count = ?, status = ?, created_at = ?, settled_at = ? WHERE batch_id = ?`,
+		batch.Currency, batch.Count, batch.Total, batch.PaidCount, batch.FailedCount, batch.Status,
+		formatSqliteTime(batch.CreatedAt), formatSqliteOptionalTime(batch.SettledAt), batch.BatchId)
+	if err != nil {
+		return app_error.InternalServerError("Failed to update payout batch data: " + err.Error())
+	}
+	return requireSqliteRow(result, "payout batch")
+}
+
+func scanSqliteBankAccount(row sqliteScanner) (entity.BankAccount, error) {
+	var bankAccount entity.BankAccount
+	var createdAt string
+	err := row.Scan(&bankAccount.BankAccountId, &bankAccount.OwnerType, &bankAccount.Owner, &bankAccount.BankCode,
+		&bankAccount.AccountNumber, &bankAccount.AccountName, &createdAt)
+	if err != nil {
+		return entity.BankAccount{}, err
+	}
+	bankAccount.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	return bankAccount, nil
+}
+
+func scanSqliteWithdrawal(row sqliteScanner) (entity.Withdrawal, error) {
+	var withdrawal entity.Withdrawal
+	var createdAt, resolvedAt string
+	err := row.Scan(&withdrawal.WithdrawalId, &withdrawal.OwnerType, &withdrawal.Owner, &withdrawal.BankAccountId,
+		&withdrawal.BankCode, &withdrawal.AccountNumber, &withdrawal.AccountName, &withdrawal.Amount, &withdrawal.Currency,
+		&withdrawal.Status, &withdrawal.BatchId, &withdrawal.FailureReason, &withdrawal.TransactionId, &createdAt, &resolvedAt)
+	if err != nil {
+		return entity.Withdrawal{}, err
+	}
+	withdrawal.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	withdrawal.ResolvedAt = parseSqliteOptionalTime(resolvedAt)
+	return withdrawal, nil
+}
+
+func scanSqlitePayoutBatch(row sqliteScanner) (entity.PayoutBatch, error) {
+	var batch entity.PayoutBatch
+	var createdAt, settledAt string
+	err := row.Scan(&batch.BatchId, &batch.Currency, &batch.Count, &batch.Total, &batch.PaidCount, &batch.FailedCount,
+		&batch.Status, &createdAt, &settledAt)
+	if err != nil {
+		return entity.PayoutBatch{}, err
+	}
+	batch.CreatedAt, _ = time.Parse(sqliteTimeFormat, createdAt)
+	batch.SettledAt = parseSqliteOptionalTime(settledAt)
+	return batch, nil
+}
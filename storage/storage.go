@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+)
+
+const (
+	DriverJson   = "json"
+	DriverSqlite = "sqlite"
+)
+
+var (
+	ErrNotFound  = errors.New("data not found")
+	ErrDuplicate = errors.New("data already exists")
+)
+
+type CustomerStore interface {
+	FindByUsername(username string) (entity.Customer, error)
+	FindAll() ([]entity.Customer, error)
+	Insert(customer entity.Customer) error
+	Update(customer entity.Customer) error
+}
+
+type MerchantStore interface {
+	FindByCode(merchantCode string) (entity.Merchant, error)
+	FindAll() ([]entity.Merchant, error)
+	Update(merchant entity.Merchant) error
+}
+
+type HistoryCursor struct {
+	Date          time.Time
+	TransactionId string
+}
+
+type HistoryFilter struct {
+	CustomerUsername string
+	MerchantCode     string
+	Type             string
+	RefundOf         string
+	From             time.Time
+	To               time.Time
+	MinAmount        *money.Amount
+	MaxAmount        *money.Amount
+	Descending       bool
+	After            *HistoryCursor
+	Limit            int
+}
+
+type HistoryStore interface {
+	FindById(transactionId string) (entity.History, error)
+	Insert(history entity.History) error
+	Find(filter HistoryFilter) ([]entity.History, error)
+}
+
+type AccountBalance struct {
+	Account  string
+	Currency string
+	Balance  money.Amount
+}
+
+type PostingStore interface {
+	Insert(posting entity.Posting) error
+	FindByAccount(account string) ([]entity.Posting, error)
+	Balances() ([]AccountBalance, error)
+}
+
+type ApiKeyStore interface {
+	FindById(keyId string) (entity.MerchantApiKey, error)
+	FindByMerchant(merchantCode string) ([]entity.MerchantApiKey, error)
+	Insert(apiKey entity.MerchantApiKey) error
+	Update(apiKey entity.MerchantApiKey) error
+}
+
+type PaymentRequestStore interface {
+	FindById(requestId string) (entity.PaymentRequest, error)
+	FindByCustomer(customerUsername string) ([]entity.PaymentRequest, error)
+	FindByMerchant(merchantCode string) ([]entity.PaymentRequest, error)
+	Insert(paymentRequest entity.PaymentRequest) error
+	Update(paymentRequest entity.PaymentRequest) error
+}
+
+type HoldStore interface {
+	FindById(holdId string) (entity.Hold, error)
+	FindByCustomer(customerUsername string) ([]entity.Hold, error)
+	FindByMerchant(merchantCode string) ([]entity.Hold, error)
+	FindExpired(now time.Time) ([]entity.Hold, error)
+	Insert(hold entity.Hold) error
+	Update(hold entity.Hold) error
+}
+
+type WebhookStore interface {
+	FindById(webhookId string) (entity.Webhook, error)
+	FindByMerchant(merchantCode string) ([]entity.Webhook, error)
+	Insert(webhook entity.Webhook) error
+	Update(webhook entity.Webhook) error
+}
+
+type WebhookDeliveryStore interface {
+	FindById(deliveryId string) (entity.WebhookDelivery, error)
+	FindByMerchant(merchantCode string, status string) ([]entity.WebhookDelivery, error)
+	FindDue(now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	Insert(delivery entity.WebhookDelivery) error
+	Update(delivery entity.WebhookDelivery) error
+}
+
+type OutboxStore interface {
+	Append(event entity.Event) (entity.OutboxEvent, error)
+	FindAfter(sequence int64, limit int) ([]entity.OutboxEvent, error)
+}
+
+type CheckpointStore interface {
+	Find(consumer string) (entity.ConsumerCheckpoint, error)
+	Save(checkpoint entity.ConsumerCheckpoint) error
+}
+
+type TopUpStore interface {
+	FindById(topUpId string) (entity.TopUp, error)
+	FindByCustomer(customerUsername string) ([]entity.TopUp, error)
+	FindPending(createdBefore time.Time) ([]entity.TopUp, error)
+	Insert(topUp entity.TopUp) error
+	Update(topUp entity.TopUp) error
+}
+
+type BankAccountStore interface {
+	FindById(bankAccountId string) (entity.BankAccount, error)
+	FindByOwner(ownerType string, owner string) ([]entity.BankAccount, error)
+	Insert(bankAccount entity.BankAccount) error
+}
+
+type WithdrawalStore interface {
+	FindById(withdrawalId string) (entity.Withdrawal, error)
+	FindByOwner(ownerType string, owner string) ([]entity.Withdrawal, error)
+	FindByBatch(batchId string) ([]entity.Withdrawal, error)
+	FindPending() ([]entity.Withdrawal, error)
+	Insert(withdrawal entity.Withdrawal) error
+	Update(withdrawal entity.Withdrawal) error
+}
+
+type PayoutBatchStore interface {
+	FindById(batchId string) (entity.PayoutBatch, error)
+	FindAll() ([]entity.PayoutBatch, error)
+	Insert(batch entity.PayoutBatch) error
+	Update(batch entity.PayoutBatch) error
+}
+
+type FeeRuleStore interface {
+	FindById(ruleId string) (entity.FeeRule, error)
+	FindAll() ([]entity.FeeRule, error)
+	FindActive() ([]entity.FeeRule, error)
+	Insert(rule entity.FeeRule) error
+	Update(rule entity.FeeRule) error
+}
+
+type WalletStore interface {
+	Find(username string, currency string) (entity.Wallet, error)
+	FindByCustomer(username string) ([]entity.Wallet, error)
+	FindAll() ([]entity.Wallet, error)
+	Insert(wallet entity.Wallet) error
+	Update(wallet entity.Wallet) error
+}
+
+type FxRateStore interface {
+	Find(baseCurrency string, quoteCurrency string) (entity.FxRate, error)
+	FindAll() ([]entity.FxRate, error)
+	Save(rate entity.FxRate) error
+}
+
+type Storage interface {
+	Customers() CustomerStore
+	Merchants() MerchantStore
+	Histories() HistoryStore
+	Postings() PostingStore
+	ApiKeys() ApiKeyStore
+	PaymentRequests() PaymentRequestStore
+	Holds() HoldStore
+	Webhooks() WebhookStore
+	WebhookDeliveries() WebhookDeliveryStore
+	Outbox() OutboxStore
+	Checkpoints() CheckpointStore
+	TopUps() TopUpStore
+	BankAccounts() BankAccountStore
+	Withdrawals() WithdrawalStore
+	PayoutBatches() PayoutBatchStore
+	FeeRules() FeeRuleStore
+	Wallets() WalletStore
+	FxRates() FxRateStore
+	Atomic(fn func(tx Storage) error) error
+	Close() error
+}
+
+func (f HistoryFilter) Match(history entity.History) bool {
+	if f.CustomerUsername != "" && history.CustomerUsername != f.CustomerUsername && history.RecipientUsername != f.CustomerUsername {
+		return false
+	}
+	if f.MerchantCode != "" && history.MerchantCode != f.MerchantCode {
+		return false
+	}
+	if f.Type != "" && history.Type != f.Type {
+		return false
+	}
+	if f.RefundOf != "" && history.RefundOf != f.RefundOf {
+		return false
+	}
+	if !f.From.IsZero() && history.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && history.Date.After(f.To) {
+		return false
+	}
+	if f.MinAmount != nil && history.Amount < *f.MinAmount {
+		return false
+	}
+	if f.MaxAmount != nil && history.Amount > *f.MaxAmount {
+		return false
+	}
+	if f.After != nil {
+		position := f.After.Compare(history)
+		if f.Descending {
+			return position < 0
+		}
+		return position > 0
+	}
+	return true
+}
+
+func (c HistoryCursor) Compare(history entity.History) int {
+	if !history.Date.Equal(c.Date) {
+		if history.Date.Before(c.Date) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(history.TransactionId, c.TransactionId)
+}
+
+func NewStorage(config config.StorageConfig) (Storage, error) {
+	switch config.Driver {
+	case "", DriverJson:
+		return NewJsonStorage(config.JsonFileConfig)
+	case DriverSqlite:
+		return NewSqliteStorage(config.SqliteFile, config.JsonFileConfig)
+	default:
+		return nil, app_error.InternalServerError("Unknown storage driver: " + config.Driver)
+	}
+}
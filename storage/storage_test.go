@@ -0,0 +1,810 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyCustomers = []entity.Customer{
+	{
+		Uuid:     "Dummy Uuid",
+		Username: "dummyUsername",
+		Password: "dummyPassword",
+		Balance:  money.MustParse("50000.25"),
+		Currency: money.DefaultCurrency,
+	},
+}
+
+var dummyMerchants = []entity.Merchant{
+	{
+		Uuid:         "Dummy Merchant Uuid",
+		MerchantCode: "Dummy Merchant Code",
+		Name:         "Dummy Merchant",
+		Currency:     money.DefaultCurrency,
+	},
+}
+
+var dummyHistories = []entity.History{
+	{
+		TransactionId:    "Dummy Transaction Id",
+		CustomerUsername: "dummyUsername",
+		MerchantCode:     "Dummy Merchant Code",
+		Amount:           money.MustParse("20000"),
+		Currency:         money.DefaultCurrency,
+	},
+}
+
+type StorageTestSuite struct {
+	suite.Suite
+	config config.StorageConfig
+}
+
+func (suite *StorageTestSuite) SetupTest() {
+	suite.writeFixtures()
+}
+
+func (suite *StorageTestSuite) writeFixtures() {
+	dir := suite.T().TempDir()
+	suite.config = config.StorageConfig{
+		SqliteFile: filepath.Join(dir, "test.db"),
+		JsonFileConfig: config.JsonFileConfig{
+			Customer: filepath.Join(dir, "customer.json"),
+			Merchant: filepath.Join(dir, "merchant.json"),
+			History:  filepath.Join(dir, "history.json"),
+		},
+	}
+	suite.Require().NoError(utils.WriteJSON(suite.config.Customer, dummyCustomers))
+	suite.Require().NoError(utils.WriteJSON(suite.config.Merchant, dummyMerchants))
+	suite.Require().NoError(utils.WriteJSON(suite.config.History, dummyHistories))
+}
+
+func (suite *StorageTestSuite) drivers() []string {
+	return []string{DriverJson, DriverSqlite}
+}
+
+func (suite *StorageTestSuite) open(driver string) Storage {
+	suite.writeFixtures()
+	suite.config.Driver = driver
+	storage, err := NewStorage(suite.config)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { storage.Close() })
+	return storage
+}
+
+func (suite *StorageTestSuite) TestNewStorage_UnknownDriver() {
+	suite.config.Driver = "unknown"
+	_, err := NewStorage(suite.config)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *StorageTestSuite) TestFindCustomer_Success() {
+	for _, driver := range suite.drivers() {
+		customer, err := suite.open(driver).Customers().FindByUsername(dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), dummyCustomers[0], customer, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestFindCustomer_NotFound() {
+	for _, driver := range suite.drivers() {
+		_, err := suite.open(driver).Customers().FindByUsername("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestUpdateCustomer_Success() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		customer := dummyCustomers[0]
+		customer.Balance = money.MustParse("30000.10")
+		assert.Nil(suite.T(), storage.Customers().Update(customer), driver)
+		updated, err := storage.Customers().FindByUsername(customer.Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), customer.Balance, updated.Balance, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestUpdateCustomer_Credentials() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		customer := dummyCustomers[0]
+		customer.TotpSecret = "JBSWY3DPEHPK3PXP"
+		customer.TotpEnabled = true
+		customer.TotpLastStep = 56000000
+		customer.RecoveryCodes = []string{"hash1", "hash2"}
+		customer.PinHash = "pin hash"
+		customer.Role = "admin"
+		assert.Nil(suite.T(), storage.Customers().Update(customer), driver)
+		updated, err := storage.Customers().FindByUsername(customer.Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), customer, updated, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestUpdateCustomer_NotFound() {
+	for _, driver := range suite.drivers() {
+		customer := dummyCustomers[0]
+		customer.Username = "unknown"
+		err := suite.open(driver).Customers().Update(customer)
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertCustomer() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		customer := entity.Customer{Uuid: "New Uuid", Username: "newUsername", Password: "hash", Currency: money.DefaultCurrency}
+		assert.Nil(suite.T(), storage.Customers().Insert(customer), driver)
+		found, err := storage.Customers().FindByUsername(customer.Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), customer, found, driver)
+		assert.ErrorIs(suite.T(), storage.Customers().Insert(customer), ErrDuplicate, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestFindMerchant() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		merchant, err := storage.Merchants().FindByCode(dummyMerchants[0].MerchantCode)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), dummyMerchants[0], merchant, driver)
+		_, err = storage.Merchants().FindByCode("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestUpdateMerchant() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		merchant := dummyMerchants[0]
+		merchant.Balance = money.MustParse("125.50")
+		assert.Nil(suite.T(), storage.Merchants().Update(merchant), driver)
+		merchants, err := storage.Merchants().FindAll()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.Merchant{merchant}, merchants, driver)
+		merchant.MerchantCode = "unknown"
+		assert.ErrorIs(suite.T(), storage.Merchants().Update(merchant), ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndFindPostings() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		date := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		postings := []entity.Posting{
+			{EntryId: "entry-1", TransactionId: "tx-1", Account: "customer:a", Amount: money.FromMinor(-500), Currency: money.DefaultCurrency, Date: date},
+			{EntryId: "entry-1", TransactionId: "tx-1", Account: "merchant:b", Amount: money.FromMinor(500), Currency: money.DefaultCurrency, Date: date},
+			{EntryId: "entry-2", TransactionId: "tx-2", Account: "customer:a", Amount: money.FromMinor(-200), Currency: money.DefaultCurrency, Date: date},
+			{EntryId: "entry-2", TransactionId: "tx-2", Account: "merchant:b", Amount: money.FromMinor(200), Currency: money.DefaultCurrency, Date: date},
+		}
+		err := storage.Atomic(func(tx Storage) error {
+			for _, posting := range postings {
+				if err := tx.Postings().Insert(posting); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		assert.Nil(suite.T(), err, driver)
+
+		found, err := storage.Postings().FindByAccount("customer:a")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.Posting{postings[0], postings[2]}, found, driver)
+
+		balances, err := storage.Postings().Balances()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []AccountBalance{
+			{Account: "customer:a", Currency: money.DefaultCurrency, Balance: money.FromMinor(-700)},
+			{Account: "merchant:b", Currency: money.DefaultCurrency, Balance: money.FromMinor(700)},
+		}, balances, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndUpdateApiKeys() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		apiKeys := []entity.MerchantApiKey{
+			{KeyId: "key-1", MerchantCode: "Dummy Merchant Code", Label: "primary", EncryptedSecret: "hash-1", CreatedAt: createdAt},
+			{KeyId: "key-2", MerchantCode: "Dummy Merchant Code", EncryptedSecret: "hash-2", CreatedAt: createdAt.Add(time.Hour)},
+			{KeyId: "key-3", MerchantCode: "Other Merchant Code", EncryptedSecret: "hash-3", CreatedAt: createdAt},
+		}
+		for _, apiKey := range apiKeys {
+			assert.Nil(suite.T(), storage.ApiKeys().Insert(apiKey), driver)
+		}
+		assert.ErrorIs(suite.T(), storage.ApiKeys().Insert(apiKeys[0]), ErrDuplicate, driver)
+
+		found, err := storage.ApiKeys().FindByMerchant("Dummy Merchant Code")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), apiKeys[:2], found, driver)
+
+		revokedAt := createdAt.Add(2 * time.Hour)
+		apiKeys[0].RevokedAt = &revokedAt
+		assert.Nil(suite.T(), storage.ApiKeys().Update(apiKeys[0]), driver)
+		apiKey, err := storage.ApiKeys().FindById("key-1")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), apiKeys[0], apiKey, driver)
+		assert.False(suite.T(), apiKey.Active(), driver)
+
+		_, err = storage.ApiKeys().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+		assert.ErrorIs(suite.T(), storage.ApiKeys().Update(entity.MerchantApiKey{KeyId: "unknown"}), ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndUpdatePaymentRequests() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		paymentRequests := []entity.PaymentRequest{
+			{RequestId: "pr-1", MerchantCode: dummyMerchants[0].MerchantCode, CustomerUsername: dummyCustomers[0].Username, Amount: money.FromMinor(100000),
+				Currency: money.DefaultCurrency, Reference: "INV-1", Status: entity.PaymentRequestStatusPending, CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour)},
+			{RequestId: "pr-2", MerchantCode: dummyMerchants[0].MerchantCode, CustomerUsername: "otherUsername", Amount: money.FromMinor(200000),
+				Currency: money.DefaultCurrency, Reference: "INV-2", Status: entity.PaymentRequestStatusPending, CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour)},
+		}
+		for _, paymentRequest := range paymentRequests {
+			assert.Nil(suite.T(), storage.PaymentRequests().Insert(paymentRequest), driver)
+		}
+		assert.ErrorIs(suite.T(), storage.PaymentRequests().Insert(paymentRequests[0]), ErrDuplicate, driver)
+
+		found, err := storage.PaymentRequests().FindByCustomer(dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), paymentRequests[:1], found, driver)
+		found, err = storage.PaymentRequests().FindByMerchant(dummyMerchants[0].MerchantCode)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), paymentRequests, found, driver)
+
+		resolvedAt := createdAt.Add(time.Minute)
+		paymentRequests[0].Status = entity.PaymentRequestStatusDeclined
+		paymentRequests[0].ResolvedAt = &resolvedAt
+		assert.Nil(suite.T(), storage.PaymentRequests().Update(paymentRequests[0]), driver)
+		paymentRequest, err := storage.PaymentRequests().FindById("pr-1")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), paymentRequests[0], paymentRequest, driver)
+
+		_, err = storage.PaymentRequests().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+		assert.ErrorIs(suite.T(), storage.PaymentRequests().Update(entity.PaymentRequest{RequestId: "unknown"}), ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndUpdateHolds() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		holds := []entity.Hold{
+			{HoldId: "hold-1", CustomerUsername: dummyCustomers[0].Username, MerchantCode: dummyMerchants[0].MerchantCode, Amount: money.FromMinor(100000),
+				Currency: money.DefaultCurrency, Reference: "ROOM-1", Status: entity.HoldStatusAuthorized, CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour)},
+			{HoldId: "hold-2", CustomerUsername: dummyCustomers[0].Username, MerchantCode: dummyMerchants[0].MerchantCode, Amount: money.FromMinor(200000),
+				Currency: money.DefaultCurrency, Status: entity.HoldStatusAuthorized, CreatedAt: createdAt, ExpiresAt: createdAt.Add(2 * time.Hour)},
+		}
+		for _, hold := range holds {
+			assert.Nil(suite.T(), storage.Holds().Insert(hold), driver)
+		}
+		assert.ErrorIs(suite.T(), storage.Holds().Insert(holds[0]), ErrDuplicate, driver)
+
+		found, err := storage.Holds().FindByCustomer(dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), holds, found, driver)
+		found, err = storage.Holds().FindExpired(createdAt.Add(time.Hour))
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), holds[:1], found, driver)
+
+		resolvedAt := createdAt.Add(time.Minute)
+		holds[0].Status = entity.HoldStatusCaptured
+		holds[0].CapturedAmount = money.FromMinor(50000)
+		holds[0].TransactionId = "tx-1"
+		holds[0].ResolvedAt = &resolvedAt
+		assert.Nil(suite.T(), storage.Holds().Update(holds[0]), driver)
+		hold, err := storage.Holds().FindById("hold-1")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), holds[0], hold, driver)
+		found, err = storage.Holds().FindByMerchant(dummyMerchants[0].MerchantCode)
+		assert.Nil(suite.T(), err, driver)
+		assert.Len(suite.T(), found, 2, driver)
+		found, err = storage.Holds().FindExpired(createdAt.Add(3 * time.Hour))
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), holds[1:], found, driver)
+
+		_, err = storage.Holds().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndUpdateWebhooks() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		webhook := entity.Webhook{WebhookId: "wh-1", MerchantCode: dummyMerchants[0].MerchantCode, Url: "https://merchant.test/hook",
+			EventTypes: []string{entity.EventPaymentSucceeded, entity.EventRefundCreated}, EncryptedSigningKey: "key", CreatedAt: createdAt}
+		assert.Nil(suite.T(), storage.Webhooks().Insert(webhook), driver)
+		assert.ErrorIs(suite.T(), storage.Webhooks().Insert(webhook), ErrDuplicate, driver)
+		disabledAt := createdAt.Add(time.Hour)
+		webhook.DisabledAt = &disabledAt
+		assert.Nil(suite.T(), storage.Webhooks().Update(webhook), driver)
+		webhooks, err := storage.Webhooks().FindByMerchant(dummyMerchants[0].MerchantCode)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.Webhook{webhook}, webhooks, driver)
+
+		deliveries := []entity.WebhookDelivery{
+			{DeliveryId: "d-1", WebhookId: "wh-1", MerchantCode: dummyMerchants[0].MerchantCode, EventId: "ev-1", EventType: entity.EventPaymentSucceeded,
+				Payload: `{"type":"payment.succeeded"}`, Status: entity.DeliveryStatusPending, CreatedAt: createdAt, NextAttemptAt: createdAt.Add(time.Minute)},
+			{DeliveryId: "d-2", WebhookId: "wh-1", MerchantCode: dummyMerchants[0].MerchantCode, EventId: "ev-2", EventType: entity.EventRefundCreated,
+				Payload: `{"type":"refund.created"}`, Status: entity.DeliveryStatusPending, CreatedAt: createdAt, NextAttemptAt: createdAt},
+		}
+		for _, delivery := range deliveries {
+			assert.Nil(suite.T(), storage.WebhookDeliveries().Insert(delivery), driver)
+		}
+		due, err := storage.WebhookDeliveries().FindDue(createdAt.Add(time.Minute), 0)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.WebhookDelivery{deliveries[1], deliveries[0]}, due, driver)
+		due, err = storage.WebhookDeliveries().FindDue(createdAt.Add(time.Minute), 1)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), deliveries[1:], due, driver)
+
+		deliveries[1].Status = entity.DeliveryStatusDead
+		deliveries[1].Attempts = 3
+		deliveries[1].LastStatusCode = 500
+		deliveries[1].LastError = "unexpected status"
+		deliveries[1].LastAttemptAt = &disabledAt
+		assert.Nil(suite.T(), storage.WebhookDeliveries().Update(deliveries[1]), driver)
+		dead, err := storage.WebhookDeliveries().FindByMerchant(dummyMerchants[0].MerchantCode, entity.DeliveryStatusDead)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), deliveries[1:], dead, driver)
+		delivery, err := storage.WebhookDeliveries().FindById("d-1")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), deliveries[0], delivery, driver)
+
+		_, err = storage.WebhookDeliveries().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestAppendOutboxAndSaveCheckpoints() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		events := []entity.Event{
+			{EventId: "ev-1", Type: entity.EventPaymentSucceeded, MerchantCode: dummyMerchants[0].MerchantCode, CreatedAt: createdAt, Data: []byte(`{"amount":10}`)},
+			{EventId: "ev-2", Type: entity.EventRefundCreated, MerchantCode: dummyMerchants[0].MerchantCode, CreatedAt: createdAt, Data: []byte(`{"amount":5}`)},
+		}
+		var appended []entity.OutboxEvent
+		for _, event := range events {
+			outboxEvent, err := storage.Outbox().Append(event)
+			assert.Nil(suite.T(), err, driver)
+			appended = append(appended, outboxEvent)
+		}
+		assert.Less(suite.T(), appended[0].Sequence, appended[1].Sequence, driver)
+		_, err := storage.Outbox().Append(events[0])
+		assert.ErrorIs(suite.T(), err, ErrDuplicate, driver)
+
+		found, err := storage.Outbox().FindAfter(0, 0)
+		assert.Nil(suite.T(), err, driver)
+		suite.Require().Len(found, 2, driver)
+		for i := range found {
+			assert.Equal(suite.T(), appended[i].Sequence, found[i].Sequence, driver)
+			assert.Equal(suite.T(), events[i].EventId, found[i].EventId, driver)
+			assert.True(suite.T(), events[i].CreatedAt.Equal(found[i].CreatedAt), driver)
+			assert.JSONEq(suite.T(), string(events[i].Data), string(found[i].Data), driver)
+		}
+		found, err = storage.Outbox().FindAfter(appended[0].Sequence, 1)
+		assert.Nil(suite.T(), err, driver)
+		suite.Require().Len(found, 1, driver)
+		assert.Equal(suite.T(), "ev-2", found[0].EventId, driver)
+
+		_, err = storage.Checkpoints().Find("webhooks")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+		checkpoint := entity.ConsumerCheckpoint{Consumer: "webhooks", Sequence: appended[0].Sequence, UpdatedAt: createdAt}
+		assert.Nil(suite.T(), storage.Checkpoints().Save(checkpoint), driver)
+		checkpoint.Sequence = appended[1].Sequence
+		assert.Nil(suite.T(), storage.Checkpoints().Save(checkpoint), driver)
+		saved, err := storage.Checkpoints().Find("webhooks")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), checkpoint, saved, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndUpdateTopUps() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		topUps := []entity.TopUp{
+			{TopUpId: "topup-1", CustomerUsername: dummyCustomers[0].Username, Amount: money.FromMinor(100000), Currency: money.DefaultCurrency,
+				Gateway: "simulator", GatewayReference: "sim-1", Status: entity.TopUpStatusPending, CreatedAt: createdAt},
+			{TopUpId: "topup-2", CustomerUsername: dummyCustomers[0].Username, Amount: money.FromMinor(50000), Currency: money.DefaultCurrency,
+				Gateway: "simulator", Status: entity.TopUpStatusPending, CreatedAt: createdAt.Add(time.Hour)},
+		}
+		for _, topUp := range topUps {
+			assert.Nil(suite.T(), storage.TopUps().Insert(topUp), driver)
+		}
+		assert.ErrorIs(suite.T(), storage.TopUps().Insert(topUps[0]), ErrDuplicate, driver)
+
+		found, err := storage.TopUps().FindPending(createdAt.Add(time.Minute))
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), topUps[:1], found, driver)
+
+		resolvedAt := createdAt.Add(2 * time.Hour)
+		topUps[0].Status = entity.TopUpStatusSucceeded
+		topUps[0].TransactionId = "tx-1"
+		topUps[0].ResolvedAt = &resolvedAt
+		assert.Nil(suite.T(), storage.TopUps().Update(topUps[0]), driver)
+		topUp, err := storage.TopUps().FindById("topup-1")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), topUps[0], topUp, driver)
+		found, err = storage.TopUps().FindPending(createdAt.Add(3 * time.Hour))
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), topUps[1:], found, driver)
+		found, err = storage.TopUps().FindByCustomer(dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Len(suite.T(), found, 2, driver)
+
+		_, err = storage.TopUps().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndUpdateWithdrawals() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		bankAccount := entity.BankAccount{BankAccountId: "ba-1", OwnerType: entity.OwnerTypeCustomer, Owner: dummyCustomers[0].Username,
+			BankCode: "014", AccountNumber: "1234567890", AccountName: "Dummy Customer", CreatedAt: createdAt}
+		assert.Nil(suite.T(), storage.BankAccounts().Insert(bankAccount), driver)
+		duplicate := bankAccount
+		duplicate.BankAccountId = "ba-2"
+		assert.ErrorIs(suite.T(), storage.BankAccounts().Insert(duplicate), ErrDuplicate, driver)
+		bankAccounts, err := storage.BankAccounts().FindByOwner(entity.OwnerTypeCustomer, dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.BankAccount{bankAccount}, bankAccounts, driver)
+		bankAccounts, err = storage.BankAccounts().FindByOwner(entity.OwnerTypeMerchant, dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Empty(suite.T(), bankAccounts, driver)
+
+		withdrawals := []entity.Withdrawal{
+			{WithdrawalId: "wd-1", OwnerType: entity.OwnerTypeCustomer, Owner: dummyCustomers[0].Username, BankAccountId: "ba-1",
+				BankCode: "014", AccountNumber: "1234567890", AccountName: "Dummy Customer", Amount: money.FromMinor(100000),
+				Currency: money.DefaultCurrency, Status: entity.WithdrawalStatusPending, CreatedAt: createdAt},
+			{WithdrawalId: "wd-2", OwnerType: entity.OwnerTypeMerchant, Owner: "MRC125", BankAccountId: "ba-3",
+				BankCode: "008", AccountNumber: "1234567890123", AccountName: "Dummy Merchant", Amount: money.FromMinor(50000),
+				Currency: money.DefaultCurrency, Status: entity.WithdrawalStatusPending, CreatedAt: createdAt.Add(time.Hour)},
+		}
+		for _, withdrawal := range withdrawals {
+			assert.Nil(suite.T(), storage.Withdrawals().Insert(withdrawal), driver)
+		}
+		assert.ErrorIs(suite.T(), storage.Withdrawals().Insert(withdrawals[0]), ErrDuplicate, driver)
+		found, err := storage.Withdrawals().FindPending()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), withdrawals, found, driver)
+
+		batch := entity.PayoutBatch{BatchId: "batch-1", Currency: money.DefaultCurrency, Count: 1, Total: money.FromMinor(100000),
+			Status: entity.PayoutBatchStatusExported, CreatedAt: createdAt}
+		assert.Nil(suite.T(), storage.PayoutBatches().Insert(batch), driver)
+		assert.ErrorIs(suite.T(), storage.PayoutBatches().Insert(batch), ErrDuplicate, driver)
+		withdrawals[0].Status = entity.WithdrawalStatusBatched
+		withdrawals[0].BatchId = batch.BatchId
+		assert.Nil(suite.T(), storage.Withdrawals().Update(withdrawals[0]), driver)
+		found, err = storage.Withdrawals().FindPending()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), withdrawals[1:], found, driver)
+		found, err = storage.Withdrawals().FindByBatch(batch.BatchId)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), withdrawals[:1], found, driver)
+		found, err = storage.Withdrawals().FindByOwner(entity.OwnerTypeMerchant, "MRC125")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), withdrawals[1:], found, driver)
+
+		settledAt := createdAt.Add(2 * time.Hour)
+		batch.Status = entity.PayoutBatchStatusSettled
+		batch.PaidCount = 1
+		batch.SettledAt = &settledAt
+		assert.Nil(suite.T(), storage.PayoutBatches().Update(batch), driver)
+		batches, err := storage.PayoutBatches().FindAll()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.PayoutBatch{batch}, batches, driver)
+
+		_, err = storage.Withdrawals().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+		_, err = storage.PayoutBatches().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+		_, err = storage.BankAccounts().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndUpdateFeeRules() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		upTo := money.FromMinor(10000000)
+		rules := []entity.FeeRule{
+			{RuleId: "rule-1", MerchantCode: dummyMerchants[0].MerchantCode, Type: entity.FeeTypePercentage, PercentageBps: 150,
+				MinFee: money.FromMinor(100000), MaxFee: money.FromMinor(500000), ChargedTo: entity.FeeChargedToMerchant, CreatedAt: createdAt},
+			{RuleId: "rule-2", Category: "food", Type: entity.FeeTypeTiered, Tiers: []entity.FeeTier{
+				{UpTo: &upTo, FixedAmount: money.FromMinor(100000)},
+				{PercentageBps: 100},
+			}, ChargedTo: entity.FeeChargedToCustomer, CreatedAt: createdAt.Add(time.Hour)},
+		}
+		for _, rule := range rules {
+			assert.Nil(suite.T(), storage.FeeRules().Insert(rule), driver)
+		}
+		assert.ErrorIs(suite.T(), storage.FeeRules().Insert(rules[0]), ErrDuplicate, driver)
+		found, err := storage.FeeRules().FindActive()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), rules, found, driver)
+
+		disabledAt := createdAt.Add(2 * time.Hour)
+		rules[0].DisabledAt = &disabledAt
+		assert.Nil(suite.T(), storage.FeeRules().Update(rules[0]), driver)
+		found, err = storage.FeeRules().FindActive()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), rules[1:], found, driver)
+		found, err = storage.FeeRules().FindAll()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), rules, found, driver)
+		rule, err := storage.FeeRules().FindById("rule-2")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), rules[1], rule, driver)
+		_, err = storage.FeeRules().FindById("unknown")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+
+		merchant := dummyMerchants[0]
+		merchant.Category = "food"
+		assert.Nil(suite.T(), storage.Merchants().Update(merchant), driver)
+		merchant, err = storage.Merchants().FindByCode(merchant.MerchantCode)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), "food", merchant.Category, driver)
+
+		history := entity.History{TransactionId: "fee-history", Type: entity.HistoryTypePayment, CustomerUsername: dummyCustomers[0].Username,
+			MerchantCode: merchant.MerchantCode, Amount: money.FromMinor(2000000), Fee: money.FromMinor(30000),
+			FeeChargedTo: entity.FeeChargedToCustomer, Currency: money.DefaultCurrency, Date: createdAt}
+		assert.Nil(suite.T(), storage.Histories().Insert(history), driver)
+		history, err = storage.Histories().FindById("fee-history")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), money.FromMinor(30000), history.Fee, driver)
+		assert.Equal(suite.T(), entity.FeeChargedToCustomer, history.FeeChargedTo, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestWalletsAndFxRates() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		createdAt := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		wallets := []entity.Wallet{
+			{Username: dummyCustomers[0].Username, Currency: "USD", CreatedAt: createdAt},
+			{Username: dummyCustomers[0].Username, Currency: "EUR", CreatedAt: createdAt.Add(time.Hour)},
+		}
+		for _, wallet := range wallets {
+			assert.Nil(suite.T(), storage.Wallets().Insert(wallet), driver)
+		}
+		assert.ErrorIs(suite.T(), storage.Wallets().Insert(wallets[0]), ErrDuplicate, driver)
+		wallets[0].Balance = money.FromMinor(1050)
+		assert.Nil(suite.T(), storage.Wallets().Update(wallets[0]), driver)
+		wallet, err := storage.Wallets().Find(dummyCustomers[0].Username, "USD")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), wallets[0], wallet, driver)
+		found, err := storage.Wallets().FindByCustomer(dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), wallets, found, driver)
+		_, err = storage.Wallets().Find(dummyCustomers[0].Username, "JPY")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+		assert.ErrorIs(suite.T(), storage.Wallets().Update(entity.Wallet{Username: "unknown", Currency: "USD"}), ErrNotFound, driver)
+
+		rate := entity.FxRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15650.25", SpreadBps: 50, RateAt: createdAt, Source: entity.FxRateSourceFile}
+		assert.Nil(suite.T(), storage.FxRates().Save(rate), driver)
+		rate.Rate = "15700"
+		rate.Source = entity.FxRateSourceAdmin
+		assert.Nil(suite.T(), storage.FxRates().Save(rate), driver)
+		savedRate, err := storage.FxRates().Find("USD", "IDR")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), rate, savedRate, driver)
+		rates, err := storage.FxRates().FindAll()
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.FxRate{rate}, rates, driver)
+		_, err = storage.FxRates().Find("IDR", "USD")
+		assert.ErrorIs(suite.T(), err, ErrNotFound, driver)
+
+		history := entity.History{TransactionId: "fx-history", Type: entity.HistoryTypePayment, CustomerUsername: dummyCustomers[0].Username,
+			MerchantCode: dummyMerchants[0].MerchantCode, Amount: money.FromMinor(1000), Currency: "USD",
+			SettlementAmount: money.FromMinor(15700000), SettlementCurrency: money.DefaultCurrency, FxRate: rate.Rate,
+			FxSpreadBps: rate.SpreadBps, FxRateAt: &createdAt, Date: createdAt}
+		assert.Nil(suite.T(), storage.Histories().Insert(history), driver)
+		stored, err := storage.Histories().FindById("fx-history")
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), history, stored, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestInsertAndFindHistory() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		base := time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)
+		for i := 1; i <= 3; i++ {
+			err := storage.Histories().Insert(entity.History{
+				TransactionId:    "tx-" + strconv.Itoa(i),
+				CustomerUsername: dummyCustomers[0].Username,
+				MerchantCode:     dummyMerchants[0].MerchantCode,
+				Amount:           money.FromMinor(int64(i * 100000)),
+				Currency:         money.DefaultCurrency,
+				Reference:        "INV-" + strconv.Itoa(i),
+				Date:             base.Add(time.Duration(i) * time.Hour),
+			})
+			assert.Nil(suite.T(), err, driver)
+		}
+
+		histories, err := storage.Histories().Find(HistoryFilter{
+			CustomerUsername: dummyCustomers[0].Username,
+			From:             base,
+			Descending:       true,
+			Limit:            2,
+		})
+		assert.Nil(suite.T(), err, driver)
+		assert.Len(suite.T(), histories, 2, driver)
+		assert.Equal(suite.T(), "tx-3", histories[0].TransactionId, driver)
+		assert.Equal(suite.T(), "tx-2", histories[1].TransactionId, driver)
+		assert.Equal(suite.T(), "INV-3", histories[0].Reference, driver)
+
+		histories, err = storage.Histories().Find(HistoryFilter{
+			CustomerUsername: dummyCustomers[0].Username,
+			From:             base,
+			Descending:       true,
+			After:            &HistoryCursor{Date: histories[1].Date, TransactionId: histories[1].TransactionId},
+		})
+		assert.Nil(suite.T(), err, driver)
+		assert.Len(suite.T(), histories, 1, driver)
+		assert.Equal(suite.T(), "tx-1", histories[0].TransactionId, driver)
+
+		minAmount := money.MustParse("1500")
+		histories, err = storage.Histories().Find(HistoryFilter{
+			CustomerUsername: dummyCustomers[0].Username,
+			MinAmount:        &minAmount,
+			From:             base,
+			To:               base.Add(2 * time.Hour),
+		})
+		assert.Nil(suite.T(), err, driver)
+		assert.Len(suite.T(), histories, 1, driver)
+		assert.Equal(suite.T(), "tx-2", histories[0].TransactionId, driver)
+
+		transfer := entity.History{
+			TransactionId:     "tx-transfer",
+			Type:              entity.HistoryTypeTransfer,
+			CustomerUsername:  dummyCustomers[0].Username,
+			RecipientUsername: "dummyRecipient",
+			Amount:            money.FromMinor(5000),
+			Currency:          money.DefaultCurrency,
+			Note:              "dinner",
+			Date:              base.Add(4 * time.Hour),
+		}
+		assert.Nil(suite.T(), storage.Histories().Insert(transfer), driver)
+		histories, err = storage.Histories().Find(HistoryFilter{CustomerUsername: "dummyRecipient"})
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.History{transfer}, histories, driver)
+		histories, err = storage.Histories().Find(HistoryFilter{CustomerUsername: dummyCustomers[0].Username, From: base, Descending: true, Limit: 1})
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), []entity.History{transfer}, histories, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestAtomic_Rollback() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		err := storage.Atomic(func(tx Storage) error {
+			customer, err := tx.Customers().FindByUsername(dummyCustomers[0].Username)
+			suite.Require().NoError(err)
+			customer.Balance = 0
+			suite.Require().NoError(tx.Customers().Update(customer))
+			suite.Require().NoError(tx.Histories().Insert(entity.History{TransactionId: "rollback"}))
+			return errors.New("Failed")
+		})
+		assert.NotNil(suite.T(), err, driver)
+
+		customer, err := storage.Customers().FindByUsername(dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), dummyCustomers[0].Balance, customer.Balance, driver)
+		histories, err := storage.Histories().Find(HistoryFilter{})
+		assert.Nil(suite.T(), err, driver)
+		assert.Len(suite.T(), histories, len(dummyHistories), driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestAtomic_Concurrent() {
+	for _, driver := range suite.drivers() {
+		storage := suite.open(driver)
+		workers := 50
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := storage.Atomic(func(tx Storage) error {
+					customer, err := tx.Customers().FindByUsername(dummyCustomers[0].Username)
+					if err != nil {
+						return err
+					}
+					customer.Balance -= money.MustParse("0.01")
+					return tx.Customers().Update(customer)
+				})
+				assert.Nil(suite.T(), err, driver)
+			}()
+		}
+		wg.Wait()
+
+		customer, err := storage.Customers().FindByUsername(dummyCustomers[0].Username)
+		assert.Nil(suite.T(), err, driver)
+		assert.Equal(suite.T(), dummyCustomers[0].Balance-money.FromMinor(int64(workers)), customer.Balance, driver)
+	}
+}
+
+func (suite *StorageTestSuite) TestJsonStorage_ReplaysJournalOnStartup() {
+	customers := []entity.Customer{dummyCustomers[0]}
+	customers[0].Balance = money.MustParse("1")
+	data, err := json.Marshal(customers)
+	suite.Require().NoError(err)
+	journalFile := filepath.Join(filepath.Dir(suite.config.Customer), jsonJournalFileName)
+	suite.Require().NoError(writeJournal(journalFile, []journalEntry{{FileName: suite.config.Customer, Data: data}}))
+
+	storage, err := NewJsonStorage(suite.config.JsonFileConfig)
+	suite.Require().NoError(err)
+	customer, err := storage.Customers().FindByUsername(dummyCustomers[0].Username)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.MustParse("1"), customer.Balance)
+	assert.NoFileExists(suite.T(), journalFile)
+}
+
+func (suite *StorageTestSuite) TestJsonStorage_MigratesLegacyMoney() {
+	legacy := `[{"uuid":"Dummy Uuid","username":"dummyUsername","password":"dummyPassword","balance":476723.77}]`
+	suite.Require().NoError(os.WriteFile(suite.config.Customer, []byte(legacy), 0644))
+
+	storage, err := NewJsonStorage(suite.config.JsonFileConfig)
+	suite.Require().NoError(err)
+	customer, err := storage.Customers().FindByUsername("dummyUsername")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.FromMinor(47672377), customer.Balance)
+	assert.Equal(suite.T(), money.DefaultCurrency, customer.Currency)
+
+	data, err := os.ReadFile(suite.config.Customer)
+	suite.Require().NoError(err)
+	assert.Contains(suite.T(), string(data), `"balance": 476723.77`)
+	assert.Contains(suite.T(), string(data), `"currency": "IDR"`)
+}
+
+func (suite *StorageTestSuite) TestSqliteStorage_MigratesLegacyMoney() {
+	db, err := sql.Open("sqlite", suite.config.SqliteFile)
+	suite.Require().NoError(err)
+	_, err = db.Exec(sqliteMigrations[0])
+	suite.Require().NoError(err)
+	_, err = db.Exec(`INSERT INTO customers (uuid, username, password, balance) VALUES ('Dummy Uuid', 'dummyUsername', 'dummyPassword', 476723.77)`)
+	suite.Require().NoError(err)
+	_, err = db.Exec(`INSERT INTO histories (transaction_id, customer_username, merchant_code, amount, date) VALUES ('tx', 'dummyUsername', 'MRC', 0.29, '2023-06-27T00:00:00.000000000Z')`)
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.Close())
+
+	storage, err := NewSqliteStorage(suite.config.SqliteFile, suite.config.JsonFileConfig)
+	suite.Require().NoError(err)
+	defer storage.Close()
+	customer, err := storage.Customers().FindByUsername("dummyUsername")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), money.FromMinor(47672377), customer.Balance)
+	assert.Equal(suite.T(), money.DefaultCurrency, customer.Currency)
+	histories, err := storage.Histories().Find(HistoryFilter{CustomerUsername: "dummyUsername"})
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), histories, 1)
+	assert.Equal(suite.T(), money.FromMinor(29), histories[0].Amount)
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	suite.Run(t, new(StorageTestSuite))
+}
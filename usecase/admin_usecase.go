@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/loginguard"
+)
+
+type AdminUsecase interface {
+	UnlockLogin(request req.UnlockRequest) error
+	SetRole(request req.RoleRequest) error
+	Reconcile() (res.LedgerReconciliation, error)
+}
+
+type adminUsecase struct {
+	loginGuard       loginguard.LoginGuard
+	roleRepository   repository.RoleRepository
+	ledgerRepository repository.LedgerRepository
+}
+
+func (a *adminUsecase) UnlockLogin(request req.UnlockRequest) error {
+	if request.Username == "" && request.Ip == "" {
+		return app_error.InvalidError("username or ip is required")
+	}
+	return a.loginGuard.Unlock(request.Username, request.Ip)
+}
+
+func (a *adminUsecase) SetRole(request req.RoleRequest) error {
+	if request.Username == "" {
+		return app_error.InvalidError("username is required")
+	}
+	if !authenticator.ValidRole(request.Role) {
+		return app_error.InvalidError("invalid role")
+	}
+	return a.roleRepository.SetRole(request.Username, request.Role)
+}
+
+func (a *adminUsecase) Reconcile() (res.LedgerReconciliation, error) {
+	report, err := a.ledgerRepository.Check()
+	if err != nil {
+		return res.LedgerReconciliation{}, err
+	}
+	reconciliation := res.LedgerReconciliation{
+		Balanced:   report.Balanced(),
+		Totals:     report.Totals,
+		Mismatches: []res.LedgerMismatch{},
+	}
+	for _, mismatch := range report.Mismatches {
+		reconciliation.Mismatches = append(reconciliation.Mismatches, res.LedgerMismatch{
+			Account:  mismatch.Account,
+			Currency: mismatch.Currency,
+			Cached:   mismatch.Cached,
+			Derived:  mismatch.Derived,
+		})
+	}
+	return reconciliation, nil
+}
+
+func NewAdminUsecase(loginGuard loginguard.LoginGuard, roleRepository repository.RoleRepository, ledgerRepository repository.LedgerRepository) AdminUsecase {
+	return &adminUsecase{
+		loginGuard:       loginGuard,
+		roleRepository:   roleRepository,
+		ledgerRepository: ledgerRepository,
+	}
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/ledger"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type roleRepoMock struct {
+	mock.Mock
+}
+
+func (r *roleRepoMock) SetRole(username string, role string) error {
+	args := r.Called(username, role)
+	return args.Error(0)
+}
+
+type ledgerRepoMock struct {
+	mock.Mock
+}
+
+func (l *ledgerRepoMock) Check() (ledger.Report, error) {
+	args := l.Called()
+	return args.Get(0).(ledger.Report), args.Error(1)
+}
+
+type AdminUsecaseTestSuite struct {
+	loginGuardMock *loginGuardMock
+	roleRepoMock   *roleRepoMock
+	ledgerRepoMock *ledgerRepoMock
+	suite.Suite
+}
+
+func (suite *AdminUsecaseTestSuite) TestUnlockLogin_Success() {
+	suite.loginGuardMock.On("Unlock", "dummyUsername", "").Return(nil)
+	err := NewAdminUsecase(suite.loginGuardMock, suite.roleRepoMock, suite.ledgerRepoMock).UnlockLogin(req.UnlockRequest{Username: "dummyUsername"})
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *AdminUsecaseTestSuite) TestUnlockLogin_FailedEmptyRequest() {
+	err := NewAdminUsecase(suite.loginGuardMock, suite.roleRepoMock, suite.ledgerRepoMock).UnlockLogin(req.UnlockRequest{})
+	assert.NotNil(suite.T(), err)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Unlock", mock.Anything, mock.Anything)
+}
+
+func (suite *AdminUsecaseTestSuite) TestSetRole_Success() {
+	suite.roleRepoMock.On("SetRole", "dummyUsername", "merchant").Return(nil)
+	err := NewAdminUsecase(suite.loginGuardMock, suite.roleRepoMock, suite.ledgerRepoMock).SetRole(req.RoleRequest{Username: "dummyUsername", Role: "merchant"})
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *AdminUsecaseTestSuite) TestSetRole_FailedInvalidRequest() {
+	requests := []req.RoleRequest{
+		{Role: "admin"},
+		{Username: "dummyUsername", Role: "superuser"},
+		{Username: "dummyUsername"},
+	}
+	for _, request := range requests {
+		assert.NotNil(suite.T(), NewAdminUsecase(suite.loginGuardMock, suite.roleRepoMock, suite.ledgerRepoMock).SetRole(request))
+	}
+	suite.roleRepoMock.AssertNotCalled(suite.T(), "SetRole", mock.Anything, mock.Anything)
+}
+
+func (suite *AdminUsecaseTestSuite) TestSetRole_FailedRepository() {
+	suite.roleRepoMock.On("SetRole", "dummyUsername", "admin").Return(errors.New("Failed"))
+	err := NewAdminUsecase(suite.loginGuardMock, suite.roleRepoMock, suite.ledgerRepoMock).SetRole(req.RoleRequest{Username: "dummyUsername", Role: "admin"})
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *AdminUsecaseTestSuite) TestReconcile_Imbalanced() {
+	suite.ledgerRepoMock.On("Check").Return(ledger.Report{
+		Totals: map[string]money.Amount{"IDR": money.MustParse("10")},
+		Mismatches: []ledger.Mismatch{
+			{Account: "merchant:MRC125", Currency: "IDR", Cached: money.MustParse("60"), Derived: money.MustParse("50")},
+		},
+	}, nil)
+	reconciliation, err := NewAdminUsecase(suite.loginGuardMock, suite.roleRepoMock, suite.ledgerRepoMock).Reconcile()
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), reconciliation.Balanced)
+	assert.Equal(suite.T(), money.MustParse("10"), reconciliation.Totals["IDR"])
+	suite.Require().Len(reconciliation.Mismatches, 1)
+	assert.Equal(suite.T(), "merchant:MRC125", reconciliation.Mismatches[0].Account)
+	assert.Equal(suite.T(), money.MustParse("50"), reconciliation.Mismatches[0].Derived)
+}
+
+func (suite *AdminUsecaseTestSuite) TestReconcile_Balanced() {
+	suite.ledgerRepoMock.On("Check").Return(ledger.Report{Totals: map[string]money.Amount{"IDR": 0}}, nil)
+	reconciliation, err := NewAdminUsecase(suite.loginGuardMock, suite.roleRepoMock, suite.ledgerRepoMock).Reconcile()
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), reconciliation.Balanced)
+	assert.Empty(suite.T(), reconciliation.Mismatches)
+}
+
+func (suite *AdminUsecaseTestSuite) SetupTest() {
+	suite.loginGuardMock = new(loginGuardMock)
+	suite.roleRepoMock = new(roleRepoMock)
+	suite.ledgerRepoMock = new(ledgerRepoMock)
+}
+
+func TestAdminUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminUsecaseTestSuite))
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/fee"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/google/uuid"
+)
+
+const MaxMerchantCategoryLength = 50
+
+type FeeUsecase interface {
+	SaveRule(request req.FeeRuleRequest) (entity.FeeRule, error)
+	ListRules() ([]entity.FeeRule, error)
+	DisableRule(ruleId string) (entity.FeeRule, error)
+	SetCategory(request req.MerchantCategoryRequest) (entity.Merchant, error)
+	Quote(query req.FeeQuoteQuery) (res.FeeQuote, error)
+}
+
+type feeUsecase struct {
+	feeRepository repository.FeeRepository
+}
+
+func (f *feeUsecase) SaveRule(request req.FeeRuleRequest) (entity.FeeRule, error) {
+	rule := entity.FeeRule{
+		RuleId:        uuid.New().String(),
+		MerchantCode:  strings.TrimSpace(request.MerchantCode),
+		Category:      normalizeCategory(request.Category),
+		Currency:      request.Currency,
+		Type:          request.Type,
+		FixedAmount:   request.FixedAmount,
+		PercentageBps: request.PercentageBps,
+		MinFee:        request.MinFee,
+		MaxFee:        request.MaxFee,
+		ChargedTo:     request.ChargedTo,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if rule.ChargedTo == "" {
+		rule.ChargedTo = entity.FeeChargedToCustomer
+	}
+	if rule.Currency == "" {
+		rule.Currency = money.DefaultCurrency
+	}
+	if money.ValidateCurrency(rule.Currency) != nil {
+		return entity.FeeRule{}, app_error.InvalidError("invalid currency")
+	}
+	for _, tier := range request.Tiers {
+		rule.Tiers = append(rule.Tiers, entity.FeeTier{
+			UpTo:          tier.UpTo,
+			FixedAmount:   tier.FixedAmount,
+			PercentageBps: tier.PercentageBps,
+		})
+	}
+	if len(rule.Category) > MaxMerchantCategoryLength {
+		return entity.FeeRule{}, app_error.InvalidError("category is too long")
+	}
+	if err := fee.Validate(rule); err != nil {
+		return entity.FeeRule{}, app_error.InvalidError(err.Error())
+	}
+	return f.feeRepository.SaveRule(rule)
+}
+
+func (f *feeUsecase) ListRules() ([]entity.FeeRule, error) {
+	return f.feeRepository.FindRules()
+}
+
+func (f *feeUsecase) DisableRule(ruleId string) (entity.FeeRule, error) {
+	return f.feeRepository.DisableRule(ruleId)
+}
+
+func (f *feeUsecase) SetCategory(request req.MerchantCategoryRequest) (entity.Merchant, error) {
+	category := normalizeCategory(request.Category)
+	if len(category) > MaxMerchantCategoryLength {
+		return entity.Merchant{}, app_error.InvalidError("category is too long")
+	}
+	return f.feeRepository.SetCategory(request.MerchantCode, category)
+}
+
+func (f *feeUsecase) Quote(query req.FeeQuoteQuery) (res.FeeQuote, error) {
+	if query.MerchantCode == "" {
+		return res.FeeQuote{}, app_error.InvalidError("merchant code is required")
+	}
+	amount, err := money.Parse(query.Amount)
+	if err != nil || amount < 0 {
+		return res.FeeQuote{}, app_error.InvalidError("invalid amount")
+	}
+	if query.Currency != "" && money.ValidateCurrency(query.Currency) != nil {
+		return res.FeeQuote{}, app_error.InvalidError("invalid currency")
+	}
+	return f.feeRepository.Quote(query.MerchantCode, query.Currency, amount)
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+func NewFeeUsecase(feeRepository repository.FeeRepository) FeeUsecase {
+	return &feeUsecase{
+		feeRepository: feeRepository,
+	}
+}
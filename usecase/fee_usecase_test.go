@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type feeRepoMock struct {
+	mock.Mock
+}
+
+func (f *feeRepoMock) SaveRule(rule entity.FeeRule) (entity.FeeRule, error) {
+	args := f.Called(rule)
+	return args.Get(0).(entity.FeeRule), args.Error(1)
+}
+
+func (f *feeRepoMock) FindRules() ([]entity.FeeRule, error) {
+	args := f.Called()
+	return args.Get(0).([]entity.FeeRule), args.Error(1)
+}
+
+func (f *feeRepoMock) DisableRule(ruleId string) (entity.FeeRule, error) {
+	args := f.Called(ruleId)
+	return args.Get(0).(entity.FeeRule), args.Error(1)
+}
+
+func (f *feeRepoMock) SetCategory(merchantCode string, category string) (entity.Merchant, error) {
+	args := f.Called(merchantCode, category)
+	return args.Get(0).(entity.Merchant), args.Error(1)
+}
+
+func (f *feeRepoMock) Quote(merchantCode string, currency string, amount money.Amount) (res.FeeQuote, error) {
+	args := f.Called(merchantCode, currency, amount)
+	return args.Get(0).(res.FeeQuote), args.Error(1)
+}
+
+type FeeUsecaseTestSuite struct {
+	suite.Suite
+	feeRepoMock *feeRepoMock
+}
+
+func (suite *FeeUsecaseTestSuite) TestSaveRule_Success() {
+	upTo := money.MustParse("100000")
+	suite.feeRepoMock.On("SaveRule", mock.MatchedBy(func(rule entity.FeeRule) bool {
+		return rule.RuleId != "" && rule.Category == "food" && rule.ChargedTo == entity.FeeChargedToCustomer &&
+			len(rule.Tiers) == 2 && *rule.Tiers[0].UpTo == upTo && rule.Tiers[1].PercentageBps == 100 && !rule.CreatedAt.IsZero()
+	})).Return(entity.FeeRule{RuleId: "Dummy Rule Id"}, nil)
+
+	rule, err := NewFeeUsecase(suite.feeRepoMock).SaveRule(req.FeeRuleRequest{
+		Category: " Food ",
+		Type:     entity.FeeTypeTiered,
+		Tiers: []req.FeeTierRequest{
+			{UpTo: &upTo, FixedAmount: money.MustParse("1000")},
+			{PercentageBps: 100},
+		},
+	})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "Dummy Rule Id", rule.RuleId)
+}
+
+func (suite *FeeUsecaseTestSuite) TestSaveRule_FailedValidation() {
+	requests := []req.FeeRuleRequest{
+		{Type: entity.FeeTypeFixed},
+		{MerchantCode: "MRC125", Category: "food", Type: entity.FeeTypeFixed},
+		{MerchantCode: "MRC125", Type: entity.FeeTypePercentage, PercentageBps: 20000},
+		{MerchantCode: "MRC125", Type: entity.FeeTypeFixed, ChargedTo: "bank"},
+		{Category: "a very long category name that goes on and on and on", Type: entity.FeeTypeFixed},
+	}
+	for _, request := range requests {
+		_, err := NewFeeUsecase(suite.feeRepoMock).SaveRule(request)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.feeRepoMock.AssertNotCalled(suite.T(), "SaveRule", mock.Anything)
+}
+
+func (suite *FeeUsecaseTestSuite) TestSetCategory_Success() {
+	suite.feeRepoMock.On("SetCategory", "MRC125", "food").Return(entity.Merchant{MerchantCode: "MRC125", Category: "food"}, nil)
+
+	merchant, err := NewFeeUsecase(suite.feeRepoMock).SetCategory(req.MerchantCategoryRequest{MerchantCode: "MRC125", Category: "Food"})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "food", merchant.Category)
+}
+
+func (suite *FeeUsecaseTestSuite) TestQuote_Success() {
+	quote := res.FeeQuote{MerchantCode: "MRC125", Fee: money.MustParse("500")}
+	suite.feeRepoMock.On("Quote", "MRC125", "", money.MustParse("20000")).Return(quote, nil)
+
+	result, err := NewFeeUsecase(suite.feeRepoMock).Quote(req.FeeQuoteQuery{MerchantCode: "MRC125", Amount: "20000"})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), quote, result)
+}
+
+func (suite *FeeUsecaseTestSuite) TestQuote_FailedValidation() {
+	queries := []req.FeeQuoteQuery{
+		{Amount: "20000"},
+		{MerchantCode: "MRC125"},
+		{MerchantCode: "MRC125", Amount: "abc"},
+		{MerchantCode: "MRC125", Amount: "-1"},
+		{MerchantCode: "MRC125", Amount: "10", Currency: "usd"},
+	}
+	for _, query := range queries {
+		_, err := NewFeeUsecase(suite.feeRepoMock).Quote(query)
+		assert.NotNil(suite.T(), err)
+	}
+
+	suite.feeRepoMock.AssertNotCalled(suite.T(), "Quote", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *FeeUsecaseTestSuite) SetupTest() {
+	suite.feeRepoMock = new(feeRepoMock)
+}
+
+func TestFeeUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(FeeUsecaseTestSuite))
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"os"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/fx"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+type FxUsecase interface {
+	SaveRate(request req.FxRateRequest) (entity.FxRate, error)
+	ListRates() ([]entity.FxRate, error)
+	LoadFile(fileName string) (int, error)
+	SetSettlementCurrency(request req.SettlementCurrencyRequest) (entity.Merchant, error)
+}
+
+type fxUsecase struct {
+	fxRepository repository.FxRepository
+}
+
+func (f *fxUsecase) SaveRate(request req.FxRateRequest) (entity.FxRate, error) {
+	now := time.Now().UTC()
+	rate := entity.FxRate{
+		BaseCurrency:  request.BaseCurrency,
+		QuoteCurrency: request.QuoteCurrency,
+		Rate:          request.Rate,
+		SpreadBps:     request.SpreadBps,
+		RateAt:        now,
+		Source:        entity.FxRateSourceAdmin,
+	}
+	if request.RateAt != nil {
+		if request.RateAt.After(now) {
+			return entity.FxRate{}, app_error.InvalidError("rate_at must not be in the future")
+		}
+		rate.RateAt = request.RateAt.UTC()
+	}
+	if err := fx.Validate(rate); err != nil {
+		return entity.FxRate{}, app_error.InvalidError(err.Error())
+	}
+	if err := f.fxRepository.SaveRates([]entity.FxRate{rate}); err != nil {
+		return entity.FxRate{}, err
+	}
+	return rate, nil
+}
+
+func (f *fxUsecase) ListRates() ([]entity.FxRate, error) {
+	return f.fxRepository.FindRates()
+}
+
+func (f *fxUsecase) LoadFile(fileName string) (int, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return 0, app_error.InternalServerError("Failed to open fx rates file: " + err.Error())
+	}
+	defer file.Close()
+
+	rates, err := fx.ReadRates(file)
+	if err != nil {
+		return 0, app_error.InternalServerError("Failed to read fx rates file: " + err.Error())
+	}
+	now := time.Now().UTC()
+	for i := range rates {
+		rates[i].Source = entity.FxRateSourceFile
+		if rates[i].RateAt.IsZero() {
+			rates[i].RateAt = now
+		}
+	}
+	if err = f.fxRepository.SaveRates(rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+func (f *fxUsecase) SetSettlementCurrency(request req.SettlementCurrencyRequest) (entity.Merchant, error) {
+	if money.ValidateCurrency(request.Currency) != nil {
+		return entity.Merchant{}, app_error.InvalidError("invalid currency")
+	}
+	return f.fxRepository.SetSettlementCurrency(request.MerchantCode, request.Currency)
+}
+
+func NewFxUsecase(fxRepository repository.FxRepository) FxUsecase {
+	return &fxUsecase{
+		fxRepository: fxRepository,
+	}
+}
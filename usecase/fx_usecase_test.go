@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type fxRepoMock struct {
+	mock.Mock
+}
+
+func (f *fxRepoMock) SaveRates(rates []entity.FxRate) error {
+	args := f.Called(rates)
+	return args.Error(0)
+}
+
+func (f *fxRepoMock) FindRates() ([]entity.FxRate, error) {
+	args := f.Called()
+	return args.Get(0).([]entity.FxRate), args.Error(1)
+}
+
+func (f *fxRepoMock) SetSettlementCurrency(merchantCode string, currency string) (entity.Merchant, error) {
+	args := f.Called(merchantCode, currency)
+	return args.Get(0).(entity.Merchant), args.Error(1)
+}
+
+type FxUsecaseTestSuite struct {
+	suite.Suite
+	fxRepoMock *fxRepoMock
+}
+
+func (suite *FxUsecaseTestSuite) TestSaveRate_Success() {
+	suite.fxRepoMock.On("SaveRates", mock.MatchedBy(func(rates []entity.FxRate) bool {
+		return len(rates) == 1 && rates[0].Rate == "15650.25" && rates[0].Source == entity.FxRateSourceAdmin && !rates[0].RateAt.IsZero()
+	})).Return(nil)
+
+	rate, err := NewFxUsecase(suite.fxRepoMock).SaveRate(req.FxRateRequest{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "15650.25", SpreadBps: 50})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(50), rate.SpreadBps)
+}
+
+func (suite *FxUsecaseTestSuite) TestSaveRate_FailedValidation() {
+	future := time.Now().Add(time.Hour)
+	requests := []req.FxRateRequest{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "abc"},
+		{BaseCurrency: "USD", QuoteCurrency: "USD", Rate: "1"},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "1", SpreadBps: -1},
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "1", RateAt: &future},
+	}
+	for _, request := range requests {
+		_, err := NewFxUsecase(suite.fxRepoMock).SaveRate(request)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.fxRepoMock.AssertNotCalled(suite.T(), "SaveRates", mock.Anything)
+}
+
+func (suite *FxUsecaseTestSuite) TestLoadFile() {
+	fileName := filepath.Join(suite.T().TempDir(), "fx_rates.json")
+	suite.Require().NoError(os.WriteFile(fileName, []byte(`[
+		{"base_currency":"USD","quote_currency":"IDR","rate":"15650.25","spread_bps":50},
+		{"base_currency":"IDR","quote_currency":"USD","rate":"0.0000639","rate_at":"2026-10-01T08:00:00Z"}
+	]`), 0644))
+	suite.fxRepoMock.On("SaveRates", mock.MatchedBy(func(rates []entity.FxRate) bool {
+		return len(rates) == 2 && rates[0].Source == entity.FxRateSourceFile && !rates[0].RateAt.IsZero() &&
+			rates[1].RateAt.Equal(time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC))
+	})).Return(nil)
+
+	loaded, err := NewFxUsecase(suite.fxRepoMock).LoadFile(fileName)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 2, loaded)
+}
+
+func (suite *FxUsecaseTestSuite) TestLoadFile_Failed() {
+	fileName := filepath.Join(suite.T().TempDir(), "fx_rates.json")
+	_, err := NewFxUsecase(suite.fxRepoMock).LoadFile(fileName)
+	assert.NotNil(suite.T(), err)
+
+	suite.Require().NoError(os.WriteFile(fileName, []byte(`[{"base_currency":"USD","quote_currency":"IDR","rate":"-1"}]`), 0644))
+	_, err = NewFxUsecase(suite.fxRepoMock).LoadFile(fileName)
+	assert.NotNil(suite.T(), err)
+	suite.fxRepoMock.AssertNotCalled(suite.T(), "SaveRates", mock.Anything)
+}
+
+func (suite *FxUsecaseTestSuite) TestSetSettlementCurrency() {
+	suite.fxRepoMock.On("SetSettlementCurrency", "MRC125", "USD").Return(entity.Merchant{MerchantCode: "MRC125", Currency: "USD"}, nil)
+
+	merchant, err := NewFxUsecase(suite.fxRepoMock).SetSettlementCurrency(req.SettlementCurrencyRequest{MerchantCode: "MRC125", Currency: "USD"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "USD", merchant.Currency)
+
+	_, err = NewFxUsecase(suite.fxRepoMock).SetSettlementCurrency(req.SettlementCurrencyRequest{MerchantCode: "MRC125", Currency: "dollar"})
+	assert.NotNil(suite.T(), err)
+	suite.fxRepoMock.AssertNumberOfCalls(suite.T(), "SetSettlementCurrency", 1)
+}
+
+func (suite *FxUsecaseTestSuite) SetupTest() {
+	suite.fxRepoMock = new(fxRepoMock)
+}
+
+func TestFxUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(FxUsecaseTestSuite))
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+const (
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+type HistoryUsecase interface {
+	FindHistories(query req.HistoryQuery) (res.HistoryPage, error)
+}
+
+type historyUsecase struct {
+	historyRepository repository.HistoryRepository
+}
+
+func (h *historyUsecase) FindHistories(query req.HistoryQuery) (res.HistoryPage, error) {
+	if query.Limit == 0 {
+		query.Limit = DefaultHistoryLimit
+	}
+	if query.Limit < 0 || query.Limit > MaxHistoryLimit {
+		return res.HistoryPage{}, app_error.InvalidError("invalid limit")
+	}
+	if query.Sort == "" {
+		query.Sort = repository.SortDescending
+	}
+	if query.Sort != repository.SortAscending && query.Sort != repository.SortDescending {
+		return res.HistoryPage{}, app_error.InvalidError("invalid sort order")
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.From.After(query.To) {
+		return res.HistoryPage{}, app_error.InvalidError("invalid date range")
+	}
+	if (query.MinAmount != nil && *query.MinAmount < 0) || (query.MaxAmount != nil && *query.MaxAmount < 0) {
+		return res.HistoryPage{}, app_error.InvalidError("invalid amount")
+	}
+	if query.MinAmount != nil && query.MaxAmount != nil && *query.MinAmount > *query.MaxAmount {
+		return res.HistoryPage{}, app_error.InvalidError("invalid amount range")
+	}
+	return h.historyRepository.FindHistories(query)
+}
+
+func NewHistoryUsecase(historyRepository repository.HistoryRepository) HistoryUsecase {
+	return &historyUsecase{
+		historyRepository: historyRepository,
+	}
+}
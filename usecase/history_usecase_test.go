@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyHistoryPage = res.HistoryPage{
+	Items: []entity.History{
+		{
+			TransactionId:    "Dummy Transaction Id",
+			CustomerUsername: "dummyUsername",
+			MerchantCode:     "Dummy Merchant Code",
+			Amount:           money.MustParse("20000.00"),
+		},
+	},
+}
+
+type historyRepoMock struct {
+	mock.Mock
+}
+
+func (h *historyRepoMock) FindHistories(query req.HistoryQuery) (res.HistoryPage, error) {
+	args := h.Called(query)
+	return args.Get(0).(res.HistoryPage), args.Error(1)
+}
+
+type HistoryUsecaseTestSuite struct {
+	historyRepoMock *historyRepoMock
+	suite.Suite
+}
+
+func (suite *HistoryUsecaseTestSuite) TestFindHistories_SuccessDefaults() {
+	historyUsecase := NewHistoryUsecase(suite.historyRepoMock)
+	query := req.HistoryQuery{CustomerUsername: "dummyUsername"}
+	expected := query
+	expected.Limit = DefaultHistoryLimit
+	expected.Sort = "desc"
+	suite.historyRepoMock.On("FindHistories", expected).Return(dummyHistoryPage, nil)
+	page, err := historyUsecase.FindHistories(query)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), dummyHistoryPage, page)
+}
+
+func (suite *HistoryUsecaseTestSuite) TestFindHistories_FailedRepo() {
+	historyUsecase := NewHistoryUsecase(suite.historyRepoMock)
+	query := req.HistoryQuery{CustomerUsername: "dummyUsername", Limit: 10, Sort: "asc"}
+	suite.historyRepoMock.On("FindHistories", query).Return(res.HistoryPage{}, errors.New("failed"))
+	_, err := historyUsecase.FindHistories(query)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *HistoryUsecaseTestSuite) TestFindHistories_FailedInvalidQuery() {
+	historyUsecase := NewHistoryUsecase(suite.historyRepoMock)
+	minAmount, maxAmount := money.MustParse("5000"), money.MustParse("1000")
+	now := time.Now()
+	queries := []req.HistoryQuery{
+		{Limit: MaxHistoryLimit + 1},
+		{Sort: "random"},
+		{From: now, To: now.Add(-time.Hour)},
+		{MinAmount: &minAmount, MaxAmount: &maxAmount},
+	}
+	for _, query := range queries {
+		_, err := historyUsecase.FindHistories(query)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.historyRepoMock.AssertNotCalled(suite.T(), "FindHistories", mock.Anything)
+}
+
+func (suite *HistoryUsecaseTestSuite) SetupTest() {
+	suite.historyRepoMock = new(historyRepoMock)
+}
+
+func TestHistoryUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(HistoryUsecaseTestSuite))
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/google/uuid"
+)
+
+const DefaultHoldLifetime = 7 * 24 * time.Hour
+
+type HoldUsecase interface {
+	Authorize(request req.HoldRequest, authorization req.PaymentAuthorization) (entity.Hold, error)
+	Capture(request req.CaptureRequest) (entity.Hold, error)
+	Void(merchantCode string, holdId string) (entity.Hold, error)
+	ListForCustomer(customerUsername string) ([]entity.Hold, error)
+	ListForMerchant(merchantCode string) ([]entity.Hold, error)
+	Balance(customerUsername string) (res.Balance, error)
+	ExpireStale() (int, error)
+}
+
+type holdUsecase struct {
+	holdRepository repository.HoldRepository
+	pinUsecase     PinUsecase
+	lifetime       time.Duration
+}
+
+func (h *holdUsecase) Authorize(request req.HoldRequest, authorization req.PaymentAuthorization) (entity.Hold, error) {
+	if request.MerchantCode == "" {
+		return entity.Hold{}, app_error.InvalidError("merchant code is required")
+	}
+	if request.Amount <= 0 {
+		return entity.Hold{}, app_error.InvalidError("invalid amount")
+	}
+	if request.Currency != "" && money.ValidateCurrency(request.Currency) != nil {
+		return entity.Hold{}, app_error.InvalidError("invalid currency")
+	}
+	if len(request.Reference) > MaxPaymentReferenceLength {
+		return entity.Hold{}, app_error.InvalidError("reference is too long")
+	}
+	if err := h.pinUsecase.AuthorizeAmount(request.CustomerUsername, request.Amount, request.Currency, authorization); err != nil {
+		return entity.Hold{}, err
+	}
+
+	now := time.Now().UTC()
+	return h.holdRepository.Authorize(entity.Hold{
+		HoldId:           uuid.New().String(),
+		CustomerUsername: request.CustomerUsername,
+		MerchantCode:     request.MerchantCode,
+		Amount:           request.Amount,
+		Currency:         request.Currency,
+		Reference:        request.Reference,
+		Status:           entity.HoldStatusAuthorized,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(h.lifetime),
+	})
+}
+
+func (h *holdUsecase) Capture(request req.CaptureRequest) (entity.Hold, error) {
+	if request.Amount <= 0 {
+		return entity.Hold{}, app_error.InvalidError("invalid amount")
+	}
+	return h.holdRepository.Capture(request.MerchantCode, request.HoldId, request.Amount)
+}
+
+func (h *holdUsecase) Void(merchantCode string, holdId string) (entity.Hold, error) {
+	return h.holdRepository.Void(merchantCode, holdId)
+}
+
+func (h *holdUsecase) ListForCustomer(customerUsername string) ([]entity.Hold, error) {
+	return h.holdRepository.FindByCustomer(customerUsername)
+}
+
+func (h *holdUsecase) ListForMerchant(merchantCode string) ([]entity.Hold, error) {
+	return h.holdRepository.FindByMerchant(merchantCode)
+}
+
+func (h *holdUsecase) Balance(customerUsername string) (res.Balance, error) {
+	return h.holdRepository.Balance(customerUsername)
+}
+
+func (h *holdUsecase) ExpireStale() (int, error) {
+	return h.holdRepository.ExpireStale()
+}
+
+func NewHoldUsecase(holdRepository repository.HoldRepository, pinUsecase PinUsecase, lifetime time.Duration) HoldUsecase {
+	if lifetime <= 0 {
+		lifetime = DefaultHoldLifetime
+	}
+	return &holdUsecase{
+		holdRepository: holdRepository,
+		pinUsecase:     pinUsecase,
+		lifetime:       lifetime,
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyHoldRequest = req.HoldRequest{
+	CustomerUsername: "dummyUsername",
+	MerchantCode:     "MRC125",
+	Amount:           money.MustParse("10000"),
+	Reference:        "ROOM-1",
+}
+
+type holdRepoMock struct {
+	mock.Mock
+}
+
+func (h *holdRepoMock) Authorize(hold entity.Hold) (entity.Hold, error) {
+	args := h.Called(hold)
+	return args.Get(0).(entity.Hold), args.Error(1)
+}
+
+func (h *holdRepoMock) Capture(merchantCode string, holdId string, amount money.Amount) (entity.Hold, error) {
+	args := h.Called(merchantCode, holdId, amount)
+	return args.Get(0).(entity.Hold), args.Error(1)
+}
+
+func (h *holdRepoMock) Void(merchantCode string, holdId string) (entity.Hold, error) {
+	args := h.Called(merchantCode, holdId)
+	return args.Get(0).(entity.Hold), args.Error(1)
+}
+
+func (h *holdRepoMock) FindByCustomer(customerUsername string) ([]entity.Hold, error) {
+	args := h.Called(customerUsername)
+	return args.Get(0).([]entity.Hold), args.Error(1)
+}
+
+func (h *holdRepoMock) FindByMerchant(merchantCode string) ([]entity.Hold, error) {
+	args := h.Called(merchantCode)
+	return args.Get(0).([]entity.Hold), args.Error(1)
+}
+
+func (h *holdRepoMock) ExpireStale() (int, error) {
+	args := h.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (h *holdRepoMock) Balance(customerUsername string) (res.Balance, error) {
+	args := h.Called(customerUsername)
+	return args.Get(0).(res.Balance), args.Error(1)
+}
+
+type HoldUsecaseTestSuite struct {
+	suite.Suite
+	holdRepoMock   *holdRepoMock
+	pinUsecaseMock *pinUsecaseMock
+}
+
+func (suite *HoldUsecaseTestSuite) TestAuthorize_Success() {
+	holdUsecase := NewHoldUsecase(suite.holdRepoMock, suite.pinUsecaseMock, time.Hour)
+	hold := entity.Hold{HoldId: "Dummy Hold Id", Status: entity.HoldStatusAuthorized}
+	suite.holdRepoMock.On("Authorize", mock.MatchedBy(func(hold entity.Hold) bool {
+		return hold.HoldId != "" && hold.CustomerUsername == "dummyUsername" && hold.MerchantCode == "MRC125" &&
+			hold.Status == entity.HoldStatusAuthorized && hold.ExpiresAt.Sub(hold.CreatedAt) == time.Hour
+	})).Return(hold, nil)
+
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyHoldRequest.Amount, dummyHoldRequest.Currency, req.PaymentAuthorization{}).Return(nil)
+
+	result, err := holdUsecase.Authorize(dummyHoldRequest, req.PaymentAuthorization{})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), hold, result)
+}
+
+func (suite *HoldUsecaseTestSuite) TestAuthorize_FailedPin() {
+	holdUsecase := NewHoldUsecase(suite.holdRepoMock, suite.pinUsecaseMock, time.Hour)
+	authorization := req.PaymentAuthorization{Pin: "000000"}
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyHoldRequest.Amount, dummyHoldRequest.Currency, authorization).Return(errors.New("failed"))
+
+	_, err := holdUsecase.Authorize(dummyHoldRequest, authorization)
+
+	assert.NotNil(suite.T(), err)
+	suite.holdRepoMock.AssertNotCalled(suite.T(), "Authorize", mock.Anything)
+}
+
+func (suite *HoldUsecaseTestSuite) TestAuthorize_FailedInvalidRequest() {
+	holdUsecase := NewHoldUsecase(suite.holdRepoMock, suite.pinUsecaseMock, time.Hour)
+	requests := []req.HoldRequest{
+		{Amount: money.MustParse("1")},
+		{MerchantCode: "MRC125"},
+		{MerchantCode: "MRC125", Amount: money.MustParse("1"), Currency: "XX"},
+		{MerchantCode: "MRC125", Amount: money.MustParse("1"), Reference: strings.Repeat("x", MaxPaymentReferenceLength+1)},
+	}
+	for _, request := range requests {
+		_, err := holdUsecase.Authorize(request, req.PaymentAuthorization{})
+		assert.NotNil(suite.T(), err)
+	}
+	suite.holdRepoMock.AssertNotCalled(suite.T(), "Authorize", mock.Anything)
+}
+
+func (suite *HoldUsecaseTestSuite) TestCapture() {
+	holdUsecase := NewHoldUsecase(suite.holdRepoMock, suite.pinUsecaseMock, time.Hour)
+	hold := entity.Hold{HoldId: "Dummy Hold Id", Status: entity.HoldStatusCaptured}
+	suite.holdRepoMock.On("Capture", "MRC125", "Dummy Hold Id", money.MustParse("50")).Return(hold, nil)
+
+	result, err := holdUsecase.Capture(req.CaptureRequest{MerchantCode: "MRC125", HoldId: "Dummy Hold Id", Amount: money.MustParse("50")})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), hold, result)
+
+	_, err = holdUsecase.Capture(req.CaptureRequest{MerchantCode: "MRC125", HoldId: "Dummy Hold Id"})
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *HoldUsecaseTestSuite) TestNewHoldUsecase_DefaultLifetime() {
+	holdUsecase := NewHoldUsecase(suite.holdRepoMock, suite.pinUsecaseMock, 0)
+	suite.holdRepoMock.On("Authorize", mock.MatchedBy(func(hold entity.Hold) bool {
+		return hold.ExpiresAt.Sub(hold.CreatedAt) == DefaultHoldLifetime
+	})).Return(entity.Hold{}, nil)
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyHoldRequest.Amount, dummyHoldRequest.Currency, req.PaymentAuthorization{}).Return(nil)
+
+	_, err := holdUsecase.Authorize(dummyHoldRequest, req.PaymentAuthorization{})
+
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *HoldUsecaseTestSuite) SetupTest() {
+	suite.holdRepoMock = new(holdRepoMock)
+	suite.pinUsecaseMock = new(pinUsecaseMock)
+}
+
+func TestHoldUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(HoldUsecaseTestSuite))
+}
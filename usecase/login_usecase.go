@@ -1,41 +1,125 @@
 package usecase
 
 import (
+	"errors"
+	"net/http"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
 	"github.com/febriansr/simple-payment-api/repository"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/loginchallenge"
+	"github.com/febriansr/simple-payment-api/utils/loginguard"
 )
 
 type LoginUsecase interface {
-	Login(customer entity.Customer) (token string, err error)
+	Login(request req.LoginRequest, client authenticator.ClientInfo) (res.LoginResponse, error)
+	VerifyLogin(request req.LoginVerifyRequest, client authenticator.ClientInfo) (res.TokenPair, error)
 }
 
 type loginUsecase struct {
 	loginRepository repository.LoginRepository
 	authenticator   authenticator.AccessToken
+	loginGuard      loginguard.LoginGuard
+	loginChallenge  loginchallenge.LoginChallenge
 }
 
-func (l *loginUsecase) Login(customer entity.Customer) (token string, err error) {
-	res := l.loginRepository.FindCustomer(customer)
+func (l *loginUsecase) Login(request req.LoginRequest, client authenticator.ClientInfo) (res.LoginResponse, error) {
+	err := l.loginGuard.Attempt(request.Username, client.Ip)
+	if err != nil {
+		return res.LoginResponse{}, err
+	}
 
-	if res == nil {
-		tokenDetails, err := l.authenticator.CreateAccessToken(&customer)
-		if err != nil {
-			return "", err
+	found, err := l.loginRepository.FindCustomer(entity.Customer{Username: request.Username, Password: request.Password})
+	if err = l.recordAttempt(request.Username, client, err); err != nil {
+		return res.LoginResponse{}, err
+	}
+
+	if found.TotpEnabled {
+		if err = l.loginGuard.Release(request.Username, client.Ip); err != nil {
+			return res.LoginResponse{}, err
 		}
-		err = l.authenticator.StoreAccessToken(customer.Username, tokenDetails)
+		challenge, err := l.loginChallenge.Create(found.Username)
 		if err != nil {
-			return "", err
+			return res.LoginResponse{}, err
 		}
-		return tokenDetails.AccessToken, nil
-	} else {
-		return "", res
+		return res.LoginResponse{
+			TwoFactorRequired:  true,
+			ChallengeToken:     challenge.Token,
+			ChallengeExpiresAt: challenge.ExpiresAt,
+		}, nil
+	}
+
+	if err = l.loginGuard.Succeeded(request.Username, client.Ip); err != nil {
+		return res.LoginResponse{}, err
+	}
+	tokenPair, err := l.issueTokens(found, client)
+	if err != nil {
+		return res.LoginResponse{}, err
+	}
+	return res.LoginResponse{TokenPair: &tokenPair}, nil
+}
+
+func (l *loginUsecase) VerifyLogin(request req.LoginVerifyRequest, client authenticator.ClientInfo) (res.TokenPair, error) {
+	if request.ChallengeToken == "" || request.Code == "" {
+		return res.TokenPair{}, app_error.InvalidError("challenge token and code are required")
+	}
+	challenge, err := l.loginChallenge.Fetch(request.ChallengeToken)
+	if err != nil {
+		return res.TokenPair{}, err
+	}
+	if err = l.loginGuard.Attempt(challenge.Username, client.Ip); err != nil {
+		return res.TokenPair{}, err
+	}
+
+	found, err := l.loginRepository.VerifyTwoFactorCode(challenge.Username, request.Code)
+	if err = l.recordAttempt(challenge.Username, client, err); err != nil {
+		return res.TokenPair{}, err
+	}
+	if err = l.loginChallenge.Delete(challenge.Token); err != nil {
+		return res.TokenPair{}, err
+	}
+	if err = l.loginGuard.Succeeded(challenge.Username, client.Ip); err != nil {
+		return res.TokenPair{}, err
+	}
+	return l.issueTokens(found, client)
+}
+
+// recordAttempt keeps the attempt counted when the credentials are wrong and
+// hands it back when the check failed for another reason.
+func (l *loginUsecase) recordAttempt(username string, client authenticator.ClientInfo, err error) error {
+	var appError *app_error.AppError
+	if err == nil || (errors.As(err, &appError) && appError.ErrorType == http.StatusUnauthorized) {
+		return err
+	}
+	if guardErr := l.loginGuard.Release(username, client.Ip); guardErr != nil {
+		return guardErr
+	}
+	return err
+}
+
+// issueTokens builds the token claims from the stored customer record so the
+// role and scopes can never be supplied by the caller.
+func (l *loginUsecase) issueTokens(customer entity.Customer, client authenticator.ClientInfo) (res.TokenPair, error) {
+	tokenDetails, err := l.authenticator.CreateAccessToken(&customer)
+	if err != nil {
+		return res.TokenPair{}, err
+	}
+	tokenDetails.Client = client
+	err = l.authenticator.StoreAccessToken(customer.Username, tokenDetails)
+	if err != nil {
+		return res.TokenPair{}, err
 	}
+	return res.NewTokenPair(tokenDetails), nil
 }
 
-func NewLoginUsecase(loginRepository repository.LoginRepository, authenticator authenticator.AccessToken) LoginUsecase {
+func NewLoginUsecase(loginRepository repository.LoginRepository, authenticator authenticator.AccessToken, loginGuard loginguard.LoginGuard, loginChallenge loginchallenge.LoginChallenge) LoginUsecase {
 	return &loginUsecase{
 		loginRepository: loginRepository,
 		authenticator:   authenticator,
+		loginGuard:      loginGuard,
+		loginChallenge:  loginChallenge,
 	}
 }
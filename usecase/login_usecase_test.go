@@ -1,11 +1,16 @@
 package usecase
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
 	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/loginchallenge"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -18,22 +23,92 @@ var dummyCustomer = &entity.Customer{
 
 var dummyTokenDetails = []authenticator.TokenDetails{
 	{
-		AccessToken: "Dummy Access Token",
-		AccessUuid:  "Dummy Access Uuid",
-		AtExpires:   5,
+		AccessToken:  "Dummy Access Token",
+		AccessUuid:   "Dummy Access Uuid",
+		AtExpires:    5,
+		RefreshToken: "Dummy Refresh Token",
+		RefreshUuid:  "Dummy Refresh Uuid",
+		RtExpires:    10,
+		FamilyId:     "Dummy Family Id",
 	},
 }
 
+var dummyLoginRequest = req.LoginRequest{
+	Username: "dummyUsername",
+	Password: "dummyPassword",
+}
+
+var dummyChallenge = loginchallenge.Challenge{
+	Token:     "Dummy Challenge Token",
+	Username:  "dummyUsername",
+	ExpiresAt: 300,
+}
+
+var dummyLoginVerifyRequest = req.LoginVerifyRequest{
+	ChallengeToken: "Dummy Challenge Token",
+	Code:           "123456",
+}
+
+var dummyClient = authenticator.ClientInfo{
+	UserAgent: "Dummy User Agent",
+	Ip:        "127.0.0.1",
+}
+
 type loginRepoMock struct {
 	mock.Mock
 }
 
-func (l *loginRepoMock) FindCustomer(customer entity.Customer) error {
+func (l *loginRepoMock) FindCustomer(customer entity.Customer) (entity.Customer, error) {
 	args := l.Called(customer)
-	if args[0] != nil {
-		return errors.New("Failed")
-	}
-	return nil
+	return args.Get(0).(entity.Customer), args.Error(1)
+}
+
+func (l *loginRepoMock) VerifyTwoFactorCode(username string, code string) (entity.Customer, error) {
+	args := l.Called(username, code)
+	return args.Get(0).(entity.Customer), args.Error(1)
+}
+
+type loginChallengeMock struct {
+	mock.Mock
+}
+
+func (c *loginChallengeMock) Create(username string) (loginchallenge.Challenge, error) {
+	args := c.Called(username)
+	return args.Get(0).(loginchallenge.Challenge), args.Error(1)
+}
+
+func (c *loginChallengeMock) Fetch(token string) (loginchallenge.Challenge, error) {
+	args := c.Called(token)
+	return args.Get(0).(loginchallenge.Challenge), args.Error(1)
+}
+
+func (c *loginChallengeMock) Delete(token string) error {
+	args := c.Called(token)
+	return args.Error(0)
+}
+
+type loginGuardMock struct {
+	mock.Mock
+}
+
+func (g *loginGuardMock) Attempt(username string, ip string) error {
+	args := g.Called(username, ip)
+	return args.Error(0)
+}
+
+func (g *loginGuardMock) Succeeded(username string, ip string) error {
+	args := g.Called(username, ip)
+	return args.Error(0)
+}
+
+func (g *loginGuardMock) Release(username string, ip string) error {
+	args := g.Called(username, ip)
+	return args.Error(0)
+}
+
+func (g *loginGuardMock) Unlock(username string, ip string) error {
+	args := g.Called(username, ip)
+	return args.Error(0)
 }
 
 type authMock struct {
@@ -80,52 +155,187 @@ func (a *authMock) DeleteAccessToken(accessUuid string) error {
 	return nil
 }
 
+func (a *authMock) RefreshAccessToken(refreshToken string, currentRole func(username string) (string, error)) (authenticator.TokenDetails, error) {
+	args := a.Called(refreshToken)
+	return args.Get(0).(authenticator.TokenDetails), args.Error(1)
+}
+
+func (a *authMock) RevokeTokenFamily(familyId string) error {
+	args := a.Called(familyId)
+	return args.Error(0)
+}
+
+func (a *authMock) RevokeSessions(username string) error {
+	args := a.Called(username)
+	return args.Error(0)
+}
+
+func (a *authMock) ListSessions(username string) ([]authenticator.Session, error) {
+	args := a.Called(username)
+	return args.Get(0).([]authenticator.Session), args.Error(1)
+}
+
+func (a *authMock) RevokeSession(username string, sessionId string) error {
+	args := a.Called(username, sessionId)
+	return args.Error(0)
+}
+
+func dummyStoredTokenDetails() authenticator.TokenDetails {
+	tokenDetails := dummyTokenDetails[0]
+	tokenDetails.Client = dummyClient
+	return tokenDetails
+}
+
 type LoginUsecaseTestSuite struct {
-	loginRepoMock *loginRepoMock
-	authMock      *authMock
+	loginRepoMock      *loginRepoMock
+	authMock           *authMock
+	loginGuardMock     *loginGuardMock
+	loginChallengeMock *loginChallengeMock
 	suite.Suite
 }
 
 func (suite *LoginUsecaseTestSuite) TestLogin_Success() {
-	loginUsecase := NewLoginUsecase(suite.loginRepoMock, suite.authMock)
-	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(nil)
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(*dummyCustomer, nil)
 	suite.authMock.On("CreateAccessToken", dummyCustomer).Return(dummyTokenDetails[0], nil)
-	suite.authMock.On("StoreAccessToken", dummyCustomer.Username, dummyTokenDetails[0]).Return(nil)
-	tokenDetails, err := loginUsecase.Login(*dummyCustomer)
+	suite.authMock.On("StoreAccessToken", dummyCustomer.Username, dummyStoredTokenDetails()).Return(nil)
+	tokenDetails, err := loginUsecase.Login(dummyLoginRequest, dummyClient)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), dummyTokenDetails[0].AccessToken, tokenDetails.Token)
+	assert.Equal(suite.T(), dummyTokenDetails[0].RefreshToken, tokenDetails.RefreshToken)
+}
+
+func (suite *LoginUsecaseTestSuite) TestLogin_IgnoresRoleFromRequest() {
+	loginUsecase := suite.newLoginUsecase()
+	storedCustomer := *dummyCustomer
+	storedCustomer.Password = "storedHash"
+	storedCustomer.Role = authenticator.RoleCustomer
+	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(storedCustomer, nil)
+	suite.authMock.On("CreateAccessToken", &storedCustomer).Return(dummyTokenDetails[0], nil)
+	suite.authMock.On("StoreAccessToken", dummyCustomer.Username, dummyStoredTokenDetails()).Return(nil)
+	var request req.LoginRequest
+	suite.Require().NoError(json.Unmarshal([]byte(`{"username":"dummyUsername","password":"dummyPassword","role":"admin"}`), &request))
+	_, err := loginUsecase.Login(request, dummyClient)
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), dummyTokenDetails[0].AccessToken, tokenDetails)
+	suite.authMock.AssertCalled(suite.T(), "CreateAccessToken", &storedCustomer)
+	suite.authMock.AssertNotCalled(suite.T(), "CreateAccessToken", mock.MatchedBy(func(customer *entity.Customer) bool {
+		return customer.Role == authenticator.RoleAdmin
+	}))
 }
 
 func (suite *LoginUsecaseTestSuite) TestLogin_FailedFindCustomer() {
-	loginUsecase := NewLoginUsecase(suite.loginRepoMock, suite.authMock)
-	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(errors.New("Failed"))
-	tokenDetails, err := loginUsecase.Login(*dummyCustomer)
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(entity.Customer{}, errors.New("Failed"))
+	tokenDetails, err := loginUsecase.Login(dummyLoginRequest, dummyClient)
 	assert.NotNil(suite.T(), err)
-	assert.Equal(suite.T(), "", tokenDetails)
+	assert.Equal(suite.T(), res.LoginResponse{}, tokenDetails)
+	suite.loginGuardMock.AssertCalled(suite.T(), "Release", dummyCustomer.Username, dummyClient.Ip)
+}
+
+func (suite *LoginUsecaseTestSuite) TestLogin_FailedInvalidCredentials() {
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(entity.Customer{}, app_error.Unauthorized("Invalid username or password"))
+	_, err := loginUsecase.Login(dummyLoginRequest, dummyClient)
+	assert.NotNil(suite.T(), err)
+	suite.loginGuardMock.AssertCalled(suite.T(), "Attempt", dummyCustomer.Username, dummyClient.Ip)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Release", mock.Anything, mock.Anything)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Succeeded", mock.Anything, mock.Anything)
+}
+
+func (suite *LoginUsecaseTestSuite) TestLogin_FailedLocked() {
+	suite.loginChallengeMock = new(loginChallengeMock)
+	suite.loginGuardMock = new(loginGuardMock)
+	suite.loginGuardMock.On("Attempt", dummyCustomer.Username, dummyClient.Ip).Return(app_error.TooManyRequests(""))
+	loginUsecase := suite.newLoginUsecase()
+	_, err := loginUsecase.Login(dummyLoginRequest, dummyClient)
+	assert.NotNil(suite.T(), err)
+	suite.loginRepoMock.AssertNotCalled(suite.T(), "FindCustomer", mock.Anything)
 }
 
 func (suite *LoginUsecaseTestSuite) TestLogin_FailedCreateAccessToken() {
-	loginUsecase := NewLoginUsecase(suite.loginRepoMock, suite.authMock)
-	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(nil)
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(*dummyCustomer, nil)
 	suite.authMock.On("CreateAccessToken", dummyCustomer).Return(authenticator.TokenDetails{}, errors.New("Failed"))
-	tokenDetails, err := loginUsecase.Login(*dummyCustomer)
+	tokenDetails, err := loginUsecase.Login(dummyLoginRequest, dummyClient)
 	assert.NotNil(suite.T(), err)
-	assert.Equal(suite.T(), "", tokenDetails)
+	assert.Equal(suite.T(), res.LoginResponse{}, tokenDetails)
 }
 
 func (suite *LoginUsecaseTestSuite) TestLogin_FailedStoreAccessToken() {
-	loginUsecase := NewLoginUsecase(suite.loginRepoMock, suite.authMock)
-	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(nil)
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(*dummyCustomer, nil)
 	suite.authMock.On("CreateAccessToken", dummyCustomer).Return(dummyTokenDetails[0], nil)
-	suite.authMock.On("StoreAccessToken", dummyCustomer.Username, dummyTokenDetails[0]).Return(errors.New("Failed"))
-	tokenDetails, err := loginUsecase.Login(*dummyCustomer)
+	suite.authMock.On("StoreAccessToken", dummyCustomer.Username, dummyStoredTokenDetails()).Return(errors.New("Failed"))
+	tokenDetails, err := loginUsecase.Login(dummyLoginRequest, dummyClient)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), res.LoginResponse{}, tokenDetails)
+}
+
+func (suite *LoginUsecaseTestSuite) TestLogin_TwoFactorRequired() {
+	loginUsecase := suite.newLoginUsecase()
+	customer := *dummyCustomer
+	customer.TotpEnabled = true
+	suite.loginRepoMock.On("FindCustomer", *dummyCustomer).Return(customer, nil)
+	suite.loginChallengeMock.On("Create", dummyCustomer.Username).Return(dummyChallenge, nil)
+	response, err := loginUsecase.Login(dummyLoginRequest, dummyClient)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), response.TwoFactorRequired)
+	assert.Equal(suite.T(), dummyChallenge.Token, response.ChallengeToken)
+	assert.Nil(suite.T(), response.TokenPair)
+	suite.authMock.AssertNotCalled(suite.T(), "CreateAccessToken", mock.Anything)
+	suite.loginGuardMock.AssertCalled(suite.T(), "Release", dummyCustomer.Username, dummyClient.Ip)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Succeeded", mock.Anything, mock.Anything)
+}
+
+func (suite *LoginUsecaseTestSuite) TestVerifyLogin_Success() {
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginChallengeMock.On("Fetch", dummyChallenge.Token).Return(dummyChallenge, nil)
+	suite.loginChallengeMock.On("Delete", dummyChallenge.Token).Return(nil)
+	storedCustomer := entity.Customer{Username: dummyChallenge.Username, TotpEnabled: true, Role: authenticator.RoleAdmin}
+	suite.loginRepoMock.On("VerifyTwoFactorCode", dummyChallenge.Username, dummyLoginVerifyRequest.Code).Return(storedCustomer, nil)
+	suite.authMock.On("CreateAccessToken", &storedCustomer).Return(dummyTokenDetails[0], nil)
+	suite.authMock.On("StoreAccessToken", dummyChallenge.Username, dummyStoredTokenDetails()).Return(nil)
+	tokenPair, err := loginUsecase.VerifyLogin(dummyLoginVerifyRequest, dummyClient)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), dummyTokenDetails[0].AccessToken, tokenPair.Token)
+	suite.loginGuardMock.AssertCalled(suite.T(), "Succeeded", dummyChallenge.Username, dummyClient.Ip)
+}
+
+func (suite *LoginUsecaseTestSuite) TestVerifyLogin_FailedInvalidCode() {
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginChallengeMock.On("Fetch", dummyChallenge.Token).Return(dummyChallenge, nil)
+	suite.loginRepoMock.On("VerifyTwoFactorCode", dummyChallenge.Username, dummyLoginVerifyRequest.Code).Return(entity.Customer{}, app_error.Unauthorized("Invalid verification code"))
+	_, err := loginUsecase.VerifyLogin(dummyLoginVerifyRequest, dummyClient)
+	assert.NotNil(suite.T(), err)
+	suite.loginGuardMock.AssertCalled(suite.T(), "Attempt", dummyChallenge.Username, dummyClient.Ip)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Release", mock.Anything, mock.Anything)
+	suite.loginChallengeMock.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+	suite.authMock.AssertNotCalled(suite.T(), "CreateAccessToken", mock.Anything)
+}
+
+func (suite *LoginUsecaseTestSuite) TestVerifyLogin_FailedChallenge() {
+	loginUsecase := suite.newLoginUsecase()
+	suite.loginChallengeMock.On("Fetch", dummyChallenge.Token).Return(loginchallenge.Challenge{}, app_error.Unauthorized("Invalid or expired login challenge"))
+	_, err := loginUsecase.VerifyLogin(dummyLoginVerifyRequest, dummyClient)
 	assert.NotNil(suite.T(), err)
-	assert.Equal(suite.T(), "", tokenDetails)
+	suite.loginRepoMock.AssertNotCalled(suite.T(), "VerifyTwoFactorCode", mock.Anything, mock.Anything)
+
+	_, err = loginUsecase.VerifyLogin(req.LoginVerifyRequest{ChallengeToken: dummyChallenge.Token}, dummyClient)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *LoginUsecaseTestSuite) newLoginUsecase() LoginUsecase {
+	return NewLoginUsecase(suite.loginRepoMock, suite.authMock, suite.loginGuardMock, suite.loginChallengeMock)
 }
 
 func (suite *LoginUsecaseTestSuite) SetupTest() {
 	suite.loginRepoMock = new(loginRepoMock)
 	suite.authMock = new(authMock)
+	suite.loginChallengeMock = new(loginChallengeMock)
+	suite.loginGuardMock = new(loginGuardMock)
+	suite.loginGuardMock.On("Attempt", mock.Anything, mock.Anything).Return(nil)
+	suite.loginGuardMock.On("Succeeded", mock.Anything, mock.Anything).Return(nil)
+	suite.loginGuardMock.On("Release", mock.Anything, mock.Anything).Return(nil)
 }
 
 func TestLoginUsecaseTestSuite(t *testing.T) {
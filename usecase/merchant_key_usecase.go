@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/utils/hmacsign"
+	"github.com/febriansr/simple-payment-api/utils/replayguard"
+	"github.com/febriansr/simple-payment-api/utils/secretbox"
+)
+
+const (
+	MaxApiKeyLabelLength = 64
+	minNonceLength       = 8
+	maxNonceLength       = 64
+)
+
+type MerchantKeyUsecase interface {
+	Issue(request req.ApiKeyRequest) (res.ApiKeySecret, error)
+	List(merchantCode string) ([]res.ApiKey, error)
+	Revoke(merchantCode string, keyId string) error
+	Authenticate(request req.SignedRequest) (entity.Merchant, error)
+}
+
+type merchantKeyUsecase struct {
+	merchantKeyRepository repository.MerchantKeyRepository
+	replayGuard           replayguard.ReplayGuard
+	secretBox             secretbox.SecretBox
+}
+
+func (m *merchantKeyUsecase) Issue(request req.ApiKeyRequest) (res.ApiKeySecret, error) {
+	if request.MerchantCode == "" {
+		return res.ApiKeySecret{}, app_error.InvalidError("merchant code is required")
+	}
+	if len(request.Label) > MaxApiKeyLabelLength {
+		return res.ApiKeySecret{}, app_error.InvalidError("label is too long")
+	}
+	keyId, err := randomToken(16)
+	if err != nil {
+		return res.ApiKeySecret{}, err
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return res.ApiKeySecret{}, err
+	}
+	plainSecret := "msk_" + base64.RawURLEncoding.EncodeToString(secret)
+	encryptedSecret, err := m.secretBox.Seal(hmacsign.SigningKey(plainSecret))
+	if err != nil {
+		return res.ApiKeySecret{}, err
+	}
+	apiKey := entity.MerchantApiKey{
+		KeyId:           "mk_" + hex.EncodeToString(keyId),
+		MerchantCode:    request.MerchantCode,
+		Label:           request.Label,
+		EncryptedSecret: encryptedSecret,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err = m.merchantKeyRepository.Create(apiKey); err != nil {
+		return res.ApiKeySecret{}, err
+	}
+	return res.ApiKeySecret{ApiKey: apiKeyResponse(apiKey), Secret: plainSecret}, nil
+}
+
+func (m *merchantKeyUsecase) List(merchantCode string) ([]res.ApiKey, error) {
+	apiKeys, err := m.merchantKeyRepository.FindByMerchant(merchantCode)
+	if err != nil {
+		return nil, err
+	}
+	response := []res.ApiKey{}
+	for _, apiKey := range apiKeys {
+		response = append(response, apiKeyResponse(apiKey))
+	}
+	return response, nil
+}
+
+func (m *merchantKeyUsecase) Revoke(merchantCode string, keyId string) error {
+	return m.merchantKeyRepository.Revoke(merchantCode, keyId)
+}
+
+func (m *merchantKeyUsecase) Authenticate(request req.SignedRequest) (entity.Merchant, error) {
+	if request.KeyId == "" || request.Signature == "" {
+		return entity.Merchant{}, app_error.Unauthorized("Missing request signature")
+	}
+	if len(request.Nonce) < minNonceLength || len(request.Nonce) > maxNonceLength {
+		return entity.Merchant{}, app_error.Unauthorized("Nonce must be between 8 and 64 characters")
+	}
+	timestamp, err := strconv.ParseInt(request.Timestamp, 10, 64)
+	if err != nil {
+		return entity.Merchant{}, app_error.Unauthorized("Invalid request timestamp")
+	}
+	apiKey, merchant, err := m.merchantKeyRepository.FindActive(request.KeyId)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+	signingKey, err := m.secretBox.Open(apiKey.EncryptedSecret)
+	if err != nil {
+		return entity.Merchant{}, err
+	}
+	stringToSign := hmacsign.StringToSign(request.Method, request.Path, request.Timestamp, request.Nonce, request.Body)
+	if !hmacsign.Verify(signingKey, stringToSign, request.Signature) {
+		return entity.Merchant{}, app_error.Unauthorized("Invalid request signature")
+	}
+	if err = m.replayGuard.Remember(apiKey.KeyId, time.Unix(timestamp, 0), request.Nonce); err != nil {
+		return entity.Merchant{}, err
+	}
+	return merchant, nil
+}
+
+func apiKeyResponse(apiKey entity.MerchantApiKey) res.ApiKey {
+	return res.ApiKey{
+		KeyId:     apiKey.KeyId,
+		Label:     apiKey.Label,
+		Active:    apiKey.Active(),
+		CreatedAt: apiKey.CreatedAt,
+		RevokedAt: apiKey.RevokedAt,
+	}
+}
+
+func randomToken(size int) ([]byte, error) {
+	token := make([]byte, size)
+	if _, err := rand.Read(token); err != nil {
+		return nil, app_error.InternalServerError("Failed to generate random token: " + err.Error())
+	}
+	return token, nil
+}
+
+func NewMerchantKeyUsecase(merchantKeyRepository repository.MerchantKeyRepository, replayGuard replayguard.ReplayGuard, secretBox secretbox.SecretBox) MerchantKeyUsecase {
+	return &merchantKeyUsecase{
+		merchantKeyRepository: merchantKeyRepository,
+		replayGuard:           replayGuard,
+		secretBox:             secretBox,
+	}
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/utils/hmacsign"
+	"github.com/febriansr/simple-payment-api/utils/secretbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummySecretBox = secretbox.NewSecretBox("Dummy Encryption Key")
+
+var dummyMerchantApiKey = entity.MerchantApiKey{
+	KeyId:           "mk_dummy",
+	MerchantCode:    "MRC125",
+	EncryptedSecret: mustSeal(hmacsign.SigningKey("msk_dummy")),
+}
+
+func mustSeal(plaintext string) string {
+	sealed, err := dummySecretBox.Seal(plaintext)
+	if err != nil {
+		panic(err)
+	}
+	return sealed
+}
+
+type merchantKeyRepoMock struct {
+	mock.Mock
+}
+
+func (m *merchantKeyRepoMock) Create(apiKey entity.MerchantApiKey) error {
+	args := m.Called(apiKey)
+	return args.Error(0)
+}
+
+func (m *merchantKeyRepoMock) FindByMerchant(merchantCode string) ([]entity.MerchantApiKey, error) {
+	args := m.Called(merchantCode)
+	return args.Get(0).([]entity.MerchantApiKey), args.Error(1)
+}
+
+func (m *merchantKeyRepoMock) Revoke(merchantCode string, keyId string) error {
+	args := m.Called(merchantCode, keyId)
+	return args.Error(0)
+}
+
+func (m *merchantKeyRepoMock) FindActive(keyId string) (entity.MerchantApiKey, entity.Merchant, error) {
+	args := m.Called(keyId)
+	return args.Get(0).(entity.MerchantApiKey), args.Get(1).(entity.Merchant), args.Error(2)
+}
+
+type replayGuardMock struct {
+	mock.Mock
+}
+
+func (r *replayGuardMock) Remember(keyId string, timestamp time.Time, nonce string) error {
+	args := r.Called(keyId, timestamp, nonce)
+	return args.Error(0)
+}
+
+type MerchantKeyUsecaseTestSuite struct {
+	merchantKeyRepoMock *merchantKeyRepoMock
+	replayGuardMock     *replayGuardMock
+	suite.Suite
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) newUsecase() MerchantKeyUsecase {
+	return NewMerchantKeyUsecase(suite.merchantKeyRepoMock, suite.replayGuardMock, dummySecretBox)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) signedRequest(secret string) req.SignedRequest {
+	request := req.SignedRequest{
+		KeyId:     dummyMerchantApiKey.KeyId,
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Nonce:     "dummy-nonce",
+		Method:    "POST",
+		Path:      "/v1/merchant/profile",
+		Body:      []byte(`{"amount":"10"}`),
+	}
+	request.Signature = hmacsign.Sign(hmacsign.SigningKey(secret), hmacsign.StringToSign(request.Method, request.Path, request.Timestamp, request.Nonce, request.Body))
+	return request
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestIssue_Success() {
+	var created entity.MerchantApiKey
+	suite.merchantKeyRepoMock.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(entity.MerchantApiKey)
+	}).Return(nil)
+
+	apiKey, err := suite.newUsecase().Issue(req.ApiKeyRequest{MerchantCode: "MRC125", Label: "primary"})
+
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(apiKey.KeyId, "mk_"))
+	assert.True(suite.T(), strings.HasPrefix(apiKey.Secret, "msk_"))
+	assert.True(suite.T(), apiKey.Active)
+	assert.Equal(suite.T(), apiKey.KeyId, created.KeyId)
+	assert.Equal(suite.T(), "MRC125", created.MerchantCode)
+	assert.True(suite.T(), secretbox.Sealed(created.EncryptedSecret))
+	assert.NotContains(suite.T(), created.EncryptedSecret, hmacsign.SigningKey(apiKey.Secret))
+	signingKey, err := dummySecretBox.Open(created.EncryptedSecret)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), hmacsign.SigningKey(apiKey.Secret), signingKey)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestIssue_FailedEncryptionKeyMissing() {
+	_, err := NewMerchantKeyUsecase(suite.merchantKeyRepoMock, suite.replayGuardMock, secretbox.NewSecretBox("")).Issue(req.ApiKeyRequest{MerchantCode: "MRC125"})
+	assert.NotNil(suite.T(), err)
+	suite.merchantKeyRepoMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestIssue_FailedInvalidRequest() {
+	_, err := suite.newUsecase().Issue(req.ApiKeyRequest{})
+	assert.NotNil(suite.T(), err)
+	_, err = suite.newUsecase().Issue(req.ApiKeyRequest{MerchantCode: "MRC125", Label: strings.Repeat("a", MaxApiKeyLabelLength+1)})
+	assert.NotNil(suite.T(), err)
+	suite.merchantKeyRepoMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestList_HidesSecretHash() {
+	suite.merchantKeyRepoMock.On("FindByMerchant", "MRC125").Return([]entity.MerchantApiKey{dummyMerchantApiKey}, nil)
+
+	apiKeys, err := suite.newUsecase().List("MRC125")
+
+	assert.Nil(suite.T(), err)
+	suite.Require().Len(apiKeys, 1)
+	assert.Equal(suite.T(), dummyMerchantApiKey.KeyId, apiKeys[0].KeyId)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestAuthenticate_Success() {
+	request := suite.signedRequest("msk_dummy")
+	suite.merchantKeyRepoMock.On("FindActive", dummyMerchantApiKey.KeyId).Return(dummyMerchantApiKey, entity.Merchant{MerchantCode: "MRC125"}, nil)
+	suite.replayGuardMock.On("Remember", dummyMerchantApiKey.KeyId, mock.Anything, request.Nonce).Return(nil)
+
+	merchant, err := suite.newUsecase().Authenticate(request)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "MRC125", merchant.MerchantCode)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestAuthenticate_FailedUnsealedSecret() {
+	unsealedApiKey := dummyMerchantApiKey
+	unsealedApiKey.EncryptedSecret = hmacsign.SigningKey("msk_dummy")
+	suite.merchantKeyRepoMock.On("FindActive", dummyMerchantApiKey.KeyId).Return(unsealedApiKey, entity.Merchant{MerchantCode: "MRC125"}, nil)
+
+	_, err := suite.newUsecase().Authenticate(suite.signedRequest("msk_dummy"))
+
+	assert.NotNil(suite.T(), err)
+	suite.replayGuardMock.AssertNotCalled(suite.T(), "Remember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestAuthenticate_FailedWrongSecret() {
+	suite.merchantKeyRepoMock.On("FindActive", dummyMerchantApiKey.KeyId).Return(dummyMerchantApiKey, entity.Merchant{MerchantCode: "MRC125"}, nil)
+
+	_, err := suite.newUsecase().Authenticate(suite.signedRequest("msk_wrong"))
+
+	assert.NotNil(suite.T(), err)
+	suite.replayGuardMock.AssertNotCalled(suite.T(), "Remember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestAuthenticate_FailedTamperedBody() {
+	request := suite.signedRequest("msk_dummy")
+	request.Body = []byte(`{"amount":"1000"}`)
+	suite.merchantKeyRepoMock.On("FindActive", dummyMerchantApiKey.KeyId).Return(dummyMerchantApiKey, entity.Merchant{MerchantCode: "MRC125"}, nil)
+
+	_, err := suite.newUsecase().Authenticate(request)
+
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestAuthenticate_FailedReplay() {
+	request := suite.signedRequest("msk_dummy")
+	suite.merchantKeyRepoMock.On("FindActive", dummyMerchantApiKey.KeyId).Return(dummyMerchantApiKey, entity.Merchant{MerchantCode: "MRC125"}, nil)
+	suite.replayGuardMock.On("Remember", dummyMerchantApiKey.KeyId, mock.Anything, request.Nonce).Return(app_error.Unauthorized("Request nonce already used"))
+
+	_, err := suite.newUsecase().Authenticate(request)
+
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestAuthenticate_FailedInvalidHeaders() {
+	requests := []req.SignedRequest{
+		{},
+		{KeyId: "mk_dummy", Signature: "abc", Nonce: "short", Timestamp: "1"},
+		{KeyId: "mk_dummy", Signature: "abc", Nonce: "dummy-nonce", Timestamp: "abc"},
+	}
+	for _, request := range requests {
+		_, err := suite.newUsecase().Authenticate(request)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.merchantKeyRepoMock.AssertNotCalled(suite.T(), "FindActive", mock.Anything)
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) TestRevoke() {
+	suite.merchantKeyRepoMock.On("Revoke", "MRC125", "mk_dummy").Return(errors.New("Failed"))
+	assert.NotNil(suite.T(), suite.newUsecase().Revoke("MRC125", "mk_dummy"))
+}
+
+func (suite *MerchantKeyUsecaseTestSuite) SetupTest() {
+	suite.merchantKeyRepoMock = new(merchantKeyRepoMock)
+	suite.replayGuardMock = new(replayGuardMock)
+}
+
+func TestMerchantKeyUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantKeyUsecaseTestSuite))
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxPaymentReferenceLength     = 64
+	DefaultPaymentRequestLifetime = 24 * time.Hour
+	MaxPaymentRequestLifetime     = 7 * 24 * time.Hour
+)
+
+type PaymentRequestUsecase interface {
+	Create(request req.CreatePaymentRequest) (entity.PaymentRequest, error)
+	ListForMerchant(merchantCode string) ([]entity.PaymentRequest, error)
+	ListPending(customerUsername string) ([]entity.PaymentRequest, error)
+	Approve(customerUsername string, requestId string, authorization req.PaymentAuthorization) (entity.PaymentRequest, error)
+	Decline(customerUsername string, requestId string) (entity.PaymentRequest, error)
+}
+
+type paymentRequestUsecase struct {
+	paymentRequestRepository repository.PaymentRequestRepository
+	pinUsecase               PinUsecase
+}
+
+func (p *paymentRequestUsecase) Create(request req.CreatePaymentRequest) (entity.PaymentRequest, error) {
+	if request.CustomerUsername == "" {
+		return entity.PaymentRequest{}, app_error.InvalidError("customer username is required")
+	}
+	if request.Amount <= 0 {
+		return entity.PaymentRequest{}, app_error.InvalidError("invalid amount")
+	}
+	if request.Currency != "" && money.ValidateCurrency(request.Currency) != nil {
+		return entity.PaymentRequest{}, app_error.InvalidError("invalid currency")
+	}
+	if request.Reference == "" {
+		return entity.PaymentRequest{}, app_error.InvalidError("reference is required")
+	}
+	if len(request.Reference) > MaxPaymentReferenceLength {
+		return entity.PaymentRequest{}, app_error.InvalidError("reference is too long")
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(DefaultPaymentRequestLifetime)
+	if request.ExpiresAt != nil {
+		expiresAt = request.ExpiresAt.UTC()
+	}
+	if !expiresAt.After(now) || expiresAt.After(now.Add(MaxPaymentRequestLifetime)) {
+		return entity.PaymentRequest{}, app_error.InvalidError("invalid expiry")
+	}
+
+	return p.paymentRequestRepository.Create(entity.PaymentRequest{
+		RequestId:        uuid.New().String(),
+		MerchantCode:     request.MerchantCode,
+		CustomerUsername: request.CustomerUsername,
+		Amount:           request.Amount,
+		Currency:         request.Currency,
+		Reference:        request.Reference,
+		Status:           entity.PaymentRequestStatusPending,
+		CreatedAt:        now,
+		ExpiresAt:        expiresAt,
+	})
+}
+
+func (p *paymentRequestUsecase) ListForMerchant(merchantCode string) ([]entity.PaymentRequest, error) {
+	paymentRequests, err := p.paymentRequestRepository.FindByMerchant(merchantCode)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range paymentRequests {
+		paymentRequests[i].Status = paymentRequests[i].StatusAt(now)
+	}
+	return paymentRequests, nil
+}
+
+func (p *paymentRequestUsecase) ListPending(customerUsername string) ([]entity.PaymentRequest, error) {
+	paymentRequests, err := p.paymentRequestRepository.FindByCustomer(customerUsername)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	pending := []entity.PaymentRequest{}
+	for _, paymentRequest := range paymentRequests {
+		if paymentRequest.StatusAt(now) == entity.PaymentRequestStatusPending {
+			pending = append(pending, paymentRequest)
+		}
+	}
+	return pending, nil
+}
+
+func (p *paymentRequestUsecase) Approve(customerUsername string, requestId string, authorization req.PaymentAuthorization) (entity.PaymentRequest, error) {
+	paymentRequest, err := p.paymentRequestRepository.FindById(customerUsername, requestId)
+	if err != nil {
+		return entity.PaymentRequest{}, err
+	}
+	err = p.pinUsecase.AuthorizeAmount(customerUsername, paymentRequest.Amount, paymentRequest.Currency, authorization)
+	if err != nil {
+		return entity.PaymentRequest{}, err
+	}
+	return p.paymentRequestRepository.Approve(customerUsername, requestId)
+}
+
+func (p *paymentRequestUsecase) Decline(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	return p.paymentRequestRepository.Decline(customerUsername, requestId)
+}
+
+func NewPaymentRequestUsecase(paymentRequestRepository repository.PaymentRequestRepository, pinUsecase PinUsecase) PaymentRequestUsecase {
+	return &paymentRequestUsecase{
+		paymentRequestRepository: paymentRequestRepository,
+		pinUsecase:               pinUsecase,
+	}
+}
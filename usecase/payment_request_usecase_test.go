@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyCreatePaymentRequest = req.CreatePaymentRequest{
+	MerchantCode:     "MRC125",
+	CustomerUsername: "dummyUsername",
+	Amount:           money.MustParse("10000"),
+	Reference:        "INV-1",
+}
+
+var dummyPaymentRequest = entity.PaymentRequest{
+	RequestId:        "Dummy Request Id",
+	MerchantCode:     "MRC125",
+	CustomerUsername: "dummyUsername",
+	Amount:           money.MustParse("10000"),
+	Currency:         money.DefaultCurrency,
+	Reference:        "INV-1",
+	Status:           entity.PaymentRequestStatusPending,
+}
+
+type paymentRequestRepoMock struct {
+	mock.Mock
+}
+
+func (p *paymentRequestRepoMock) Create(paymentRequest entity.PaymentRequest) (entity.PaymentRequest, error) {
+	args := p.Called(paymentRequest)
+	return args.Get(0).(entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestRepoMock) FindByCustomer(customerUsername string) ([]entity.PaymentRequest, error) {
+	args := p.Called(customerUsername)
+	return args.Get(0).([]entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestRepoMock) FindByMerchant(merchantCode string) ([]entity.PaymentRequest, error) {
+	args := p.Called(merchantCode)
+	return args.Get(0).([]entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestRepoMock) FindById(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	args := p.Called(customerUsername, requestId)
+	return args.Get(0).(entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestRepoMock) Approve(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	args := p.Called(customerUsername, requestId)
+	return args.Get(0).(entity.PaymentRequest), args.Error(1)
+}
+
+func (p *paymentRequestRepoMock) Decline(customerUsername string, requestId string) (entity.PaymentRequest, error) {
+	args := p.Called(customerUsername, requestId)
+	return args.Get(0).(entity.PaymentRequest), args.Error(1)
+}
+
+type PaymentRequestUsecaseTestSuite struct {
+	suite.Suite
+	repoMock       *paymentRequestRepoMock
+	pinUsecaseMock *pinUsecaseMock
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) TestCreate_Success() {
+	paymentRequestUsecase := NewPaymentRequestUsecase(suite.repoMock, suite.pinUsecaseMock)
+	suite.repoMock.On("Create", mock.MatchedBy(func(paymentRequest entity.PaymentRequest) bool {
+		lifetime := paymentRequest.ExpiresAt.Sub(paymentRequest.CreatedAt)
+		return paymentRequest.RequestId != "" && paymentRequest.MerchantCode == "MRC125" &&
+			paymentRequest.Status == entity.PaymentRequestStatusPending && lifetime == DefaultPaymentRequestLifetime
+	})).Return(dummyPaymentRequest, nil)
+
+	result, err := paymentRequestUsecase.Create(dummyCreatePaymentRequest)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), dummyPaymentRequest, result)
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) TestCreate_FailedInvalidRequest() {
+	paymentRequestUsecase := NewPaymentRequestUsecase(suite.repoMock, suite.pinUsecaseMock)
+	past := time.Now().Add(-time.Minute)
+	distant := time.Now().Add(MaxPaymentRequestLifetime + time.Hour)
+	requests := []req.CreatePaymentRequest{
+		{Amount: money.MustParse("1"), Reference: "INV-1"},
+		{CustomerUsername: "dummyUsername", Reference: "INV-1"},
+		{CustomerUsername: "dummyUsername", Amount: money.MustParse("1"), Currency: "XX", Reference: "INV-1"},
+		{CustomerUsername: "dummyUsername", Amount: money.MustParse("1")},
+		{CustomerUsername: "dummyUsername", Amount: money.MustParse("1"), Reference: strings.Repeat("x", MaxPaymentReferenceLength+1)},
+		{CustomerUsername: "dummyUsername", Amount: money.MustParse("1"), Reference: "INV-1", ExpiresAt: &past},
+		{CustomerUsername: "dummyUsername", Amount: money.MustParse("1"), Reference: "INV-1", ExpiresAt: &distant},
+	}
+	for _, request := range requests {
+		_, err := paymentRequestUsecase.Create(request)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.repoMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) TestListPending() {
+	paymentRequestUsecase := NewPaymentRequestUsecase(suite.repoMock, suite.pinUsecaseMock)
+	now := time.Now()
+	suite.repoMock.On("FindByCustomer", "dummyUsername").Return([]entity.PaymentRequest{
+		{RequestId: "pr-1", Status: entity.PaymentRequestStatusPending, ExpiresAt: now.Add(time.Hour)},
+		{RequestId: "pr-2", Status: entity.PaymentRequestStatusPending, ExpiresAt: now.Add(-time.Hour)},
+		{RequestId: "pr-3", Status: entity.PaymentRequestStatusDeclined, ExpiresAt: now.Add(time.Hour)},
+	}, nil)
+
+	result, err := paymentRequestUsecase.ListPending("dummyUsername")
+
+	assert.Nil(suite.T(), err)
+	suite.Require().Len(result, 1)
+	assert.Equal(suite.T(), "pr-1", result[0].RequestId)
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) TestListForMerchant() {
+	paymentRequestUsecase := NewPaymentRequestUsecase(suite.repoMock, suite.pinUsecaseMock)
+	suite.repoMock.On("FindByMerchant", "MRC125").Return([]entity.PaymentRequest{
+		{RequestId: "pr-1", Status: entity.PaymentRequestStatusPending, ExpiresAt: time.Now().Add(-time.Hour)},
+	}, nil)
+
+	result, err := paymentRequestUsecase.ListForMerchant("MRC125")
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), entity.PaymentRequestStatusExpired, result[0].Status)
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) TestApprove_Success() {
+	paymentRequestUsecase := NewPaymentRequestUsecase(suite.repoMock, suite.pinUsecaseMock)
+	authorization := req.PaymentAuthorization{Pin: "123456"}
+	approved := dummyPaymentRequest
+	approved.Status = entity.PaymentRequestStatusApproved
+	approved.TransactionId = "Dummy Transaction Id"
+	suite.repoMock.On("FindById", "dummyUsername", dummyPaymentRequest.RequestId).Return(dummyPaymentRequest, nil)
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyPaymentRequest.Amount, money.DefaultCurrency, authorization).Return(nil)
+	suite.repoMock.On("Approve", "dummyUsername", dummyPaymentRequest.RequestId).Return(approved, nil)
+
+	result, err := paymentRequestUsecase.Approve("dummyUsername", dummyPaymentRequest.RequestId, authorization)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), approved, result)
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) TestApprove_FailedPin() {
+	paymentRequestUsecase := NewPaymentRequestUsecase(suite.repoMock, suite.pinUsecaseMock)
+	suite.repoMock.On("FindById", "dummyUsername", dummyPaymentRequest.RequestId).Return(dummyPaymentRequest, nil)
+	suite.pinUsecaseMock.On("AuthorizeAmount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("failed"))
+
+	_, err := paymentRequestUsecase.Approve("dummyUsername", dummyPaymentRequest.RequestId, req.PaymentAuthorization{})
+
+	assert.NotNil(suite.T(), err)
+	suite.repoMock.AssertNotCalled(suite.T(), "Approve", mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) TestApprove_FailedNotFound() {
+	paymentRequestUsecase := NewPaymentRequestUsecase(suite.repoMock, suite.pinUsecaseMock)
+	suite.repoMock.On("FindById", "dummyUsername", dummyPaymentRequest.RequestId).Return(entity.PaymentRequest{}, errors.New("failed"))
+
+	_, err := paymentRequestUsecase.Approve("dummyUsername", dummyPaymentRequest.RequestId, req.PaymentAuthorization{})
+
+	assert.NotNil(suite.T(), err)
+	suite.pinUsecaseMock.AssertNotCalled(suite.T(), "AuthorizeAmount", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.repoMock.AssertNotCalled(suite.T(), "Approve", mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentRequestUsecaseTestSuite) SetupTest() {
+	suite.repoMock = new(paymentRequestRepoMock)
+	suite.pinUsecaseMock = new(pinUsecaseMock)
+}
+
+func TestPaymentRequestUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentRequestUsecaseTestSuite))
+}
@@ -2,27 +2,38 @@ package usecase
 
 import (
 	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
 	"github.com/febriansr/simple-payment-api/repository"
 )
 
 type PaymentUsecase interface {
-	PayTransaction(transaction entity.History) error
+	PayTransaction(transaction entity.History, authorization req.PaymentAuthorization) error
 }
 
 type paymentUsecase struct {
 	paymentRepository repository.PaymentRepository
+	pinUsecase        PinUsecase
 }
 
-func (p *paymentUsecase) PayTransaction(transaction entity.History) error {
+func (p *paymentUsecase) PayTransaction(transaction entity.History, authorization req.PaymentAuthorization) error {
 	if transaction.Amount < 0 {
 		return app_error.InvalidError("invalid amount")
 	}
-	return p.paymentRepository.PayTransaction(transaction)
+	if transaction.Currency != "" && money.ValidateCurrency(transaction.Currency) != nil {
+		return app_error.InvalidError("invalid currency")
+	}
+	if err := p.pinUsecase.AuthorizeAmount(transaction.CustomerUsername, transaction.Amount, transaction.Currency, authorization); err != nil {
+		return err
+	}
+	_, err := p.paymentRepository.PayTransaction(transaction)
+	return err
 }
 
-func NewPaymentUsecase(paymentRepository repository.PaymentRepository) PaymentUsecase {
+func NewPaymentUsecase(paymentRepository repository.PaymentRepository, pinUsecase PinUsecase) PaymentUsecase {
 	return &paymentUsecase{
 		paymentRepository: paymentRepository,
+		pinUsecase:        pinUsecase,
 	}
 }
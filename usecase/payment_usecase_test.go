@@ -4,7 +4,11 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
 	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -13,11 +17,11 @@ import (
 var dummyTransaction = []entity.History{
 	{
 		MerchantCode: "Dummy Merchant Code",
-		Amount:       20000.00,
+		Amount:       money.MustParse("20000.00"),
 	},
 	{
 		MerchantCode: "Dummy Merchant Code",
-		Amount:       -30000.00,
+		Amount:       money.MustParse("-30000.00"),
 	},
 }
 
@@ -25,42 +29,89 @@ type paymentRepoMock struct {
 	mock.Mock
 }
 
-func (p *paymentRepoMock) PayTransaction(transaction entity.History) error {
+func (p *paymentRepoMock) PayTransaction(transaction entity.History) (entity.History, error) {
 	args := p.Called(transaction)
 	if args[0] != nil {
-		return errors.New("Failed")
+		return entity.History{}, errors.New("Failed")
 	}
-	return nil
+	return transaction, nil
+}
+
+type pinUsecaseMock struct {
+	mock.Mock
+}
+
+func (p *pinUsecaseMock) SetPin(request req.SetPinRequest) error {
+	args := p.Called(request)
+	return args.Error(0)
+}
+
+func (p *pinUsecaseMock) StepUp(request req.StepUpRequest) (res.StepUpToken, error) {
+	args := p.Called(request)
+	return args.Get(0).(res.StepUpToken), args.Error(1)
+}
+
+func (p *pinUsecaseMock) Authorize(username string, authorization req.PaymentAuthorization) error {
+	args := p.Called(username, authorization)
+	return args.Error(0)
+}
+
+func (p *pinUsecaseMock) AuthorizeAmount(username string, amount money.Amount, currency string, authorization req.PaymentAuthorization) error {
+	args := p.Called(username, amount, currency, authorization)
+	return args.Error(0)
 }
 
 type PaymentUsecaseTestSuite struct {
 	paymentRepoMock *paymentRepoMock
+	pinUsecaseMock  *pinUsecaseMock
 	suite.Suite
 }
 
 func (suite *PaymentUsecaseTestSuite) TestPayTransaction_Success() {
-	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock)
+	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock, suite.pinUsecaseMock)
+	suite.pinUsecaseMock.On("AuthorizeAmount", dummyTransaction[0].CustomerUsername, dummyTransaction[0].Amount, dummyTransaction[0].Currency, req.PaymentAuthorization{}).Return(nil)
 	suite.paymentRepoMock.On("PayTransaction", dummyTransaction[0]).Return(nil)
-	err := paymentUsecase.PayTransaction(dummyTransaction[0])
+	err := paymentUsecase.PayTransaction(dummyTransaction[0], req.PaymentAuthorization{})
 	assert.Nil(suite.T(), err)
 }
 
 func (suite *PaymentUsecaseTestSuite) TestPayTransaction_FailedRepo() {
-	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock)
+	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock, suite.pinUsecaseMock)
+	suite.pinUsecaseMock.On("AuthorizeAmount", dummyTransaction[0].CustomerUsername, dummyTransaction[0].Amount, dummyTransaction[0].Currency, req.PaymentAuthorization{}).Return(nil)
 	suite.paymentRepoMock.On("PayTransaction", dummyTransaction[0]).Return(errors.New("failed"))
-	err := paymentUsecase.PayTransaction(dummyTransaction[0])
+	err := paymentUsecase.PayTransaction(dummyTransaction[0], req.PaymentAuthorization{})
 	assert.NotNil(suite.T(), err)
 }
 
 func (suite *PaymentUsecaseTestSuite) TestPayTransaction_FailedInvalidAmount() {
-	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock)
+	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock, suite.pinUsecaseMock)
 	suite.paymentRepoMock.On("PayTransaction", dummyTransaction[1]).Return(errors.New("failed"))
-	err := paymentUsecase.PayTransaction(dummyTransaction[1])
+	err := paymentUsecase.PayTransaction(dummyTransaction[1], req.PaymentAuthorization{})
+	assert.NotNil(suite.T(), err)
+	suite.pinUsecaseMock.AssertNotCalled(suite.T(), "AuthorizeAmount", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentUsecaseTestSuite) TestPayTransaction_ForwardsAuthorization() {
+	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock, suite.pinUsecaseMock)
+	authorization := req.PaymentAuthorization{Pin: "123456"}
+	suite.pinUsecaseMock.On("AuthorizeAmount", dummyTransaction[0].CustomerUsername, dummyTransaction[0].Amount, dummyTransaction[0].Currency, authorization).Return(nil)
+	suite.paymentRepoMock.On("PayTransaction", dummyTransaction[0]).Return(nil)
+	err := paymentUsecase.PayTransaction(dummyTransaction[0], authorization)
+	assert.Nil(suite.T(), err)
+	suite.pinUsecaseMock.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentUsecaseTestSuite) TestPayTransaction_FailedPin() {
+	paymentUsecase := NewPaymentUsecase(suite.paymentRepoMock, suite.pinUsecaseMock)
+	suite.pinUsecaseMock.On("AuthorizeAmount", dummyTransaction[0].CustomerUsername, dummyTransaction[0].Amount, dummyTransaction[0].Currency, req.PaymentAuthorization{}).Return(app_error.Forbidden("Transaction PIN required"))
+	err := paymentUsecase.PayTransaction(dummyTransaction[0], req.PaymentAuthorization{})
 	assert.NotNil(suite.T(), err)
+	suite.paymentRepoMock.AssertNotCalled(suite.T(), "PayTransaction", mock.Anything)
 }
 
 func (suite *PaymentUsecaseTestSuite) SetupTest() {
 	suite.paymentRepoMock = new(paymentRepoMock)
+	suite.pinUsecaseMock = new(pinUsecaseMock)
 }
 
 func TestPaymentUsecaseTestSuite(t *testing.T) {
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"bytes"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/payout"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+const DefaultPayoutBatchSize = 500
+
+type PayoutUsecase interface {
+	CreateBatches() ([]entity.PayoutBatch, error)
+	ListBatches() ([]entity.PayoutBatch, error)
+	ExportBatch(batchId string) (string, []byte, error)
+	ImportSettlement(batchId string, file []byte) (entity.PayoutBatch, error)
+}
+
+type payoutUsecase struct {
+	withdrawalRepository repository.WithdrawalRepository
+	batchSize            int
+}
+
+func (p *payoutUsecase) CreateBatches() ([]entity.PayoutBatch, error) {
+	return p.withdrawalRepository.CreateBatches(p.batchSize)
+}
+
+func (p *payoutUsecase) ListBatches() ([]entity.PayoutBatch, error) {
+	return p.withdrawalRepository.FindBatches()
+}
+
+func (p *payoutUsecase) ExportBatch(batchId string) (string, []byte, error) {
+	batch, withdrawals, err := p.withdrawalRepository.FindBatch(batchId)
+	if err != nil {
+		return "", nil, err
+	}
+	var file bytes.Buffer
+	if err = payout.WriteBatchFile(&file, batch, withdrawals); err != nil {
+		return "", nil, app_error.InternalServerError("Failed to write payout file: " + err.Error())
+	}
+	return payout.FileName(batch), file.Bytes(), nil
+}
+
+func (p *payoutUsecase) ImportSettlement(batchId string, file []byte) (entity.PayoutBatch, error) {
+	results, err := payout.ReadSettlementFile(bytes.NewReader(file), batchId)
+	if err != nil {
+		return entity.PayoutBatch{}, app_error.InvalidError(err.Error())
+	}
+	return p.withdrawalRepository.Settle(batchId, results)
+}
+
+func NewPayoutUsecase(withdrawalRepository repository.WithdrawalRepository, payoutConfig config.PayoutConfig) PayoutUsecase {
+	if payoutConfig.BatchSize <= 0 {
+		payoutConfig.BatchSize = DefaultPayoutBatchSize
+	}
+	return &payoutUsecase{
+		withdrawalRepository: withdrawalRepository,
+		batchSize:            payoutConfig.BatchSize,
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PayoutUsecaseTestSuite struct {
+	suite.Suite
+	withdrawalRepoMock *withdrawalRepoMock
+}
+
+func (suite *PayoutUsecaseTestSuite) TestCreateBatches_DefaultBatchSize() {
+	payoutUsecase := NewPayoutUsecase(suite.withdrawalRepoMock, config.PayoutConfig{})
+	suite.withdrawalRepoMock.On("CreateBatches", DefaultPayoutBatchSize).Return([]entity.PayoutBatch{{BatchId: "batch-1"}}, nil)
+
+	batches, err := payoutUsecase.CreateBatches()
+
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), batches, 1)
+}
+
+func (suite *PayoutUsecaseTestSuite) TestExportBatch_Success() {
+	payoutUsecase := NewPayoutUsecase(suite.withdrawalRepoMock, config.PayoutConfig{BatchSize: 10})
+	batch := entity.PayoutBatch{BatchId: "batch-1", Currency: "IDR", Count: 1, Total: money.MustParse("50000"),
+		CreatedAt: time.Date(2023, 6, 27, 0, 0, 0, 0, time.UTC)}
+	suite.withdrawalRepoMock.On("FindBatch", "batch-1").Return(batch, []entity.Withdrawal{
+		{WithdrawalId: "wd-1", BankCode: "014", AccountNumber: "1234567890", AccountName: "Dummy Customer", Amount: money.MustParse("50000"), Currency: "IDR"},
+	}, nil)
+
+	fileName, file, err := payoutUsecase.ExportBatch("batch-1")
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "payout_20230627_batch-1.csv", fileName)
+	assert.Equal(suite.T(), "H,batch-1,20230627,IDR,1,50000.00\nD,wd-1,014,1234567890,Dummy Customer,50000.00,IDR\nT,1,50000.00\n", string(file))
+}
+
+func (suite *PayoutUsecaseTestSuite) TestImportSettlement_Success() {
+	payoutUsecase := NewPayoutUsecase(suite.withdrawalRepoMock, config.PayoutConfig{})
+	suite.withdrawalRepoMock.On("Settle", "batch-1", []entity.PayoutResult{
+		{WithdrawalId: "wd-1", Amount: money.MustParse("50000"), Status: entity.WithdrawalStatusFailed, FailureReason: "Account closed"},
+	}).Return(entity.PayoutBatch{BatchId: "batch-1", Status: entity.PayoutBatchStatusSettled}, nil)
+
+	batch, err := payoutUsecase.ImportSettlement("batch-1", []byte("H,batch-1,20230628\nD,wd-1,50000,FAILED,Account closed\nT,1\n"))
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), entity.PayoutBatchStatusSettled, batch.Status)
+}
+
+func (suite *PayoutUsecaseTestSuite) TestImportSettlement_FailedInvalidFile() {
+	payoutUsecase := NewPayoutUsecase(suite.withdrawalRepoMock, config.PayoutConfig{})
+
+	_, err := payoutUsecase.ImportSettlement("batch-1", []byte("H,batch-2,20230628\nT,0\n"))
+
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusBadRequest, appError.ErrorType)
+	suite.withdrawalRepoMock.AssertNotCalled(suite.T(), "Settle", mock.Anything, mock.Anything)
+}
+
+func (suite *PayoutUsecaseTestSuite) SetupTest() {
+	suite.withdrawalRepoMock = new(withdrawalRepoMock)
+}
+
+func TestPayoutUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PayoutUsecaseTestSuite))
+}
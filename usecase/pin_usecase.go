@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/utils/pinguard"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var pinPattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+type PinUsecase interface {
+	SetPin(request req.SetPinRequest) error
+	StepUp(request req.StepUpRequest) (res.StepUpToken, error)
+	Authorize(username string, authorization req.PaymentAuthorization) error
+	AuthorizeAmount(username string, amount money.Amount, currency string, authorization req.PaymentAuthorization) error
+}
+
+type pinUsecase struct {
+	pinRepository repository.PinRepository
+	pinGuard      pinguard.PinGuard
+	config        config.SecurityConfig
+}
+
+func (p *pinUsecase) SetPin(request req.SetPinRequest) error {
+	if !pinPattern.MatchString(request.Pin) {
+		return app_error.InvalidError("PIN must be 6 digits")
+	}
+	if request.Password == "" {
+		return app_error.InvalidError("password is required")
+	}
+	if err := p.pinGuard.Check(request.CustomerUsername); err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.Pin), p.config.BcryptCost)
+	if err != nil {
+		return app_error.InternalServerError("Failed to hash PIN: " + err.Error())
+	}
+	err = p.pinRepository.SetPin(request.CustomerUsername, request.Password, string(hash))
+	return p.recordAttempt(request.CustomerUsername, err)
+}
+
+func (p *pinUsecase) StepUp(request req.StepUpRequest) (res.StepUpToken, error) {
+	if err := p.verifyPin(request.CustomerUsername, request.Pin); err != nil {
+		return res.StepUpToken{}, err
+	}
+	stepUp, err := p.pinGuard.IssueStepUp(request.CustomerUsername)
+	if err != nil {
+		return res.StepUpToken{}, err
+	}
+	return res.StepUpToken{
+		StepUpToken: stepUp.Token,
+		ExpiresAt:   stepUp.ExpiresAt,
+	}, nil
+}
+
+func (p *pinUsecase) Authorize(username string, authorization req.PaymentAuthorization) error {
+	if authorization.StepUpToken != "" {
+		return p.pinGuard.RedeemStepUp(username, authorization.StepUpToken)
+	}
+	if authorization.Pin != "" {
+		return p.verifyPin(username, authorization.Pin)
+	}
+	return app_error.Forbidden("Transaction PIN required")
+}
+
+// AuthorizeAmount asks for the PIN when the amount, valued in the default
+// currency, is above PAYMENT_PIN_THRESHOLD. Amounts that cannot be valued for
+// lack of an exchange rate always need the PIN.
+func (p *pinUsecase) AuthorizeAmount(username string, amount money.Amount, currency string, authorization req.PaymentAuthorization) error {
+	if p.config.PaymentPinThreshold <= 0 {
+		return nil
+	}
+	converted, ok, err := p.pinRepository.ConvertAmount(username, amount, currency, money.DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	if ok && converted <= p.config.PaymentPinThreshold {
+		return nil
+	}
+	return p.Authorize(username, authorization)
+}
+
+func (p *pinUsecase) verifyPin(username string, pin string) error {
+	if pin == "" {
+		return app_error.InvalidError("PIN is required")
+	}
+	if err := p.pinGuard.Check(username); err != nil {
+		return err
+	}
+	return p.recordAttempt(username, p.pinRepository.VerifyPin(username, pin))
+}
+
+func (p *pinUsecase) recordAttempt(username string, err error) error {
+	var appError *app_error.AppError
+	if errors.As(err, &appError) && appError.ErrorType == http.StatusForbidden {
+		if guardErr := p.pinGuard.Failed(username); guardErr != nil {
+			return guardErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return p.pinGuard.Succeeded(username)
+}
+
+func NewPinUsecase(pinRepository repository.PinRepository, pinGuard pinguard.PinGuard, config config.SecurityConfig) PinUsecase {
+	return &pinUsecase{
+		pinRepository: pinRepository,
+		pinGuard:      pinGuard,
+		config:        config,
+	}
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/utils/pinguard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var dummySetPinRequest = req.SetPinRequest{
+	CustomerUsername: "dummyUsername",
+	Pin:              "123456",
+	Password:         "dummyPassword1",
+}
+
+type pinRepoMock struct {
+	mock.Mock
+}
+
+func (p *pinRepoMock) SetPin(username string, password string, pinHash string) error {
+	args := p.Called(username, password, pinHash)
+	return args.Error(0)
+}
+
+func (p *pinRepoMock) VerifyPin(username string, pin string) error {
+	args := p.Called(username, pin)
+	return args.Error(0)
+}
+
+func (p *pinRepoMock) ConvertAmount(username string, amount money.Amount, currency string, target string) (money.Amount, bool, error) {
+	args := p.Called(username, amount, currency, target)
+	return args.Get(0).(money.Amount), args.Bool(1), args.Error(2)
+}
+
+type pinGuardMock struct {
+	mock.Mock
+}
+
+func (g *pinGuardMock) Check(username string) error {
+	args := g.Called(username)
+	return args.Error(0)
+}
+
+func (g *pinGuardMock) Failed(username string) error {
+	args := g.Called(username)
+	return args.Error(0)
+}
+
+func (g *pinGuardMock) Succeeded(username string) error {
+	args := g.Called(username)
+	return args.Error(0)
+}
+
+func (g *pinGuardMock) IssueStepUp(username string) (pinguard.StepUp, error) {
+	args := g.Called(username)
+	return args.Get(0).(pinguard.StepUp), args.Error(1)
+}
+
+func (g *pinGuardMock) RedeemStepUp(username string, token string) error {
+	args := g.Called(username, token)
+	return args.Error(0)
+}
+
+type PinUsecaseTestSuite struct {
+	pinRepoMock  *pinRepoMock
+	pinGuardMock *pinGuardMock
+	suite.Suite
+}
+
+func (suite *PinUsecaseTestSuite) newPinUsecase() PinUsecase {
+	return NewPinUsecase(suite.pinRepoMock, suite.pinGuardMock, config.SecurityConfig{BcryptCost: bcrypt.MinCost})
+}
+
+func (suite *PinUsecaseTestSuite) TestSetPin_Success() {
+	var pinHash string
+	suite.pinRepoMock.On("SetPin", dummySetPinRequest.CustomerUsername, dummySetPinRequest.Password, mock.Anything).Run(func(args mock.Arguments) {
+		pinHash = args.String(2)
+	}).Return(nil)
+	err := suite.newPinUsecase().SetPin(dummySetPinRequest)
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), bcrypt.CompareHashAndPassword([]byte(pinHash), []byte(dummySetPinRequest.Pin)))
+	suite.pinGuardMock.AssertCalled(suite.T(), "Succeeded", dummySetPinRequest.CustomerUsername)
+}
+
+func (suite *PinUsecaseTestSuite) TestSetPin_FailedWrongPassword() {
+	suite.pinRepoMock.On("SetPin", dummySetPinRequest.CustomerUsername, dummySetPinRequest.Password, mock.Anything).Return(app_error.Forbidden("Invalid password"))
+	err := suite.newPinUsecase().SetPin(dummySetPinRequest)
+	assert.NotNil(suite.T(), err)
+	suite.pinGuardMock.AssertCalled(suite.T(), "Failed", dummySetPinRequest.CustomerUsername)
+}
+
+func (suite *PinUsecaseTestSuite) TestSetPin_FailedInvalidRequest() {
+	requests := []req.SetPinRequest{
+		{CustomerUsername: "dummyUsername", Pin: "12345", Password: "dummyPassword1"},
+		{CustomerUsername: "dummyUsername", Pin: "12345a", Password: "dummyPassword1"},
+		{CustomerUsername: "dummyUsername", Pin: "123456"},
+	}
+	for _, request := range requests {
+		assert.NotNil(suite.T(), suite.newPinUsecase().SetPin(request))
+	}
+	suite.pinRepoMock.AssertNotCalled(suite.T(), "SetPin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PinUsecaseTestSuite) TestStepUp_Success() {
+	suite.pinRepoMock.On("VerifyPin", "dummyUsername", "123456").Return(nil)
+	suite.pinGuardMock.On("IssueStepUp", "dummyUsername").Return(pinguard.StepUp{Token: "Dummy Step Up Token", ExpiresAt: 300}, nil)
+	stepUpToken, err := suite.newPinUsecase().StepUp(req.StepUpRequest{CustomerUsername: "dummyUsername", Pin: "123456"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "Dummy Step Up Token", stepUpToken.StepUpToken)
+}
+
+func (suite *PinUsecaseTestSuite) TestStepUp_FailedLocked() {
+	suite.pinGuardMock = new(pinGuardMock)
+	suite.pinGuardMock.On("Check", "dummyUsername").Return(app_error.TooManyRequests(""))
+	_, err := suite.newPinUsecase().StepUp(req.StepUpRequest{CustomerUsername: "dummyUsername", Pin: "123456"})
+	assert.NotNil(suite.T(), err)
+	suite.pinRepoMock.AssertNotCalled(suite.T(), "VerifyPin", mock.Anything, mock.Anything)
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorize_StepUpToken() {
+	suite.pinGuardMock.On("RedeemStepUp", "dummyUsername", "Dummy Step Up Token").Return(nil)
+	err := suite.newPinUsecase().Authorize("dummyUsername", req.PaymentAuthorization{StepUpToken: "Dummy Step Up Token", Pin: "000000"})
+	assert.Nil(suite.T(), err)
+	suite.pinRepoMock.AssertNotCalled(suite.T(), "VerifyPin", mock.Anything, mock.Anything)
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorize_WrongPin() {
+	suite.pinRepoMock.On("VerifyPin", "dummyUsername", "000000").Return(app_error.Forbidden("Invalid transaction PIN"))
+	err := suite.newPinUsecase().Authorize("dummyUsername", req.PaymentAuthorization{Pin: "000000"})
+	assert.NotNil(suite.T(), err)
+	suite.pinGuardMock.AssertCalled(suite.T(), "Failed", "dummyUsername")
+	suite.pinGuardMock.AssertNotCalled(suite.T(), "Succeeded", mock.Anything)
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorize_PinNotSetIsNotAFailure() {
+	suite.pinRepoMock.On("VerifyPin", "dummyUsername", "000000").Return(app_error.InvalidError("Transaction PIN not set"))
+	err := suite.newPinUsecase().Authorize("dummyUsername", req.PaymentAuthorization{Pin: "000000"})
+	assert.NotNil(suite.T(), err)
+	suite.pinGuardMock.AssertNotCalled(suite.T(), "Failed", mock.Anything)
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorize_Missing() {
+	err := suite.newPinUsecase().Authorize("dummyUsername", req.PaymentAuthorization{})
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), "Transaction PIN required", appError.ErrorMessage)
+}
+
+func (suite *PinUsecaseTestSuite) newThresholdPinUsecase() PinUsecase {
+	return NewPinUsecase(suite.pinRepoMock, suite.pinGuardMock, config.SecurityConfig{BcryptCost: bcrypt.MinCost, PaymentPinThreshold: money.MustParse("1000000")})
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorizeAmount_DisabledThreshold() {
+	err := suite.newPinUsecase().AuthorizeAmount("dummyUsername", money.MustParse("5000000"), "", req.PaymentAuthorization{})
+	assert.Nil(suite.T(), err)
+	suite.pinRepoMock.AssertNotCalled(suite.T(), "ConvertAmount", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorizeAmount_ComparesInDefaultCurrency() {
+	suite.pinRepoMock.On("ConvertAmount", "dummyUsername", money.MustParse("100"), "USD", money.DefaultCurrency).Return(money.MustParse("1500000"), true, nil)
+	suite.pinRepoMock.On("ConvertAmount", "dummyUsername", money.MustParse("50"), "USD", money.DefaultCurrency).Return(money.MustParse("750000"), true, nil)
+	pinUsecase := suite.newThresholdPinUsecase()
+
+	err := pinUsecase.AuthorizeAmount("dummyUsername", money.MustParse("100"), "USD", req.PaymentAuthorization{})
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), "Transaction PIN required", appError.ErrorMessage)
+
+	err = pinUsecase.AuthorizeAmount("dummyUsername", money.MustParse("50"), "USD", req.PaymentAuthorization{})
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorizeAmount_AboveThresholdWithPin() {
+	suite.pinRepoMock.On("ConvertAmount", "dummyUsername", money.MustParse("100"), "USD", money.DefaultCurrency).Return(money.MustParse("1500000"), true, nil)
+	suite.pinRepoMock.On("VerifyPin", "dummyUsername", "123456").Return(nil)
+
+	err := suite.newThresholdPinUsecase().AuthorizeAmount("dummyUsername", money.MustParse("100"), "USD", req.PaymentAuthorization{Pin: "123456"})
+
+	assert.Nil(suite.T(), err)
+	suite.pinRepoMock.AssertCalled(suite.T(), "VerifyPin", "dummyUsername", "123456")
+}
+
+func (suite *PinUsecaseTestSuite) TestAuthorizeAmount_NoRateRequiresPin() {
+	suite.pinRepoMock.On("ConvertAmount", "dummyUsername", money.MustParse("1"), "JPY", money.DefaultCurrency).Return(money.Amount(0), false, nil)
+
+	err := suite.newThresholdPinUsecase().AuthorizeAmount("dummyUsername", money.MustParse("1"), "JPY", req.PaymentAuthorization{})
+
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *PinUsecaseTestSuite) SetupTest() {
+	suite.pinRepoMock = new(pinRepoMock)
+	suite.pinGuardMock = new(pinGuardMock)
+	suite.pinGuardMock.On("Check", mock.Anything).Return(nil)
+	suite.pinGuardMock.On("Failed", mock.Anything).Return(nil)
+	suite.pinGuardMock.On("Succeeded", mock.Anything).Return(nil)
+}
+
+func TestPinUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PinUsecaseTestSuite))
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+type RefreshUsecase interface {
+	Refresh(request req.RefreshRequest) (res.TokenPair, error)
+}
+
+type refreshUsecase struct {
+	refreshRepository repository.RefreshRepository
+}
+
+func (r *refreshUsecase) Refresh(request req.RefreshRequest) (res.TokenPair, error) {
+	if request.RefreshToken == "" {
+		return res.TokenPair{}, app_error.InvalidError("refresh token is required")
+	}
+	tokenDetails, err := r.refreshRepository.Refresh(request.RefreshToken)
+	if err != nil {
+		return res.TokenPair{}, err
+	}
+	return res.NewTokenPair(tokenDetails), nil
+}
+
+func NewRefreshUsecase(refreshRepository repository.RefreshRepository) RefreshUsecase {
+	return &refreshUsecase{
+		refreshRepository: refreshRepository,
+	}
+}
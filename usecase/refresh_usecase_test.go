@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type refreshRepoMock struct {
+	mock.Mock
+}
+
+func (r *refreshRepoMock) Refresh(refreshToken string) (authenticator.TokenDetails, error) {
+	args := r.Called(refreshToken)
+	return args.Get(0).(authenticator.TokenDetails), args.Error(1)
+}
+
+type RefreshUsecaseTestSuite struct {
+	refreshRepoMock *refreshRepoMock
+	suite.Suite
+}
+
+func (suite *RefreshUsecaseTestSuite) TestRefresh_Success() {
+	refreshUsecase := NewRefreshUsecase(suite.refreshRepoMock)
+	suite.refreshRepoMock.On("Refresh", dummyTokenDetails[0].RefreshToken).Return(dummyTokenDetails[0], nil)
+	tokenPair, err := refreshUsecase.Refresh(req.RefreshRequest{RefreshToken: dummyTokenDetails[0].RefreshToken})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), res.NewTokenPair(dummyTokenDetails[0]), tokenPair)
+}
+
+func (suite *RefreshUsecaseTestSuite) TestRefresh_FailedEmptyToken() {
+	refreshUsecase := NewRefreshUsecase(suite.refreshRepoMock)
+	_, err := refreshUsecase.Refresh(req.RefreshRequest{})
+	assert.NotNil(suite.T(), err)
+	suite.refreshRepoMock.AssertNotCalled(suite.T(), "Refresh", mock.Anything)
+}
+
+func (suite *RefreshUsecaseTestSuite) TestRefresh_FailedRepo() {
+	refreshUsecase := NewRefreshUsecase(suite.refreshRepoMock)
+	suite.refreshRepoMock.On("Refresh", dummyTokenDetails[0].RefreshToken).Return(authenticator.TokenDetails{}, errors.New("Failed"))
+	tokenPair, err := refreshUsecase.Refresh(req.RefreshRequest{RefreshToken: dummyTokenDetails[0].RefreshToken})
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), res.TokenPair{}, tokenPair)
+}
+
+func (suite *RefreshUsecaseTestSuite) SetupTest() {
+	suite.refreshRepoMock = new(refreshRepoMock)
+}
+
+func TestRefreshUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshUsecaseTestSuite))
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+const MaxRefundReasonLength = 255
+
+type RefundUsecase interface {
+	Refund(request req.RefundRequest) (entity.History, error)
+}
+
+type refundUsecase struct {
+	refundRepository repository.RefundRepository
+}
+
+func (r *refundUsecase) Refund(request req.RefundRequest) (entity.History, error) {
+	if request.TransactionId == "" {
+		return entity.History{}, app_error.InvalidError("transaction id is required")
+	}
+	if request.Amount < 0 {
+		return entity.History{}, app_error.InvalidError("invalid amount")
+	}
+	if len(request.Reason) > MaxRefundReasonLength {
+		return entity.History{}, app_error.InvalidError("reason is too long")
+	}
+	return r.refundRepository.Refund(request)
+}
+
+func NewRefundUsecase(refundRepository repository.RefundRepository) RefundUsecase {
+	return &refundUsecase{
+		refundRepository: refundRepository,
+	}
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyRefundRequest = req.RefundRequest{
+	MerchantCode:  "MRC125",
+	TransactionId: "Dummy Transaction Id",
+	Amount:        money.MustParse("10000"),
+	Reason:        "Dummy Reason",
+}
+
+type refundRepoMock struct {
+	mock.Mock
+}
+
+func (r *refundRepoMock) Refund(request req.RefundRequest) (entity.History, error) {
+	args := r.Called(request)
+	return args.Get(0).(entity.History), args.Error(1)
+}
+
+type RefundUsecaseTestSuite struct {
+	refundRepoMock *refundRepoMock
+	suite.Suite
+}
+
+func (suite *RefundUsecaseTestSuite) TestRefund_Success() {
+	refundUsecase := NewRefundUsecase(suite.refundRepoMock)
+	refund := entity.History{TransactionId: "Dummy Refund Id", Type: entity.HistoryTypeRefund}
+	suite.refundRepoMock.On("Refund", dummyRefundRequest).Return(refund, nil)
+	result, err := refundUsecase.Refund(dummyRefundRequest)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), refund, result)
+}
+
+func (suite *RefundUsecaseTestSuite) TestRefund_FailedRepo() {
+	refundUsecase := NewRefundUsecase(suite.refundRepoMock)
+	suite.refundRepoMock.On("Refund", dummyRefundRequest).Return(entity.History{}, errors.New("failed"))
+	_, err := refundUsecase.Refund(dummyRefundRequest)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *RefundUsecaseTestSuite) TestRefund_FailedInvalidRequest() {
+	refundUsecase := NewRefundUsecase(suite.refundRepoMock)
+	requests := []req.RefundRequest{
+		{TransactionId: ""},
+		{TransactionId: "Dummy Transaction Id", Amount: money.MustParse("-1")},
+		{TransactionId: "Dummy Transaction Id", Reason: strings.Repeat("x", MaxRefundReasonLength+1)},
+	}
+	for _, request := range requests {
+		_, err := refundUsecase.Refund(request)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.refundRepoMock.AssertNotCalled(suite.T(), "Refund", mock.Anything)
+}
+
+func (suite *RefundUsecaseTestSuite) SetupTest() {
+	suite.refundRepoMock = new(refundRepoMock)
+}
+
+func TestRefundUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundUsecaseTestSuite))
+}
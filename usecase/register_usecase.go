@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"regexp"
+	"unicode"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	DefaultPasswordMinLength = 8
+	MaxPasswordLength        = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,32}$`)
+
+type RegisterUsecase interface {
+	Register(request req.RegisterRequest) (res.CustomerProfile, error)
+}
+
+type registerUsecase struct {
+	registerRepository repository.RegisterRepository
+	config             config.SecurityConfig
+}
+
+func (r *registerUsecase) Register(request req.RegisterRequest) (res.CustomerProfile, error) {
+	if !usernamePattern.MatchString(request.Username) {
+		return res.CustomerProfile{}, app_error.InvalidError("username must be 3-32 letters, digits, '.', '_' or '-'")
+	}
+	if err := r.validatePassword(request.Username, request.Password); err != nil {
+		return res.CustomerProfile{}, err
+	}
+	if request.Currency == "" {
+		request.Currency = money.DefaultCurrency
+	}
+	if err := money.ValidateCurrency(request.Currency); err != nil {
+		return res.CustomerProfile{}, app_error.InvalidError(err.Error())
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.Password), r.config.BcryptCost)
+	if err != nil {
+		return res.CustomerProfile{}, app_error.InternalServerError("Failed to hash password: " + err.Error())
+	}
+	customer := entity.Customer{
+		Uuid:     uuid.New().String(),
+		Username: request.Username,
+		Password: string(hash),
+		Currency: request.Currency,
+	}
+	if err = r.registerRepository.CreateCustomer(customer); err != nil {
+		return res.CustomerProfile{}, err
+	}
+	return res.CustomerProfile{
+		Uuid:     customer.Uuid,
+		Username: customer.Username,
+		Balance:  customer.Balance,
+		Currency: customer.Currency,
+	}, nil
+}
+
+func (r *registerUsecase) validatePassword(username string, password string) error {
+	minLength := r.config.PasswordMinLength
+	if minLength <= 0 {
+		minLength = DefaultPasswordMinLength
+	}
+	if len(password) < minLength {
+		return app_error.InvalidError("password is too short")
+	}
+	if len(password) > MaxPasswordLength {
+		return app_error.InvalidError("password is too long")
+	}
+	if password == username {
+		return app_error.InvalidError("password must not match the username")
+	}
+	var hasLetter, hasDigit bool
+	for _, c := range password {
+		hasLetter = hasLetter || unicode.IsLetter(c)
+		hasDigit = hasDigit || unicode.IsDigit(c)
+	}
+	if !hasLetter || !hasDigit {
+		return app_error.InvalidError("password must contain letters and digits")
+	}
+	return nil
+}
+
+func NewRegisterUsecase(registerRepository repository.RegisterRepository, config config.SecurityConfig) RegisterUsecase {
+	return &registerUsecase{
+		registerRepository: registerRepository,
+		config:             config,
+	}
+}
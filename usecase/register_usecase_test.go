@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var dummyRegisterRequest = req.RegisterRequest{
+	Username: "dummyUsername",
+	Password: "dummyPassword1",
+}
+
+type registerRepoMock struct {
+	mock.Mock
+}
+
+func (r *registerRepoMock) CreateCustomer(customer entity.Customer) error {
+	args := r.Called(customer)
+	return args.Error(0)
+}
+
+type RegisterUsecaseTestSuite struct {
+	registerRepoMock *registerRepoMock
+	suite.Suite
+}
+
+func (suite *RegisterUsecaseTestSuite) newUsecase() RegisterUsecase {
+	return NewRegisterUsecase(suite.registerRepoMock, config.SecurityConfig{BcryptCost: bcrypt.MinCost})
+}
+
+func (suite *RegisterUsecaseTestSuite) TestRegister_Success() {
+	var created entity.Customer
+	suite.registerRepoMock.On("CreateCustomer", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(entity.Customer)
+	}).Return(nil)
+	profile, err := suite.newUsecase().Register(dummyRegisterRequest)
+	assert.Nil(suite.T(), err)
+	assert.NotEmpty(suite.T(), profile.Uuid)
+	assert.Equal(suite.T(), created.Uuid, profile.Uuid)
+	assert.Equal(suite.T(), dummyRegisterRequest.Username, profile.Username)
+	assert.Equal(suite.T(), money.Amount(0), profile.Balance)
+	assert.Equal(suite.T(), money.DefaultCurrency, profile.Currency)
+	assert.Nil(suite.T(), bcrypt.CompareHashAndPassword([]byte(created.Password), []byte(dummyRegisterRequest.Password)))
+}
+
+func (suite *RegisterUsecaseTestSuite) TestRegister_FailedRepo() {
+	suite.registerRepoMock.On("CreateCustomer", mock.Anything).Return(errors.New("failed"))
+	_, err := suite.newUsecase().Register(dummyRegisterRequest)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *RegisterUsecaseTestSuite) TestRegister_FailedInvalidRequest() {
+	requests := []req.RegisterRequest{
+		{Username: "ab", Password: "dummyPassword1"},
+		{Username: "dummy user", Password: "dummyPassword1"},
+		{Username: "dummyUsername", Password: "short1"},
+		{Username: "dummyUsername", Password: "onlyletters"},
+		{Username: "dummyUsername", Password: "1234567890"},
+		{Username: "dummyUsername", Password: strings.Repeat("a1", MaxPasswordLength)},
+		{Username: "dummyUser1", Password: "dummyUser1"},
+		{Username: "dummyUsername", Password: "dummyPassword1", Currency: "idr"},
+	}
+	for _, request := range requests {
+		_, err := suite.newUsecase().Register(request)
+		assert.NotNil(suite.T(), err, request)
+	}
+	suite.registerRepoMock.AssertNotCalled(suite.T(), "CreateCustomer", mock.Anything)
+}
+
+func (suite *RegisterUsecaseTestSuite) SetupTest() {
+	suite.registerRepoMock = new(registerRepoMock)
+}
+
+func TestRegisterUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RegisterUsecaseTestSuite))
+}
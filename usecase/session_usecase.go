@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+)
+
+type SessionUsecase interface {
+	ListSessions(accessDetails authenticator.AccessDetails) ([]authenticator.Session, error)
+	RevokeSession(accessDetails authenticator.AccessDetails, sessionId string) error
+	RevokeOtherSessions(accessDetails authenticator.AccessDetails) (int, error)
+}
+
+type sessionUsecase struct {
+	sessionRepository repository.SessionRepository
+}
+
+func (s *sessionUsecase) ListSessions(accessDetails authenticator.AccessDetails) ([]authenticator.Session, error) {
+	sessions, err := s.sessionRepository.FindSessions(accessDetails.Username)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == accessDetails.FamilyId
+	}
+	return sessions, nil
+}
+
+func (s *sessionUsecase) RevokeSession(accessDetails authenticator.AccessDetails, sessionId string) error {
+	if sessionId == "" {
+		return app_error.InvalidError("session id is required")
+	}
+	return s.sessionRepository.RevokeSession(accessDetails.Username, sessionId)
+}
+
+func (s *sessionUsecase) RevokeOtherSessions(accessDetails authenticator.AccessDetails) (int, error) {
+	sessions, err := s.sessionRepository.FindSessions(accessDetails.Username)
+	if err != nil {
+		return 0, err
+	}
+	revoked := 0
+	for _, session := range sessions {
+		if session.Id == accessDetails.FamilyId {
+			continue
+		}
+		if err = s.sessionRepository.RevokeSession(accessDetails.Username, session.Id); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+func NewSessionUsecase(sessionRepository repository.SessionRepository) SessionUsecase {
+	return &sessionUsecase{
+		sessionRepository: sessionRepository,
+	}
+}
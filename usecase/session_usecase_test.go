@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummySessionAccessDetails = authenticator.AccessDetails{
+	AccessUuid: "Dummy Access Uuid",
+	Username:   "dummyUsername",
+	FamilyId:   "session-1",
+}
+
+type sessionRepoMock struct {
+	mock.Mock
+}
+
+func (s *sessionRepoMock) FindSessions(username string) ([]authenticator.Session, error) {
+	args := s.Called(username)
+	return args.Get(0).([]authenticator.Session), args.Error(1)
+}
+
+func (s *sessionRepoMock) RevokeSession(username string, sessionId string) error {
+	args := s.Called(username, sessionId)
+	return args.Error(0)
+}
+
+type SessionUsecaseTestSuite struct {
+	sessionRepoMock *sessionRepoMock
+	suite.Suite
+}
+
+func (suite *SessionUsecaseTestSuite) TestListSessions_MarksCurrent() {
+	sessionUsecase := NewSessionUsecase(suite.sessionRepoMock)
+	suite.sessionRepoMock.On("FindSessions", "dummyUsername").Return([]authenticator.Session{{Id: "session-1"}, {Id: "session-2"}}, nil)
+	sessions, err := sessionUsecase.ListSessions(dummySessionAccessDetails)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []authenticator.Session{{Id: "session-1", Current: true}, {Id: "session-2"}}, sessions)
+}
+
+func (suite *SessionUsecaseTestSuite) TestListSessions_FailedRepo() {
+	sessionUsecase := NewSessionUsecase(suite.sessionRepoMock)
+	suite.sessionRepoMock.On("FindSessions", "dummyUsername").Return([]authenticator.Session(nil), errors.New("Failed"))
+	_, err := sessionUsecase.ListSessions(dummySessionAccessDetails)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *SessionUsecaseTestSuite) TestRevokeSession() {
+	sessionUsecase := NewSessionUsecase(suite.sessionRepoMock)
+	suite.sessionRepoMock.On("RevokeSession", "dummyUsername", "session-2").Return(nil)
+	assert.Nil(suite.T(), sessionUsecase.RevokeSession(dummySessionAccessDetails, "session-2"))
+	assert.NotNil(suite.T(), sessionUsecase.RevokeSession(dummySessionAccessDetails, ""))
+}
+
+func (suite *SessionUsecaseTestSuite) TestRevokeOtherSessions() {
+	sessionUsecase := NewSessionUsecase(suite.sessionRepoMock)
+	suite.sessionRepoMock.On("FindSessions", "dummyUsername").Return([]authenticator.Session{{Id: "session-1"}, {Id: "session-2"}, {Id: "session-3"}}, nil)
+	suite.sessionRepoMock.On("RevokeSession", "dummyUsername", "session-2").Return(nil)
+	suite.sessionRepoMock.On("RevokeSession", "dummyUsername", "session-3").Return(nil)
+	revoked, err := sessionUsecase.RevokeOtherSessions(dummySessionAccessDetails)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 2, revoked)
+	suite.sessionRepoMock.AssertNotCalled(suite.T(), "RevokeSession", "dummyUsername", "session-1")
+}
+
+func (suite *SessionUsecaseTestSuite) SetupTest() {
+	suite.sessionRepoMock = new(sessionRepoMock)
+}
+
+func TestSessionUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionUsecaseTestSuite))
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/gateway"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultTopUpReconcileAfter = 5 * time.Minute
+	DefaultTopUpLifetime       = time.Hour
+)
+
+type TopUpUsecase interface {
+	Create(request req.TopUpRequest) (entity.TopUp, error)
+	Get(customerUsername string, topUpId string) (entity.TopUp, error)
+	List(customerUsername string) ([]entity.TopUp, error)
+	HandleCallback(header http.Header, body []byte) (entity.TopUp, error)
+	Reconcile() (int, error)
+}
+
+type topUpUsecase struct {
+	topUpRepository repository.TopUpRepository
+	gateway         gateway.Gateway
+	maxAmount       money.Amount
+	reconcileAfter  time.Duration
+	lifetime        time.Duration
+}
+
+func (t *topUpUsecase) Create(request req.TopUpRequest) (entity.TopUp, error) {
+	if request.Amount <= 0 {
+		return entity.TopUp{}, app_error.InvalidError("invalid amount")
+	}
+	if request.Currency != "" && money.ValidateCurrency(request.Currency) != nil {
+		return entity.TopUp{}, app_error.InvalidError("invalid currency")
+	}
+	if request.Scenario != "" && !knownScenario(request.Scenario) {
+		return entity.TopUp{}, app_error.InvalidError("invalid scenario")
+	}
+
+	topUp, err := t.topUpRepository.Create(entity.TopUp{
+		TopUpId:          uuid.New().String(),
+		CustomerUsername: request.CustomerUsername,
+		Amount:           request.Amount,
+		Currency:         request.Currency,
+		Gateway:          t.gateway.Name(),
+		Status:           entity.TopUpStatusPending,
+		CreatedAt:        time.Now().UTC(),
+	}, t.maxAmount)
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+
+	charge, err := t.gateway.CreateCharge(gateway.ChargeRequest{
+		OrderId:  topUp.TopUpId,
+		Amount:   topUp.Amount,
+		Currency: topUp.Currency,
+		Scenario: request.Scenario,
+	})
+	if err != nil {
+		if _, failErr := t.topUpRepository.Fail(topUp.TopUpId, "gateway unavailable"); failErr != nil {
+			log.Println("Failed to mark top-up as failed:", failErr)
+		}
+		return entity.TopUp{}, app_error.InternalServerError("Failed to create charge: " + err.Error())
+	}
+	return t.topUpRepository.AttachCharge(topUp.TopUpId, charge.Reference)
+}
+
+func (t *topUpUsecase) Get(customerUsername string, topUpId string) (entity.TopUp, error) {
+	topUp, err := t.topUpRepository.FindById(topUpId)
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	if topUp.CustomerUsername != customerUsername {
+		return entity.TopUp{}, app_error.DataNotFound("top-up not found")
+	}
+	return topUp, nil
+}
+
+func (t *topUpUsecase) List(customerUsername string) ([]entity.TopUp, error) {
+	return t.topUpRepository.FindByCustomer(customerUsername)
+}
+
+func (t *topUpUsecase) HandleCallback(header http.Header, body []byte) (entity.TopUp, error) {
+	charge, err := t.gateway.VerifyCallback(header, body)
+	if errors.Is(err, gateway.ErrInvalidSignature) {
+		return entity.TopUp{}, app_error.Unauthorized("Invalid gateway signature")
+	}
+	if err != nil {
+		return entity.TopUp{}, app_error.InvalidError("invalid callback body")
+	}
+
+	topUp, err := t.topUpRepository.FindById(charge.OrderId)
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	if topUp.GatewayReference != "" && topUp.GatewayReference != charge.Reference {
+		return entity.TopUp{}, app_error.InvalidError("charge reference mismatch")
+	}
+	return t.apply(topUp, charge, false)
+}
+
+func (t *topUpUsecase) Reconcile() (int, error) {
+	now := time.Now()
+	topUps, err := t.topUpRepository.FindPending(now.Add(-t.reconcileAfter))
+	if err != nil {
+		return 0, err
+	}
+
+	var resolved int
+	var firstErr error
+	for _, topUp := range topUps {
+		reconciled, err := t.reconcile(topUp, now)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !reconciled.Pending() {
+			resolved++
+		}
+	}
+	return resolved, firstErr
+}
+
+func (t *topUpUsecase) reconcile(topUp entity.TopUp, now time.Time) (entity.TopUp, error) {
+	expired := !now.Before(topUp.CreatedAt.Add(t.lifetime))
+	if topUp.GatewayReference == "" {
+		if expired {
+			return t.topUpRepository.Fail(topUp.TopUpId, "charge was not created")
+		}
+		return topUp, nil
+	}
+
+	charge, err := t.gateway.FindCharge(topUp.GatewayReference)
+	if errors.Is(err, gateway.ErrChargeNotFound) {
+		if expired {
+			return t.topUpRepository.Fail(topUp.TopUpId, "charge not found at the gateway")
+		}
+		return topUp, nil
+	}
+	if err != nil {
+		return entity.TopUp{}, err
+	}
+	return t.apply(topUp, charge, expired)
+}
+
+func (t *topUpUsecase) apply(topUp entity.TopUp, charge gateway.Charge, expired bool) (entity.TopUp, error) {
+	switch charge.Status {
+	case gateway.StatusSucceeded:
+		return t.topUpRepository.Complete(topUp.TopUpId, charge.Amount, charge.Currency)
+	case gateway.StatusFailed:
+		return t.topUpRepository.Fail(topUp.TopUpId, charge.FailureReason)
+	}
+	if expired {
+		return t.topUpRepository.Fail(topUp.TopUpId, "top-up expired")
+	}
+	return topUp, nil
+}
+
+func knownScenario(scenario string) bool {
+	for _, known := range gateway.Scenarios {
+		if known == scenario {
+			return true
+		}
+	}
+	return false
+}
+
+func NewTopUpUsecase(topUpRepository repository.TopUpRepository, gateway gateway.Gateway, topUpConfig config.TopUpConfig) TopUpUsecase {
+	if topUpConfig.ReconcileAfter <= 0 {
+		topUpConfig.ReconcileAfter = DefaultTopUpReconcileAfter
+	}
+	if topUpConfig.Lifetime <= 0 {
+		topUpConfig.Lifetime = DefaultTopUpLifetime
+	}
+	return &topUpUsecase{
+		topUpRepository: topUpRepository,
+		gateway:         gateway,
+		maxAmount:       topUpConfig.MaxAmount,
+		reconcileAfter:  topUpConfig.ReconcileAfter,
+		lifetime:        topUpConfig.Lifetime,
+	}
+}
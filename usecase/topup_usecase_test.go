@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/gateway"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type topUpRepoMock struct {
+	mock.Mock
+}
+
+func (t *topUpRepoMock) Create(topUp entity.TopUp, maxAmount money.Amount) (entity.TopUp, error) {
+	args := t.Called(topUp, maxAmount)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpRepoMock) AttachCharge(topUpId string, reference string) (entity.TopUp, error) {
+	args := t.Called(topUpId, reference)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpRepoMock) Complete(topUpId string, amount money.Amount, currency string) (entity.TopUp, error) {
+	args := t.Called(topUpId, amount, currency)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpRepoMock) Fail(topUpId string, reason string) (entity.TopUp, error) {
+	args := t.Called(topUpId, reason)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpRepoMock) FindById(topUpId string) (entity.TopUp, error) {
+	args := t.Called(topUpId)
+	return args.Get(0).(entity.TopUp), args.Error(1)
+}
+
+func (t *topUpRepoMock) FindByCustomer(customerUsername string) ([]entity.TopUp, error) {
+	args := t.Called(customerUsername)
+	return args.Get(0).([]entity.TopUp), args.Error(1)
+}
+
+func (t *topUpRepoMock) FindPending(createdBefore time.Time) ([]entity.TopUp, error) {
+	args := t.Called(createdBefore)
+	return args.Get(0).([]entity.TopUp), args.Error(1)
+}
+
+type gatewayMock struct {
+	mock.Mock
+}
+
+func (g *gatewayMock) Name() string {
+	return "mock"
+}
+
+func (g *gatewayMock) CreateCharge(request gateway.ChargeRequest) (gateway.Charge, error) {
+	args := g.Called(request)
+	return args.Get(0).(gateway.Charge), args.Error(1)
+}
+
+func (g *gatewayMock) FindCharge(reference string) (gateway.Charge, error) {
+	args := g.Called(reference)
+	return args.Get(0).(gateway.Charge), args.Error(1)
+}
+
+func (g *gatewayMock) VerifyCallback(header http.Header, body []byte) (gateway.Charge, error) {
+	args := g.Called(header, body)
+	return args.Get(0).(gateway.Charge), args.Error(1)
+}
+
+var dummyTopUp = entity.TopUp{
+	TopUpId:          "Dummy TopUp Id",
+	CustomerUsername: "dummyUsername",
+	Amount:           money.MustParse("50"),
+	Currency:         money.DefaultCurrency,
+	Gateway:          "mock",
+	GatewayReference: "Dummy Reference",
+	Status:           entity.TopUpStatusPending,
+}
+
+type TopUpUsecaseTestSuite struct {
+	suite.Suite
+	topUpRepoMock *topUpRepoMock
+	gatewayMock   *gatewayMock
+	topUpUsecase  TopUpUsecase
+}
+
+func (suite *TopUpUsecaseTestSuite) TestCreate_Success() {
+	created := dummyTopUp
+	created.GatewayReference = ""
+	suite.topUpRepoMock.On("Create", mock.MatchedBy(func(topUp entity.TopUp) bool {
+		return topUp.TopUpId != "" && topUp.CustomerUsername == "dummyUsername" && topUp.Gateway == "mock" && topUp.Status == entity.TopUpStatusPending
+	}), money.MustParse("1000000")).Return(created, nil)
+	suite.gatewayMock.On("CreateCharge", gateway.ChargeRequest{OrderId: "Dummy TopUp Id", Amount: money.MustParse("50"), Currency: money.DefaultCurrency, Scenario: gateway.ScenarioDelayed}).
+		Return(gateway.Charge{Reference: "Dummy Reference", Status: gateway.StatusPending}, nil)
+	suite.topUpRepoMock.On("AttachCharge", "Dummy TopUp Id", "Dummy Reference").Return(dummyTopUp, nil)
+
+	result, err := suite.topUpUsecase.Create(req.TopUpRequest{CustomerUsername: "dummyUsername", Amount: money.MustParse("50"), Scenario: gateway.ScenarioDelayed})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), dummyTopUp, result)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestCreate_FailedGateway() {
+	suite.topUpRepoMock.On("Create", mock.Anything, mock.Anything).Return(dummyTopUp, nil)
+	suite.gatewayMock.On("CreateCharge", mock.Anything).Return(gateway.Charge{}, errors.New("connection refused"))
+	suite.topUpRepoMock.On("Fail", "Dummy TopUp Id", "gateway unavailable").Return(dummyTopUp, nil)
+
+	_, err := suite.topUpUsecase.Create(req.TopUpRequest{CustomerUsername: "dummyUsername", Amount: money.MustParse("50")})
+
+	assert.NotNil(suite.T(), err)
+	suite.topUpRepoMock.AssertNotCalled(suite.T(), "AttachCharge", mock.Anything, mock.Anything)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestCreate_FailedInvalidRequest() {
+	requests := []req.TopUpRequest{
+		{},
+		{Amount: money.MustParse("1"), Currency: "XX"},
+		{Amount: money.MustParse("1"), Scenario: "unknown"},
+	}
+	for _, request := range requests {
+		_, err := suite.topUpUsecase.Create(request)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.topUpRepoMock.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestGet_FailedOtherCustomer() {
+	suite.topUpRepoMock.On("FindById", "Dummy TopUp Id").Return(dummyTopUp, nil)
+
+	_, err := suite.topUpUsecase.Get("otherUsername", "Dummy TopUp Id")
+
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusNotFound, appError.ErrorType)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestHandleCallback_Succeeded() {
+	body := []byte(`{}`)
+	succeeded := dummyTopUp
+	succeeded.Status = entity.TopUpStatusSucceeded
+	suite.gatewayMock.On("VerifyCallback", http.Header{}, body).Return(gateway.Charge{
+		Reference: "Dummy Reference", OrderId: "Dummy TopUp Id", Amount: money.MustParse("50"), Currency: money.DefaultCurrency, Status: gateway.StatusSucceeded,
+	}, nil)
+	suite.topUpRepoMock.On("FindById", "Dummy TopUp Id").Return(dummyTopUp, nil)
+	suite.topUpRepoMock.On("Complete", "Dummy TopUp Id", money.MustParse("50"), money.DefaultCurrency).Return(succeeded, nil)
+
+	result, err := suite.topUpUsecase.HandleCallback(http.Header{}, body)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), succeeded, result)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestHandleCallback_FailedSignature() {
+	suite.gatewayMock.On("VerifyCallback", mock.Anything, mock.Anything).Return(gateway.Charge{}, gateway.ErrInvalidSignature)
+
+	_, err := suite.topUpUsecase.HandleCallback(http.Header{}, nil)
+
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusUnauthorized, appError.ErrorType)
+	suite.topUpRepoMock.AssertNotCalled(suite.T(), "FindById", mock.Anything)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestHandleCallback_FailedReferenceMismatch() {
+	suite.gatewayMock.On("VerifyCallback", mock.Anything, mock.Anything).
+		Return(gateway.Charge{Reference: "Other Reference", OrderId: "Dummy TopUp Id", Status: gateway.StatusSucceeded}, nil)
+	suite.topUpRepoMock.On("FindById", "Dummy TopUp Id").Return(dummyTopUp, nil)
+
+	_, err := suite.topUpUsecase.HandleCallback(http.Header{}, nil)
+
+	assert.NotNil(suite.T(), err)
+	suite.topUpRepoMock.AssertNotCalled(suite.T(), "Complete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestReconcile() {
+	now := time.Now()
+	settled := dummyTopUp
+	settled.CreatedAt = now.Add(-10 * time.Minute)
+	stale := dummyTopUp
+	stale.TopUpId = "Stale TopUp Id"
+	stale.GatewayReference = "Stale Reference"
+	stale.CreatedAt = now.Add(-2 * time.Hour)
+	waiting := dummyTopUp
+	waiting.TopUpId = "Waiting TopUp Id"
+	waiting.GatewayReference = "Waiting Reference"
+	waiting.CreatedAt = now.Add(-10 * time.Minute)
+	suite.topUpRepoMock.On("FindPending", mock.Anything).Return([]entity.TopUp{settled, stale, waiting}, nil)
+	suite.gatewayMock.On("FindCharge", "Dummy Reference").
+		Return(gateway.Charge{Amount: money.MustParse("50"), Currency: money.DefaultCurrency, Status: gateway.StatusSucceeded}, nil)
+	suite.gatewayMock.On("FindCharge", "Stale Reference").Return(gateway.Charge{}, gateway.ErrChargeNotFound)
+	suite.gatewayMock.On("FindCharge", "Waiting Reference").Return(gateway.Charge{Status: gateway.StatusPending}, nil)
+	suite.topUpRepoMock.On("Complete", "Dummy TopUp Id", money.MustParse("50"), money.DefaultCurrency).
+		Return(entity.TopUp{Status: entity.TopUpStatusSucceeded}, nil)
+	suite.topUpRepoMock.On("Fail", "Stale TopUp Id", "charge not found at the gateway").
+		Return(entity.TopUp{Status: entity.TopUpStatusFailed}, nil)
+
+	resolved, err := suite.topUpUsecase.Reconcile()
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 2, resolved)
+	suite.topUpRepoMock.AssertNotCalled(suite.T(), "Fail", "Waiting TopUp Id", mock.Anything)
+}
+
+func (suite *TopUpUsecaseTestSuite) TestSimulator_CallbackCreditsTopUp() {
+	received := make(chan []byte, 1)
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer server.Close()
+	simulator := gateway.NewSimulator(config.GatewayConfig{Secret: "gateway-secret", CallbackUrl: server.URL, CallbackDelay: time.Millisecond})
+	topUpUsecase := NewTopUpUsecase(suite.topUpRepoMock, simulator, config.TopUpConfig{})
+	suite.topUpRepoMock.On("Create", mock.Anything, mock.Anything).Return(dummyTopUp, nil)
+	suite.topUpRepoMock.On("AttachCharge", "Dummy TopUp Id", mock.Anything).Return(dummyTopUp, nil)
+
+	_, err := topUpUsecase.Create(req.TopUpRequest{CustomerUsername: "dummyUsername", Amount: money.MustParse("50"), Scenario: gateway.ScenarioFailure})
+	suite.Require().NoError(err)
+
+	var body []byte
+	select {
+	case body = <-received:
+	case <-time.After(5 * time.Second):
+		suite.FailNow("callback was not sent")
+	}
+	suite.topUpRepoMock.On("FindById", "Dummy TopUp Id").Return(entity.TopUp{TopUpId: "Dummy TopUp Id", Status: entity.TopUpStatusPending}, nil)
+	suite.topUpRepoMock.On("Fail", "Dummy TopUp Id", "card declined").Return(entity.TopUp{Status: entity.TopUpStatusFailed}, nil)
+
+	result, err := topUpUsecase.HandleCallback(header, body)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), entity.TopUpStatusFailed, result.Status)
+
+	_, err = topUpUsecase.HandleCallback(header, append(body, ' '))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *TopUpUsecaseTestSuite) SetupTest() {
+	suite.topUpRepoMock = new(topUpRepoMock)
+	suite.gatewayMock = new(gatewayMock)
+	suite.topUpUsecase = NewTopUpUsecase(suite.topUpRepoMock, suite.gatewayMock, config.TopUpConfig{
+		MaxAmount:      money.MustParse("1000000"),
+		ReconcileAfter: time.Minute,
+		Lifetime:       time.Hour,
+	})
+}
+
+func TestTopUpUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TopUpUsecaseTestSuite))
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+const MaxTransferNoteLength = 140
+
+type TransferUsecase interface {
+	Transfer(request req.TransferRequest, authorization req.PaymentAuthorization) (entity.History, error)
+}
+
+type transferUsecase struct {
+	transferRepository repository.TransferRepository
+	pinUsecase         PinUsecase
+}
+
+func (t *transferUsecase) Transfer(request req.TransferRequest, authorization req.PaymentAuthorization) (entity.History, error) {
+	if request.RecipientUsername == "" {
+		return entity.History{}, app_error.InvalidError("recipient username is required")
+	}
+	if request.RecipientUsername == request.CustomerUsername {
+		return entity.History{}, app_error.InvalidError("cannot transfer to yourself")
+	}
+	if request.Amount <= 0 {
+		return entity.History{}, app_error.InvalidError("invalid amount")
+	}
+	if request.Currency != "" && money.ValidateCurrency(request.Currency) != nil {
+		return entity.History{}, app_error.InvalidError("invalid currency")
+	}
+	if len(request.Note) > MaxTransferNoteLength {
+		return entity.History{}, app_error.InvalidError("note is too long")
+	}
+	if err := t.pinUsecase.AuthorizeAmount(request.CustomerUsername, request.Amount, request.Currency, authorization); err != nil {
+		return entity.History{}, err
+	}
+
+	return t.transferRepository.Transfer(entity.History{
+		CustomerUsername:  request.CustomerUsername,
+		RecipientUsername: request.RecipientUsername,
+		Amount:            request.Amount,
+		Currency:          request.Currency,
+		Note:              request.Note,
+	})
+}
+
+func NewTransferUsecase(transferRepository repository.TransferRepository, pinUsecase PinUsecase) TransferUsecase {
+	return &transferUsecase{
+		transferRepository: transferRepository,
+		pinUsecase:         pinUsecase,
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyTransferRequest = req.TransferRequest{
+	CustomerUsername:  "dummyUsername",
+	RecipientUsername: "dummyRecipient",
+	Amount:            money.MustParse("10000"),
+	Note:              "dinner",
+}
+
+type transferRepoMock struct {
+	mock.Mock
+}
+
+func (t *transferRepoMock) Transfer(transfer entity.History) (entity.History, error) {
+	args := t.Called(transfer)
+	return args.Get(0).(entity.History), args.Error(1)
+}
+
+type TransferUsecaseTestSuite struct {
+	suite.Suite
+	transferRepoMock *transferRepoMock
+	pinUsecaseMock   *pinUsecaseMock
+}
+
+func (suite *TransferUsecaseTestSuite) TestTransfer_Success() {
+	transferUsecase := NewTransferUsecase(suite.transferRepoMock, suite.pinUsecaseMock)
+	transfer := entity.History{TransactionId: "Dummy Transaction Id", Type: entity.HistoryTypeTransfer}
+	suite.transferRepoMock.On("Transfer", entity.History{
+		CustomerUsername:  "dummyUsername",
+		RecipientUsername: "dummyRecipient",
+		Amount:            money.MustParse("10000"),
+		Note:              "dinner",
+	}).Return(transfer, nil)
+
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyTransferRequest.Amount, dummyTransferRequest.Currency, req.PaymentAuthorization{}).Return(nil)
+
+	result, err := transferUsecase.Transfer(dummyTransferRequest, req.PaymentAuthorization{})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), transfer, result)
+}
+
+func (suite *TransferUsecaseTestSuite) TestTransfer_FailedPin() {
+	transferUsecase := NewTransferUsecase(suite.transferRepoMock, suite.pinUsecaseMock)
+	authorization := req.PaymentAuthorization{Pin: "000000"}
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyTransferRequest.Amount, dummyTransferRequest.Currency, authorization).Return(errors.New("failed"))
+
+	_, err := transferUsecase.Transfer(dummyTransferRequest, authorization)
+
+	assert.NotNil(suite.T(), err)
+	suite.transferRepoMock.AssertNotCalled(suite.T(), "Transfer", mock.Anything)
+}
+
+func (suite *TransferUsecaseTestSuite) TestTransfer_FailedInvalidRequest() {
+	transferUsecase := NewTransferUsecase(suite.transferRepoMock, suite.pinUsecaseMock)
+	requests := []req.TransferRequest{
+		{CustomerUsername: "dummyUsername", Amount: money.MustParse("1")},
+		{CustomerUsername: "dummyUsername", RecipientUsername: "dummyUsername", Amount: money.MustParse("1")},
+		{CustomerUsername: "dummyUsername", RecipientUsername: "dummyRecipient"},
+		{CustomerUsername: "dummyUsername", RecipientUsername: "dummyRecipient", Amount: money.MustParse("-1")},
+		{CustomerUsername: "dummyUsername", RecipientUsername: "dummyRecipient", Amount: money.MustParse("1"), Currency: "XX"},
+		{CustomerUsername: "dummyUsername", RecipientUsername: "dummyRecipient", Amount: money.MustParse("1"), Note: strings.Repeat("x", MaxTransferNoteLength+1)},
+	}
+	for _, request := range requests {
+		_, err := transferUsecase.Transfer(request, req.PaymentAuthorization{})
+		assert.NotNil(suite.T(), err)
+	}
+	suite.transferRepoMock.AssertNotCalled(suite.T(), "Transfer", mock.Anything)
+}
+
+func (suite *TransferUsecaseTestSuite) SetupTest() {
+	suite.transferRepoMock = new(transferRepoMock)
+	suite.pinUsecaseMock = new(pinUsecaseMock)
+}
+
+func TestTransferUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferUsecaseTestSuite))
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/utils/authenticator"
+	"github.com/febriansr/simple-payment-api/utils/loginguard"
+	"github.com/febriansr/simple-payment-api/utils/totp"
+)
+
+type TwoFactorUsecase interface {
+	Enroll(username string) (res.TwoFactorEnrollment, error)
+	Confirm(username string, code string) (res.RecoveryCodes, error)
+	Disable(username string, code string, client authenticator.ClientInfo) error
+}
+
+type twoFactorUsecase struct {
+	twoFactorRepository repository.TwoFactorRepository
+	loginGuard          loginguard.LoginGuard
+	issuer              string
+}
+
+func (t *twoFactorUsecase) Enroll(username string) (res.TwoFactorEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return res.TwoFactorEnrollment{}, app_error.InternalServerError("Failed to generate secret: " + err.Error())
+	}
+	if err = t.twoFactorRepository.SaveSecret(username, secret); err != nil {
+		return res.TwoFactorEnrollment{}, err
+	}
+	return res.TwoFactorEnrollment{
+		Secret:     secret,
+		OtpauthUri: totp.URI(t.issuer, username, secret),
+	}, nil
+}
+
+func (t *twoFactorUsecase) Confirm(username string, code string) (res.RecoveryCodes, error) {
+	if code == "" {
+		return res.RecoveryCodes{}, app_error.InvalidError("code is required")
+	}
+	codes, err := totp.GenerateRecoveryCodes()
+	if err != nil {
+		return res.RecoveryCodes{}, app_error.InternalServerError("Failed to generate recovery codes: " + err.Error())
+	}
+	hashes := make([]string, len(codes))
+	for i, recoveryCode := range codes {
+		hashes[i] = totp.HashRecoveryCode(recoveryCode)
+	}
+	if err = t.twoFactorRepository.Enable(username, code, hashes); err != nil {
+		return res.RecoveryCodes{}, err
+	}
+	return res.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// Disable shares the login attempt counter, so wrong codes sent here lock the
+// account the same way wrong codes sent to the login verification do.
+func (t *twoFactorUsecase) Disable(username string, code string, client authenticator.ClientInfo) error {
+	if code == "" {
+		return app_error.InvalidError("code is required")
+	}
+	if err := t.loginGuard.Attempt(username, client.Ip); err != nil {
+		return err
+	}
+
+	err := t.twoFactorRepository.Disable(username, code)
+	var appError *app_error.AppError
+	if errors.As(err, &appError) && appError.ErrorMessage == repository.InvalidTwoFactorCodeMessage {
+		return err
+	}
+	if err != nil {
+		if guardErr := t.loginGuard.Release(username, client.Ip); guardErr != nil {
+			return guardErr
+		}
+		return err
+	}
+	return t.loginGuard.Succeeded(username, client.Ip)
+}
+
+func NewTwoFactorUsecase(twoFactorRepository repository.TwoFactorRepository, loginGuard loginguard.LoginGuard, issuer string) TwoFactorUsecase {
+	return &twoFactorUsecase{
+		twoFactorRepository: twoFactorRepository,
+		loginGuard:          loginGuard,
+		issuer:              issuer,
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/utils/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type twoFactorRepoMock struct {
+	mock.Mock
+}
+
+func (t *twoFactorRepoMock) SaveSecret(username string, secret string) error {
+	args := t.Called(username, secret)
+	return args.Error(0)
+}
+
+func (t *twoFactorRepoMock) Enable(username string, code string, recoveryCodeHashes []string) error {
+	args := t.Called(username, code, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (t *twoFactorRepoMock) Disable(username string, code string) error {
+	args := t.Called(username, code)
+	return args.Error(0)
+}
+
+type TwoFactorUsecaseTestSuite struct {
+	twoFactorRepoMock *twoFactorRepoMock
+	loginGuardMock    *loginGuardMock
+	suite.Suite
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestEnroll_Success() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	suite.twoFactorRepoMock.On("SaveSecret", "dummyUsername", mock.Anything).Return(nil)
+	enrollment, err := twoFactorUsecase.Enroll("dummyUsername")
+	assert.Nil(suite.T(), err)
+	assert.NotEmpty(suite.T(), enrollment.Secret)
+	assert.True(suite.T(), strings.HasPrefix(enrollment.OtpauthUri, "otpauth://totp/Dummy%20Issuer:dummyUsername?"))
+	suite.twoFactorRepoMock.AssertCalled(suite.T(), "SaveSecret", "dummyUsername", enrollment.Secret)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestEnroll_FailedRepo() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	suite.twoFactorRepoMock.On("SaveSecret", "dummyUsername", mock.Anything).Return(errors.New("Failed"))
+	_, err := twoFactorUsecase.Enroll("dummyUsername")
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestConfirm_Success() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	var hashes []string
+	suite.twoFactorRepoMock.On("Enable", "dummyUsername", "123456", mock.Anything).Run(func(args mock.Arguments) {
+		hashes = args.Get(2).([]string)
+	}).Return(nil)
+	recoveryCodes, err := twoFactorUsecase.Confirm("dummyUsername", "123456")
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), recoveryCodes.RecoveryCodes, totp.RecoveryCodeCount)
+	for i, code := range recoveryCodes.RecoveryCodes {
+		assert.Equal(suite.T(), totp.HashRecoveryCode(code), hashes[i])
+	}
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestConfirm_FailedRepo() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	suite.twoFactorRepoMock.On("Enable", "dummyUsername", "123456", mock.Anything).Return(errors.New("Failed"))
+	recoveryCodes, err := twoFactorUsecase.Confirm("dummyUsername", "123456")
+	assert.NotNil(suite.T(), err)
+	assert.Empty(suite.T(), recoveryCodes.RecoveryCodes)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestDisable() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	suite.twoFactorRepoMock.On("Disable", "dummyUsername", "123456").Return(nil)
+	assert.Nil(suite.T(), twoFactorUsecase.Disable("dummyUsername", "123456", dummyClient))
+	suite.loginGuardMock.AssertCalled(suite.T(), "Succeeded", "dummyUsername", dummyClient.Ip)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Release", mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestDisable_WrongCodeRecordsFailure() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	suite.twoFactorRepoMock.On("Disable", "dummyUsername", "000000").Return(app_error.InvalidError(repository.InvalidTwoFactorCodeMessage))
+	err := twoFactorUsecase.Disable("dummyUsername", "000000", dummyClient)
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), repository.InvalidTwoFactorCodeMessage, appError.ErrorMessage)
+	suite.loginGuardMock.AssertCalled(suite.T(), "Attempt", "dummyUsername", dummyClient.Ip)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Release", mock.Anything, mock.Anything)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Succeeded", mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestDisable_FailedRepoReleasesAttempt() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	suite.twoFactorRepoMock.On("Disable", "dummyUsername", "123456").Return(errors.New("Failed"))
+	assert.NotNil(suite.T(), twoFactorUsecase.Disable("dummyUsername", "123456", dummyClient))
+	suite.loginGuardMock.AssertCalled(suite.T(), "Release", "dummyUsername", dummyClient.Ip)
+	suite.loginGuardMock.AssertNotCalled(suite.T(), "Succeeded", mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestDisable_FailedLockedOut() {
+	suite.loginGuardMock = new(loginGuardMock)
+	suite.loginGuardMock.On("Attempt", "dummyUsername", dummyClient.Ip).Return(app_error.TooManyRequests(""))
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	err := twoFactorUsecase.Disable("dummyUsername", "123456", dummyClient)
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusTooManyRequests, appError.ErrorType)
+	suite.twoFactorRepoMock.AssertNotCalled(suite.T(), "Disable", mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) TestFailedEmptyCode() {
+	twoFactorUsecase := NewTwoFactorUsecase(suite.twoFactorRepoMock, suite.loginGuardMock, "Dummy Issuer")
+	_, err := twoFactorUsecase.Confirm("dummyUsername", "")
+	assert.NotNil(suite.T(), err)
+	assert.NotNil(suite.T(), twoFactorUsecase.Disable("dummyUsername", "", dummyClient))
+	suite.twoFactorRepoMock.AssertNotCalled(suite.T(), "Enable", mock.Anything, mock.Anything, mock.Anything)
+	suite.twoFactorRepoMock.AssertNotCalled(suite.T(), "Disable", mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUsecaseTestSuite) SetupTest() {
+	suite.twoFactorRepoMock = new(twoFactorRepoMock)
+	suite.loginGuardMock = new(loginGuardMock)
+	suite.loginGuardMock.On("Attempt", mock.Anything, mock.Anything).Return(nil)
+	suite.loginGuardMock.On("Succeeded", mock.Anything, mock.Anything).Return(nil)
+	suite.loginGuardMock.On("Release", mock.Anything, mock.Anything).Return(nil)
+}
+
+func TestTwoFactorUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorUsecaseTestSuite))
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+)
+
+type WalletUsecase interface {
+	Open(request req.WalletRequest) (entity.Wallet, error)
+	List(customerUsername string) ([]res.Wallet, error)
+}
+
+type walletUsecase struct {
+	walletRepository repository.WalletRepository
+}
+
+func (w *walletUsecase) Open(request req.WalletRequest) (entity.Wallet, error) {
+	if money.ValidateCurrency(request.Currency) != nil {
+		return entity.Wallet{}, app_error.InvalidError("invalid currency")
+	}
+	return w.walletRepository.Open(entity.Wallet{
+		Username:  request.CustomerUsername,
+		Currency:  request.Currency,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+func (w *walletUsecase) List(customerUsername string) ([]res.Wallet, error) {
+	return w.walletRepository.FindByCustomer(customerUsername)
+}
+
+func NewWalletUsecase(walletRepository repository.WalletRepository) WalletUsecase {
+	return &walletUsecase{
+		walletRepository: walletRepository,
+	}
+}
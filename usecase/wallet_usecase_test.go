@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type walletRepoMock struct {
+	mock.Mock
+}
+
+func (w *walletRepoMock) Open(wallet entity.Wallet) (entity.Wallet, error) {
+	args := w.Called(wallet)
+	return args.Get(0).(entity.Wallet), args.Error(1)
+}
+
+func (w *walletRepoMock) FindByCustomer(customerUsername string) ([]res.Wallet, error) {
+	args := w.Called(customerUsername)
+	return args.Get(0).([]res.Wallet), args.Error(1)
+}
+
+type WalletUsecaseTestSuite struct {
+	suite.Suite
+	walletRepoMock *walletRepoMock
+}
+
+func (suite *WalletUsecaseTestSuite) TestOpen_Success() {
+	suite.walletRepoMock.On("Open", mock.MatchedBy(func(wallet entity.Wallet) bool {
+		return wallet.Username == "dummyUsername" && wallet.Currency == "USD" && !wallet.CreatedAt.IsZero()
+	})).Return(entity.Wallet{Username: "dummyUsername", Currency: "USD"}, nil)
+
+	wallet, err := NewWalletUsecase(suite.walletRepoMock).Open(req.WalletRequest{CustomerUsername: "dummyUsername", Currency: "USD"})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "USD", wallet.Currency)
+}
+
+func (suite *WalletUsecaseTestSuite) TestOpen_FailedInvalidCurrency() {
+	for _, currency := range []string{"", "usd", "US"} {
+		_, err := NewWalletUsecase(suite.walletRepoMock).Open(req.WalletRequest{CustomerUsername: "dummyUsername", Currency: currency})
+		assert.NotNil(suite.T(), err)
+	}
+	suite.walletRepoMock.AssertNotCalled(suite.T(), "Open", mock.Anything)
+}
+
+func (suite *WalletUsecaseTestSuite) TestList() {
+	wallets := []res.Wallet{{Currency: money.DefaultCurrency, Primary: true}}
+	suite.walletRepoMock.On("FindByCustomer", "dummyUsername").Return(wallets, nil)
+	suite.walletRepoMock.On("FindByCustomer", "unknownUsername").Return([]res.Wallet(nil), app_error.DataNotFound("customer not found"))
+
+	result, err := NewWalletUsecase(suite.walletRepoMock).List("dummyUsername")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), wallets, result)
+	_, err = NewWalletUsecase(suite.walletRepoMock).List("unknownUsername")
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *WalletUsecaseTestSuite) SetupTest() {
+	suite.walletRepoMock = new(walletRepoMock)
+}
+
+func TestWalletUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletUsecaseTestSuite))
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/febriansr/simple-payment-api/utils/egress"
+	"github.com/febriansr/simple-payment-api/utils/hmacsign"
+	"github.com/febriansr/simple-payment-api/utils/secretbox"
+)
+
+const (
+	WebhookIdHeader  = "X-Webhook-Id"
+	DeliveryIdHeader = "X-Delivery-Id"
+	EventTypeHeader  = "X-Event-Type"
+)
+
+const (
+	MaxWebhookUrlLength          = 2048
+	MaxWebhookRetryDelay         = 6 * time.Hour
+	DefaultWebhookMaxAttempts    = 8
+	DefaultWebhookRetryBaseDelay = 30 * time.Second
+	DefaultWebhookTimeout        = 10 * time.Second
+	webhookDispatchBatch         = 100
+)
+
+type WebhookUsecase interface {
+	Handle(event entity.OutboxEvent) error
+	Register(request req.WebhookRequest) (res.WebhookSecret, error)
+	List(merchantCode string) ([]res.Webhook, error)
+	Disable(merchantCode string, webhookId string) error
+	Deliveries(merchantCode string, status string) ([]entity.WebhookDelivery, error)
+	Redeliver(merchantCode string, deliveryId string) (entity.WebhookDelivery, error)
+	Dispatch() (int, error)
+}
+
+type webhookUsecase struct {
+	webhookRepository repository.WebhookRepository
+	policy            egress.Policy
+	client            *http.Client
+	secretBox         secretbox.SecretBox
+	maxAttempts       int
+	retryBaseDelay    time.Duration
+}
+
+func (w *webhookUsecase) Register(request req.WebhookRequest) (res.WebhookSecret, error) {
+	if request.MerchantCode == "" {
+		return res.WebhookSecret{}, app_error.InvalidError("merchant code is required")
+	}
+	if len(request.Url) > MaxWebhookUrlLength {
+		return res.WebhookSecret{}, app_error.InvalidError("url is too long")
+	}
+	target, err := url.Parse(request.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return res.WebhookSecret{}, app_error.InvalidError("invalid url")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.client.Timeout)
+	defer cancel()
+	if err = w.policy.CheckHost(ctx, target.Hostname()); err != nil {
+		if errors.Is(err, egress.ErrForbiddenAddress) {
+			return res.WebhookSecret{}, app_error.InvalidError("url must resolve to a public address")
+		}
+		return res.WebhookSecret{}, app_error.InvalidError("url host cannot be resolved")
+	}
+	if len(request.EventTypes) == 0 {
+		return res.WebhookSecret{}, app_error.InvalidError("event types are required")
+	}
+	for _, eventType := range request.EventTypes {
+		if !knownEventType(eventType) {
+			return res.WebhookSecret{}, app_error.InvalidError("unknown event type " + eventType)
+		}
+	}
+	webhookId, err := randomToken(16)
+	if err != nil {
+		return res.WebhookSecret{}, err
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return res.WebhookSecret{}, err
+	}
+	plainSecret := "whsec_" + base64.RawURLEncoding.EncodeToString(secret)
+	encryptedSigningKey, err := w.secretBox.Seal(hmacsign.SigningKey(plainSecret))
+	if err != nil {
+		return res.WebhookSecret{}, err
+	}
+	webhook := entity.Webhook{
+		WebhookId:           "wh_" + hex.EncodeToString(webhookId),
+		MerchantCode:        request.MerchantCode,
+		Url:                 request.Url,
+		EventTypes:          request.EventTypes,
+		EncryptedSigningKey: encryptedSigningKey,
+		CreatedAt:           time.Now().UTC(),
+	}
+	if err = w.webhookRepository.Create(webhook); err != nil {
+		return res.WebhookSecret{}, err
+	}
+	return res.WebhookSecret{Webhook: webhookResponse(webhook), Secret: plainSecret}, nil
+}
+
+func (w *webhookUsecase) List(merchantCode string) ([]res.Webhook, error) {
+	webhooks, err := w.webhookRepository.FindByMerchant(merchantCode)
+	if err != nil {
+		return nil, err
+	}
+	response := []res.Webhook{}
+	for _, webhook := range webhooks {
+		response = append(response, webhookResponse(webhook))
+	}
+	return response, nil
+}
+
+func (w *webhookUsecase) Disable(merchantCode string, webhookId string) error {
+	return w.webhookRepository.Disable(merchantCode, webhookId)
+}
+
+func (w *webhookUsecase) Deliveries(merchantCode string, status string) ([]entity.WebhookDelivery, error) {
+	switch status {
+	case "", entity.DeliveryStatusPending, entity.DeliveryStatusSucceeded, entity.DeliveryStatusDead:
+	default:
+		return nil, app_error.InvalidError("invalid status")
+	}
+	return w.webhookRepository.FindDeliveries(merchantCode, status)
+}
+
+func (w *webhookUsecase) Redeliver(merchantCode string, deliveryId string) (entity.WebhookDelivery, error) {
+	return w.webhookRepository.Redeliver(merchantCode, deliveryId)
+}
+
+func (w *webhookUsecase) Handle(event entity.OutboxEvent) error {
+	if event.MerchantCode == "" {
+		return nil
+	}
+	_, err := w.webhookRepository.Enqueue(event.Event)
+	return err
+}
+
+func (w *webhookUsecase) Dispatch() (int, error) {
+	deliveries, err := w.webhookRepository.FindDue(webhookDispatchBatch)
+	if err != nil {
+		return 0, err
+	}
+	var delivered int
+	for _, delivery := range deliveries {
+		delivery = w.attempt(delivery)
+		if err = w.webhookRepository.UpdateDelivery(delivery); err != nil {
+			return delivered, err
+		}
+		if delivery.Status == entity.DeliveryStatusSucceeded {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+func (w *webhookUsecase) attempt(delivery entity.WebhookDelivery) entity.WebhookDelivery {
+	now := time.Now().UTC()
+	webhook, err := w.webhookRepository.FindById(delivery.WebhookId)
+	if err != nil || !webhook.Active() {
+		delivery.Status = entity.DeliveryStatusDead
+		delivery.LastError = "webhook is disabled"
+		return delivery
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.LastStatusCode, err = w.send(webhook, delivery, now)
+	if err == nil {
+		delivery.Status = entity.DeliveryStatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return delivery
+	}
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= w.maxAttempts {
+		delivery.Status = entity.DeliveryStatusDead
+		return delivery
+	}
+	delivery.NextAttemptAt = now.Add(w.retryDelay(delivery.Attempts))
+	return delivery
+}
+
+func (w *webhookUsecase) send(webhook entity.Webhook, delivery entity.WebhookDelivery, now time.Time) (int, error) {
+	target, err := url.Parse(webhook.Url)
+	if err != nil {
+		return 0, err
+	}
+	signingKey := webhook.EncryptedSigningKey
+	if secretbox.Sealed(signingKey) {
+		if signingKey, err = w.secretBox.Open(signingKey); err != nil {
+			return 0, err
+		}
+	}
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	request, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookIdHeader, webhook.WebhookId)
+	request.Header.Set(DeliveryIdHeader, delivery.DeliveryId)
+	request.Header.Set(EventTypeHeader, delivery.EventType)
+	request.Header.Set(hmacsign.TimestampHeader, timestamp)
+	request.Header.Set(hmacsign.SignatureHeader, hmacsign.Sign(signingKey,
+		hmacsign.StringToSign(http.MethodPost, target.RequestURI(), timestamp, delivery.DeliveryId, body)))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+func (w *webhookUsecase) retryDelay(attempts int) time.Duration {
+	delay := w.retryBaseDelay
+	for i := 1; i < attempts && delay < MaxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > MaxWebhookRetryDelay {
+		return MaxWebhookRetryDelay
+	}
+	return delay
+}
+
+func knownEventType(eventType string) bool {
+	for _, known := range entity.EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookResponse(webhook entity.Webhook) res.Webhook {
+	return res.Webhook{
+		WebhookId:  webhook.WebhookId,
+		Url:        webhook.Url,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active(),
+		CreatedAt:  webhook.CreatedAt,
+		DisabledAt: webhook.DisabledAt,
+	}
+}
+
+func NewWebhookUsecase(webhookRepository repository.WebhookRepository, webhookConfig config.WebhookConfig, secretBox secretbox.SecretBox) WebhookUsecase {
+	if webhookConfig.MaxAttempts <= 0 {
+		webhookConfig.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if webhookConfig.RetryBaseDelay <= 0 {
+		webhookConfig.RetryBaseDelay = DefaultWebhookRetryBaseDelay
+	}
+	if webhookConfig.Timeout <= 0 {
+		webhookConfig.Timeout = DefaultWebhookTimeout
+	}
+	policy := egress.NewPolicy(webhookConfig.AllowedNetworks)
+	return &webhookUsecase{
+		webhookRepository: webhookRepository,
+		policy:            policy,
+		client:            policy.Client(webhookConfig.Timeout),
+		maxAttempts:       webhookConfig.MaxAttempts,
+		retryBaseDelay:    webhookConfig.RetryBaseDelay,
+		secretBox:         secretBox,
+	}
+}
@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/utils/hmacsign"
+	"github.com/febriansr/simple-payment-api/utils/secretbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type webhookRepoMock struct {
+	mock.Mock
+}
+
+func (w *webhookRepoMock) Create(webhook entity.Webhook) error {
+	args := w.Called(webhook)
+	return args.Error(0)
+}
+
+func (w *webhookRepoMock) FindByMerchant(merchantCode string) ([]entity.Webhook, error) {
+	args := w.Called(merchantCode)
+	return args.Get(0).([]entity.Webhook), args.Error(1)
+}
+
+func (w *webhookRepoMock) FindById(webhookId string) (entity.Webhook, error) {
+	args := w.Called(webhookId)
+	return args.Get(0).(entity.Webhook), args.Error(1)
+}
+
+func (w *webhookRepoMock) Disable(merchantCode string, webhookId string) error {
+	args := w.Called(merchantCode, webhookId)
+	return args.Error(0)
+}
+
+func (w *webhookRepoMock) Enqueue(event entity.Event) (int, error) {
+	args := w.Called(event)
+	return args.Int(0), args.Error(1)
+}
+
+func (w *webhookRepoMock) FindDeliveries(merchantCode string, status string) ([]entity.WebhookDelivery, error) {
+	args := w.Called(merchantCode, status)
+	return args.Get(0).([]entity.WebhookDelivery), args.Error(1)
+}
+
+func (w *webhookRepoMock) FindDue(limit int) ([]entity.WebhookDelivery, error) {
+	args := w.Called(limit)
+	return args.Get(0).([]entity.WebhookDelivery), args.Error(1)
+}
+
+func (w *webhookRepoMock) UpdateDelivery(delivery entity.WebhookDelivery) error {
+	args := w.Called(delivery)
+	return args.Error(0)
+}
+
+func (w *webhookRepoMock) Redeliver(merchantCode string, deliveryId string) (entity.WebhookDelivery, error) {
+	args := w.Called(merchantCode, deliveryId)
+	return args.Get(0).(entity.WebhookDelivery), args.Error(1)
+}
+
+var receiverNetworks = []string{"127.0.0.1/32"}
+
+type WebhookUsecaseTestSuite struct {
+	suite.Suite
+	webhookRepoMock *webhookRepoMock
+	receivedHeader  http.Header
+	receivedBody    []byte
+	receiverStatus  int
+	receiver        *httptest.Server
+}
+
+func (suite *WebhookUsecaseTestSuite) dummyWebhook(secret string) entity.Webhook {
+	encryptedSigningKey, _ := dummySecretBox.Seal(hmacsign.SigningKey(secret))
+	return entity.Webhook{
+		WebhookId:           "wh_dummy",
+		MerchantCode:        "MRC125",
+		Url:                 suite.receiver.URL + "/hooks?source=payment",
+		EventTypes:          []string{entity.EventPaymentSucceeded},
+		EncryptedSigningKey: encryptedSigningKey,
+	}
+}
+
+func dummyDelivery() entity.WebhookDelivery {
+	return entity.WebhookDelivery{
+		DeliveryId:   "Dummy Delivery Id",
+		WebhookId:    "wh_dummy",
+		MerchantCode: "MRC125",
+		EventType:    entity.EventPaymentSucceeded,
+		Payload:      `{"event_id":"Dummy Event Id"}`,
+		Status:       entity.DeliveryStatusPending,
+	}
+}
+
+func (suite *WebhookUsecaseTestSuite) TestRegister_Success() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, dummySecretBox)
+	var created entity.Webhook
+	suite.webhookRepoMock.On("Create", mock.MatchedBy(func(webhook entity.Webhook) bool {
+		return strings.HasPrefix(webhook.WebhookId, "wh_") && webhook.MerchantCode == "MRC125"
+	})).Run(func(args mock.Arguments) {
+		created = args.Get(0).(entity.Webhook)
+	}).Return(nil)
+
+	result, err := webhookUsecase.Register(req.WebhookRequest{
+		MerchantCode: "MRC125",
+		Url:          "https://203.0.113.10/hooks",
+		EventTypes:   []string{entity.EventPaymentSucceeded, entity.EventRefundCreated},
+	})
+
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(result.Secret, "whsec_"))
+	assert.True(suite.T(), result.Active)
+	assert.Equal(suite.T(), "https://203.0.113.10/hooks", result.Url)
+	assert.True(suite.T(), secretbox.Sealed(created.EncryptedSigningKey))
+	signingKey, err := dummySecretBox.Open(created.EncryptedSigningKey)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), hmacsign.SigningKey(result.Secret), signingKey)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestRegister_FailedEncryptionKeyMissing() {
+	_, err := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, secretbox.NewSecretBox("")).Register(req.WebhookRequest{
+		MerchantCode: "MRC125",
+		Url:          "https://203.0.113.10/hooks",
+		EventTypes:   []string{entity.EventPaymentSucceeded},
+	})
+
+	assert.NotNil(suite.T(), err)
+	suite.webhookRepoMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestRegister_FailedInvalidRequest() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, dummySecretBox)
+	requests := []req.WebhookRequest{
+		{Url: "https://203.0.113.10/hooks", EventTypes: []string{entity.EventPaymentSucceeded}},
+		{MerchantCode: "MRC125", Url: "ftp://merchant.example/hooks", EventTypes: []string{entity.EventPaymentSucceeded}},
+		{MerchantCode: "MRC125", Url: "merchant.example/hooks", EventTypes: []string{entity.EventPaymentSucceeded}},
+		{MerchantCode: "MRC125", Url: "https://203.0.113.10/" + strings.Repeat("x", MaxWebhookUrlLength), EventTypes: []string{entity.EventPaymentSucceeded}},
+		{MerchantCode: "MRC125", Url: "https://203.0.113.10/hooks"},
+		{MerchantCode: "MRC125", Url: "https://203.0.113.10/hooks", EventTypes: []string{"payment.unknown"}},
+	}
+	for _, request := range requests {
+		_, err := webhookUsecase.Register(request)
+		assert.NotNil(suite.T(), err)
+	}
+	suite.webhookRepoMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestRegister_FailedInternalAddress() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, dummySecretBox)
+	for _, url := range []string{"http://127.0.0.1/hooks", "http://10.0.0.1/hooks", "http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hooks", "http://0.0.0.0/hooks", "http://localhost:8080/hooks"} {
+		_, err := webhookUsecase.Register(req.WebhookRequest{MerchantCode: "MRC125", Url: url, EventTypes: []string{entity.EventPaymentSucceeded}})
+		assert.NotNil(suite.T(), err, url)
+	}
+	suite.webhookRepoMock.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestRegister_AllowedNetwork() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{AllowedNetworks: receiverNetworks}, dummySecretBox)
+	suite.webhookRepoMock.On("Create", mock.Anything).Return(nil)
+
+	_, err := webhookUsecase.Register(req.WebhookRequest{MerchantCode: "MRC125", Url: suite.receiver.URL + "/hooks", EventTypes: []string{entity.EventPaymentSucceeded}})
+
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDeliveries_FailedInvalidStatus() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, dummySecretBox)
+
+	_, err := webhookUsecase.Deliveries("MRC125", "unknown")
+
+	assert.NotNil(suite.T(), err)
+	suite.webhookRepoMock.AssertNotCalled(suite.T(), "FindDeliveries", mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestHandle() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, dummySecretBox)
+	event := entity.Event{EventId: "Dummy Event Id", Type: entity.EventPaymentSucceeded, MerchantCode: "MRC125"}
+	suite.webhookRepoMock.On("Enqueue", event).Return(1, nil)
+
+	err := webhookUsecase.Handle(entity.OutboxEvent{Sequence: 1, Event: event})
+
+	assert.Nil(suite.T(), err)
+	suite.webhookRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUsecaseTestSuite) TestHandle_FailedRepo() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, dummySecretBox)
+	suite.webhookRepoMock.On("Enqueue", mock.Anything).Return(0, errors.New("failed"))
+
+	err := webhookUsecase.Handle(entity.OutboxEvent{Sequence: 1, Event: entity.Event{MerchantCode: "MRC125"}})
+
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_Success() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{AllowedNetworks: receiverNetworks}, dummySecretBox)
+	webhook := suite.dummyWebhook("whsec_dummy")
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{dummyDelivery()}, nil)
+	suite.webhookRepoMock.On("FindById", "wh_dummy").Return(webhook, nil)
+	suite.webhookRepoMock.On("UpdateDelivery", mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		return delivery.Status == entity.DeliveryStatusSucceeded && delivery.Attempts == 1 &&
+			delivery.LastStatusCode == http.StatusOK && delivery.DeliveredAt != nil
+	})).Return(nil)
+
+	delivered, err := webhookUsecase.Dispatch()
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, delivered)
+	assert.Equal(suite.T(), `{"event_id":"Dummy Event Id"}`, string(suite.receivedBody))
+	assert.Equal(suite.T(), "wh_dummy", suite.receivedHeader.Get(WebhookIdHeader))
+	assert.Equal(suite.T(), entity.EventPaymentSucceeded, suite.receivedHeader.Get(EventTypeHeader))
+	stringToSign := hmacsign.StringToSign(http.MethodPost, "/hooks?source=payment", suite.receivedHeader.Get(hmacsign.TimestampHeader),
+		suite.receivedHeader.Get(DeliveryIdHeader), suite.receivedBody)
+	assert.True(suite.T(), hmacsign.Verify(hmacsign.SigningKey("whsec_dummy"), stringToSign, suite.receivedHeader.Get(hmacsign.SignatureHeader)))
+	assert.False(suite.T(), hmacsign.Verify(hmacsign.SigningKey("whsec_other"), stringToSign, suite.receivedHeader.Get(hmacsign.SignatureHeader)))
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_PlainSigningKey() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{AllowedNetworks: receiverNetworks}, dummySecretBox)
+	webhook := suite.dummyWebhook("whsec_dummy")
+	webhook.EncryptedSigningKey = hmacsign.SigningKey("whsec_dummy")
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{dummyDelivery()}, nil)
+	suite.webhookRepoMock.On("FindById", "wh_dummy").Return(webhook, nil)
+	suite.webhookRepoMock.On("UpdateDelivery", mock.Anything).Return(nil)
+
+	delivered, err := webhookUsecase.Dispatch()
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, delivered)
+	stringToSign := hmacsign.StringToSign(http.MethodPost, "/hooks?source=payment", suite.receivedHeader.Get(hmacsign.TimestampHeader),
+		suite.receivedHeader.Get(DeliveryIdHeader), suite.receivedBody)
+	assert.True(suite.T(), hmacsign.Verify(hmacsign.SigningKey("whsec_dummy"), stringToSign, suite.receivedHeader.Get(hmacsign.SignatureHeader)))
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_RetryWithBackoff() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{MaxAttempts: 3, RetryBaseDelay: time.Minute, AllowedNetworks: receiverNetworks}, dummySecretBox)
+	suite.receiverStatus = http.StatusInternalServerError
+	delivery := dummyDelivery()
+	delivery.Attempts = 1
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{delivery}, nil)
+	suite.webhookRepoMock.On("FindById", "wh_dummy").Return(suite.dummyWebhook("whsec_dummy"), nil)
+	suite.webhookRepoMock.On("UpdateDelivery", mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		delay := time.Until(delivery.NextAttemptAt)
+		return delivery.Status == entity.DeliveryStatusPending && delivery.Attempts == 2 &&
+			delivery.LastStatusCode == http.StatusInternalServerError && delivery.LastError != "" &&
+			delay > time.Minute && delay <= 2*time.Minute
+	})).Return(nil)
+
+	delivered, err := webhookUsecase.Dispatch()
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0, delivered)
+	suite.webhookRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_RefusesInternalAddress() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{}, dummySecretBox)
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{dummyDelivery()}, nil)
+	suite.webhookRepoMock.On("FindById", "wh_dummy").Return(suite.dummyWebhook("whsec_dummy"), nil)
+	suite.webhookRepoMock.On("UpdateDelivery", mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		return delivery.Status == entity.DeliveryStatusPending && delivery.Attempts == 1 &&
+			delivery.LastStatusCode == 0 && strings.Contains(delivery.LastError, "address is not allowed")
+	})).Return(nil)
+
+	delivered, err := webhookUsecase.Dispatch()
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0, delivered)
+	assert.Nil(suite.T(), suite.receivedBody)
+	suite.webhookRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_DoesNotFollowRedirects() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{AllowedNetworks: receiverNetworks}, dummySecretBox)
+	suite.receiverStatus = http.StatusFound
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{dummyDelivery()}, nil)
+	suite.webhookRepoMock.On("FindById", "wh_dummy").Return(suite.dummyWebhook("whsec_dummy"), nil)
+	suite.webhookRepoMock.On("UpdateDelivery", mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		return delivery.Status == entity.DeliveryStatusPending && delivery.LastStatusCode == http.StatusFound
+	})).Return(nil)
+
+	delivered, err := webhookUsecase.Dispatch()
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0, delivered)
+	suite.webhookRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_DeadAfterMaxAttempts() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{MaxAttempts: 3, AllowedNetworks: receiverNetworks}, dummySecretBox)
+	suite.receiverStatus = http.StatusBadGateway
+	delivery := dummyDelivery()
+	delivery.Attempts = 2
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{delivery}, nil)
+	suite.webhookRepoMock.On("FindById", "wh_dummy").Return(suite.dummyWebhook("whsec_dummy"), nil)
+	suite.webhookRepoMock.On("UpdateDelivery", mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		return delivery.Status == entity.DeliveryStatusDead && delivery.Attempts == 3 && delivery.LastStatusCode == http.StatusBadGateway
+	})).Return(nil)
+
+	_, err := webhookUsecase.Dispatch()
+
+	assert.Nil(suite.T(), err)
+	suite.webhookRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_DeadWhenWebhookDisabled() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{AllowedNetworks: receiverNetworks}, dummySecretBox)
+	webhook := suite.dummyWebhook("whsec_dummy")
+	disabledAt := time.Now()
+	webhook.DisabledAt = &disabledAt
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{dummyDelivery()}, nil)
+	suite.webhookRepoMock.On("FindById", "wh_dummy").Return(webhook, nil)
+	suite.webhookRepoMock.On("UpdateDelivery", mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		return delivery.Status == entity.DeliveryStatusDead && delivery.Attempts == 0
+	})).Return(nil)
+
+	_, err := webhookUsecase.Dispatch()
+
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), suite.receivedBody)
+	suite.webhookRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUsecaseTestSuite) TestDispatch_FailedRepo() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{AllowedNetworks: receiverNetworks}, dummySecretBox)
+	suite.webhookRepoMock.On("FindDue", webhookDispatchBatch).Return([]entity.WebhookDelivery{}, errors.New("failed"))
+
+	_, err := webhookUsecase.Dispatch()
+
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestRetryDelay() {
+	webhookUsecase := NewWebhookUsecase(suite.webhookRepoMock, config.WebhookConfig{RetryBaseDelay: time.Minute}, dummySecretBox).(*webhookUsecase)
+
+	assert.Equal(suite.T(), time.Minute, webhookUsecase.retryDelay(1))
+	assert.Equal(suite.T(), 4*time.Minute, webhookUsecase.retryDelay(3))
+	assert.Equal(suite.T(), MaxWebhookRetryDelay, webhookUsecase.retryDelay(30))
+}
+
+func (suite *WebhookUsecaseTestSuite) SetupTest() {
+	suite.webhookRepoMock = new(webhookRepoMock)
+	suite.receivedHeader = nil
+	suite.receivedBody = nil
+	suite.receiverStatus = http.StatusOK
+	suite.receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.receivedHeader = r.Header
+		suite.receivedBody, _ = io.ReadAll(r.Body)
+		if suite.receiverStatus == http.StatusFound {
+			w.Header().Set("Location", "/redirected")
+		}
+		w.WriteHeader(suite.receiverStatus)
+		json.NewEncoder(w).Encode(map[string]string{"status": "received"})
+	}))
+}
+
+func (suite *WebhookUsecaseTestSuite) TearDownTest() {
+	suite.receiver.Close()
+}
+
+func TestWebhookUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookUsecaseTestSuite))
+}
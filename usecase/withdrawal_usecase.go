@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/febriansr/simple-payment-api/repository"
+	"github.com/google/uuid"
+)
+
+const MaxAccountNameLength = 100
+
+var bankAccountNumberLengths = map[string]int{
+	"002": 15, // BRI
+	"008": 13, // Mandiri
+	"009": 10, // BNI
+	"014": 10, // BCA
+	"451": 10, // BSI
+}
+
+type WithdrawalUsecase interface {
+	AddBankAccount(request req.BankAccountRequest) (entity.BankAccount, error)
+	ListBankAccounts(ownerType string, owner string) ([]entity.BankAccount, error)
+	Request(request req.WithdrawalRequest, authorization req.PaymentAuthorization) (entity.Withdrawal, error)
+	List(ownerType string, owner string) ([]entity.Withdrawal, error)
+}
+
+type withdrawalUsecase struct {
+	withdrawalRepository repository.WithdrawalRepository
+	pinUsecase           PinUsecase
+	minAmount            money.Amount
+}
+
+func (w *withdrawalUsecase) AddBankAccount(request req.BankAccountRequest) (entity.BankAccount, error) {
+	length, ok := bankAccountNumberLengths[request.BankCode]
+	if !ok {
+		return entity.BankAccount{}, app_error.InvalidError("unsupported bank code")
+	}
+	if len(request.AccountNumber) != length || strings.IndexFunc(request.AccountNumber, notDigit) >= 0 {
+		return entity.BankAccount{}, app_error.InvalidError("invalid account number for the bank")
+	}
+	accountName := strings.Join(strings.Fields(request.AccountName), " ")
+	if accountName == "" || len(accountName) > MaxAccountNameLength || strings.IndexFunc(accountName, unicode.IsControl) >= 0 {
+		return entity.BankAccount{}, app_error.InvalidError("invalid account name")
+	}
+
+	return w.withdrawalRepository.AddBankAccount(entity.BankAccount{
+		BankAccountId: uuid.New().String(),
+		OwnerType:     request.OwnerType,
+		Owner:         request.Owner,
+		BankCode:      request.BankCode,
+		AccountNumber: request.AccountNumber,
+		AccountName:   accountName,
+		CreatedAt:     time.Now().UTC(),
+	})
+}
+
+func (w *withdrawalUsecase) ListBankAccounts(ownerType string, owner string) ([]entity.BankAccount, error) {
+	return w.withdrawalRepository.FindBankAccounts(ownerType, owner)
+}
+
+func (w *withdrawalUsecase) Request(request req.WithdrawalRequest, authorization req.PaymentAuthorization) (entity.Withdrawal, error) {
+	if request.BankAccountId == "" {
+		return entity.Withdrawal{}, app_error.InvalidError("bank account id is required")
+	}
+	if request.Amount <= 0 {
+		return entity.Withdrawal{}, app_error.InvalidError("invalid amount")
+	}
+	if request.OwnerType == entity.OwnerTypeCustomer {
+		if err := w.pinUsecase.AuthorizeAmount(request.Owner, request.Amount, "", authorization); err != nil {
+			return entity.Withdrawal{}, err
+		}
+	}
+
+	return w.withdrawalRepository.Request(entity.Withdrawal{
+		WithdrawalId:  uuid.New().String(),
+		OwnerType:     request.OwnerType,
+		Owner:         request.Owner,
+		BankAccountId: request.BankAccountId,
+		Amount:        request.Amount,
+		Status:        entity.WithdrawalStatusPending,
+		CreatedAt:     time.Now().UTC(),
+	}, w.minAmount)
+}
+
+func (w *withdrawalUsecase) List(ownerType string, owner string) ([]entity.Withdrawal, error) {
+	return w.withdrawalRepository.FindByOwner(ownerType, owner)
+}
+
+func notDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
+
+func NewWithdrawalUsecase(withdrawalRepository repository.WithdrawalRepository, pinUsecase PinUsecase, minAmount money.Amount) WithdrawalUsecase {
+	return &withdrawalUsecase{
+		withdrawalRepository: withdrawalRepository,
+		pinUsecase:           pinUsecase,
+		minAmount:            minAmount,
+	}
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/febriansr/simple-payment-api/model/dto/req"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/febriansr/simple-payment-api/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyWithdrawalRequest = req.WithdrawalRequest{
+	OwnerType:     entity.OwnerTypeCustomer,
+	Owner:         "dummyUsername",
+	BankAccountId: "Dummy Bank Account Id",
+	Amount:        money.MustParse("50000"),
+}
+
+type withdrawalRepoMock struct {
+	mock.Mock
+}
+
+func (w *withdrawalRepoMock) AddBankAccount(bankAccount entity.BankAccount) (entity.BankAccount, error) {
+	args := w.Called(bankAccount)
+	return args.Get(0).(entity.BankAccount), args.Error(1)
+}
+
+func (w *withdrawalRepoMock) FindBankAccounts(ownerType string, owner string) ([]entity.BankAccount, error) {
+	args := w.Called(ownerType, owner)
+	return args.Get(0).([]entity.BankAccount), args.Error(1)
+}
+
+func (w *withdrawalRepoMock) Request(withdrawal entity.Withdrawal, minAmount money.Amount) (entity.Withdrawal, error) {
+	args := w.Called(withdrawal, minAmount)
+	return args.Get(0).(entity.Withdrawal), args.Error(1)
+}
+
+func (w *withdrawalRepoMock) FindByOwner(ownerType string, owner string) ([]entity.Withdrawal, error) {
+	args := w.Called(ownerType, owner)
+	return args.Get(0).([]entity.Withdrawal), args.Error(1)
+}
+
+func (w *withdrawalRepoMock) CreateBatches(limit int) ([]entity.PayoutBatch, error) {
+	args := w.Called(limit)
+	return args.Get(0).([]entity.PayoutBatch), args.Error(1)
+}
+
+func (w *withdrawalRepoMock) FindBatches() ([]entity.PayoutBatch, error) {
+	args := w.Called()
+	return args.Get(0).([]entity.PayoutBatch), args.Error(1)
+}
+
+func (w *withdrawalRepoMock) FindBatch(batchId string) (entity.PayoutBatch, []entity.Withdrawal, error) {
+	args := w.Called(batchId)
+	return args.Get(0).(entity.PayoutBatch), args.Get(1).([]entity.Withdrawal), args.Error(2)
+}
+
+func (w *withdrawalRepoMock) Settle(batchId string, results []entity.PayoutResult) (entity.PayoutBatch, error) {
+	args := w.Called(batchId, results)
+	return args.Get(0).(entity.PayoutBatch), args.Error(1)
+}
+
+type WithdrawalUsecaseTestSuite struct {
+	suite.Suite
+	withdrawalRepoMock *withdrawalRepoMock
+	pinUsecaseMock     *pinUsecaseMock
+}
+
+func (suite *WithdrawalUsecaseTestSuite) TestAddBankAccount_Success() {
+	withdrawalUsecase := NewWithdrawalUsecase(suite.withdrawalRepoMock, suite.pinUsecaseMock, 0)
+	suite.withdrawalRepoMock.On("AddBankAccount", mock.MatchedBy(func(bankAccount entity.BankAccount) bool {
+		return bankAccount.BankAccountId != "" && bankAccount.OwnerType == entity.OwnerTypeCustomer && bankAccount.Owner == "dummyUsername" &&
+			bankAccount.BankCode == "014" && bankAccount.AccountNumber == "1234567890" && bankAccount.AccountName == "Dummy Customer"
+	})).Return(entity.BankAccount{BankAccountId: "Dummy Bank Account Id"}, nil)
+
+	bankAccount, err := withdrawalUsecase.AddBankAccount(req.BankAccountRequest{
+		OwnerType:     entity.OwnerTypeCustomer,
+		Owner:         "dummyUsername",
+		BankCode:      "014",
+		AccountNumber: "1234567890",
+		AccountName:   "  Dummy   Customer ",
+	})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "Dummy Bank Account Id", bankAccount.BankAccountId)
+}
+
+func (suite *WithdrawalUsecaseTestSuite) TestAddBankAccount_FailedInvalidFormat() {
+	withdrawalUsecase := NewWithdrawalUsecase(suite.withdrawalRepoMock, suite.pinUsecaseMock, 0)
+	requests := []req.BankAccountRequest{
+		{BankCode: "999", AccountNumber: "1234567890", AccountName: "Dummy"},
+		{BankCode: "014", AccountNumber: "123456789", AccountName: "Dummy"},
+		{BankCode: "014", AccountNumber: "12345678901", AccountName: "Dummy"},
+		{BankCode: "014", AccountNumber: "12345678a0", AccountName: "Dummy"},
+		{BankCode: "008", AccountNumber: "1234567890", AccountName: "Dummy"},
+		{BankCode: "014", AccountNumber: "1234567890", AccountName: "   "},
+		{BankCode: "014", AccountNumber: "1234567890", AccountName: strings.Repeat("x", MaxAccountNameLength+1)},
+		{BankCode: "014", AccountNumber: "1234567890", AccountName: "Dummy\x00"},
+	}
+	for _, request := range requests {
+		_, err := withdrawalUsecase.AddBankAccount(request)
+		assert.NotNil(suite.T(), err, request)
+	}
+	suite.withdrawalRepoMock.AssertNotCalled(suite.T(), "AddBankAccount", mock.Anything)
+}
+
+func (suite *WithdrawalUsecaseTestSuite) TestRequest_Success() {
+	withdrawalUsecase := NewWithdrawalUsecase(suite.withdrawalRepoMock, suite.pinUsecaseMock, money.MustParse("10000"))
+	suite.withdrawalRepoMock.On("Request", mock.MatchedBy(func(withdrawal entity.Withdrawal) bool {
+		return withdrawal.WithdrawalId != "" && withdrawal.Owner == "dummyUsername" && withdrawal.BankAccountId == "Dummy Bank Account Id" &&
+			withdrawal.Amount == money.MustParse("50000") && withdrawal.Status == entity.WithdrawalStatusPending
+	}), money.MustParse("10000")).Return(entity.Withdrawal{WithdrawalId: "Dummy Withdrawal Id"}, nil)
+
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyWithdrawalRequest.Amount, "", req.PaymentAuthorization{}).Return(nil)
+
+	withdrawal, err := withdrawalUsecase.Request(dummyWithdrawalRequest, req.PaymentAuthorization{})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "Dummy Withdrawal Id", withdrawal.WithdrawalId)
+}
+
+func (suite *WithdrawalUsecaseTestSuite) TestRequest_FailedInvalidRequest() {
+	withdrawalUsecase := NewWithdrawalUsecase(suite.withdrawalRepoMock, suite.pinUsecaseMock, money.MustParse("10000"))
+	requests := []req.WithdrawalRequest{
+		{OwnerType: entity.OwnerTypeCustomer, Owner: "dummyUsername", Amount: money.MustParse("50000")},
+		{OwnerType: entity.OwnerTypeCustomer, Owner: "dummyUsername", BankAccountId: "Dummy Bank Account Id"},
+		{OwnerType: entity.OwnerTypeCustomer, Owner: "dummyUsername", BankAccountId: "Dummy Bank Account Id", Amount: money.MustParse("-1")},
+	}
+	for _, request := range requests {
+		_, err := withdrawalUsecase.Request(request, req.PaymentAuthorization{})
+		assert.NotNil(suite.T(), err)
+	}
+	suite.withdrawalRepoMock.AssertNotCalled(suite.T(), "Request", mock.Anything, mock.Anything)
+}
+
+func (suite *WithdrawalUsecaseTestSuite) TestRequest_FailedPin() {
+	withdrawalUsecase := NewWithdrawalUsecase(suite.withdrawalRepoMock, suite.pinUsecaseMock, 0)
+	authorization := req.PaymentAuthorization{Pin: "000000"}
+	suite.pinUsecaseMock.On("AuthorizeAmount", "dummyUsername", dummyWithdrawalRequest.Amount, "", authorization).Return(errors.New("failed"))
+
+	_, err := withdrawalUsecase.Request(dummyWithdrawalRequest, authorization)
+
+	assert.NotNil(suite.T(), err)
+	suite.withdrawalRepoMock.AssertNotCalled(suite.T(), "Request", mock.Anything, mock.Anything)
+}
+
+func (suite *WithdrawalUsecaseTestSuite) TestRequest_MerchantSkipsPin() {
+	withdrawalUsecase := NewWithdrawalUsecase(suite.withdrawalRepoMock, suite.pinUsecaseMock, 0)
+	request := dummyWithdrawalRequest
+	request.OwnerType = entity.OwnerTypeMerchant
+	request.Owner = "MRC125"
+	suite.withdrawalRepoMock.On("Request", mock.Anything, mock.Anything).Return(entity.Withdrawal{WithdrawalId: "Dummy Withdrawal Id"}, nil)
+
+	_, err := withdrawalUsecase.Request(request, req.PaymentAuthorization{})
+
+	assert.Nil(suite.T(), err)
+	suite.pinUsecaseMock.AssertNotCalled(suite.T(), "AuthorizeAmount", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WithdrawalUsecaseTestSuite) SetupTest() {
+	suite.withdrawalRepoMock = new(withdrawalRepoMock)
+	suite.pinUsecaseMock = new(pinUsecaseMock)
+}
+
+func TestWithdrawalUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WithdrawalUsecaseTestSuite))
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func WriteFileAtomic(fileName string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(fileName)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(fileName)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpName, fileName); err != nil {
+		return err
+	}
+	return SyncDir(dir)
+}
+
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// Directories cannot be synced on every platform, so the result is ignored.
+	d.Sync()
+	return nil
+}
@@ -3,4 +3,7 @@ package authenticator
 type AccessDetails struct {
 	AccessUuid string
 	Username   string
+	FamilyId   string
+	Role       string
+	Scopes     []string
 }
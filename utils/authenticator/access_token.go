@@ -2,6 +2,8 @@ package authenticator
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/febriansr/simple-payment-api/model/app_error"
@@ -13,21 +15,33 @@ import (
 	"github.com/google/uuid"
 )
 
+const defaultRefreshTokenLifetime = 7 * 24 * time.Hour
+
 type AccessToken interface {
 	CreateAccessToken(customer *entity.Customer) (TokenDetails, error)
 	VerifyAccessToken(tokenString string) (AccessDetails, error)
 	StoreAccessToken(username string, tokenDetails TokenDetails) error
 	FetchAccessToken(accessDetails AccessDetails) error
 	DeleteAccessToken(accessUuid string) error
+	RefreshAccessToken(refreshToken string, currentRole func(username string) (string, error)) (TokenDetails, error)
+	RevokeTokenFamily(familyId string) error
+	RevokeSessions(username string) error
+	ListSessions(username string) ([]Session, error)
+	RevokeSession(username string, sessionId string) error
 }
 
 type accessToken struct {
 	config config.TokenConfig
+	keys   KeySet
 	client *redis.Client
 }
 
 func (t *accessToken) CreateAccessToken(customer *entity.Customer) (TokenDetails, error) {
-	tokenDetails := TokenDetails{}
+	return t.createTokens(customer.Username, RoleOrDefault(customer.Role), uuid.New().String())
+}
+
+func (t *accessToken) createTokens(username string, role string, familyId string) (TokenDetails, error) {
+	tokenDetails := TokenDetails{FamilyId: familyId}
 	now := time.Now().UTC()
 	end := now.Add(t.config.AccessTokenLifetime)
 	tokenDetails.AtExpires = end.Unix()
@@ -36,54 +50,126 @@ func (t *accessToken) CreateAccessToken(customer *entity.Customer) (TokenDetails
 		StandardClaims: jwt.StandardClaims{
 			Issuer: t.config.ApplicationName,
 		},
-		Username:   customer.Username,
+		Username:   username,
+		TokenType:  TokenTypeAccess,
 		AccessUuid: tokenDetails.AccessUuid,
+		FamilyId:   familyId,
+		Role:       role,
+		Scopes:     ScopesFor(role),
 	}
 	claims.IssuedAt = now.Unix()
 	claims.ExpiresAt = end.Unix()
-	token := jwt.NewWithClaims(
-		t.config.JwtSigningMethod,
-		claims,
-	)
-	newToken, err := token.SignedString([]byte(t.config.JwtSignatureKey))
+	newToken, err := t.sign(claims)
 	if err != nil {
 		return tokenDetails, app_error.InternalServerError("Failed to sign access token: " + err.Error())
 	}
 	tokenDetails.AccessToken = newToken
+
+	refreshLifetime := t.config.RefreshTokenLifetime
+	if refreshLifetime <= 0 {
+		refreshLifetime = defaultRefreshTokenLifetime
+	}
+	refreshEnd := now.Add(refreshLifetime)
+	tokenDetails.RtExpires = refreshEnd.Unix()
+	tokenDetails.RefreshUuid = uuid.New().String()
+	refreshClaims := Claims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer: t.config.ApplicationName,
+		},
+		Username:    username,
+		TokenType:   TokenTypeRefresh,
+		RefreshUuid: tokenDetails.RefreshUuid,
+		FamilyId:    familyId,
+		Role:        role,
+	}
+	refreshClaims.IssuedAt = now.Unix()
+	refreshClaims.ExpiresAt = refreshEnd.Unix()
+	newRefreshToken, err := t.sign(refreshClaims)
+	if err != nil {
+		return tokenDetails, app_error.InternalServerError("Failed to sign refresh token: " + err.Error())
+	}
+	tokenDetails.RefreshToken = newRefreshToken
 	return tokenDetails, nil
 }
 
-func (t *accessToken) VerifyAccessToken(tokenString string) (AccessDetails, error) {
-	token, _ := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if method, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, app_error.Unauthorized("Invalid signing method")
-		} else if method != t.config.JwtSigningMethod {
-			return nil, app_error.Unauthorized("Invalid signing method")
-		}
-		return []byte(t.config.JwtSignatureKey), nil
-	})
+func (t *accessToken) sign(claims Claims) (string, error) {
+	return t.keys.Sign(claims)
+}
+
+func (t *accessToken) parse(tokenString string, tokenType string) (Claims, error) {
+	claims := Claims{}
+	token, _ := jwt.ParseWithClaims(tokenString, &claims, t.keys.Verify)
+
+	if token == nil || !token.Valid || claims.Issuer != t.config.ApplicationName {
+		return Claims{}, app_error.Unauthorized("Invalid access method")
+	}
+	if claims.TokenType == "" {
+		claims.TokenType = TokenTypeAccess
+	}
+	if claims.TokenType != tokenType {
+		return Claims{}, app_error.Unauthorized("Invalid token type")
+	}
+	if claims.Role == "" {
+		claims.Role = RoleCustomer
+		claims.Scopes = ScopesFor(RoleCustomer)
+	}
+	return claims, nil
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	accessDetails := AccessDetails{}
-	if !ok || !token.Valid || claims["iss"] != t.config.ApplicationName {
-		return accessDetails, app_error.Unauthorized("Invalid access method")
+func (t *accessToken) VerifyAccessToken(tokenString string) (AccessDetails, error) {
+	claims, err := t.parse(tokenString, TokenTypeAccess)
+	if err != nil {
+		return AccessDetails{}, err
 	}
-	username := claims["username"].(string)
-	accessDetails.AccessUuid = claims["AccessUuid"].(string)
-	accessDetails.Username = username
-	return accessDetails, nil
+	return AccessDetails{
+		AccessUuid: claims.AccessUuid,
+		Username:   claims.Username,
+		FamilyId:   claims.FamilyId,
+		Role:       claims.Role,
+		Scopes:     claims.Scopes,
+	}, nil
 }
 
 func (t *accessToken) StoreAccessToken(username string, tokenDetails TokenDetails) error {
-	end := time.Unix(tokenDetails.AtExpires, 0)
-	now := time.Now()
-	err := t.client.Set(context.Background(), tokenDetails.AccessUuid, username, end.Sub(now)).Err()
+	ctx := context.Background()
+	_, err := t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		t.storeTokens(pipe, username, tokenDetails)
+		if tokenDetails.FamilyId == "" {
+			return nil
+		}
+		now := time.Now()
+		pipe.HSet(ctx, tokenFamilyKey(tokenDetails.FamilyId),
+			"user_agent", tokenDetails.Client.UserAgent,
+			"ip", tokenDetails.Client.Ip,
+			"issued_at", now.Unix(),
+		)
+		pipe.ZAdd(ctx, sessionIndexKey(username), &redis.Z{Score: float64(now.Unix()), Member: tokenDetails.FamilyId})
+		pipe.ExpireAt(ctx, sessionIndexKey(username), time.Unix(tokenDetails.RtExpires, 0))
+		return nil
+	})
 	if err != nil {
 		return app_error.InternalServerError("Failed to store access token: " + err.Error())
 	}
 	return nil
 }
 
+func (t *accessToken) storeTokens(pipe redis.Pipeliner, username string, tokenDetails TokenDetails) {
+	ctx := context.Background()
+	now := time.Now()
+	pipe.Set(ctx, tokenDetails.AccessUuid, username, time.Unix(tokenDetails.AtExpires, 0).Sub(now))
+	if tokenDetails.FamilyId == "" {
+		return
+	}
+	familyKey := tokenFamilyKey(tokenDetails.FamilyId)
+	pipe.HSet(ctx, familyKey,
+		"username", username,
+		"refresh_uuid", tokenDetails.RefreshUuid,
+		"access_uuid", tokenDetails.AccessUuid,
+		"expires_at", tokenDetails.RtExpires,
+	)
+	pipe.ExpireAt(ctx, familyKey, time.Unix(tokenDetails.RtExpires, 0))
+}
+
 func (t *accessToken) FetchAccessToken(accessDetails AccessDetails) error {
 	username, err := t.client.Get(context.Background(), accessDetails.AccessUuid).Result()
 	if err != nil {
@@ -106,9 +192,157 @@ func (t *accessToken) DeleteAccessToken(accessUuid string) error {
 	return nil
 }
 
-func NewAccessToken(config config.TokenConfig, client *redis.Client) AccessToken {
+// RefreshAccessToken rotates the refresh token of a family. The role of the new
+// tokens comes from currentRole rather than the old token, so a role change
+// applies on the next refresh.
+func (t *accessToken) RefreshAccessToken(refreshToken string, currentRole func(username string) (string, error)) (TokenDetails, error) {
+	claims, err := t.parse(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return TokenDetails{}, err
+	}
+
+	ctx := context.Background()
+	familyKey := tokenFamilyKey(claims.FamilyId)
+	var tokenDetails TokenDetails
+	err = t.client.Watch(ctx, func(tx *redis.Tx) error {
+		family, err := tx.HGetAll(ctx, familyKey).Result()
+		if err != nil {
+			return app_error.InternalServerError("Failed to fetch refresh token: " + err.Error())
+		}
+		if len(family) == 0 {
+			return app_error.Unauthorized("Refresh token revoked")
+		}
+		if family["refresh_uuid"] != claims.RefreshUuid {
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				revokeFamily(pipe, claims.FamilyId, family)
+				return nil
+			})
+			if err != nil {
+				return app_error.InternalServerError("Failed to revoke token family: " + err.Error())
+			}
+			return app_error.Unauthorized("Refresh token reuse detected, please login again")
+		}
+
+		role, err := currentRole(claims.Username)
+		if err != nil {
+			return err
+		}
+		tokenDetails, err = t.createTokens(claims.Username, RoleOrDefault(role), claims.FamilyId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, family["access_uuid"])
+			t.storeTokens(pipe, claims.Username, tokenDetails)
+			pipe.ExpireAt(ctx, sessionIndexKey(claims.Username), time.Unix(tokenDetails.RtExpires, 0))
+			return nil
+		})
+		return err
+	}, familyKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return TokenDetails{}, app_error.Unauthorized("Refresh token already used")
+	}
+	if err != nil {
+		var appError *app_error.AppError
+		if !errors.As(err, &appError) {
+			err = app_error.InternalServerError("Failed to rotate refresh token: " + err.Error())
+		}
+		return TokenDetails{}, err
+	}
+	return tokenDetails, nil
+}
+
+func (t *accessToken) RevokeTokenFamily(familyId string) error {
+	ctx := context.Background()
+	familyKey := tokenFamilyKey(familyId)
+	family, err := t.client.HGetAll(ctx, familyKey).Result()
+	if err != nil {
+		return app_error.InternalServerError("Failed to revoke refresh token: " + err.Error())
+	}
+	if len(family) == 0 {
+		return nil
+	}
+	_, err = t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		revokeFamily(pipe, familyId, family)
+		return nil
+	})
+	if err != nil {
+		return app_error.InternalServerError("Failed to revoke refresh token: " + err.Error())
+	}
+	return nil
+}
+
+func (t *accessToken) RevokeSessions(username string) error {
+	ids, err := t.client.ZRange(context.Background(), sessionIndexKey(username), 0, -1).Result()
+	if err != nil {
+		return app_error.InternalServerError("Failed to revoke sessions: " + err.Error())
+	}
+	for _, id := range ids {
+		if err = t.RevokeTokenFamily(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *accessToken) ListSessions(username string) ([]Session, error) {
+	ctx := context.Background()
+	ids, err := t.client.ZRevRange(ctx, sessionIndexKey(username), 0, -1).Result()
+	if err != nil {
+		return nil, app_error.InternalServerError("Failed to list sessions: " + err.Error())
+	}
+	sessions := []Session{}
+	for _, id := range ids {
+		family, err := t.client.HGetAll(ctx, tokenFamilyKey(id)).Result()
+		if err != nil {
+			return nil, app_error.InternalServerError("Failed to list sessions: " + err.Error())
+		}
+		if len(family) == 0 {
+			t.client.ZRem(ctx, sessionIndexKey(username), id)
+			continue
+		}
+		issuedAt, _ := strconv.ParseInt(family["issued_at"], 10, 64)
+		expiresAt, _ := strconv.ParseInt(family["expires_at"], 10, 64)
+		sessions = append(sessions, Session{
+			Id:        id,
+			UserAgent: family["user_agent"],
+			Ip:        family["ip"],
+			IssuedAt:  time.Unix(issuedAt, 0).UTC(),
+			ExpiresAt: time.Unix(expiresAt, 0).UTC(),
+		})
+	}
+	return sessions, nil
+}
+
+func (t *accessToken) RevokeSession(username string, sessionId string) error {
+	owner, err := t.client.HGet(context.Background(), tokenFamilyKey(sessionId), "username").Result()
+	if errors.Is(err, redis.Nil) || (err == nil && owner != username) {
+		return app_error.DataNotFound("session not found")
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to revoke session: " + err.Error())
+	}
+	return t.RevokeTokenFamily(sessionId)
+}
+
+func revokeFamily(pipe redis.Pipeliner, familyId string, family map[string]string) {
+	ctx := context.Background()
+	pipe.Del(ctx, tokenFamilyKey(familyId), family["access_uuid"])
+	pipe.ZRem(ctx, sessionIndexKey(family["username"]), familyId)
+}
+
+func tokenFamilyKey(familyId string) string {
+	return "token_family:" + familyId
+}
+
+func sessionIndexKey(username string) string {
+	return "sessions:" + username
+}
+
+func NewAccessToken(config config.TokenConfig, keys KeySet, client *redis.Client) AccessToken {
 	return &accessToken{
 		config: config,
+		keys:   keys,
 		client: client,
 	}
 }
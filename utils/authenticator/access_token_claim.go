@@ -4,8 +4,18 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 type Claims struct {
 	jwt.StandardClaims
-	Username   string `json:"username"`
-	AccessUuid string
+	Username    string   `json:"username"`
+	TokenType   string   `json:"token_type,omitempty"`
+	AccessUuid  string   `json:",omitempty"`
+	RefreshUuid string   `json:",omitempty"`
+	FamilyId    string   `json:",omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
 }
@@ -0,0 +1,198 @@
+package authenticator
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	entity "github.com/febriansr/simple-payment-api/model/entity"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummyCustomer = &entity.Customer{
+	Username: "dummyUsername",
+}
+
+type AccessTokenTestSuite struct {
+	suite.Suite
+	server        *miniredis.Miniredis
+	authenticator AccessToken
+	roles         map[string]string
+}
+
+func (suite *AccessTokenTestSuite) currentRole(username string) (string, error) {
+	return suite.roles[username], nil
+}
+
+func (suite *AccessTokenTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+	client := redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	tokenConfig := config.TokenConfig{
+		ApplicationName:      "simplepayment",
+		JwtSignatureKey:      "secretkey",
+		JwtSigningMethod:     "HS256",
+		AccessTokenLifetime:  time.Minute,
+		RefreshTokenLifetime: time.Hour,
+	}
+	keys, err := NewKeySet(tokenConfig)
+	suite.Require().NoError(err)
+	suite.authenticator = NewAccessToken(tokenConfig, keys, client)
+	suite.roles = map[string]string{"dummyAdmin": RoleAdmin}
+}
+
+func (suite *AccessTokenTestSuite) login() TokenDetails {
+	tokenDetails, err := suite.authenticator.CreateAccessToken(dummyCustomer)
+	suite.Require().NoError(err)
+	tokenDetails.Client = ClientInfo{UserAgent: "Dummy User Agent", Ip: "127.0.0.1"}
+	suite.Require().NoError(suite.authenticator.StoreAccessToken(dummyCustomer.Username, tokenDetails))
+	return tokenDetails
+}
+
+func (suite *AccessTokenTestSuite) assertUnauthorized(err error) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusUnauthorized, appError.ErrorType)
+}
+
+func (suite *AccessTokenTestSuite) TestCreateAccessToken_IssuesPair() {
+	tokenDetails := suite.login()
+	accessDetails, err := suite.authenticator.VerifyAccessToken(tokenDetails.AccessToken)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), AccessDetails{
+		AccessUuid: tokenDetails.AccessUuid,
+		Username:   dummyCustomer.Username,
+		FamilyId:   tokenDetails.FamilyId,
+		Role:       RoleCustomer,
+		Scopes:     []string{ScopePaymentsWrite, ScopeHistoryRead},
+	}, accessDetails)
+	assert.NotEmpty(suite.T(), tokenDetails.RefreshToken)
+	assert.True(suite.T(), suite.server.Exists("token_family:"+tokenDetails.FamilyId))
+
+	_, err = suite.authenticator.VerifyAccessToken(tokenDetails.RefreshToken)
+	suite.assertUnauthorized(err)
+}
+
+func (suite *AccessTokenTestSuite) TestRefreshAccessToken_UsesStoredRole() {
+	tokenDetails, err := suite.authenticator.CreateAccessToken(&entity.Customer{Username: "dummyAdmin", Role: RoleAdmin})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.authenticator.StoreAccessToken("dummyAdmin", tokenDetails))
+
+	refreshed, err := suite.authenticator.RefreshAccessToken(tokenDetails.RefreshToken, suite.currentRole)
+	suite.Require().NoError(err)
+	accessDetails, err := suite.authenticator.VerifyAccessToken(refreshed.AccessToken)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), RoleAdmin, accessDetails.Role)
+	assert.Equal(suite.T(), []string{ScopeAdminManage}, accessDetails.Scopes)
+	assert.True(suite.T(), accessDetails.HasRole(RoleAdmin))
+	assert.False(suite.T(), accessDetails.HasScope(ScopePaymentsWrite))
+}
+
+func (suite *AccessTokenTestSuite) TestRefreshAccessToken_DemotedRole() {
+	tokenDetails, err := suite.authenticator.CreateAccessToken(&entity.Customer{Username: "dummyAdmin", Role: RoleAdmin})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.authenticator.StoreAccessToken("dummyAdmin", tokenDetails))
+	suite.roles["dummyAdmin"] = ""
+
+	refreshed, err := suite.authenticator.RefreshAccessToken(tokenDetails.RefreshToken, suite.currentRole)
+	suite.Require().NoError(err)
+	accessDetails, err := suite.authenticator.VerifyAccessToken(refreshed.AccessToken)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), RoleCustomer, accessDetails.Role)
+	assert.False(suite.T(), accessDetails.HasScope(ScopeAdminManage))
+}
+
+func (suite *AccessTokenTestSuite) TestRevokeSessions() {
+	first := suite.login()
+	second := suite.login()
+
+	assert.Nil(suite.T(), suite.authenticator.RevokeSessions(dummyCustomer.Username))
+	sessions, err := suite.authenticator.ListSessions(dummyCustomer.Username)
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), sessions)
+	_, err = suite.authenticator.RefreshAccessToken(first.RefreshToken, suite.currentRole)
+	suite.assertUnauthorized(err)
+	_, err = suite.authenticator.RefreshAccessToken(second.RefreshToken, suite.currentRole)
+	suite.assertUnauthorized(err)
+}
+
+func (suite *AccessTokenTestSuite) TestRefreshAccessToken_Rotates() {
+	tokenDetails := suite.login()
+	rotated, err := suite.authenticator.RefreshAccessToken(tokenDetails.RefreshToken, suite.currentRole)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), tokenDetails.FamilyId, rotated.FamilyId)
+	assert.NotEqual(suite.T(), tokenDetails.RefreshUuid, rotated.RefreshUuid)
+	assert.False(suite.T(), suite.server.Exists(tokenDetails.AccessUuid))
+	assert.Nil(suite.T(), suite.authenticator.FetchAccessToken(AccessDetails{AccessUuid: rotated.AccessUuid}))
+
+	_, err = suite.authenticator.RefreshAccessToken(rotated.AccessToken, suite.currentRole)
+	suite.assertUnauthorized(err)
+}
+
+func (suite *AccessTokenTestSuite) TestRefreshAccessToken_ReuseRevokesFamily() {
+	tokenDetails := suite.login()
+	rotated, err := suite.authenticator.RefreshAccessToken(tokenDetails.RefreshToken, suite.currentRole)
+	suite.Require().NoError(err)
+
+	_, err = suite.authenticator.RefreshAccessToken(tokenDetails.RefreshToken, suite.currentRole)
+	suite.assertUnauthorized(err)
+	assert.False(suite.T(), suite.server.Exists("token_family:"+tokenDetails.FamilyId))
+	assert.False(suite.T(), suite.server.Exists(rotated.AccessUuid))
+
+	_, err = suite.authenticator.RefreshAccessToken(rotated.RefreshToken, suite.currentRole)
+	suite.assertUnauthorized(err)
+}
+
+func (suite *AccessTokenTestSuite) TestRevokeTokenFamily() {
+	tokenDetails := suite.login()
+	assert.Nil(suite.T(), suite.authenticator.RevokeTokenFamily(tokenDetails.FamilyId))
+	assert.False(suite.T(), suite.server.Exists(tokenDetails.AccessUuid))
+	_, err := suite.authenticator.RefreshAccessToken(tokenDetails.RefreshToken, suite.currentRole)
+	suite.assertUnauthorized(err)
+	assert.Nil(suite.T(), suite.authenticator.RevokeTokenFamily(tokenDetails.FamilyId))
+}
+
+func (suite *AccessTokenTestSuite) TestListSessions() {
+	first := suite.login()
+	second := suite.login()
+	_, err := suite.authenticator.RefreshAccessToken(second.RefreshToken, suite.currentRole)
+	suite.Require().NoError(err)
+
+	sessions, err := suite.authenticator.ListSessions(dummyCustomer.Username)
+	assert.Nil(suite.T(), err)
+	suite.Require().Len(sessions, 2)
+	ids := []string{sessions[0].Id, sessions[1].Id}
+	assert.ElementsMatch(suite.T(), []string{first.FamilyId, second.FamilyId}, ids)
+	assert.Equal(suite.T(), "Dummy User Agent", sessions[0].UserAgent)
+	assert.Equal(suite.T(), "127.0.0.1", sessions[0].Ip)
+	assert.False(suite.T(), sessions[0].IssuedAt.IsZero())
+	assert.True(suite.T(), sessions[0].ExpiresAt.After(sessions[0].IssuedAt))
+
+	suite.server.Del("token_family:" + first.FamilyId)
+	sessions, err = suite.authenticator.ListSessions(dummyCustomer.Username)
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), sessions, 1)
+}
+
+func (suite *AccessTokenTestSuite) TestRevokeSession() {
+	tokenDetails := suite.login()
+	err := suite.authenticator.RevokeSession("otherUsername", tokenDetails.FamilyId)
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusNotFound, appError.ErrorType)
+
+	assert.Nil(suite.T(), suite.authenticator.RevokeSession(dummyCustomer.Username, tokenDetails.FamilyId))
+	assert.False(suite.T(), suite.server.Exists(tokenDetails.AccessUuid))
+	sessions, err := suite.authenticator.ListSessions(dummyCustomer.Username)
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), sessions)
+}
+
+func TestAccessTokenTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenTestSuite))
+}
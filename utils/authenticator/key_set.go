@@ -0,0 +1,266 @@
+package authenticator
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+)
+
+const defaultSigningMethod = "HS256"
+
+type JsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JsonWebKeySet struct {
+	Keys []JsonWebKey `json:"keys"`
+}
+
+type KeySet interface {
+	Sign(claims jwt.Claims) (string, error)
+	Verify(token *jwt.Token) (interface{}, error)
+	Jwks() JsonWebKeySet
+}
+
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+type keySet struct {
+	signing *signingKey
+	keys    map[string]*signingKey
+	order   []string
+}
+
+func (k *keySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	if k.signing.id != "" {
+		token.Header["kid"] = k.signing.id
+	}
+	return token.SignedString(k.signing.signKey)
+}
+
+func (k *keySet) Verify(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, app_error.Unauthorized("Unknown signing key")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, app_error.Unauthorized("Invalid signing method")
+	}
+	return key.verifyKey, nil
+}
+
+func (k *keySet) Jwks() JsonWebKeySet {
+	jwks := JsonWebKeySet{Keys: []JsonWebKey{}}
+	for _, id := range k.order {
+		key := k.keys[id]
+		if jwk, ok := publicJwk(key.verifyKey); ok {
+			jwk.Use = "sig"
+			jwk.Alg = key.method.Alg()
+			jwk.Kid = key.id
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+func (k *keySet) add(key *signingKey) {
+	if _, exists := k.keys[key.id]; exists {
+		return
+	}
+	k.keys[key.id] = key
+	k.order = append(k.order, key.id)
+}
+
+func NewKeySet(config config.TokenConfig) (KeySet, error) {
+	methodName := config.JwtSigningMethod
+	if methodName == "" {
+		methodName = defaultSigningMethod
+	}
+	method := jwt.GetSigningMethod(methodName)
+	keys := &keySet{keys: map[string]*signingKey{}}
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if config.JwtSignatureKey == "" {
+			return nil, errors.New("JWT_SIGNATURE_KEY is required for " + methodName)
+		}
+		keys.signing = &signingKey{method: method, signKey: []byte(config.JwtSignatureKey), verifyKey: []byte(config.JwtSignatureKey)}
+		keys.add(keys.signing)
+		return keys, nil
+	case *jwt.SigningMethodRSA, *SigningMethodEd25519:
+	default:
+		return nil, errors.New("unsupported JWT signing method " + methodName)
+	}
+
+	if config.JwtPrivateKeyFile == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_FILE is required for " + methodName)
+	}
+	privateKey, err := readPrivateKey(config.JwtPrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if methodFor(privateKey.Public(), method) != method {
+		return nil, fmt.Errorf("%s does not contain a %s key", config.JwtPrivateKeyFile, methodName)
+	}
+	keys.signing, err = newSigningKey(method, privateKey, privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	keys.add(keys.signing)
+
+	for _, file := range config.JwtVerificationKeyFiles {
+		publicKey, err := readPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+		key, err := newSigningKey(methodFor(publicKey, method), nil, publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		keys.add(key)
+	}
+	return keys, nil
+}
+
+func newSigningKey(method jwt.SigningMethod, signKey interface{}, publicKey crypto.PublicKey) (*signingKey, error) {
+	if method == nil {
+		return nil, errors.New("unsupported key type")
+	}
+	id, err := thumbprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &signingKey{id: id, method: method, signKey: signKey, verifyKey: publicKey}, nil
+}
+
+func methodFor(publicKey crypto.PublicKey, preferred jwt.SigningMethod) jwt.SigningMethod {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		if _, ok := preferred.(*jwt.SigningMethodRSA); ok {
+			return preferred
+		}
+		return jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		return SigningMethodEdDSA
+	}
+	return nil
+}
+
+func readPrivateKey(file string) (crypto.Signer, error) {
+	block, err := readPem(file)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported private key", file)
+	}
+	return signer, nil
+}
+
+func readPublicKey(file string) (crypto.PublicKey, error) {
+	block, err := readPem(file)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	privateKey, err := readPrivateKey(file)
+	if err != nil {
+		return nil, err
+	}
+	return privateKey.Public(), nil
+}
+
+func readPem(file string) (*pem.Block, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", file)
+	}
+	return block, nil
+}
+
+func publicJwk(publicKey interface{}) (JsonWebKey, bool) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JsonWebKey{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JsonWebKey{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}, true
+	}
+	return JsonWebKey{}, false
+}
+
+func thumbprint(publicKey crypto.PublicKey) (string, error) {
+	jwk, ok := publicJwk(publicKey)
+	if !ok {
+		return "", errors.New("unsupported key type")
+	}
+	var members interface{}
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+	content, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
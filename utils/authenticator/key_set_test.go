@@ -0,0 +1,161 @@
+package authenticator
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeySetTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (suite *KeySetTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *KeySetTestSuite) writePem(name string, blockType string, content []byte) string {
+	file := filepath.Join(suite.dir, name)
+	suite.Require().NoError(os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600))
+	return file
+}
+
+func (suite *KeySetTestSuite) rsaKey(name string) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	suite.Require().NoError(err)
+	return suite.writePem(name+".pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		suite.writePem(name+".pub", "PUBLIC KEY", public)
+}
+
+func (suite *KeySetTestSuite) ed25519Key(name string) (string, string) {
+	public, key, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+	suite.Require().NoError(err)
+	publicBytes, err := x509.MarshalPKIXPublicKey(public)
+	suite.Require().NoError(err)
+	return suite.writePem(name+".pem", "PRIVATE KEY", private),
+		suite.writePem(name+".pub", "PUBLIC KEY", publicBytes)
+}
+
+func (suite *KeySetTestSuite) parse(keys KeySet, tokenString string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, &Claims{}, keys.Verify)
+}
+
+func (suite *KeySetTestSuite) sign(keys KeySet) string {
+	token, err := keys.Sign(Claims{StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix()}, Username: "dummyUsername"})
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *KeySetTestSuite) TestRS256_SignAndVerify() {
+	privateKey, _ := suite.rsaKey("current")
+	keys, err := NewKeySet(config.TokenConfig{JwtSigningMethod: "RS256", JwtPrivateKeyFile: privateKey})
+	suite.Require().NoError(err)
+
+	token, err := suite.parse(keys, suite.sign(keys))
+	suite.Require().NoError(err)
+	assert.True(suite.T(), token.Valid)
+	assert.Equal(suite.T(), "RS256", token.Header["alg"])
+
+	jwks := keys.Jwks()
+	suite.Require().Len(jwks.Keys, 1)
+	assert.Equal(suite.T(), token.Header["kid"], jwks.Keys[0].Kid)
+	assert.Equal(suite.T(), "RSA", jwks.Keys[0].Kty)
+	assert.Equal(suite.T(), "AQAB", jwks.Keys[0].E)
+	assert.NotEmpty(suite.T(), jwks.Keys[0].N)
+}
+
+func (suite *KeySetTestSuite) TestEdDSA_SignAndVerify() {
+	privateKey, _ := suite.ed25519Key("current")
+	keys, err := NewKeySet(config.TokenConfig{JwtSigningMethod: "EdDSA", JwtPrivateKeyFile: privateKey})
+	suite.Require().NoError(err)
+
+	token, err := suite.parse(keys, suite.sign(keys))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "EdDSA", token.Header["alg"])
+
+	jwks := keys.Jwks()
+	suite.Require().Len(jwks.Keys, 1)
+	assert.Equal(suite.T(), JsonWebKey{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: token.Header["kid"].(string), Crv: "Ed25519", X: jwks.Keys[0].X}, jwks.Keys[0])
+}
+
+func (suite *KeySetTestSuite) TestRotation_OldKeyStillVerifies() {
+	oldPrivateKey, oldPublicKey := suite.rsaKey("old")
+	newPrivateKey, _ := suite.ed25519Key("new")
+	oldKeys, err := NewKeySet(config.TokenConfig{JwtSigningMethod: "RS256", JwtPrivateKeyFile: oldPrivateKey})
+	suite.Require().NoError(err)
+	oldToken := suite.sign(oldKeys)
+
+	keys, err := NewKeySet(config.TokenConfig{
+		JwtSigningMethod:        "EdDSA",
+		JwtPrivateKeyFile:       newPrivateKey,
+		JwtVerificationKeyFiles: []string{oldPublicKey},
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.parse(keys, oldToken)
+	assert.Nil(suite.T(), err)
+	_, err = suite.parse(keys, suite.sign(keys))
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), keys.Jwks().Keys, 2)
+
+	withoutOldKey, err := NewKeySet(config.TokenConfig{JwtSigningMethod: "EdDSA", JwtPrivateKeyFile: newPrivateKey})
+	suite.Require().NoError(err)
+	_, err = suite.parse(withoutOldKey, oldToken)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *KeySetTestSuite) TestVerify_RejectsAlgorithmMismatch() {
+	privateKey, _ := suite.rsaKey("current")
+	keys, err := NewKeySet(config.TokenConfig{JwtSigningMethod: "RS256", JwtPrivateKeyFile: privateKey})
+	suite.Require().NoError(err)
+	kid := keys.Jwks().Keys[0].Kid
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{Username: "dummyUsername"})
+	forged.Header["kid"] = kid
+	tokenString, err := forged.SignedString([]byte("secretkey"))
+	suite.Require().NoError(err)
+	_, err = suite.parse(keys, tokenString)
+	assert.NotNil(suite.T(), err)
+
+	hmacKeys, err := NewKeySet(config.TokenConfig{JwtSignatureKey: "secretkey"})
+	suite.Require().NoError(err)
+	_, err = suite.parse(keys, suite.sign(hmacKeys))
+	assert.NotNil(suite.T(), err)
+	assert.Empty(suite.T(), hmacKeys.Jwks().Keys)
+}
+
+func (suite *KeySetTestSuite) TestNewKeySet_Failed() {
+	rsaPrivateKey, _ := suite.rsaKey("rsa")
+	configs := []config.TokenConfig{
+		{JwtSigningMethod: "HS256"},
+		{JwtSigningMethod: "none"},
+		{JwtSigningMethod: "RS256"},
+		{JwtSigningMethod: "RS256", JwtPrivateKeyFile: filepath.Join(suite.dir, "missing.pem")},
+		{JwtSigningMethod: "EdDSA", JwtPrivateKeyFile: rsaPrivateKey},
+		{JwtSigningMethod: "RS256", JwtPrivateKeyFile: rsaPrivateKey, JwtVerificationKeyFiles: []string{suite.writePem("invalid.pub", "PUBLIC KEY", []byte("invalid"))}},
+	}
+	for _, tokenConfig := range configs {
+		_, err := NewKeySet(tokenConfig)
+		assert.NotNil(suite.T(), err, tokenConfig.JwtSigningMethod)
+	}
+}
+
+func TestKeySetTestSuite(t *testing.T) {
+	suite.Run(t, new(KeySetTestSuite))
+}
@@ -0,0 +1,53 @@
+package authenticator
+
+const (
+	RoleCustomer = "customer"
+	RoleMerchant = "merchant"
+	RoleAdmin    = "admin"
+)
+
+const (
+	ScopePaymentsWrite = "payments:write"
+	ScopeHistoryRead   = "history:read"
+	ScopeAdminManage   = "admin:manage"
+)
+
+var roleScopes = map[string][]string{
+	RoleCustomer: {ScopePaymentsWrite, ScopeHistoryRead},
+	RoleMerchant: {ScopeHistoryRead},
+	RoleAdmin:    {ScopeAdminManage},
+}
+
+func ValidRole(role string) bool {
+	_, ok := roleScopes[role]
+	return ok
+}
+
+func RoleOrDefault(role string) string {
+	if role == "" {
+		return RoleCustomer
+	}
+	return role
+}
+
+func ScopesFor(role string) []string {
+	return append([]string{}, roleScopes[RoleOrDefault(role)]...)
+}
+
+func (a AccessDetails) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if RoleOrDefault(a.Role) == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (a AccessDetails) HasScope(scope string) bool {
+	for _, granted := range a.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
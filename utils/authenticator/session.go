@@ -0,0 +1,17 @@
+package authenticator
+
+import "time"
+
+type ClientInfo struct {
+	UserAgent string
+	Ip        string
+}
+
+type Session struct {
+	Id        string    `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	Ip        string    `json:"ip"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Current   bool      `json:"current"`
+}
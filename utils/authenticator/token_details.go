@@ -1,7 +1,12 @@
 package authenticator
 
 type TokenDetails struct {
-	AccessToken string
-	AccessUuid  string
-	AtExpires   int64
+	AccessToken  string
+	AccessUuid   string
+	AtExpires    int64
+	RefreshToken string
+	RefreshUuid  string
+	RtExpires    int64
+	FamilyId     string
+	Client       ClientInfo
 }
@@ -0,0 +1,129 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const dialTimeout = 30 * time.Second
+
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+var reservedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// Policy decides which addresses requests to URLs supplied by users may
+// reach. Loopback, private, link-local, multicast and unspecified addresses
+// are refused unless they are in the allowlist.
+type Policy struct {
+	allowed []netip.Prefix
+}
+
+func (p Policy) Allowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range reservedNetworks {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves the host and fails unless every address it resolves to
+// is allowed.
+func (p Policy) CheckHost(ctx context.Context, host string) error {
+	addrs, err := lookup(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !p.Allowed(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+		}
+	}
+	return nil
+}
+
+// Client returns an HTTP client that checks the address of every connection
+// as it is dialed, so a host that resolves to another address after
+// CheckHost is refused too. Redirects are not followed and no proxy is used.
+func (p Policy) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout, KeepAlive: dialTimeout, Control: p.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (p Policy) control(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !p.Allowed(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+func lookup(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+	return addrs, nil
+}
+
+// NewPolicy takes the allowlist as addresses or networks in CIDR notation.
+// Invalid entries are logged and skipped.
+func NewPolicy(allowlist []string) Policy {
+	var policy Policy
+	for _, entry := range allowlist {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				log.Println("Ignoring invalid allowed network:", entry)
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		policy.allowed = append(policy.allowed, prefix.Masked())
+	}
+	return policy
+}
@@ -0,0 +1,66 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowed(t *testing.T) {
+	policy := NewPolicy(nil)
+	for _, address := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "100.64.0.1", "::1", "::", "fe80::1", "fc00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, policy.Allowed(netip.MustParseAddr(address)), address)
+	}
+	for _, address := range []string{"203.0.113.10", "8.8.8.8", "2001:4860:4860::8888"} {
+		assert.True(t, policy.Allowed(netip.MustParseAddr(address)), address)
+	}
+}
+
+func TestAllowed_Allowlist(t *testing.T) {
+	policy := NewPolicy([]string{"127.0.0.1", " 10.1.0.0/16 ", "not a network", ""})
+	assert.True(t, policy.Allowed(netip.MustParseAddr("127.0.0.1")))
+	assert.True(t, policy.Allowed(netip.MustParseAddr("10.1.2.3")))
+	assert.False(t, policy.Allowed(netip.MustParseAddr("127.0.0.2")))
+	assert.False(t, policy.Allowed(netip.MustParseAddr("10.2.0.1")))
+}
+
+func TestCheckHost(t *testing.T) {
+	policy := NewPolicy(nil)
+	assert.NoError(t, policy.CheckHost(context.Background(), "203.0.113.10"))
+	assert.ErrorIs(t, policy.CheckHost(context.Background(), "169.254.169.254"), ErrForbiddenAddress)
+	assert.ErrorIs(t, policy.CheckHost(context.Background(), "localhost"), ErrForbiddenAddress)
+}
+
+func TestClient_RefusesForbiddenAddressOnDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := NewPolicy(nil).Client(time.Second).Get(server.URL)
+	assert.True(t, errors.Is(err, ErrForbiddenAddress))
+
+	response, err := NewPolicy([]string{"127.0.0.1"}).Client(time.Second).Get(server.URL)
+	assert.NoError(t, err)
+	response.Body.Close()
+}
+
+func TestClient_DoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	response, err := NewPolicy([]string{"127.0.0.1"}).Client(time.Second).Get(server.URL)
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusFound, response.StatusCode)
+}
@@ -0,0 +1,40 @@
+package hmacsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	ApiKeyHeader    = "X-Api-Key"
+	TimestampHeader = "X-Timestamp"
+	NonceHeader     = "X-Nonce"
+	SignatureHeader = "X-Signature"
+)
+
+func SigningKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func StringToSign(method string, path string, timestamp string, nonce string, body []byte) string {
+	digest := sha256.Sum256(body)
+	return strings.Join([]string{strings.ToUpper(method), path, timestamp, nonce, hex.EncodeToString(digest[:])}, "\n")
+}
+
+func Sign(signingKey string, stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(signingKey string, stringToSign string, signature string) bool {
+	expected, _ := hex.DecodeString(Sign(signingKey, stringToSign))
+	actual, err := hex.DecodeString(strings.ToLower(signature))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, actual)
+}
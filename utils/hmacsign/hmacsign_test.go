@@ -0,0 +1,29 @@
+package hmacsign
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringToSign(t *testing.T) {
+	stringToSign := StringToSign("post", "/v1/merchant/charges?a=b", "1687824000", "nonce-1", []byte(`{"amount":"10"}`))
+	lines := strings.Split(stringToSign, "\n")
+	assert.Equal(t, []string{"POST", "/v1/merchant/charges?a=b", "1687824000", "nonce-1"}, lines[:4])
+	assert.Len(t, lines[4], 64)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", strings.Split(StringToSign("GET", "/", "1", "n", nil), "\n")[4])
+}
+
+func TestSignAndVerify(t *testing.T) {
+	signingKey := SigningKey("msk_secret")
+	assert.Len(t, signingKey, 64)
+	stringToSign := StringToSign("GET", "/v1/merchant/profile", "1687824000", "nonce-1", nil)
+	signature := Sign(signingKey, stringToSign)
+
+	assert.True(t, Verify(signingKey, stringToSign, signature))
+	assert.True(t, Verify(signingKey, stringToSign, strings.ToUpper(signature)))
+	assert.False(t, Verify(SigningKey("other"), stringToSign, signature))
+	assert.False(t, Verify(signingKey, stringToSign+"x", signature))
+	assert.False(t, Verify(signingKey, stringToSign, "not hex"))
+}
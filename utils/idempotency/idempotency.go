@@ -0,0 +1,150 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+	MaxKeyLength   = 255
+
+	stateProcessing = "processing"
+	stateCompleted  = "completed"
+	pollInterval    = 50 * time.Millisecond
+
+	defaultProcessingLifetime = time.Minute
+)
+
+type Record struct {
+	State       string          `json:"state"`
+	Fingerprint string          `json:"fingerprint"`
+	StatusCode  int             `json:"status_code,omitempty"`
+	Response    res.ApiResponse `json:"response"`
+}
+
+type Idempotency interface {
+	Begin(scope string, key string, fingerprint string) (*Record, error)
+	Complete(scope string, key string, fingerprint string, statusCode int, response res.ApiResponse) error
+	Release(scope string, key string) error
+}
+
+type idempotency struct {
+	config config.IdempotencyConfig
+	client *redis.Client
+}
+
+func (i *idempotency) Begin(scope string, key string, fingerprint string) (*Record, error) {
+	if key == "" || len(key) > MaxKeyLength {
+		return nil, app_error.InvalidError("invalid idempotency key")
+	}
+	redisKey := redisKey(scope, key)
+	processing, _ := json.Marshal(Record{State: stateProcessing, Fingerprint: fingerprint})
+	deadline := time.Now().Add(i.config.WaitTimeout)
+
+	for {
+		acquired, err := i.client.SetNX(context.Background(), redisKey, processing, i.processingLifetime()).Result()
+		if err != nil {
+			return nil, app_error.InternalServerError("Failed to store idempotency key: " + err.Error())
+		}
+		if acquired {
+			return nil, nil
+		}
+
+		data, err := i.client.Get(context.Background(), redisKey).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, app_error.InternalServerError("Failed to fetch idempotency key: " + err.Error())
+		}
+		var record Record
+		if err = json.Unmarshal(data, &record); err != nil {
+			return nil, app_error.InternalServerError("Failed to parse idempotency record: " + err.Error())
+		}
+		if record.Fingerprint != fingerprint {
+			return nil, app_error.InvalidError("Idempotency key was already used with a different request body")
+		}
+		if record.State == stateCompleted {
+			return &record, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, app_error.Conflict("A request with the same idempotency key is still being processed")
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func (i *idempotency) Complete(scope string, key string, fingerprint string, statusCode int, response res.ApiResponse) error {
+	data, err := json.Marshal(Record{
+		State:       stateCompleted,
+		Fingerprint: fingerprint,
+		StatusCode:  statusCode,
+		Response:    response,
+	})
+	if err != nil {
+		return app_error.InternalServerError("Failed to marshal idempotency record: " + err.Error())
+	}
+	err = i.client.Set(context.Background(), redisKey(scope, key), data, i.config.KeyLifetime).Err()
+	if err != nil {
+		return app_error.InternalServerError("Failed to store idempotency record: " + err.Error())
+	}
+	return nil
+}
+
+// processingLifetime bounds how long an unfinished request holds its key, so a
+// crash between Begin and Complete does not block retries for KeyLifetime.
+func (i *idempotency) processingLifetime() time.Duration {
+	if i.config.ProcessingLifetime <= 0 {
+		return defaultProcessingLifetime
+	}
+	return i.config.ProcessingLifetime
+}
+
+func (i *idempotency) Release(scope string, key string) error {
+	err := i.client.Del(context.Background(), redisKey(scope, key)).Err()
+	if err != nil {
+		return app_error.InternalServerError("Failed to release idempotency key: " + err.Error())
+	}
+	return nil
+}
+
+// Cacheable reports whether a response may be stored and replayed for its key.
+// Server errors and authentication, step-up and rate limit failures are
+// released instead, so that a retry carrying the missing credentials, or sent
+// after the lockout, is processed again under the same key.
+func Cacheable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode < http.StatusInternalServerError
+}
+
+func Fingerprint(request any) string {
+	data, _ := json.Marshal(request)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func redisKey(scope string, key string) string {
+	return "idempotency:" + scope + ":" + key
+}
+
+func NewIdempotency(config config.IdempotencyConfig, client *redis.Client) Idempotency {
+	return &idempotency{
+		config: config,
+		client: client,
+	}
+}
@@ -0,0 +1,143 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/febriansr/simple-payment-api/model/dto/res"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+	server      *miniredis.Miniredis
+	idempotency Idempotency
+}
+
+func (suite *IdempotencyTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+	client := redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	suite.idempotency = NewIdempotency(config.IdempotencyConfig{
+		ProcessingLifetime: time.Minute,
+		KeyLifetime:        time.Hour,
+		WaitTimeout:        100 * time.Millisecond,
+	}, client)
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_FirstRequest() {
+	record, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), record)
+	assert.True(suite.T(), suite.server.Exists("idempotency:dummyUsername:key"))
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_ReplaysCompletedResponse() {
+	_, response := res.NewSuccessMessage(nil)
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.idempotency.Complete("dummyUsername", "key", "fingerprint", http.StatusOK, response))
+
+	record, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, record.StatusCode)
+	assert.Equal(suite.T(), response, record.Response)
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_ScopedPerUser() {
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	record, err := suite.idempotency.Begin("otherUsername", "key", "fingerprint")
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), record)
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_FailedMismatchedFingerprint() {
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	_, err = suite.idempotency.Begin("dummyUsername", "key", "other fingerprint")
+	var appError *app_error.AppError
+	assert.True(suite.T(), errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusBadRequest, appError.ErrorType)
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_FailedStillProcessing() {
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	_, err = suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	var appError *app_error.AppError
+	assert.True(suite.T(), errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusConflict, appError.ErrorType)
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_WaitsForConcurrentDuplicate() {
+	_, response := res.NewSuccessMessage(nil)
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		suite.idempotency.Complete("dummyUsername", "key", "fingerprint", http.StatusOK, response)
+	}()
+	record, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), record)
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_ExpiredProcessingLock() {
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), time.Minute, suite.server.TTL("idempotency:dummyUsername:key"))
+
+	suite.server.FastForward(time.Minute)
+
+	record, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), record)
+}
+
+func (suite *IdempotencyTestSuite) TestComplete_StoresForKeyLifetime() {
+	_, response := res.NewSuccessMessage(nil)
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.idempotency.Complete("dummyUsername", "key", "fingerprint", http.StatusOK, response))
+
+	assert.Equal(suite.T(), time.Hour, suite.server.TTL("idempotency:dummyUsername:key"))
+	suite.server.FastForward(time.Minute)
+	record, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), record)
+}
+
+func (suite *IdempotencyTestSuite) TestBegin_FailedInvalidKey() {
+	_, err := suite.idempotency.Begin("dummyUsername", "", "fingerprint")
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *IdempotencyTestSuite) TestRelease() {
+	_, err := suite.idempotency.Begin("dummyUsername", "key", "fingerprint")
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), suite.idempotency.Release("dummyUsername", "key"))
+	record, err := suite.idempotency.Begin("dummyUsername", "key", "other fingerprint")
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), record)
+}
+
+func (suite *IdempotencyTestSuite) TestCacheable() {
+	assert.True(suite.T(), Cacheable(http.StatusOK))
+	assert.True(suite.T(), Cacheable(http.StatusBadRequest))
+	assert.True(suite.T(), Cacheable(http.StatusConflict))
+	assert.False(suite.T(), Cacheable(http.StatusUnauthorized))
+	assert.False(suite.T(), Cacheable(http.StatusForbidden))
+	assert.False(suite.T(), Cacheable(http.StatusTooManyRequests))
+	assert.False(suite.T(), Cacheable(http.StatusInternalServerError))
+}
+
+func TestIdempotencyTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
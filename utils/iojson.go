@@ -27,7 +27,7 @@ func WriteJSON(fileName string, data any) error {
 		return app_error.InternalServerError("Failed to marshal JSON data: " + err.Error())
 	}
 
-	err = ioutil.WriteFile(fileName, jsonData, 0644)
+	err = WriteFileAtomic(fileName, jsonData, 0644)
 	if err != nil {
 		return app_error.InternalServerError("Failed to write JSON data to file: " + err.Error())
 	}
@@ -0,0 +1,105 @@
+package loginchallenge
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	DefaultLifetime = 5 * time.Minute
+	MaxAttempts     = 5
+)
+
+type Challenge struct {
+	Token     string
+	Username  string
+	ExpiresAt int64
+}
+
+type LoginChallenge interface {
+	Create(username string) (Challenge, error)
+	Fetch(token string) (Challenge, error)
+	Delete(token string) error
+}
+
+type loginChallenge struct {
+	lifetime time.Duration
+	client   *redis.Client
+}
+
+func (l *loginChallenge) Create(username string) (Challenge, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return Challenge{}, app_error.InternalServerError("Failed to create login challenge: " + err.Error())
+	}
+	challenge := Challenge{
+		Token:     base64.RawURLEncoding.EncodeToString(raw),
+		Username:  username,
+		ExpiresAt: time.Now().Add(l.lifetime).Unix(),
+	}
+	ctx := context.Background()
+	pipe := l.client.TxPipeline()
+	pipe.HSet(ctx, challengeKey(challenge.Token), "username", username, "attempts", 0)
+	pipe.Expire(ctx, challengeKey(challenge.Token), l.lifetime)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Challenge{}, app_error.InternalServerError("Failed to store login challenge: " + err.Error())
+	}
+	return challenge, nil
+}
+
+func (l *loginChallenge) Fetch(token string) (Challenge, error) {
+	if token == "" {
+		return Challenge{}, app_error.Unauthorized("Invalid or expired login challenge")
+	}
+	ctx := context.Background()
+	key := challengeKey(token)
+	pipe := l.client.TxPipeline()
+	username := pipe.HGet(ctx, key, "username")
+	attempts := pipe.HIncrBy(ctx, key, "attempts", 1)
+	ttl := pipe.TTL(ctx, key)
+	pipe.Exec(ctx)
+	if username.Err() == redis.Nil {
+		l.client.Del(ctx, key)
+		return Challenge{}, app_error.Unauthorized("Invalid or expired login challenge")
+	}
+	if username.Err() != nil {
+		return Challenge{}, app_error.InternalServerError("Failed to fetch login challenge: " + username.Err().Error())
+	}
+	if attempts.Val() > MaxAttempts {
+		l.client.Del(ctx, key)
+		return Challenge{}, app_error.Unauthorized("Too many verification attempts, please login again")
+	}
+	return Challenge{
+		Token:     token,
+		Username:  username.Val(),
+		ExpiresAt: time.Now().Add(ttl.Val()).Unix(),
+	}, nil
+}
+
+func (l *loginChallenge) Delete(token string) error {
+	if err := l.client.Del(context.Background(), challengeKey(token)).Err(); err != nil {
+		return app_error.InternalServerError("Failed to delete login challenge: " + err.Error())
+	}
+	return nil
+}
+
+func challengeKey(token string) string {
+	return "login_challenge:" + token
+}
+
+func NewLoginChallenge(config config.SecurityConfig, client *redis.Client) LoginChallenge {
+	challenge := &loginChallenge{
+		lifetime: config.TwoFactorChallengeLifetime,
+		client:   client,
+	}
+	if challenge.lifetime <= 0 {
+		challenge.lifetime = DefaultLifetime
+	}
+	return challenge
+}
@@ -0,0 +1,61 @@
+package loginchallenge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginChallengeTestSuite struct {
+	suite.Suite
+	server         *miniredis.Miniredis
+	loginChallenge LoginChallenge
+}
+
+func (suite *LoginChallengeTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+	client := redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	suite.loginChallenge = NewLoginChallenge(config.SecurityConfig{TwoFactorChallengeLifetime: time.Minute}, client)
+}
+
+func (suite *LoginChallengeTestSuite) TestCreateAndFetch() {
+	challenge, err := suite.loginChallenge.Create("dummyUsername")
+	assert.Nil(suite.T(), err)
+	assert.NotEmpty(suite.T(), challenge.Token)
+
+	fetched, err := suite.loginChallenge.Fetch(challenge.Token)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "dummyUsername", fetched.Username)
+
+	assert.Nil(suite.T(), suite.loginChallenge.Delete(challenge.Token))
+	_, err = suite.loginChallenge.Fetch(challenge.Token)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *LoginChallengeTestSuite) TestFetch_Expired() {
+	challenge, _ := suite.loginChallenge.Create("dummyUsername")
+	suite.server.FastForward(time.Minute + time.Second)
+	_, err := suite.loginChallenge.Fetch(challenge.Token)
+	assert.NotNil(suite.T(), err)
+	assert.False(suite.T(), suite.server.Exists(challengeKey(challenge.Token)))
+}
+
+func (suite *LoginChallengeTestSuite) TestFetch_TooManyAttempts() {
+	challenge, _ := suite.loginChallenge.Create("dummyUsername")
+	for i := 0; i < MaxAttempts; i++ {
+		_, err := suite.loginChallenge.Fetch(challenge.Token)
+		suite.Require().NoError(err)
+	}
+	_, err := suite.loginChallenge.Fetch(challenge.Token)
+	assert.NotNil(suite.T(), err)
+	assert.False(suite.T(), suite.server.Exists(challengeKey(challenge.Token)))
+}
+
+func TestLoginChallengeTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginChallengeTestSuite))
+}
@@ -0,0 +1,218 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	DefaultMaxAttempts     = 5
+	DefaultMaxIpAttempts   = 20
+	DefaultBaseDelay       = time.Second
+	DefaultLockoutDuration = 15 * time.Minute
+)
+
+const maxAttemptRetries = 3
+
+type LoginGuard interface {
+	Attempt(username string, ip string) error
+	Succeeded(username string, ip string) error
+	Release(username string, ip string) error
+	Unlock(username string, ip string) error
+}
+
+type loginGuard struct {
+	maxAttempts     int
+	maxIpAttempts   int
+	baseDelay       time.Duration
+	lockoutDuration time.Duration
+	client          *redis.Client
+}
+
+// Attempt records a login attempt as failed before the credentials are
+// verified, so concurrent guesses cannot all get in before the first failure
+// is counted. Once the credentials are verified the caller hands the attempt
+// back with Succeeded, or with Release when it ended for another reason.
+func (g *loginGuard) Attempt(username string, ip string) error {
+	ctx := context.Background()
+	user, address := userKey(username), ipKey(ip)
+	for i := 0; i < maxAttemptRetries; i++ {
+		err := g.client.Watch(ctx, func(tx *redis.Tx) error {
+			now := time.Now()
+			userCount, err := g.read(ctx, tx, user, now)
+			if err != nil {
+				return err
+			}
+			ipCount, err := g.read(ctx, tx, address, now)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				g.write(ctx, pipe, user, userCount+1, now.Add(g.userDelay(userCount+1)))
+				g.write(ctx, pipe, address, ipCount+1, now.Add(g.ipDelay(ipCount+1)))
+				return nil
+			})
+			return err
+		}, user, address)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return recordError(err)
+		}
+	}
+	return app_error.TooManyRequests("Too many concurrent login attempts, try again")
+}
+
+// read returns the attempt count of a key, or an error while the key is
+// blocked.
+func (g *loginGuard) read(ctx context.Context, tx *redis.Tx, key string, now time.Time) (int, error) {
+	fields, err := tx.HGetAll(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	count, _ := strconv.Atoi(fields["count"])
+	blockedUntil, _ := strconv.ParseInt(fields["blocked_until"], 10, 64)
+	wait := time.Unix(0, blockedUntil).Sub(now)
+	if wait > 0 {
+		seconds := int((wait + time.Second - 1) / time.Second)
+		return 0, app_error.TooManyRequests("Too many failed login attempts, try again in " + strconv.Itoa(seconds) + " seconds")
+	}
+	return count, nil
+}
+
+func (g *loginGuard) write(ctx context.Context, pipe redis.Pipeliner, key string, count int, blockedUntil time.Time) {
+	pipe.HSet(ctx, key, "count", count, "blocked_until", blockedUntil.UnixNano())
+	pipe.Expire(ctx, key, time.Until(blockedUntil)+g.lockoutDuration)
+}
+
+// Succeeded clears the failed attempts of the user and hands the attempt back
+// to the IP address.
+func (g *loginGuard) Succeeded(username string, ip string) error {
+	if err := g.client.Del(context.Background(), userKey(username)).Err(); err != nil {
+		return app_error.InternalServerError("Failed to reset login attempts: " + err.Error())
+	}
+	return recordError(g.giveBack(ipKey(ip), g.ipDelay))
+}
+
+// Release hands an attempt back without clearing earlier failures, for
+// attempts that ended before the credentials were found to be wrong, or that
+// were right but are not a completed login yet.
+func (g *loginGuard) Release(username string, ip string) error {
+	if err := g.giveBack(userKey(username), g.userDelay); err != nil {
+		return recordError(err)
+	}
+	return recordError(g.giveBack(ipKey(ip), g.ipDelay))
+}
+
+// giveBack takes one attempt off the count of a key. The key was not blocked
+// when the attempt was counted, so its block is recalculated from the lower
+// count, which keeps a lockout that concurrent attempts reached.
+func (g *loginGuard) giveBack(key string, delay func(count int) time.Duration) error {
+	ctx := context.Background()
+	for i := 0; i < maxAttemptRetries; i++ {
+		err := g.client.Watch(ctx, func(tx *redis.Tx) error {
+			count, err := tx.HGet(ctx, key, "count").Int()
+			if errors.Is(err, redis.Nil) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				if count <= 1 {
+					pipe.Del(ctx, key)
+					return nil
+				}
+				blockedUntil := time.Now()
+				if delay(count-1) >= g.lockoutDuration {
+					blockedUntil = blockedUntil.Add(g.lockoutDuration)
+				}
+				g.write(ctx, pipe, key, count-1, blockedUntil)
+				return nil
+			})
+			return err
+		}, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return redis.TxFailedErr
+}
+
+func recordError(err error) error {
+	var appError *app_error.AppError
+	if err == nil || errors.As(err, &appError) {
+		return err
+	}
+	return app_error.InternalServerError("Failed to record login attempt: " + err.Error())
+}
+
+func (g *loginGuard) Unlock(username string, ip string) error {
+	var keys []string
+	if username != "" {
+		keys = append(keys, userKey(username))
+	}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	if len(keys) == 0 {
+		return app_error.InvalidError("username or ip is required")
+	}
+	if err := g.client.Del(context.Background(), keys...).Err(); err != nil {
+		return app_error.InternalServerError("Failed to unlock login: " + err.Error())
+	}
+	return nil
+}
+
+func (g *loginGuard) userDelay(count int) time.Duration {
+	if count >= g.maxAttempts {
+		return g.lockoutDuration
+	}
+	delay := g.baseDelay << (count - 1)
+	if delay <= 0 || delay > g.lockoutDuration {
+		return g.lockoutDuration
+	}
+	return delay
+}
+
+func (g *loginGuard) ipDelay(count int) time.Duration {
+	if count >= g.maxIpAttempts {
+		return g.lockoutDuration
+	}
+	return 0
+}
+
+func userKey(username string) string {
+	return "login_failures:user:" + username
+}
+
+func ipKey(ip string) string {
+	return "login_failures:ip:" + ip
+}
+
+func NewLoginGuard(config config.SecurityConfig, client *redis.Client) LoginGuard {
+	guard := &loginGuard{
+		maxAttempts:     config.LoginMaxAttempts,
+		maxIpAttempts:   config.LoginMaxIpAttempts,
+		baseDelay:       config.LoginBaseDelay,
+		lockoutDuration: config.LoginLockoutDuration,
+		client:          client,
+	}
+	if guard.maxAttempts <= 0 {
+		guard.maxAttempts = DefaultMaxAttempts
+	}
+	if guard.maxIpAttempts <= 0 {
+		guard.maxIpAttempts = DefaultMaxIpAttempts
+	}
+	if guard.baseDelay <= 0 {
+		guard.baseDelay = DefaultBaseDelay
+	}
+	if guard.lockoutDuration <= 0 {
+		guard.lockoutDuration = DefaultLockoutDuration
+	}
+	return guard
+}
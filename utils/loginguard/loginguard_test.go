@@ -0,0 +1,156 @@
+package loginguard
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginGuardTestSuite struct {
+	suite.Suite
+	server     *miniredis.Miniredis
+	loginGuard LoginGuard
+}
+
+func (suite *LoginGuardTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+	client := redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	suite.loginGuard = NewLoginGuard(config.SecurityConfig{
+		LoginMaxAttempts:     3,
+		LoginMaxIpAttempts:   5,
+		LoginBaseDelay:       time.Minute,
+		LoginLockoutDuration: time.Hour,
+	}, client)
+}
+
+func (suite *LoginGuardTestSuite) assertLocked(err error) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusTooManyRequests, appError.ErrorType)
+}
+
+func (suite *LoginGuardTestSuite) blockedFor(key string) time.Duration {
+	value := suite.server.HGet(key, "blocked_until")
+	blockedUntil, _ := strconv.ParseInt(value, 10, 64)
+	return time.Until(time.Unix(0, blockedUntil)).Round(time.Minute)
+}
+
+func (suite *LoginGuardTestSuite) count(key string) int {
+	count, _ := strconv.Atoi(suite.server.HGet(key, "count"))
+	return count
+}
+
+func (suite *LoginGuardTestSuite) elapse(key string) {
+	suite.server.HSet(key, "blocked_until", "0")
+}
+
+func (suite *LoginGuardTestSuite) TestAttempt_Succeeded() {
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	suite.Require().NoError(suite.loginGuard.Succeeded("dummyUsername", "127.0.0.1"))
+
+	assert.False(suite.T(), suite.server.Exists("login_failures:user:dummyUsername"))
+	assert.False(suite.T(), suite.server.Exists("login_failures:ip:127.0.0.1"))
+	assert.Nil(suite.T(), suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+}
+
+func (suite *LoginGuardTestSuite) TestAttempt_ConcurrentGuesses() {
+	results := make(chan error, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- suite.loginGuard.Attempt("dummyUsername", "127.0.0.1")
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	passed := 0
+	for err := range results {
+		if err == nil {
+			passed++
+			continue
+		}
+		suite.assertLocked(err)
+	}
+	assert.Equal(suite.T(), 1, passed)
+	assert.Equal(suite.T(), 1, suite.count("login_failures:user:dummyUsername"))
+}
+
+func (suite *LoginGuardTestSuite) TestAttempt_ProgressiveDelayThenLockout() {
+	key := "login_failures:user:dummyUsername"
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	suite.assertLocked(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	assert.Equal(suite.T(), time.Minute, suite.blockedFor(key))
+
+	suite.elapse(key)
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	assert.Equal(suite.T(), 2*time.Minute, suite.blockedFor(key))
+
+	suite.elapse(key)
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	assert.Equal(suite.T(), time.Hour, suite.blockedFor(key))
+
+	assert.Nil(suite.T(), suite.loginGuard.Attempt("otherUsername", "127.0.0.1"))
+}
+
+func (suite *LoginGuardTestSuite) TestAttempt_IpLockout() {
+	for i := 0; i < 5; i++ {
+		suite.Require().NoError(suite.loginGuard.Attempt("user"+strconv.Itoa(i), "127.0.0.1"))
+	}
+	suite.assertLocked(suite.loginGuard.Attempt("otherUsername", "127.0.0.1"))
+	assert.Nil(suite.T(), suite.loginGuard.Attempt("otherUsername", "127.0.0.2"))
+}
+
+func (suite *LoginGuardTestSuite) TestSucceeded_ResetsUser() {
+	suite.Require().NoError(suite.loginGuard.Attempt("otherUsername", "127.0.0.1"))
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+
+	assert.Nil(suite.T(), suite.loginGuard.Succeeded("dummyUsername", "127.0.0.1"))
+	assert.False(suite.T(), suite.server.Exists("login_failures:user:dummyUsername"))
+	assert.Equal(suite.T(), 1, suite.count("login_failures:ip:127.0.0.1"))
+}
+
+func (suite *LoginGuardTestSuite) TestRelease_KeepsEarlierFailures() {
+	key := "login_failures:user:dummyUsername"
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	suite.elapse(key)
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+
+	assert.Nil(suite.T(), suite.loginGuard.Release("dummyUsername", "127.0.0.1"))
+	assert.Equal(suite.T(), 1, suite.count(key))
+	assert.Equal(suite.T(), 1, suite.count("login_failures:ip:127.0.0.1"))
+	assert.Nil(suite.T(), suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+}
+
+func (suite *LoginGuardTestSuite) TestRelease_RecalculatesIpLockout() {
+	for i := 0; i < 5; i++ {
+		suite.Require().NoError(suite.loginGuard.Attempt("user"+strconv.Itoa(i), "127.0.0.1"))
+	}
+
+	assert.Nil(suite.T(), suite.loginGuard.Release("user4", "127.0.0.1"))
+	assert.Nil(suite.T(), suite.loginGuard.Attempt("otherUsername", "127.0.0.1"))
+	suite.assertLocked(suite.loginGuard.Attempt("anotherUsername", "127.0.0.1"))
+}
+
+func (suite *LoginGuardTestSuite) TestUnlock() {
+	suite.Require().NoError(suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	assert.Nil(suite.T(), suite.loginGuard.Unlock("dummyUsername", "127.0.0.1"))
+	assert.Nil(suite.T(), suite.loginGuard.Attempt("dummyUsername", "127.0.0.1"))
+	assert.NotNil(suite.T(), suite.loginGuard.Unlock("", ""))
+}
+
+func TestLoginGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginGuardTestSuite))
+}
@@ -0,0 +1,137 @@
+package pinguard
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	DefaultMaxAttempts         = 3
+	DefaultLockoutDuration     = 30 * time.Minute
+	DefaultStepUpTokenLifetime = 5 * time.Minute
+)
+
+type StepUp struct {
+	Token     string
+	ExpiresAt int64
+}
+
+type PinGuard interface {
+	Check(username string) error
+	Failed(username string) error
+	Succeeded(username string) error
+	IssueStepUp(username string) (StepUp, error)
+	RedeemStepUp(username string, token string) error
+}
+
+type pinGuard struct {
+	maxAttempts         int
+	lockoutDuration     time.Duration
+	stepUpTokenLifetime time.Duration
+	client              *redis.Client
+}
+
+func (g *pinGuard) Check(username string) error {
+	ttl, err := g.client.TTL(context.Background(), lockKey(username)).Result()
+	if err != nil {
+		return app_error.InternalServerError("Failed to read PIN attempts: " + err.Error())
+	}
+	if ttl > 0 {
+		minutes := int((ttl + time.Minute - 1) / time.Minute)
+		return app_error.TooManyRequests("Too many wrong PIN attempts, try again in " + strconv.Itoa(minutes) + " minutes")
+	}
+	return nil
+}
+
+func (g *pinGuard) Failed(username string) error {
+	ctx := context.Background()
+	pipe := g.client.TxPipeline()
+	count := pipe.Incr(ctx, failuresKey(username))
+	pipe.Expire(ctx, failuresKey(username), g.lockoutDuration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return app_error.InternalServerError("Failed to record PIN attempt: " + err.Error())
+	}
+	if count.Val() < int64(g.maxAttempts) {
+		return nil
+	}
+	pipe = g.client.TxPipeline()
+	pipe.Set(ctx, lockKey(username), 1, g.lockoutDuration)
+	pipe.Del(ctx, failuresKey(username))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return app_error.InternalServerError("Failed to record PIN attempt: " + err.Error())
+	}
+	return nil
+}
+
+func (g *pinGuard) Succeeded(username string) error {
+	if err := g.client.Del(context.Background(), failuresKey(username)).Err(); err != nil {
+		return app_error.InternalServerError("Failed to reset PIN attempts: " + err.Error())
+	}
+	return nil
+}
+
+func (g *pinGuard) IssueStepUp(username string) (StepUp, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return StepUp{}, app_error.InternalServerError("Failed to create step-up token: " + err.Error())
+	}
+	stepUp := StepUp{
+		Token:     base64.RawURLEncoding.EncodeToString(raw),
+		ExpiresAt: time.Now().Add(g.stepUpTokenLifetime).Unix(),
+	}
+	err := g.client.Set(context.Background(), stepUpKey(stepUp.Token), username, g.stepUpTokenLifetime).Err()
+	if err != nil {
+		return StepUp{}, app_error.InternalServerError("Failed to store step-up token: " + err.Error())
+	}
+	return stepUp, nil
+}
+
+func (g *pinGuard) RedeemStepUp(username string, token string) error {
+	owner, err := g.client.GetDel(context.Background(), stepUpKey(token)).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && owner != username) {
+		return app_error.Forbidden("Invalid or expired step-up token")
+	}
+	if err != nil {
+		return app_error.InternalServerError("Failed to redeem step-up token: " + err.Error())
+	}
+	return nil
+}
+
+func failuresKey(username string) string {
+	return "pin_failures:" + username
+}
+
+func lockKey(username string) string {
+	return "pin_locked:" + username
+}
+
+func stepUpKey(token string) string {
+	return "step_up:" + token
+}
+
+func NewPinGuard(config config.SecurityConfig, client *redis.Client) PinGuard {
+	guard := &pinGuard{
+		maxAttempts:         config.PinMaxAttempts,
+		lockoutDuration:     config.PinLockoutDuration,
+		stepUpTokenLifetime: config.StepUpTokenLifetime,
+		client:              client,
+	}
+	if guard.maxAttempts <= 0 {
+		guard.maxAttempts = DefaultMaxAttempts
+	}
+	if guard.lockoutDuration <= 0 {
+		guard.lockoutDuration = DefaultLockoutDuration
+	}
+	if guard.stepUpTokenLifetime <= 0 {
+		guard.stepUpTokenLifetime = DefaultStepUpTokenLifetime
+	}
+	return guard
+}
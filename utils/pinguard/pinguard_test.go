@@ -0,0 +1,79 @@
+package pinguard
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PinGuardTestSuite struct {
+	suite.Suite
+	server   *miniredis.Miniredis
+	pinGuard PinGuard
+}
+
+func (suite *PinGuardTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+	client := redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	suite.pinGuard = NewPinGuard(config.SecurityConfig{
+		PinMaxAttempts:      3,
+		PinLockoutDuration:  10 * time.Minute,
+		StepUpTokenLifetime: time.Minute,
+	}, client)
+}
+
+func (suite *PinGuardTestSuite) assertStatus(err error, status int) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), status, appError.ErrorType)
+}
+
+func (suite *PinGuardTestSuite) TestFailed_Lockout() {
+	for i := 0; i < 2; i++ {
+		suite.Require().NoError(suite.pinGuard.Failed("dummyUsername"))
+		assert.Nil(suite.T(), suite.pinGuard.Check("dummyUsername"))
+	}
+	suite.Require().NoError(suite.pinGuard.Failed("dummyUsername"))
+	suite.assertStatus(suite.pinGuard.Check("dummyUsername"), http.StatusTooManyRequests)
+	assert.Nil(suite.T(), suite.pinGuard.Check("otherUsername"))
+
+	suite.server.FastForward(10*time.Minute + time.Second)
+	assert.Nil(suite.T(), suite.pinGuard.Check("dummyUsername"))
+}
+
+func (suite *PinGuardTestSuite) TestSucceeded_ResetsFailures() {
+	suite.Require().NoError(suite.pinGuard.Failed("dummyUsername"))
+	suite.Require().NoError(suite.pinGuard.Failed("dummyUsername"))
+	assert.Nil(suite.T(), suite.pinGuard.Succeeded("dummyUsername"))
+	suite.Require().NoError(suite.pinGuard.Failed("dummyUsername"))
+	assert.Nil(suite.T(), suite.pinGuard.Check("dummyUsername"))
+}
+
+func (suite *PinGuardTestSuite) TestStepUp_SingleUse() {
+	stepUp, err := suite.pinGuard.IssueStepUp("dummyUsername")
+	suite.Require().NoError(err)
+	assert.NotEmpty(suite.T(), stepUp.Token)
+
+	suite.assertStatus(suite.pinGuard.RedeemStepUp("otherUsername", stepUp.Token), http.StatusForbidden)
+	stepUp, _ = suite.pinGuard.IssueStepUp("dummyUsername")
+	assert.Nil(suite.T(), suite.pinGuard.RedeemStepUp("dummyUsername", stepUp.Token))
+	suite.assertStatus(suite.pinGuard.RedeemStepUp("dummyUsername", stepUp.Token), http.StatusForbidden)
+}
+
+func (suite *PinGuardTestSuite) TestStepUp_Expired() {
+	stepUp, _ := suite.pinGuard.IssueStepUp("dummyUsername")
+	suite.server.FastForward(time.Minute + time.Second)
+	suite.assertStatus(suite.pinGuard.RedeemStepUp("dummyUsername", stepUp.Token), http.StatusForbidden)
+}
+
+func TestPinGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(PinGuardTestSuite))
+}
@@ -0,0 +1,51 @@
+package replayguard
+
+import (
+	"context"
+	"time"
+
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/go-redis/redis/v8"
+)
+
+const DefaultWindow = 5 * time.Minute
+
+type ReplayGuard interface {
+	Remember(keyId string, timestamp time.Time, nonce string) error
+}
+
+type replayGuard struct {
+	window time.Duration
+	client *redis.Client
+}
+
+func (g *replayGuard) Remember(keyId string, timestamp time.Time, nonce string) error {
+	age := time.Since(timestamp)
+	if age > g.window || age < -g.window {
+		return app_error.Unauthorized("Request timestamp is outside the allowed window")
+	}
+	stored, err := g.client.SetNX(context.Background(), nonceKey(keyId, nonce), 1, 2*g.window).Result()
+	if err != nil {
+		return app_error.InternalServerError("Failed to record request nonce: " + err.Error())
+	}
+	if !stored {
+		return app_error.Unauthorized("Request nonce already used")
+	}
+	return nil
+}
+
+func nonceKey(keyId string, nonce string) string {
+	return "request_nonce:" + keyId + ":" + nonce
+}
+
+func NewReplayGuard(config config.SecurityConfig, client *redis.Client) ReplayGuard {
+	guard := &replayGuard{
+		window: config.MerchantSignatureWindow,
+		client: client,
+	}
+	if guard.window <= 0 {
+		guard.window = DefaultWindow
+	}
+	return guard
+}
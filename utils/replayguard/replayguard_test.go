@@ -0,0 +1,50 @@
+package replayguard
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/febriansr/simple-payment-api/config"
+	"github.com/febriansr/simple-payment-api/model/app_error"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReplayGuardTestSuite struct {
+	suite.Suite
+	server      *miniredis.Miniredis
+	replayGuard ReplayGuard
+}
+
+func (suite *ReplayGuardTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+	client := redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	suite.replayGuard = NewReplayGuard(config.SecurityConfig{MerchantSignatureWindow: time.Minute}, client)
+}
+
+func (suite *ReplayGuardTestSuite) assertUnauthorized(err error) {
+	var appError *app_error.AppError
+	suite.Require().True(errors.As(err, &appError))
+	assert.Equal(suite.T(), http.StatusUnauthorized, appError.ErrorType)
+}
+
+func (suite *ReplayGuardTestSuite) TestRemember_RejectsReplay() {
+	assert.Nil(suite.T(), suite.replayGuard.Remember("key-1", time.Now(), "nonce-1"))
+	suite.assertUnauthorized(suite.replayGuard.Remember("key-1", time.Now(), "nonce-1"))
+	assert.Nil(suite.T(), suite.replayGuard.Remember("key-2", time.Now(), "nonce-1"))
+	assert.Equal(suite.T(), 2*time.Minute, suite.server.TTL("request_nonce:key-1:nonce-1"))
+}
+
+func (suite *ReplayGuardTestSuite) TestRemember_RejectsOutsideWindow() {
+	suite.assertUnauthorized(suite.replayGuard.Remember("key-1", time.Now().Add(-2*time.Minute), "nonce-1"))
+	suite.assertUnauthorized(suite.replayGuard.Remember("key-1", time.Now().Add(2*time.Minute), "nonce-2"))
+	assert.False(suite.T(), suite.server.Exists("request_nonce:key-1:nonce-1"))
+}
+
+func TestReplayGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplayGuardTestSuite))
+}
@@ -0,0 +1,41 @@
+package secretbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealAndOpen(t *testing.T) {
+	box := NewSecretBox("Dummy Encryption Key")
+	sealed, err := box.Seal("signing key")
+	assert.Nil(t, err)
+	assert.True(t, Sealed(sealed))
+	assert.NotContains(t, sealed, "signing key")
+
+	other, err := box.Seal("signing key")
+	assert.Nil(t, err)
+	assert.NotEqual(t, sealed, other)
+
+	plaintext, err := box.Open(sealed)
+	assert.Nil(t, err)
+	assert.Equal(t, "signing key", plaintext)
+}
+
+func TestOpen_FailedWrongKey(t *testing.T) {
+	sealed, err := NewSecretBox("Dummy Encryption Key").Seal("signing key")
+	assert.Nil(t, err)
+
+	_, err = NewSecretBox("Other Encryption Key").Open(sealed)
+	assert.NotNil(t, err)
+	_, err = NewSecretBox("Dummy Encryption Key").Open("signing key")
+	assert.NotNil(t, err)
+}
+
+func TestEmptyKey(t *testing.T) {
+	box := NewSecretBox("")
+	_, err := box.Seal("signing key")
+	assert.NotNil(t, err)
+	_, err = box.Open(sealedPrefix + "AAAA")
+	assert.NotNil(t, err)
+}
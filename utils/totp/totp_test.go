@@ -0,0 +1,80 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type TotpTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TotpTestSuite) TestCode_RfcVectors() {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1234567890:  "005924",
+		20000000000: "353130",
+	}
+	for unix, expected := range vectors {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		assert.Nil(suite.T(), err)
+		assert.Equal(suite.T(), expected, code, unix)
+	}
+}
+
+func (suite *TotpTestSuite) TestValidate() {
+	now := time.Unix(1111111109, 0)
+	code, _ := Code(rfcSecret, Step(now)-1)
+
+	step, ok := Validate(rfcSecret, code, now, 0)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), Step(now)-1, step)
+
+	_, ok = Validate(rfcSecret, code, now, step)
+	assert.False(suite.T(), ok)
+
+	_, ok = Validate(rfcSecret, code, now.Add(2*Period), 0)
+	assert.False(suite.T(), ok)
+
+	_, ok = Validate(rfcSecret, "12345", now, 0)
+	assert.False(suite.T(), ok)
+}
+
+func (suite *TotpTestSuite) TestGenerateSecret() {
+	secret, err := GenerateSecret()
+	assert.Nil(suite.T(), err)
+	_, err = Code(secret, 1)
+	assert.Nil(suite.T(), err)
+	other, _ := GenerateSecret()
+	assert.NotEqual(suite.T(), secret, other)
+}
+
+func (suite *TotpTestSuite) TestURI() {
+	uri, err := url.Parse(URI("Simple Payment", "dummyUsername", rfcSecret))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "otpauth", uri.Scheme)
+	assert.Equal(suite.T(), "totp", uri.Host)
+	assert.Equal(suite.T(), "/Simple Payment:dummyUsername", uri.Path)
+	assert.Equal(suite.T(), rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(suite.T(), "Simple Payment", uri.Query().Get("issuer"))
+}
+
+func (suite *TotpTestSuite) TestRecoveryCodes() {
+	codes, err := GenerateRecoveryCodes()
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), codes, RecoveryCodeCount)
+	assert.Equal(suite.T(), HashRecoveryCode(codes[0]), HashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+	assert.NotEqual(suite.T(), HashRecoveryCode(codes[0]), HashRecoveryCode(codes[1]))
+}
+
+func TestTotpTestSuite(t *testing.T) {
+	suite.Run(t, new(TotpTestSuite))
+}